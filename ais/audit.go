@@ -0,0 +1,150 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/audit"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	auditMaxResp  = 4 * cmn.KiB // max size of the error response to look at
+	auditMaxError = 512         // max length of the recorded error message
+)
+
+type auditWriter struct {
+	http.ResponseWriter
+	status int
+	errMsg []byte
+}
+
+// read-only actions that are POST-ed
+var auditSkipActions = map[string]struct{}{
+	cmn.ActListObjects:   {},
+	cmn.ActSummaryBucket: {},
+}
+
+// auditHandler wraps REST handler to record user requests (but not intra-cluster
+// requests and reads) in the audit log.
+// NOTE: gateways redirect object requests to targets, and so object PUT and
+// DELETE are recorded by the gateway as redirected (307 along with the user ID),
+// and by the target - with the actual result (see initDaemon)
+func auditHandler(authn *authManager) func(http.HandlerFunc) http.HandlerFunc {
+	return func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || isIntraCall(r) {
+				h(w, r)
+				return
+			}
+			rec := newAuditRecord(r, authn)
+			if _, ok := auditSkipActions[rec.Action]; ok {
+				h(w, r)
+				return
+			}
+			aw := &auditWriter{ResponseWriter: w, status: http.StatusOK}
+			h(aw, r)
+			rec.Latency = int64(time.Since(rec.Time))
+			rec.Status = aw.status
+			if aw.status >= http.StatusBadRequest {
+				rec.Error = aw.errorMessage()
+			}
+			audit.Add(rec)
+		}
+	}
+}
+
+func newAuditRecord(r *http.Request, authn *authManager) *audit.Record {
+	rec := &audit.Record{
+		Time:     time.Now(),
+		User:     auditUser(r, authn),
+		ClientIP: r.RemoteAddr,
+		Method:   r.Method,
+		Path:     r.URL.Path,
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		rec.ClientIP = host
+	}
+	items, err := cmn.MatchRESTItems(r.URL.Path, 0, false, cmn.Version)
+	if err != nil || len(items) == 0 {
+		return rec
+	}
+	isObj := items[0] == cmn.Objects
+	if (items[0] == cmn.Buckets || isObj) && len(items) > 1 {
+		query := r.URL.Query()
+		rec.Bucket = &cmn.Bck{
+			Name:     items[1],
+			Provider: query.Get(cmn.URLParamProvider),
+			Ns:       cmn.ParseNsUname(query.Get(cmn.URLParamNamespace)),
+		}
+		if isObj && len(items) > 2 {
+			rec.Object = strings.Join(items[2:], "/")
+		}
+	}
+	// NOTE: object PUT carries the object's content, everything else - optional ActionMsg
+	if isObj && (r.Method == http.MethodPut || r.Method == http.MethodDelete) {
+		rec.Action = strings.ToLower(r.Method)
+	} else if msg, _ := peekActionMsg(r); msg != nil {
+		rec.Action = msg.Action
+	}
+	return rec
+}
+
+func auditUser(r *http.Request, authn *authManager) string {
+	config := cmn.GCO.Get()
+	if !config.Auth.Enabled || authn == nil {
+		return ""
+	}
+	s := strings.SplitN(r.Header.Get(cmn.HeaderAuthorization), " ", 2)
+	if len(s) != 2 || s[0] != cmn.HeaderBearer {
+		if config.Auth.AllowGuest {
+			return guestAcc.userID
+		}
+		return ""
+	}
+	rec, err := authn.validateToken(s[1])
+	if err != nil {
+		return ""
+	}
+	return rec.userID
+}
+
+//
+// auditWriter
+//
+
+func (aw *auditWriter) WriteHeader(status int) {
+	aw.status = status
+	aw.ResponseWriter.WriteHeader(status)
+}
+
+func (aw *auditWriter) Write(b []byte) (int, error) {
+	if aw.status >= http.StatusBadRequest && len(aw.errMsg) < auditMaxResp {
+		n := cmn.Min(len(b), auditMaxResp-len(aw.errMsg))
+		aw.errMsg = append(aw.errMsg, b[:n]...)
+	}
+	return aw.ResponseWriter.Write(b)
+}
+
+// error message from the (cmn.HTTPError) response
+func (aw *auditWriter) errorMessage() string {
+	var (
+		httpErr = cmn.HTTPError{}
+		msg     = string(aw.errMsg)
+	)
+	if jsoniter.Unmarshal(aw.errMsg, &httpErr) == nil && httpErr.Message != "" {
+		msg = httpErr.Message
+	}
+	msg = strings.TrimSpace(msg)
+	if len(msg) > auditMaxError {
+		msg = msg[:auditMaxError] + "..."
+	}
+	return msg
+}
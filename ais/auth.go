@@ -28,12 +28,17 @@ package ais
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/jsp"
 	jwt "github.com/dgrijalva/jwt-go"
+	jsoniter "github.com/json-iterator/go"
 )
 
 // Declare a new type for Context field names
@@ -45,11 +50,14 @@ const (
 	ctxUserCreds contextID = "userCreds" // a field of a context that contains user credentials
 )
 
+const revokedIDsFname = ".ais.revoked"
+
 type (
 	// TokenList is a list of tokens pushed by authn
 	TokenList struct {
-		Tokens  []string `json:"tokens"`
-		Version int64    `json:"version,string"`
+		Tokens  []string             `json:"tokens"`
+		IDs     map[string]time.Time `json:"ids,omitempty"` // revoked tokens and API keys: ID => expiration time
+		Version int64                `json:"version,string"`
 	}
 
 	authRec struct {
@@ -57,6 +65,10 @@ type (
 		issued  time.Time
 		expires time.Time
 		creds   cmn.SimpleKVs
+		roles   []string
+		grants  []cmn.AuthGrant // effective permissions (of all the user's roles included)
+		id      string          // token ID ("jti" claim), if any
+		apiKey  string          // API key ID if the token is an API key of a service account
 		isGuest bool
 	}
 
@@ -69,7 +81,11 @@ type (
 		// list of invalid tokens(revoked or of deleted users)
 		// Authn sends these tokens to primary for broadcasting
 		revokedTokens map[string]bool
-		version       int64
+		// IDs of revoked tokens and API keys (that may never expire) and their expiration times
+		revokedIDs map[string]time.Time
+		version    int64
+		// AuthN database replicated by the cluster (proxies only, see authndb.go)
+		db *AuthNDB
 	}
 )
 
@@ -78,26 +94,37 @@ var (
 	_ revs = &TokenList{}
 )
 
+// guest can read everything
 var guestAcc = &authRec{
 	userID:  "guest",
+	grants:  []cmn.AuthGrant{{Perms: []string{cmn.PermRead}}},
 	isGuest: true,
 }
 
 // Decrypts JWT token and returns all encrypted information.
 // Used by proxy - to check a user access and token validity(e.g, expiration),
 // and by target - only to get a user name for AWS/GCP access
+// The token is either issued by AuthN (HMAC-signed with the shared secret) or,
+// if configured, it is an ID token of an external OIDC provider (see oidc.go)
 func decryptToken(tokenStr string) (*authRec, error) {
 	var (
 		issueStr, expireStr string
 		invalTokenErr       = fmt.Errorf("invalid token")
+		authConf            = &cmn.GCO.Get().Auth
+		isOIDC              bool
 	)
 	rec := &authRec{}
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			return []byte(authConf.Secret), nil
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+			if authConf.OIDC.Issuer != "" {
+				isOIDC = true
+				return oidcKeyCache.key(token, &authConf.OIDC)
+			}
 		}
-
-		return []byte(cmn.GCO.Get().Auth.Secret), nil
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 	})
 	if err != nil {
 		return nil, err
@@ -107,6 +134,9 @@ func decryptToken(tokenStr string) (*authRec, error) {
 	if !ok || !token.Valid {
 		return nil, invalTokenErr
 	}
+	if isOIDC {
+		return oidcAuthRec(claims, &authConf.OIDC)
+	}
 	if rec.userID, ok = claims["username"].(string); !ok {
 		return nil, invalTokenErr
 	}
@@ -134,6 +164,25 @@ func decryptToken(tokenStr string) (*authRec, error) {
 	} else if glog.V(4) {
 		glog.Infof("Token for %s does not contain credentials", rec.userID)
 	}
+	// NOTE: token without permissions (e.g., issued by AuthN prior to RBAC) grants nothing
+	rec.id, _ = claims["jti"].(string)
+	rec.apiKey, _ = claims["apikey"].(string)
+	if roles, ok := claims["roles"].([]interface{}); ok {
+		for _, role := range roles {
+			if asStr, ok := role.(string); ok {
+				rec.roles = append(rec.roles, asStr)
+			}
+		}
+	}
+	if perms, ok := claims["perms"]; ok {
+		b, err := jsoniter.Marshal(perms)
+		if err != nil {
+			return nil, invalTokenErr
+		}
+		if err := jsoniter.Unmarshal(b, &rec.grants); err != nil {
+			return nil, invalTokenErr
+		}
+	}
 
 	return rec, nil
 }
@@ -196,16 +245,51 @@ func (a *authManager) updateRevokedList(tokens *TokenList) {
 		a.revokedTokens[token] = true
 		delete(a.tokens, token)
 	}
+	changed := len(tokens.IDs) > 0
+	for id, expires := range tokens.IDs {
+		a.revokedIDs[id] = expires
+	}
 	// clean up the list from obsolete data
+	now := time.Now()
 	for token := range a.revokedTokens {
 		rec, err := a.extractTokenData(token)
-		if err == nil && rec.expires.Before(time.Now()) {
+		if err == nil && rec.expires.Before(now) {
 			delete(a.revokedTokens, token)
 		}
 	}
+	for id, expires := range a.revokedIDs {
+		if expires.Before(now) {
+			delete(a.revokedIDs, id)
+			changed = true
+		}
+	}
+	if changed {
+		if err := jsp.Save(revokedIDsPath(), a.revokedIDs, jsp.CCSign()); err != nil {
+			glog.Errorf("failed to store revoked token IDs: %v", err)
+		}
+	}
 	a.Unlock()
 }
 
+// Unlike tokens, API keys may never expire, and so the list of revoked ones
+// must survive cluster restart
+func (a *authManager) loadRevokedIDs(fpath string) {
+	ids := make(map[string]time.Time)
+	if err := jsp.Load(fpath, &ids, jsp.CCSign()); err != nil {
+		if !os.IsNotExist(err) {
+			glog.Errorf("failed to load revoked token IDs: %v", err)
+		}
+		return
+	}
+	a.Lock()
+	for id, expires := range ids {
+		a.revokedIDs[id] = expires
+	}
+	a.Unlock()
+}
+
+func revokedIDsPath() string { return cmn.GCO.Get().Confdir + "/" + revokedIDsFname }
+
 // Checks if a token is valid:
 //   - must not be revoked one
 //   - must not be expired
@@ -221,10 +305,88 @@ func (a *authManager) validateToken(token string) (ar *authRec, err error) {
 	}
 
 	ar, err = a.extractTokenData(token)
+	if err == nil && (a.isRevokedID(ar.id) || a.isRevokedID(ar.apiKey)) {
+		ar, err = nil, fmt.Errorf("invalid token")
+	}
 	a.Unlock()
 	return
 }
 
+// must be called under lock
+func (a *authManager) isRevokedID(id string) bool {
+	if id == "" {
+		return false
+	}
+	_, ok := a.revokedIDs[id]
+	return ok
+}
+
+// validateRequest validates the request's bearer token (see validateToken)
+func (a *authManager) validateRequest(r *http.Request) (*authRec, error) {
+	authToken := r.Header.Get(cmn.HeaderAuthorization)
+	if authToken == "" && cmn.GCO.Get().Auth.AllowGuest {
+		return guestAcc, nil
+	}
+
+	s := strings.SplitN(authToken, " ", 2)
+	if len(s) != 2 || s[0] != cmn.HeaderBearer {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	if a == nil {
+		return nil, fmt.Errorf("invalid credentials")
+	}
+
+	auth, err := a.validateToken(s[1])
+	if err != nil {
+		glog.Errorf("Invalid token: %v", err)
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	return auth, nil
+}
+
+// GET /v1/daemon|cluster?what=... that require cluster-wide cmn.PermClusterAdmin
+var adminWhat = map[string]struct{}{
+	cmn.GetWhatConfig:     {},
+	cmn.GetWhatAudit:      {},
+	cmn.GetWhatBMD:        {},
+	cmn.GetWhatSmapVote:   {}, // includes BMD
+	cmn.GetWhatMountpaths: {},
+	cmn.GetWhatRebPlan:    {}, // makes every target walk all its objects
+}
+
+// checkAuthAdmin wraps the cluster management (cluster, daemon, and reverse) API:
+// intra-cluster requests (see isIntraCall) and reads are not checked - except reading
+// configuration, audit log, and other sensitive information (see adminWhat) -
+// everything else requires cluster-wide cmn.PermClusterAdmin
+func (h *httprunner) checkAuthAdmin(authn *authManager, handler http.HandlerFunc) http.HandlerFunc {
+	wrappedFunc := func(w http.ResponseWriter, r *http.Request) {
+		if isIntraCall(r) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			if _, ok := adminWhat[r.URL.Query().Get(cmn.URLParamWhat)]; !ok {
+				handler.ServeHTTP(w, r)
+				return
+			}
+		}
+		auth, err := authn.validateRequest(r)
+		if err != nil {
+			glog.Error(err)
+			h.invalmsghdlr(w, r, "Not authorized", http.StatusUnauthorized)
+			return
+		}
+		if err = cmn.CheckPerm(auth.grants, nil, cmn.PermClusterAdmin); err != nil {
+			h.invalmsghdlr(w, r, fmt.Sprintf("%s: %v", auth.userID, err), http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}
+	return wrappedFunc
+}
+
 // Decrypts token and returns information about a user for whom the token
 // was issued. Return error is the token expired or does not include all
 // mandatory fields
@@ -258,6 +420,7 @@ func (a *authManager) revokedTokenList() *TokenList {
 	a.Lock()
 	tlist := &TokenList{
 		Tokens:  make([]string, len(a.revokedTokens)),
+		IDs:     make(map[string]time.Time, len(a.revokedIDs)),
 		Version: a.version,
 	}
 
@@ -266,6 +429,9 @@ func (a *authManager) revokedTokenList() *TokenList {
 		tlist.Tokens[idx] = token
 		idx++
 	}
+	for id, expires := range a.revokedIDs {
+		tlist.IDs[id] = expires
+	}
 
 	a.Unlock()
 	return tlist
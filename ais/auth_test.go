@@ -0,0 +1,95 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+func TestRevokedAPIKey(t *testing.T) {
+	const keyID = "key-id"
+	dir, err := ioutil.TempDir("", "auth")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+	config := cmn.GCO.BeginUpdate()
+	confdir := config.Confdir
+	config.Confdir = dir
+	cmn.GCO.CommitUpdate(config)
+	setAuthSecret("revoked-key-secret")
+	defer func() {
+		setAuthSecret("")
+		config := cmn.GCO.BeginUpdate()
+		config.Confdir = confdir
+		cmn.GCO.CommitUpdate(config)
+	}()
+
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"username": "ci",
+		"issued":   now.Format(time.RFC822),
+		"expires":  now.Add(time.Hour).Format(time.RFC822),
+		"apikey":   keyID,
+	}).SignedString([]byte("revoked-key-secret"))
+	tassert.CheckFatal(t, err)
+
+	newAuthn := func() *authManager {
+		return &authManager{
+			tokens:        make(map[string]*authRec),
+			revokedTokens: make(map[string]bool),
+			revokedIDs:    make(map[string]time.Time),
+			version:       1,
+		}
+	}
+	a := newAuthn()
+	_, err = a.validateToken(token)
+	tassert.CheckFatal(t, err)
+
+	// revoked by ID (the token itself is unknown to AuthN)
+	a.updateRevokedList(&TokenList{IDs: map[string]time.Time{keyID: now.Add(time.Hour)}})
+	_, err = a.validateToken(token)
+	tassert.Errorf(t, err != nil, "revoked API key must be rejected")
+
+	// the list of revoked API keys survives restart
+	a = newAuthn()
+	a.loadRevokedIDs(revokedIDsPath())
+	_, err = a.validateToken(token)
+	tassert.Errorf(t, err != nil, "revoked API key must be rejected after restart")
+}
+
+func TestCheckAuthAdmin(t *testing.T) {
+	var (
+		h       = &httprunner{statsT: stats.NewTrackerMock()}
+		handler = h.checkAuthAdmin(nil, func(w http.ResponseWriter, r *http.Request) {})
+	)
+	tests := []struct {
+		method string
+		what   string
+		status int
+	}{
+		{http.MethodGet, cmn.GetWhatSmap, http.StatusOK},
+		{http.MethodGet, cmn.GetWhatStats, http.StatusOK},
+		{http.MethodGet, cmn.GetWhatConfig, http.StatusUnauthorized},
+		{http.MethodGet, cmn.GetWhatAudit, http.StatusUnauthorized},
+		{http.MethodGet, cmn.GetWhatMountpaths, http.StatusUnauthorized},
+		{http.MethodGet, cmn.GetWhatRebPlan, http.StatusUnauthorized},
+		{http.MethodPut, "", http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, "/v1/cluster?"+cmn.URLParamWhat+"="+test.what, nil)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		tassert.Errorf(t, w.Code == test.status, "%s %q: expected %d, got %d", test.method, test.what, test.status, w.Code)
+	}
+}
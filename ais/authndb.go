@@ -0,0 +1,172 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/jsp"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// AuthN database (users, roles, issued tokens) replicated by the cluster, so
+// that multiple AuthN instances can share it (see authn/README.md).
+// The cluster treats the database as an opaque versioned blob: the primary
+// accepts only the next version (optimistic concurrency), persists it, and
+// metasyncs it to all proxies.
+
+const authnDBFname = ".ais.authndb"
+
+type AuthNDB struct {
+	Version int64               `json:"version,string"`
+	Data    jsoniter.RawMessage `json:"data"`
+}
+
+// interface guard
+var _ revs = &AuthNDB{}
+
+func (db *AuthNDB) tag() string     { return revsAuthNTag }
+func (db *AuthNDB) version() int64  { return db.Version }
+func (db *AuthNDB) marshal() []byte { return cmn.MustMarshal(db) }
+
+// AuthN caches the database and gets it only if the version has changed
+func (db *AuthNDB) etag() string { return ETagAuthNDB(db.Version) }
+
+func ETagAuthNDB(version int64) string { return strconv.Quote(strconv.FormatInt(version, 10)) }
+
+func (a *authManager) loadDB(fpath string) {
+	db := &AuthNDB{}
+	if err := jsp.Load(fpath, db, jsp.CCSign()); err != nil {
+		if !os.IsNotExist(err) {
+			glog.Errorf("failed to load AuthN DB: %v", err)
+		}
+		return
+	}
+	a.Lock()
+	a.db = db
+	a.Unlock()
+}
+
+func (a *authManager) getDB() (db *AuthNDB) {
+	a.Lock()
+	db = a.db
+	a.Unlock()
+	return
+}
+
+// installs a newer version of the database; if next is true, the new version
+// must be the next one (not simply newer)
+func (a *authManager) updateDB(db *AuthNDB, fpath string, next bool) (updated bool, err error) {
+	a.Lock()
+	defer a.Unlock()
+	var curVer int64
+	if a.db != nil {
+		curVer = a.db.Version
+	}
+	if db.Version <= curVer || (next && db.Version != curVer+1) {
+		return false, nil
+	}
+	if err = jsp.Save(fpath, db, jsp.CCSign()); err != nil {
+		return false, err
+	}
+	a.db = db
+	return true, nil
+}
+
+func (h *httprunner) extractAuthNDB(payload msPayload) (*AuthNDB, error) {
+	bytes, ok := payload[revsAuthNTag]
+	if !ok {
+		return nil, nil
+	}
+	db := &AuthNDB{}
+	if err := jsoniter.Unmarshal(bytes, db); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal AuthN DB, err: %v", err)
+	}
+	glog.Infof("received AuthN DB v%d", db.Version)
+	return db, nil
+}
+
+// GET | PUT /v1/authndb
+// Reading or writing the database always requires cluster-admin permissions
+func (p *proxyrunner) authnDBHandler(w http.ResponseWriter, r *http.Request) {
+	if _, err := p.checkRESTItems(w, r, 0, false, cmn.Version, cmn.AuthNDB); err != nil {
+		return
+	}
+	auth, err := p.validateToken(r)
+	if err != nil {
+		p.invalmsghdlr(w, r, "Not authorized", http.StatusUnauthorized)
+		return
+	}
+	if err = cmn.CheckPerm(auth.grants, nil, cmn.PermClusterAdmin); err != nil {
+		p.invalmsghdlr(w, r, fmt.Sprintf("%s: %v", auth.userID, err), http.StatusForbidden)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		db := p.authn.getDB()
+		if db == nil {
+			p.invalmsghdlr(w, r, "AuthN DB "+cmn.DoesNotExist, http.StatusNotFound)
+			return
+		}
+		etag := db.etag()
+		w.Header().Set(cmn.HeaderETag, etag)
+		if r.Header.Get(cmn.HeaderIfNoneMatch) == etag {
+			w.WriteHeader(http.StatusNotModified) // AuthN has the current version
+			return
+		}
+		p.writeJSON(w, r, db.marshal(), "get-authndb")
+	case http.MethodPut:
+		p.httpAuthNDBPut(w, r)
+	default:
+		cmn.InvalidHandlerWithMsg(w, r, "invalid method for /authndb path")
+	}
+}
+
+func (p *proxyrunner) httpAuthNDBPut(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if p.forwardCP(w, r, &cmn.ActionMsg{Action: cmn.ActUpdateAuthNDB}, "", body) {
+		return
+	}
+	db := &AuthNDB{}
+	if err := jsoniter.Unmarshal(body, db); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	fpath := authnDBPath()
+	updated, err := p.authn.updateDB(db, fpath, true /*next*/)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !updated {
+		cur := p.authn.getDB()
+		s := fmt.Sprintf("AuthN DB v%d is not the next version", db.Version)
+		if cur != nil {
+			s += fmt.Sprintf(" (current v%d)", cur.Version)
+		}
+		p.invalmsghdlr(w, r, s, http.StatusConflict)
+		return
+	}
+	msgInt := p.newActionMsgInternalStr(cmn.ActUpdateAuthNDB, nil, nil)
+	p.metasyncer.sync(false, revsPair{db, msgInt})
+}
+
+func (p *proxyrunner) receiveAuthNDB(db *AuthNDB) {
+	if _, err := p.authn.updateDB(db, authnDBPath(), false /*next*/); err != nil {
+		glog.Errorf("%s: failed to store AuthN DB v%d: %v", p.si, db.Version, err)
+	}
+}
+
+func authnDBPath() string { return cmn.GCO.Get().Confdir + "/" + authnDBFname }
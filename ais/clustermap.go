@@ -128,6 +128,7 @@ func (m *smapX) delTarget(sid string) {
 		cmn.AssertMsg(false, fmt.Sprintf("FATAL: target: %s is not in: %s", sid, m.pp()))
 	}
 	delete(m.Tmap, sid)
+	delete(m.Leaving, sid)
 	m.Version++
 }
 
@@ -184,6 +185,12 @@ func (m *smapX) deepCopy(dst *smapX) {
 	for id, v := range m.NonElects {
 		dst.NonElects[id] = v
 	}
+	if len(m.Leaving) > 0 {
+		dst.Leaving = make(cmn.SimpleKVs, len(m.Leaving))
+		for id, v := range m.Leaving {
+			dst.Leaving[id] = v
+		}
+	}
 }
 
 func (m *smapX) merge(dst *smapX, override bool) (added int, err error) {
@@ -373,7 +380,7 @@ func (r *smapOwner) modify(pre func(clone *smapX) error, post ...func(clone *sma
 // new cluster.Snode
 //
 //=====================================================================
-func newSnode(id, proto, daeType string, publicAddr, intraControlAddr, intraDataAddr *net.TCPAddr) (snode *cluster.Snode) {
+func newSnode(id, proto, intraProto, daeType string, publicAddr, intraControlAddr, intraDataAddr *net.TCPAddr) (snode *cluster.Snode) {
 	publicNet := cluster.NetInfo{
 		NodeIPAddr: publicAddr.IP.String(),
 		DaemonPort: strconv.Itoa(publicAddr.Port),
@@ -384,7 +391,7 @@ func newSnode(id, proto, daeType string, publicAddr, intraControlAddr, intraData
 		intraControlNet = cluster.NetInfo{
 			NodeIPAddr: intraControlAddr.IP.String(),
 			DaemonPort: strconv.Itoa(intraControlAddr.Port),
-			DirectURL:  proto + "://" + intraControlAddr.String(),
+			DirectURL:  intraProto + "://" + intraControlAddr.String(),
 		}
 	}
 	intraDataNet := publicNet
@@ -392,7 +399,7 @@ func newSnode(id, proto, daeType string, publicAddr, intraControlAddr, intraData
 		intraDataNet = cluster.NetInfo{
 			NodeIPAddr: intraDataAddr.IP.String(),
 			DaemonPort: strconv.Itoa(intraDataAddr.Port),
-			DirectURL:  proto + "://" + intraDataAddr.String(),
+			DirectURL:  intraProto + "://" + intraDataAddr.String(),
 		}
 	}
 	snode = &cluster.Snode{
@@ -10,6 +10,7 @@ import (
 	"os"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/audit"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/jsp"
@@ -19,6 +20,7 @@ import (
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/sys"
+	"github.com/NVIDIA/aistore/tracing"
 	"github.com/NVIDIA/aistore/transport"
 	jsoniter "github.com/json-iterator/go"
 )
@@ -226,9 +228,11 @@ func initDaemon(version, build string) {
 	_ = daemon.smm.Init(true /* ditto */)
 	daemon.gmm.Sibling, daemon.smm.Sibling = daemon.smm, daemon.gmm
 
+	var service, daemonID string
 	if daemon.cli.role == cmn.Proxy {
 		p := &proxyrunner{}
 		p.initSI(cmn.Proxy)
+		service, daemonID = "aisproxy", p.si.ID()
 		p.initClusterCIDR()
 		daemon.rg.add(p, cmn.Proxy)
 
@@ -241,6 +245,7 @@ func initDaemon(version, build string) {
 	} else {
 		t := &targetrunner{}
 		t.initSI(cmn.Target)
+		service, daemonID = "aistarget", t.si.ID()
 		t.initHostIP()
 		daemon.rg.add(t, cmn.Target)
 
@@ -292,6 +297,17 @@ func initDaemon(version, build string) {
 			cmn.ExitLogf("Failed to set config: %s", err)
 		}
 	}
+
+	// NOTE: must be the last, after all config changes
+	config = cmn.GCO.Get()
+	if err := tracing.Init(&config.Tracing, service, daemonID); err != nil {
+		cmn.ExitLogf("Failed to initialize tracing: %v", err)
+	}
+	// targets always record object requests (that gateways redirect) -
+	// everything else only if configured (see targetrunner.Run)
+	if err := audit.Init(&config.Audit, daemonID); err != nil {
+		cmn.ExitLogf("Failed to initialize audit log: %v", err)
+	}
 }
 
 // Run is the 'main' where everything gets started
@@ -300,6 +316,8 @@ func Run(version, build string) {
 	defer glog.Flush()
 
 	initDaemon(version, build)
+	defer tracing.Stop() // flush pending spans
+	defer audit.Stop()
 
 	// Start all the runners
 	err := daemon.rg.run()
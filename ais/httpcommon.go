@@ -5,7 +5,9 @@
 package ais
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -26,10 +28,12 @@ import (
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/3rdparty/golang/mux"
+	"github.com/NVIDIA/aistore/audit"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/reb"
 	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/tracing"
 	"github.com/NVIDIA/aistore/xaction"
 	"github.com/OneOfOne/xxhash"
 	jsoniter "github.com/json-iterator/go"
@@ -55,6 +59,7 @@ type (
 		req     cmn.ReqArgs
 		timeout time.Duration
 		si      *cluster.Snode
+		ctx     context.Context // trace context, if any
 	}
 
 	// bcastArgs contains arguments for an intra-cluster broadcast call
@@ -62,6 +67,7 @@ type (
 		req     cmn.ReqArgs
 		network string // on of the cmn.KnownNetworks
 		timeout time.Duration
+		ctx     context.Context // trace context, if any
 
 		nodes []cluster.NodeMap
 		smap  *smapX
@@ -94,6 +100,7 @@ type (
 	netServer struct {
 		s             *http.Server
 		mux           *mux.ServeMux
+		tlsConf       *tls.Config // intra-cluster mutual TLS (see cmn.NewIntraTLSConfig)
 		sndRcvBufSize int
 	}
 	httprunner struct {
@@ -105,6 +112,8 @@ type (
 		si                 *cluster.Snode
 		httpclient         *http.Client // http client for intra-cluster comm
 		httpclientGetPut   *http.Client // http client to execute target <=> target GET & PUT (object)
+		pubclient          *http.Client // same as httpclient - for the public network (see call)
+		pubclientGetPut    *http.Client // same as httpclientGetPut - for the public network
 		keepalive          keepaliver
 		owner              struct {
 			smap *smapOwner
@@ -236,7 +245,16 @@ func (server *netServer) listenAndServe(addr string, logger *log.Logger) error {
 	if server.sndRcvBufSize > 0 {
 		server.s.ConnState = server.connStateListener // setsockopt; see also cmn.NewTransport
 	}
-	if config.Net.HTTP.UseHTTPS {
+	if server.tlsConf != nil {
+		// certificates are already loaded into the TLS config
+		server.s.TLSConfig = server.tlsConf
+		if err := server.s.ListenAndServeTLS("", ""); err != nil {
+			if err != http.ErrServerClosed {
+				glog.Errorf("Terminated server with err: %v", err)
+				return err
+			}
+		}
+	} else if config.Net.HTTP.UseHTTPS {
 		if err := server.s.ListenAndServeTLS(config.Net.HTTP.Certificate, config.Net.HTTP.Key); err != nil {
 			if err != http.ErrServerClosed {
 				glog.Errorf("Terminated server with err: %v", err)
@@ -344,15 +362,32 @@ func (h *httprunner) registerIntraDataNetHandler(path string, handler func(http.
 func (h *httprunner) init(s stats.Tracker, config *cmn.Config) {
 	h.statsT = s
 	h.httpclient = cmn.NewClient(cmn.TransportArgs{
-		Timeout:  config.Timeout.Default,
-		UseHTTPS: config.Net.HTTP.UseHTTPS,
+		Timeout:     config.Timeout.Default,
+		UseHTTPS:    config.Net.HTTP.UseHTTPS,
+		UseIntraTLS: true,
 	})
 	h.httpclientGetPut = cmn.NewClient(cmn.TransportArgs{
 		Timeout:         config.Timeout.DefaultLong,
 		WriteBufferSize: config.Net.HTTP.WriteBufferSize,
 		ReadBufferSize:  config.Net.HTTP.ReadBufferSize,
-		UseHTTPS:        false, // always plain intra-cluster http for data
+		UseHTTPS:        false, // plain intra-cluster http for data unless mutual TLS is enabled
+		UseIntraTLS:     true,
 	})
+	// intra-cluster mutual TLS is used only for the intra-control and intra-data
+	// networks; the public network retains its own (UseHTTPS) settings
+	h.pubclient, h.pubclientGetPut = h.httpclient, h.httpclientGetPut
+	if config.Net.HTTP.UseIntraTLS {
+		h.pubclient = cmn.NewClient(cmn.TransportArgs{
+			Timeout:  config.Timeout.Default,
+			UseHTTPS: config.Net.HTTP.UseHTTPS,
+		})
+		h.pubclientGetPut = cmn.NewClient(cmn.TransportArgs{
+			Timeout:         config.Timeout.DefaultLong,
+			WriteBufferSize: config.Net.HTTP.WriteBufferSize,
+			ReadBufferSize:  config.Net.HTTP.ReadBufferSize,
+			UseHTTPS:        config.Net.HTTP.UseHTTPS,
+		})
+	}
 
 	bufsize := config.Net.L4.SndRcvBufSize
 	if h.si.IsProxy() {
@@ -376,6 +411,14 @@ func (h *httprunner) init(s stats.Tracker, config *cmn.Config) {
 			sndRcvBufSize: bufsize,
 		}
 	}
+	if config.Net.HTTP.UseIntraTLS { // NOTE: requires separate intra networks (see jsp.LoadConfig)
+		tlsConf, err := cmn.NewIntraTLSConfig(&config.Net.HTTP)
+		if err != nil {
+			glog.Fatalf("FATAL: %v", err)
+		}
+		h.intraControlServer.tlsConf = tlsConf
+		h.intraDataServer.tlsConf = tlsConf
+	}
 
 	h.owner.smap = newSmapOwner()
 }
@@ -471,7 +514,8 @@ func (h *httprunner) initSI(daemonType string) {
 		}
 	}
 
-	h.si = newSnode(daemonID, config.Net.HTTP.Proto, daemonType, publicAddr, intraControlAddr, intraDataAddr)
+	h.si = newSnode(daemonID, config.Net.HTTP.Proto, config.Net.HTTP.IntraProto, daemonType,
+		publicAddr, intraControlAddr, intraDataAddr)
 }
 
 func (h *httprunner) run() error {
@@ -546,11 +590,35 @@ func (h *httprunner) stop(err error) {
 	wg.Wait()
 }
 
+// isIntraURL returns true if the URL is the intra-control or intra-data URL of
+// the node (or, when the node is not specified, of any node in the cluster map)
+func (h *httprunner) isIntraURL(base string, si *cluster.Snode) bool {
+	isIntra := func(si *cluster.Snode) bool {
+		return base != si.PublicNet.DirectURL &&
+			(base == si.IntraControlNet.DirectURL || base == si.IntraDataNet.DirectURL)
+	}
+	if si != nil {
+		return isIntra(si)
+	}
+	smap := h.owner.smap.get()
+	if smap == nil {
+		return false
+	}
+	for _, nodes := range []cluster.NodeMap{smap.Tmap, smap.Pmap} {
+		for _, si := range nodes {
+			if isIntra(si) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 //
 // intra-cluster IPC, control plane
 // call another target or a proxy; optionally, include a json-encoded body
 //
-func (h *httprunner) call(args callArgs) callResult {
+func (h *httprunner) call(args callArgs) (res callResult) {
 	var (
 		req     *http.Request
 		sid     = unknownDaemonID
@@ -559,11 +627,17 @@ func (h *httprunner) call(args callArgs) callResult {
 		details string
 		status  int
 		client  *http.Client
+		span    *tracing.Span
 	)
 
 	if args.si != nil {
 		sid = args.si.ID()
 	}
+	if args.ctx != nil {
+		args.ctx, span = tracing.Start(args.ctx, args.req.Method+" "+args.req.Path, tracing.KindClient)
+		span.SetAttr("node", sid)
+		defer func() { span.End(res.err) }()
+	}
 
 	cmn.Assert(args.si != nil || args.req.Base != "") // either we have si or base
 	if args.req.Base == "" && args.si != nil {
@@ -609,9 +683,22 @@ func (h *httprunner) call(args callArgs) callResult {
 			args.req.Method, args.req.URL(), err)
 		return callResult{args.si, outjson, nil, err, details, status}
 	}
+	if !h.isIntraURL(args.req.Base, args.si) {
+		if client == h.httpclientGetPut {
+			client = h.pubclientGetPut
+		} else {
+			client = h.pubclient
+		}
+	}
 
 	req.Header.Set(cmn.HeaderCallerID, h.si.ID())
 	req.Header.Set(cmn.HeaderCallerName, h.si.Name())
+	if args.req.BodyR != nil {
+		signCall(req, h.si.ID(), intraUnsignedBody)
+	} else {
+		signCall(req, h.si.ID(), bodyDigest(args.req.Body))
+	}
+	tracing.Inject(args.ctx, req.Header)
 	if smap := h.owner.smap.get(); smap.isValid() {
 		req.Header.Set(cmn.HeaderCallerSmapVersion, strconv.FormatInt(smap.version(), 10))
 	}
@@ -774,6 +861,7 @@ func (h *httprunner) bcast(bargs bcastArgs) chan callResult {
 					si:      di,
 					req:     bargs.req,
 					timeout: bargs.timeout,
+					ctx:     bargs.ctx,
 				}
 				cargs.req.Base = di.URL(bargs.network)
 
@@ -893,7 +981,9 @@ func (h *httprunner) httpdaeget(w http.ResponseWriter, r *http.Request) {
 	)
 	switch getWhat {
 	case cmn.GetWhatConfig:
-		body = cmn.MustMarshal(cmn.GCO.Get())
+		config := *cmn.GCO.Get()
+		config.Auth.Secret = "" // never leaves the node
+		body = cmn.MustMarshal(&config)
 	case cmn.GetWhatSmap:
 		body = cmn.MustMarshal(h.owner.smap.get())
 	case cmn.GetWhatBMD:
@@ -904,6 +994,22 @@ func (h *httprunner) httpdaeget(w http.ResponseWriter, r *http.Request) {
 		body = cmn.MustMarshal(msg)
 	case cmn.GetWhatSnode:
 		body = cmn.MustMarshal(h.si)
+	case cmn.GetWhatAudit:
+		query, err := audit.QueryFromValues(r.URL.Query())
+		if err != nil {
+			h.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		recs, err := audit.Find(query)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if err == audit.ErrDisabled {
+				status = http.StatusNotFound
+			}
+			h.invalmsghdlr(w, r, err.Error(), status)
+			return
+		}
+		body = cmn.MustMarshal(recs)
 	default:
 		s := fmt.Sprintf("Invalid GET /daemon request: unrecognized what=%s", getWhat)
 		h.invalmsghdlr(w, r, s)
@@ -912,6 +1018,41 @@ func (h *httprunner) httpdaeget(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, r, body, "httpdaeget-"+getWhat)
 }
 
+// peekActionMsg returns the request's ActionMsg, if any, while leaving the request's
+// body intact for the handler to read; fails if the body is not a (reasonably sized)
+// ActionMsg - in particular, when the body is larger than 64KiB
+func peekActionMsg(r *http.Request) (*cmn.ActionMsg, error) {
+	const maxSize = 64 * cmn.KiB
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return nil, nil
+	}
+	if r.ContentLength > maxSize {
+		return nil, fmt.Errorf("request body is too large (%d)", r.ContentLength)
+	}
+	// NOTE: ContentLength is unknown (-1) when chunked
+	b, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSize+1))
+	r.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(b), r.Body), Closer: r.Body}
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxSize {
+		return nil, errors.New("request body is too large")
+	}
+	if len(b) == 0 {
+		return nil, nil
+	}
+	msg := &cmn.ActionMsg{}
+	if err := jsoniter.Unmarshal(b, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
+
 ////////////////////////////////////////////
 // HTTP err + spec message + code + stats //
 ////////////////////////////////////////////
@@ -1048,7 +1189,7 @@ func (h *httprunner) extractRevokedTokenList(payload msPayload) (*TokenList, err
 	if msgInt.Action != "" {
 		s = ", action " + msgInt.Action
 	}
-	glog.Infof("received TokenList ntokens %d%s", len(tokenList.Tokens), s)
+	glog.Infof("received TokenList ntokens %d, nids %d%s", len(tokenList.Tokens), len(tokenList.IDs), s)
 
 	return tokenList, nil
 }
@@ -0,0 +1,215 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Intra-cluster requests carry the caller's ID (cmn.HeaderCallerID) that
+// exempts them from user authentication and auditing. The header alone can be
+// set by any client, and so a request is trusted to be intra-cluster only if:
+// - it is received via (separate) intra-control or intra-data network, or
+// - it is signed with the secret shared by all nodes (config.Auth.Secret).
+// The signature covers the caller ID, the method, the path and the query, the
+// SHA-256 of the body (except streamed object content), the time of the request,
+// and a random nonce. The signature is valid for intraSigMaxSkew, and each nonce
+// is accepted only once (see intraNonces) so that a captured request cannot be
+// replayed.
+
+const (
+	intraSigMaxSkew   = 5 * time.Minute
+	intraUnsignedBody = "-" // in place of the body's digest: streamed body is not signed
+)
+
+type (
+	// nonces of the signatures verified within the last intraSigMaxSkew
+	nonceCache struct {
+		mtx     sync.Mutex
+		seen    map[string]int64 // nonce => signature expiration (unix nano)
+		cleanup int64            // next time to remove expired nonces
+	}
+	intraCallKey struct{}
+)
+
+var (
+	errNoIntraSig = errors.New("not signed")
+	intraNonces   = &nonceCache{seen: make(map[string]int64)}
+)
+
+// bodyDigest returns the digest of the request's body to be signed (see signCall)
+func bodyDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// signCall signs the request to another node (see call); digest is either the
+// body's digest (see bodyDigest) or intraUnsignedBody
+func signCall(req *http.Request, callerID, digest string) {
+	key := intraSigKey()
+	if key == nil {
+		return
+	}
+	var (
+		b  = make([]byte, 8)
+		ts = strconv.FormatInt(time.Now().UnixNano(), 10)
+	)
+	if _, err := rand.Read(b); err != nil {
+		glog.Errorf("Failed to generate nonce: %v", err)
+		return
+	}
+	nonce := hex.EncodeToString(b)
+	sig := intraSig(key, req.Method, req.URL.Path, req.URL.RawQuery, callerID, ts, nonce, digest)
+	req.Header.Set(cmn.HeaderCallerSig, strings.Join([]string{ts, nonce, digest, sig}, ":"))
+}
+
+// intraCallHandler verifies the signature of an intra-cluster request (if any)
+// before the wrapped handler - once, so that the latter may call isIntraCall
+// any number of times
+func intraCallHandler(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		callerID := r.Header.Get(cmn.HeaderCallerID)
+		if callerID != "" && r.Header.Get(cmn.HeaderCallerSig) != "" {
+			if err := verifyIntraSig(r, callerID); err == nil {
+				r = r.WithContext(context.WithValue(r.Context(), intraCallKey{}, callerID))
+			} else {
+				glog.Warningf("%s %s from %s (caller %s): %v", r.Method, r.URL.Path, r.RemoteAddr, callerID, err)
+			}
+		}
+		h(w, r)
+	}
+}
+
+// isIntraCall returns true if the request comes from another node of the cluster
+func isIntraCall(r *http.Request) bool {
+	callerID := r.Header.Get(cmn.HeaderCallerID)
+	if callerID == "" {
+		return false
+	}
+	if viaIntraNet(r) {
+		return true
+	}
+	// verified by intraCallHandler
+	verified, _ := r.Context().Value(intraCallKey{}).(string)
+	return verified == callerID
+}
+
+// viaIntraNet returns true if the request is received by the intra-control or
+// intra-data server that listens on its own port (see httprunner.init)
+func viaIntraNet(r *http.Request) bool {
+	addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	if !ok {
+		return false
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	config := cmn.GCO.Get()
+	if config.Net.UseIntraControl && tcpAddr.Port == config.Net.L4.PortIntraControl &&
+		config.Net.L4.PortIntraControl != config.Net.L4.Port {
+		return true
+	}
+	return config.Net.UseIntraData && tcpAddr.Port == config.Net.L4.PortIntraData &&
+		config.Net.L4.PortIntraData != config.Net.L4.Port
+}
+
+func verifyIntraSig(r *http.Request, callerID string) error {
+	key := intraSigKey()
+	if key == nil {
+		return errNoIntraSig
+	}
+	parts := strings.SplitN(r.Header.Get(cmn.HeaderCallerSig), ":", 4)
+	if len(parts) != 4 {
+		return errNoIntraSig
+	}
+	ts, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid signature timestamp %q", parts[0])
+	}
+	now := time.Now()
+	if skew := now.Sub(time.Unix(0, ts)); skew > intraSigMaxSkew || skew < -intraSigMaxSkew {
+		return fmt.Errorf("signature expired (%v)", skew)
+	}
+	nonce, digest := parts[1], parts[2]
+	expected := intraSig(key, r.Method, r.URL.Path, r.URL.RawQuery, callerID, parts[0], nonce, digest)
+	if !hmac.Equal([]byte(expected), []byte(parts[3])) {
+		return errors.New("invalid signature")
+	}
+	// the body is read only after the signature is verified
+	if digest != intraUnsignedBody {
+		var body []byte
+		if r.Body != nil {
+			if body, err = ioutil.ReadAll(r.Body); err != nil {
+				return err
+			}
+			r.Body.Close()
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		if bodyDigest(body) != digest {
+			return errors.New("body does not match signature")
+		}
+	}
+	if !intraNonces.add(nonce, ts+int64(intraSigMaxSkew), now.UnixNano()) {
+		return errors.New("replayed signature")
+	}
+	return nil
+}
+
+// the key is derived from (rather than being) the secret that also signs user tokens
+func intraSigKey() []byte {
+	secret := cmn.GCO.Get().Auth.Secret
+	if secret == "" {
+		return nil
+	}
+	key := sha256.Sum256([]byte("intra-cluster:" + secret))
+	return key[:]
+}
+
+func intraSig(key []byte, method, path, query, callerID, ts, nonce, digest string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(method + " " + path + "?" + query + "\n" + callerID + "\n" + ts + "\n" + nonce + "\n" + digest))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+////////////////
+// nonceCache //
+////////////////
+
+// add returns false if the nonce has been seen already
+func (c *nonceCache) add(nonce string, expires, now int64) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if now >= c.cleanup {
+		for n, exp := range c.seen {
+			if exp < now {
+				delete(c.seen, n)
+			}
+		}
+		c.cleanup = now + int64(intraSigMaxSkew/10)
+	}
+	if _, ok := c.seen[nonce]; ok {
+		return false
+	}
+	c.seen[nonce] = expires
+	return true
+}
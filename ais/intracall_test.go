@@ -0,0 +1,135 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func setAuthSecret(secret string) {
+	config := cmn.GCO.BeginUpdate()
+	config.Auth.Secret = secret
+	cmn.GCO.CommitUpdate(config)
+}
+
+// trusted passes the request through intraCallHandler and returns isIntraCall
+// (twice: the handler may check it more than once)
+func trusted(req *http.Request) (intra bool) {
+	intraCallHandler(func(w http.ResponseWriter, r *http.Request) {
+		intra = isIntraCall(r) && isIntraCall(r)
+	})(httptest.NewRecorder(), req)
+	return
+}
+
+func signedReq(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(cmn.HeaderCallerID, "p1")
+	signCall(req, "p1", bodyDigest([]byte(body)))
+	return req
+}
+
+func TestIntraCallSignature(t *testing.T) {
+	const (
+		path = "/v1/cluster/daemon/t1"
+		body = `{"action":"setconfig"}`
+	)
+	setAuthSecret("intra-call-secret")
+	defer setAuthSecret("")
+
+	req := httptest.NewRequest(http.MethodPut, path, nil)
+	tassert.Errorf(t, !trusted(req), "request without caller ID")
+
+	req.Header.Set(cmn.HeaderCallerID, "p1")
+	tassert.Errorf(t, !trusted(req), "unsigned request with (spoofed) caller ID")
+
+	req = signedReq(http.MethodPut, path+"?what=config", body)
+	tassert.Errorf(t, trusted(req), "signed request must be trusted")
+	b, err := ioutil.ReadAll(req.Body)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, string(b) == body, "body must remain intact, got %q", b)
+
+	// replayed
+	req = signedReq(http.MethodPut, path, body)
+	replayed := req.Clone(req.Context())
+	replayed.Body = ioutil.NopCloser(strings.NewReader(body))
+	tassert.Errorf(t, trusted(req), "signed request must be trusted")
+	tassert.Errorf(t, !trusted(replayed), "replayed request")
+
+	// signature does not transfer to another caller, method, path, query, or body
+	req = signedReq(http.MethodPut, path, body)
+	req.Header.Set(cmn.HeaderCallerID, "p2")
+	tassert.Errorf(t, !trusted(req), "different caller ID")
+	req = signedReq(http.MethodPut, path, body)
+	req.Method = http.MethodDelete
+	tassert.Errorf(t, !trusted(req), "different method")
+	req = signedReq(http.MethodPut, path, body)
+	req.URL.Path = "/v1/cluster/daemon/t2"
+	tassert.Errorf(t, !trusted(req), "different path")
+	req = signedReq(http.MethodGet, path+"?what=smap", "")
+	req.URL.RawQuery = "what=config"
+	tassert.Errorf(t, !trusted(req), "different query")
+	req = signedReq(http.MethodPut, path, body)
+	req.Body = ioutil.NopCloser(strings.NewReader(`{"action":"shutdown"}`))
+	tassert.Errorf(t, !trusted(req), "different body")
+
+	// streamed body is not signed
+	req = httptest.NewRequest(http.MethodPut, "/v1/objects/bck/obj", strings.NewReader("content"))
+	req.Header.Set(cmn.HeaderCallerID, "t1")
+	signCall(req, "t1", intraUnsignedBody)
+	tassert.Errorf(t, trusted(req), "signed request with streamed body must be trusted")
+
+	// expired
+	req = httptest.NewRequest(http.MethodPut, path, nil)
+	req.Header.Set(cmn.HeaderCallerID, "p1")
+	ts := strconv.FormatInt(time.Now().Add(-2*intraSigMaxSkew).UnixNano(), 10)
+	digest := bodyDigest(nil)
+	sig := intraSig(intraSigKey(), req.Method, req.URL.Path, "", "p1", ts, "nonce", digest)
+	req.Header.Set(cmn.HeaderCallerSig, strings.Join([]string{ts, "nonce", digest, sig}, ":"))
+	tassert.Errorf(t, !trusted(req), "expired signature")
+
+	// different secret
+	req = signedReq(http.MethodPut, path, "")
+	setAuthSecret("another-secret")
+	tassert.Errorf(t, !trusted(req), "signed with a different secret")
+}
+
+func TestPeekActionMsg(t *testing.T) {
+	body := string(cmn.MustMarshal(&cmn.ActionMsg{Action: cmn.ActRename, Name: "new"}))
+
+	// chunked: unknown content length
+	req := httptest.NewRequest(http.MethodPost, "/v1/objects/bck/obj", ioutil.NopCloser(strings.NewReader(body)))
+	req.ContentLength = -1
+	msg, err := peekActionMsg(req)
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, msg != nil && msg.Action == cmn.ActRename, "expected %q, got %+v", cmn.ActRename, msg)
+	b, err := ioutil.ReadAll(req.Body)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, string(b) == body, "body must remain intact, got %q", b)
+
+	// too large: fail while keeping the body intact
+	large := bytes.Repeat([]byte{' '}, 128*cmn.KiB)
+	req = httptest.NewRequest(http.MethodPost, "/v1/objects/bck/obj", ioutil.NopCloser(bytes.NewReader(large)))
+	req.ContentLength = -1
+	_, err = peekActionMsg(req)
+	tassert.Errorf(t, err != nil, "expected error for oversized body")
+	b, err = ioutil.ReadAll(req.Body)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(b) == len(large), "body must remain intact, got %d bytes", len(b))
+
+	// no body
+	req = httptest.NewRequest(http.MethodPost, "/v1/objects/bck/obj", nil)
+	msg, err = peekActionMsg(req)
+	tassert.Errorf(t, msg == nil && err == nil, "expected no message, got %+v, %v", msg, err)
+}
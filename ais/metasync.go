@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +22,7 @@ const (
 	revsSmapTag   = "smap"
 	revsBMDTag    = "bmd"
 	revsTokenTag  = "token"
+	revsAuthNTag  = "authn"
 	revsActionTag = "-action" // to make a pair (revs, action)
 )
 const (
@@ -65,6 +67,9 @@ const (
 //    pending synchronizations (for as long as those members remain in the
 //    most recent and current cluster map).
 //
+// Some REVS (see proxiesOnly) are replicated to proxies only, and the targets
+// get the rest of the payload.
+//
 // Last but not the least, metasyncer checks that only the currently elected
 // leader (aka "primary proxy") distributes the REVS objects, thus providing for
 // simple serialization of the versioned updates.
@@ -109,6 +114,12 @@ type (
 
 	msPayload map[string][]byte // tag => revs' body
 
+	// result of sending (a part of) the payload to a node
+	msResult struct {
+		callResult
+		pairs []revsPair // pairs sent to the node
+	}
+
 	metasyncer struct {
 		cmn.Named
 		p            *proxyrunner        // parent
@@ -338,26 +349,22 @@ outer:
 	}
 
 	// step 3: b-cast
-	var (
-		urlPath = cmn.URLPath(cmn.Version, cmn.Metasync)
-		body    = cmn.MustMarshal(payload)
-	)
-	res := y.p.bcastTo(bcastArgs{
+	urlPath := cmn.URLPath(cmn.Version, cmn.Metasync)
+	res := y.bcast(bcastArgs{
 		req: cmn.ReqArgs{
 			Method: method,
 			Path:   urlPath,
-			Body:   body,
 		},
-		smap:    smap,
+		network: cmn.NetworkIntraControl,
 		timeout: config.Timeout.CplaneOperation * 2, // making exception for this critical op
-		to:      cluster.AllNodes,
-	})
+		nodes:   []cluster.NodeMap{smap.Pmap, smap.Tmap},
+	}, payload, pairsToSend)
 
 	// step 4: count failures and fill-in refused
-	for r := range res {
+	for _, r := range res {
 		if r.err == nil {
 			if revsReqType == revsReqSync {
-				y.syncDone(r.si.ID(), pairsToSend)
+				y.syncDone(r.si.ID(), r.pairs)
 			}
 			continue
 		}
@@ -384,7 +391,7 @@ outer:
 			return
 		}
 
-		y.handleRefused(method, urlPath, body, refused, pairsToSend, config)
+		y.handleRefused(method, urlPath, payload, refused, pairsToSend, config)
 	}
 	// step 6: housekeep and return new pending
 	smap = y.p.owner.smap.get()
@@ -410,22 +417,21 @@ func (y *metasyncer) syncDone(sid string, pairs []revsPair) {
 	}
 }
 
-func (y *metasyncer) handleRefused(method, urlPath string, body []byte, refused cluster.NodeMap, pairs []revsPair, config *cmn.Config) {
-	res := y.p.bcast(bcastArgs{
+func (y *metasyncer) handleRefused(method, urlPath string, payload msPayload, refused cluster.NodeMap, pairs []revsPair, config *cmn.Config) {
+	res := y.bcast(bcastArgs{
 		req: cmn.ReqArgs{
 			Method: method,
 			Path:   urlPath,
-			Body:   body,
 		},
 		network: cmn.NetworkIntraControl,
 		timeout: config.Timeout.MaxKeepalive, // JSON config "max_keepalive"
 		nodes:   []cluster.NodeMap{refused},
-	})
+	}, payload, pairs)
 
-	for r := range res {
+	for _, r := range res {
 		if r.err == nil {
 			delete(refused, r.si.ID())
-			y.syncDone(r.si.ID(), pairs)
+			y.syncDone(r.si.ID(), r.pairs)
 			glog.Infof("handle-refused: sync-ed %s", r.si)
 		} else {
 			glog.Warningf("handle-refused: failing to sync %s, err: %v (%d)", r.si, r.err, r.status)
@@ -454,6 +460,9 @@ func (y *metasyncer) pending(needMap bool) (count int, pending cluster.NodeMap,
 			} else {
 				inSync := true
 				for tag, revs := range y.lastSynced {
+					if proxiesOnly(tag) && si.IsTarget() {
+						continue
+					}
 					v, ok := rvd.versions[tag]
 					if !ok || v != revs.version() {
 						cmn.Assert(!ok || v < revs.version())
@@ -493,20 +502,18 @@ func (y *metasyncer) handlePending() (cnt int) {
 		pairs = append(pairs, revsPair{revs, msgInt})
 	}
 
-	body := cmn.MustMarshal(payload)
-	res := y.p.bcast(bcastArgs{
+	res := y.bcast(bcastArgs{
 		req: cmn.ReqArgs{
 			Method: http.MethodPut,
 			Path:   cmn.URLPath(cmn.Version, cmn.Metasync),
-			Body:   body,
 		},
 		network: cmn.NetworkIntraControl,
 		timeout: cmn.GCO.Get().Timeout.CplaneOperation,
 		nodes:   []cluster.NodeMap{pending},
-	})
-	for r := range res {
+	}, payload, pairs)
+	for _, r := range res {
 		if r.err == nil {
-			y.syncDone(r.si.ID(), pairs)
+			y.syncDone(r.si.ID(), r.pairs)
 			glog.Infof("handle-pending: sync-ed %s", r.si)
 		} else {
 			cnt++
@@ -516,6 +523,59 @@ func (y *metasyncer) handlePending() (cnt int) {
 	return
 }
 
+// broadcasts the payload to the given nodes (args.nodes), except that targets
+// get the payload without proxies-only revs; returns the results along with
+// the pairs sent to each node
+func (y *metasyncer) bcast(args bcastArgs, payload msPayload, pairs []revsPair) (res []msResult) {
+	var (
+		tpayload = make(msPayload, len(payload))
+		tpairs   = make([]revsPair, 0, len(pairs))
+	)
+	for tag, body := range payload {
+		if !proxiesOnly(strings.TrimSuffix(tag, revsActionTag)) {
+			tpayload[tag] = body
+		}
+	}
+	for _, pair := range pairs {
+		if !proxiesOnly(pair.revs.tag()) {
+			tpairs = append(tpairs, pair)
+		}
+	}
+	if len(tpayload) == len(payload) {
+		args.req.Body = cmn.MustMarshal(payload)
+		return collect(res, y.p.bcast(args), pairs)
+	}
+	proxies, targets := make(cluster.NodeMap), make(cluster.NodeMap)
+	for _, nodes := range args.nodes {
+		for id, si := range nodes {
+			if si.IsProxy() {
+				proxies[id] = si
+			} else {
+				targets[id] = si
+			}
+		}
+	}
+	pargs, targs := args, args
+	pargs.req.Body, pargs.nodes = cmn.MustMarshal(payload), []cluster.NodeMap{proxies}
+	res = collect(res, y.p.bcast(pargs), pairs)
+	if len(tpairs) == 0 || len(targets) == 0 {
+		return
+	}
+	targs.req.Body, targs.nodes = cmn.MustMarshal(tpayload), []cluster.NodeMap{targets}
+	return collect(res, y.p.bcast(targs), tpairs)
+}
+
+func collect(res []msResult, ch chan callResult, pairs []revsPair) []msResult {
+	for r := range ch {
+		res = append(res, msResult{r, pairs})
+	}
+	return res
+}
+
+// revs that are replicated to proxies only, e.g. AuthN database that
+// contains user accounts and is of no use for targets
+func proxiesOnly(tag string) bool { return tag == revsAuthNTag }
+
 func (y *metasyncer) checkPrimary() bool {
 	smap := y.p.owner.smap.get()
 	cmn.Assert(smap != nil)
@@ -85,7 +85,7 @@ func newPrimary() *proxyrunner {
 	)
 
 	p.owner.smap = newSmapOwner()
-	p.si = newSnode("primary", httpProto, cmn.Proxy, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{})
+	p.si = newSnode("primary", httpProto, httpProto, cmn.Proxy, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{})
 
 	smap.addProxy(p.si)
 	smap.ProxySI = p.si
@@ -98,6 +98,7 @@ func newPrimary() *proxyrunner {
 	cmn.GCO.CommitUpdate(config)
 
 	p.httpclientGetPut = &http.Client{}
+	p.pubclientGetPut = p.httpclientGetPut
 	p.keepalive = newProxyKeepaliveRunner(p, tracker, &p.startedUp)
 
 	o := newBMDOwnerPrx(config)
@@ -108,7 +109,7 @@ func newPrimary() *proxyrunner {
 
 func newSecondary(name string) *proxyrunner {
 	p := &proxyrunner{}
-	p.si = newSnode(name, httpProto, cmn.Proxy, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{})
+	p.si = newSnode(name, httpProto, httpProto, cmn.Proxy, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{})
 	p.owner.smap = newSmapOwner()
 	p.owner.smap.put(newSmap())
 	o := newBMDOwnerPrx(cmn.GCO.Get())
@@ -145,9 +146,9 @@ func newTransportServer(primary *proxyrunner, s *metaSyncServer, ch chan<- trans
 	addrInfo := serverTCPAddr(ts.URL)
 	clone := primary.owner.smap.get().clone()
 	if s.isProxy {
-		clone.Pmap[id] = newSnode(id, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+		clone.Pmap[id] = newSnode(id, httpProto, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 	} else {
-		clone.Tmap[id] = newSnode(id, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+		clone.Tmap[id] = newSnode(id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 	}
 	clone.Version++
 	primary.owner.smap.put(clone)
@@ -437,7 +438,7 @@ func refused(t *testing.T, primary *proxyrunner, syncer *metasyncer) ([]transpor
 	})
 
 	clone := primary.owner.smap.get().clone()
-	clone.Pmap[id] = newSnode(id, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+	clone.Pmap[id] = newSnode(id, httpProto, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 	clone.Version++
 	primary.owner.smap.put(clone)
 
@@ -515,9 +516,9 @@ func TestMetaSyncData(t *testing.T) {
 		addrInfo := serverTCPAddr(ts.URL)
 		clone := primary.owner.smap.get().clone()
 		if s.isProxy {
-			clone.Pmap[id] = newSnode(id, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+			clone.Pmap[id] = newSnode(id, httpProto, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 		} else {
-			clone.Tmap[id] = newSnode(id, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+			clone.Tmap[id] = newSnode(id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 		}
 		clone.Version++
 		primary.owner.smap.put(clone)
@@ -648,7 +649,7 @@ func TestMetaSyncMembership(t *testing.T) {
 		id := "t"
 		addrInfo := serverTCPAddr(s.URL)
 		clone := primary.owner.smap.get().clone()
-		clone.addTarget(newSnode(id, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
+		clone.addTarget(newSnode(id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
 		primary.owner.smap.put(clone)
 		msgInt := primary.newActionMsgInternalStr("", clone, nil)
 		syncer.sync(true, revsPair{clone, msgInt})
@@ -691,7 +692,7 @@ func TestMetaSyncMembership(t *testing.T) {
 
 		id := "t1111"
 		addrInfo := serverTCPAddr(s1.URL)
-		di := newSnode(id, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+		di := newSnode(id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 		clone := primary.owner.smap.get().clone()
 		clone.addTarget(di)
 		primary.owner.smap.put(clone)
@@ -715,7 +716,7 @@ func TestMetaSyncMembership(t *testing.T) {
 
 		id := "t22222"
 		addrInfo := serverTCPAddr(s2.URL)
-		di := newSnode(id, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+		di := newSnode(id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 		clone := primary.owner.smap.get().clone()
 		clone.addTarget(di)
 		primary.owner.smap.put(clone)
@@ -779,7 +780,7 @@ func TestMetaSyncReceive(t *testing.T) {
 		defer s.Close()
 		addrInfo := serverTCPAddr(s.URL)
 		clone := primary.owner.smap.get().clone()
-		clone.addProxy(newSnode("p1", httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
+		clone.addProxy(newSnode("p1", httpProto, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
 		primary.owner.smap.put(clone)
 
 		proxy1 := newSecondary("p1")
@@ -807,6 +808,80 @@ func TestMetaSyncReceive(t *testing.T) {
 	}
 }
 
+// TestMetaSyncProxiesOnly checks that targets do not get AuthN database
+func TestMetaSyncProxiesOnly(t *testing.T) {
+	type data struct {
+		isProxy bool
+		payload msPayload
+	}
+	var (
+		primary = newPrimary()
+		syncer  = testSyncer(primary)
+		ch      = make(chan data, 8)
+		wg      = &sync.WaitGroup{}
+	)
+	newServer := func(id string, isProxy bool) *httptest.Server {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := make(msPayload)
+			if err := cmn.ReadJSON(w, r, &d); err == nil {
+				ch <- data{isProxy, d}
+			}
+		}))
+		addrInfo := serverTCPAddr(ts.URL)
+		clone := primary.owner.smap.get().clone()
+		if isProxy {
+			clone.Pmap[id] = newSnode(id, httpProto, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+		} else {
+			clone.Tmap[id] = newSnode(id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+		}
+		clone.Version++
+		primary.owner.smap.put(clone)
+		return ts
+	}
+	proxy := newServer("proxy", true)
+	defer proxy.Close()
+	target := newServer("target", false)
+	defer target.Close()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		syncer.Run()
+	}()
+	defer func() {
+		syncer.Stop(nil)
+		wg.Wait()
+	}()
+
+	db := &AuthNDB{Version: 1, Data: []byte(`{}`)}
+	syncer.sync(true, revsPair{newBucketMD(), &actionMsgInternal{}}, revsPair{db, &actionMsgInternal{}})
+	for i := 0; i < 2; i++ {
+		d := <-ch
+		_, hasBMD := d.payload[revsBMDTag]
+		_, hasDB := d.payload[revsAuthNTag]
+		_, hasDBAction := d.payload[revsAuthNTag+revsActionTag]
+		tassert.Errorf(t, hasBMD, "expected BMD")
+		tassert.Errorf(t, hasDB == d.isProxy && hasDBAction == d.isProxy,
+			"AuthN DB must be sent to proxies only (proxy: %t)", d.isProxy)
+	}
+
+	// nothing to send to the target
+	db = &AuthNDB{Version: 2, Data: []byte(`{}`)}
+	syncer.sync(true, revsPair{db, &actionMsgInternal{}})
+	d := <-ch
+	tassert.Errorf(t, d.isProxy, "AuthN DB must not be sent to targets")
+	select {
+	case d = <-ch:
+		t.Errorf("unexpected sync request (proxy: %t)", d.isProxy)
+	default:
+	}
+	_, pending, _ := syncer.pending(true)
+	for _, id := range []string{"proxy", "target"} {
+		_, ok := pending[id]
+		tassert.Errorf(t, !ok, "expected %s to be in sync", id)
+	}
+}
+
 func testSyncer(p *proxyrunner) (syncer *metasyncer) {
 	syncer = newMetasyncer(p)
 	return
@@ -36,8 +36,9 @@ func newDiscoverServerPrimary() *proxyrunner {
 		p       = &proxyrunner{}
 		tracker = stats.NewTrackerMock()
 	)
-	p.si = newSnode("primary", httpProto, cmn.Proxy, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{})
+	p.si = newSnode("primary", httpProto, httpProto, cmn.Proxy, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{})
 	p.httpclientGetPut = &http.Client{}
+	p.pubclientGetPut = p.httpclientGetPut
 
 	config := cmn.GCO.BeginUpdate()
 	config.KeepaliveTracker.Proxy.Name = "heartbeat"
@@ -264,9 +265,9 @@ func TestDiscoverServers(t *testing.T) {
 			ts := s.httpHandler(s.smapVersion, s.bmdVersion)
 			addrInfo := serverTCPAddr(ts.URL)
 			if s.isProxy {
-				discoverSmap.addProxy(newSnode(s.id, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
+				discoverSmap.addProxy(newSnode(s.id, httpProto, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
 			} else {
-				discoverSmap.addTarget(newSnode(s.id, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
+				discoverSmap.addTarget(newSnode(s.id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
 			}
 		}
 		smap, bucketmd := primary.uncoverMeta(discoverSmap)
@@ -0,0 +1,290 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	jwt "github.com/dgrijalva/jwt-go"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// OpenID Connect: validating ID tokens issued by an external identity provider
+// (see cmn.OIDCConf). The provider's signing keys (JWKS) are cached and get
+// refetched periodically and whenever a token is signed with an unknown key,
+// which takes care of the provider's key rotation.
+// Keys are always fetched asynchronously: token validation never waits for
+// the provider, and a token signed with a key that is not (yet) in the cache
+// gets rejected.
+
+const (
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+	oidcMinRefetch    = 10 * time.Second // refetch JWKS (upon unknown key ID) at most once per
+)
+
+type (
+	oidcKeys struct {
+		mtx        sync.Mutex
+		issuer     string
+		jwksURL    string                 // discovered or configured
+		keys       map[string]interface{} // key ID => *rsa.PublicKey or *ecdsa.PublicKey
+		fetched    time.Time
+		attempted  time.Time
+		refreshing bool // fetching in progress
+		client     *http.Client
+	}
+	oidcDiscovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURL string `json:"jwks_uri"`
+	}
+	jwkSet struct {
+		Keys []jwk `json:"keys"`
+	}
+	jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+)
+
+var oidcKeyCache = &oidcKeys{
+	client: cmn.NewClient(cmn.TransportArgs{Timeout: 30 * time.Second}),
+}
+
+// returns the key to verify a given (RSA or ECDSA signed) ID token
+func (o *oidcKeys) key(token *jwt.Token, conf *cmn.OIDCConf) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	o.configure(conf)
+	key, ok := o.keys[kid]
+	if !ok || time.Since(o.fetched) > conf.JWKSRefresh {
+		o.refresh()
+	}
+	if !ok {
+		return nil, fmt.Errorf("oidc: unknown key ID %q", kid)
+	}
+	return key, nil // NOTE: keep using the cached key while the issuer is unreachable
+}
+
+// fetches the keys in advance, so that the very first token does not get rejected
+func (o *oidcKeys) prefetch(conf *cmn.OIDCConf) {
+	o.mtx.Lock()
+	o.configure(conf)
+	o.refresh()
+	o.mtx.Unlock()
+}
+
+// (must be called under lock)
+func (o *oidcKeys) configure(conf *cmn.OIDCConf) {
+	if o.issuer == conf.Issuer && (conf.JWKSURL == "" || o.jwksURL == conf.JWKSURL) {
+		return
+	}
+	o.issuer, o.jwksURL, o.keys = conf.Issuer, conf.JWKSURL, nil
+	o.fetched, o.attempted = time.Time{}, time.Time{}
+}
+
+// starts fetching the keys unless already in progress or attempted recently
+// (must be called under lock)
+func (o *oidcKeys) refresh() {
+	if o.refreshing || time.Since(o.attempted) < oidcMinRefetch {
+		return
+	}
+	o.refreshing, o.attempted = true, time.Now()
+	go o.fetch(o.issuer, o.jwksURL)
+}
+
+// fetches JWKS (discovering its URL, if need be) and replaces the cached keys
+func (o *oidcKeys) fetch(issuer, jwksURL string) {
+	keys, jwksURL, err := o.fetchKeys(issuer, jwksURL)
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.refreshing = false
+	if o.issuer != issuer {
+		return // reconfigured in the meantime
+	}
+	if err != nil {
+		glog.Errorf("oidc: failed to fetch %s keys: %v", issuer, err)
+		return
+	}
+	o.keys, o.jwksURL, o.fetched = keys, jwksURL, time.Now()
+}
+
+func (o *oidcKeys) fetchKeys(issuer, jwksURL string) (map[string]interface{}, string, error) {
+	if jwksURL == "" {
+		disc := &oidcDiscovery{}
+		if err := o.getJSON(strings.TrimSuffix(issuer, "/")+oidcDiscoveryPath, disc); err != nil {
+			return nil, "", err
+		}
+		if disc.Issuer != issuer {
+			return nil, "", fmt.Errorf("oidc: issuer mismatch: %q vs %q (discovered)", issuer, disc.Issuer)
+		}
+		if disc.JWKSURL == "" {
+			return nil, "", errors.New("oidc: issuer does not provide jwks_uri")
+		}
+		jwksURL = disc.JWKSURL
+	}
+	set := &jwkSet{}
+	if err := o.getJSON(jwksURL, set); err != nil {
+		return nil, "", err
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for i := range set.Keys {
+		k := &set.Keys[i]
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			glog.Warningf("oidc: skipping key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		return nil, "", fmt.Errorf("oidc: no signing keys at %s", jwksURL)
+	}
+	return keys, jwksURL, nil
+}
+
+func (o *oidcKeys) getJSON(url string, v interface{}) error {
+	resp, err := o.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return jsoniter.NewDecoder(resp.Body).Decode(v)
+}
+
+func (k *jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64BigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64BigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := b64BigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64BigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func b64BigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// validates standard claims of an ID token and maps user's groups to AIS
+// roles and permissions
+func oidcAuthRec(claims jwt.MapClaims, conf *cmn.OIDCConf) (*authRec, error) {
+	if iss, _ := claims["iss"].(string); iss != conf.Issuer {
+		return nil, fmt.Errorf("oidc: unexpected issuer %q", iss)
+	}
+	if !oidcAudience(claims["aud"], conf.ClientID) {
+		return nil, fmt.Errorf("oidc: token is not issued for %q", conf.ClientID)
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.New("oidc: token does not expire")
+	}
+	rec := &authRec{expires: time.Unix(int64(exp), 0), creds: make(cmn.SimpleKVs)}
+	if iat, ok := claims["iat"].(float64); ok {
+		rec.issued = time.Unix(int64(iat), 0)
+	}
+	if rec.userID, _ = claims[conf.UserClaim].(string); rec.userID == "" {
+		return nil, fmt.Errorf("oidc: missing %q claim", conf.UserClaim)
+	}
+
+	rec.roles = append(rec.roles, conf.DefaultRoles...)
+	for _, group := range oidcGroups(claims[conf.GroupsClaim]) {
+		rec.roles = append(rec.roles, conf.GroupRoles[group]...)
+		rec.grants = append(rec.grants, conf.GroupGrants[group]...)
+	}
+	for _, name := range rec.roles {
+		if role := cmn.PredefinedRole(name); role != nil {
+			rec.grants = append(rec.grants, role.Grants...)
+		}
+	}
+	return rec, nil
+}
+
+// "aud" is either a string or an array of strings
+func oidcAudience(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// groups claim is either an array of strings or a single string
+func oidcGroups(claim interface{}) (groups []string) {
+	switch v := claim.(type) {
+	case string:
+		groups = []string{v}
+	case []interface{}:
+		for _, g := range v {
+			if s, ok := g.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+	}
+	return
+}
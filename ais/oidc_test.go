@@ -0,0 +1,193 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const oidcClientID = "aistore"
+
+// stub OIDC issuer that serves discovery document and JWKS
+type stubIssuer struct {
+	mtx   sync.Mutex
+	srv   *httptest.Server
+	keys  []jwk
+	delay chan struct{} // if set, serving keys blocks until closed
+}
+
+func newStubIssuer() *stubIssuer {
+	iss := &stubIssuer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(cmn.MustMarshal(oidcDiscovery{Issuer: iss.srv.URL, JWKSURL: iss.srv.URL + "/keys"}))
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		iss.mtx.Lock()
+		delay := iss.delay
+		iss.mtx.Unlock()
+		if delay != nil {
+			<-delay
+		}
+		iss.mtx.Lock()
+		w.Write(cmn.MustMarshal(jwkSet{Keys: iss.keys}))
+		iss.mtx.Unlock()
+	})
+	iss.srv = httptest.NewServer(mux)
+	return iss
+}
+
+func b64(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+
+func (iss *stubIssuer) setKeys(keys ...jwk) {
+	iss.mtx.Lock()
+	iss.keys = keys
+	iss.mtx.Unlock()
+}
+
+func (iss *stubIssuer) token(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	base := jwt.MapClaims{
+		"iss":    iss.srv.URL,
+		"aud":    oidcClientID,
+		"sub":    "alice",
+		"iat":    time.Now().Unix(),
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"groups": []string{"ml-team"},
+	}
+	for k, v := range claims {
+		base[k] = v
+	}
+	token := jwt.NewWithClaims(method, base)
+	token.Header["kid"] = kid
+	s, err := token.SignedString(key)
+	tassert.CheckFatal(t, err)
+	return s
+}
+
+// waits for the keys to get fetched in background
+func waitOIDCKeys(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		oidcKeyCache.mtx.Lock()
+		refreshing := oidcKeyCache.refreshing
+		oidcKeyCache.mtx.Unlock()
+		if !refreshing {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for OIDC keys")
+}
+
+// skips refetch throttling
+func resetOIDCAttempted() {
+	oidcKeyCache.mtx.Lock()
+	oidcKeyCache.attempted = time.Time{}
+	oidcKeyCache.mtx.Unlock()
+}
+
+func TestOIDC(t *testing.T) {
+	iss := newStubIssuer()
+	defer iss.srv.Close()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	tassert.CheckFatal(t, err)
+	rsaJWK := jwk{Kty: "RSA", Kid: "rsa-1", Use: "sig", N: b64(rsaKey.N), E: b64(big.NewInt(int64(rsaKey.E)))}
+	iss.setKeys(rsaJWK)
+
+	config := cmn.GCO.BeginUpdate()
+	config.Auth.OIDC = cmn.OIDCConf{
+		Issuer:       iss.srv.URL,
+		ClientID:     oidcClientID,
+		DefaultRoles: []string{cmn.RoleReadOnly},
+		GroupGrants: map[string][]cmn.AuthGrant{
+			"ml-team": {{Bucket: "train", Perms: []string{cmn.PermWrite}}},
+		},
+		GroupRoles: map[string][]string{"ais-admins": {cmn.RoleClusterAdmin}},
+	}
+	tassert.CheckFatal(t, config.Auth.Validate(config))
+	cmn.GCO.CommitUpdate(config)
+	defer func() {
+		config := cmn.GCO.BeginUpdate()
+		config.Auth.OIDC = cmn.OIDCConf{}
+		cmn.GCO.CommitUpdate(config)
+	}()
+
+	var (
+		train = &cmn.Bck{Name: "train", Provider: cmn.ProviderAIS}
+		other = &cmn.Bck{Name: "other", Provider: cmn.ProviderAIS}
+	)
+	oidcKeyCache.prefetch(&config.Auth.OIDC)
+	waitOIDCKeys(t)
+
+	// groups => permissions
+	rec, err := decryptToken(iss.token(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, nil))
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, rec.userID == "alice", "expected user alice, got %q", rec.userID)
+	tassert.Errorf(t, cmn.CheckPerm(rec.grants, train, cmn.PermWrite) == nil, "expected write permission for %s", train)
+	tassert.Errorf(t, cmn.CheckPerm(rec.grants, other, cmn.PermRead) == nil, "expected read permission for %s", other)
+	tassert.Errorf(t, cmn.CheckPerm(rec.grants, other, cmn.PermWrite) != nil, "unexpected write permission for %s", other)
+	tassert.Errorf(t, cmn.CheckPerm(rec.grants, nil, cmn.PermClusterAdmin) != nil, "unexpected cluster-admin permission")
+
+	rec, err = decryptToken(iss.token(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.MapClaims{"groups": "ais-admins"}))
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, cmn.CheckPerm(rec.grants, nil, cmn.PermClusterAdmin) == nil, "expected cluster-admin permission")
+
+	// invalid tokens
+	_, err = decryptToken(iss.token(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.MapClaims{"aud": "someone-else"}))
+	tassert.Errorf(t, err != nil, "expected audience mismatch")
+	_, err = decryptToken(iss.token(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}))
+	tassert.Errorf(t, err != nil, "expected expired token")
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	tassert.CheckFatal(t, err)
+	_, err = decryptToken(iss.token(t, jwt.SigningMethodRS256, "rsa-1", otherKey, nil))
+	tassert.Errorf(t, err != nil, "expected invalid signature")
+
+	// key rotation: the issuer replaces RSA key with ECDSA one
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tassert.CheckFatal(t, err)
+	iss.setKeys(jwk{Kty: "EC", Kid: "ec-1", Crv: "P-256", X: b64(ecKey.X), Y: b64(ecKey.Y)})
+	resetOIDCAttempted()
+
+	// unknown key: rejected right away while the keys are being refetched
+	ecToken := iss.token(t, jwt.SigningMethodES256, "ec-1", ecKey, nil)
+	_, err = decryptToken(ecToken)
+	tassert.Errorf(t, err != nil, "expected unknown key to be rejected")
+	waitOIDCKeys(t)
+	_, err = decryptToken(ecToken)
+	tassert.CheckError(t, err)
+	oidcKeyCache.mtx.Lock()
+	_, ok := oidcKeyCache.keys["rsa-1"]
+	oidcKeyCache.mtx.Unlock()
+	tassert.Errorf(t, !ok, "expected rotated key to be removed")
+
+	// slow issuer does not block validation
+	delay := make(chan struct{})
+	iss.mtx.Lock()
+	iss.delay = delay
+	iss.mtx.Unlock()
+	resetOIDCAttempted()
+	started := time.Now()
+	_, err = decryptToken(iss.token(t, jwt.SigningMethodES256, "random-kid", ecKey, nil))
+	tassert.Errorf(t, err != nil, "expected unknown key to be rejected")
+	_, err = decryptToken(ecToken)
+	tassert.CheckError(t, err)
+	tassert.Errorf(t, time.Since(started) < time.Second, "validation must not wait for the issuer")
+	close(delay)
+	waitOIDCKeys(t)
+}
@@ -6,6 +6,7 @@ package ais
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -26,12 +27,14 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/audit"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/jsp"
 	"github.com/NVIDIA/aistore/dsort"
 	"github.com/NVIDIA/aistore/objwalk"
 	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/tracing"
 	"github.com/NVIDIA/aistore/xaction"
 	jsoniter "github.com/json-iterator/go"
 )
@@ -40,7 +43,6 @@ const (
 	whatRenamedLB = "renamedlb"
 	fmtNotCloud   = "%q appears to be ais bucket (expecting cloud)"
 	fmtUnknownAct = "unexpected action message <- JSON [%v]"
-	guestError    = "guest account does not have permissions for the operation"
 	ciePrefix     = "cluster integrity error: cie#"
 	githubHome    = "https://github.com/NVIDIA/aistore"
 )
@@ -124,8 +126,14 @@ func (p *proxyrunner) Run() error {
 	p.authn = &authManager{
 		tokens:        make(map[string]*authRec),
 		revokedTokens: make(map[string]bool),
+		revokedIDs:    make(map[string]time.Time),
 		version:       1,
 	}
+	p.authn.loadDB(authnDBPath())
+	p.authn.loadRevokedIDs(revokedIDsPath())
+	if config.Auth.Enabled && config.Auth.OIDC.Issuer != "" {
+		oidcKeyCache.prefetch(&config.Auth.OIDC)
+	}
 
 	p.rproxy.init()
 
@@ -142,21 +150,42 @@ func (p *proxyrunner) Run() error {
 
 	bucketHandler, objectHandler := p.bucketHandler, p.objectHandler
 	dsortHandler, downloadHandler := dsort.ProxySortHandler, p.downloadHandler
+	daemonHandler, clusterHandler, tokenHandler := p.daemonHandler, p.clusterHandler, p.tokenHandler
+	reverseHandler := p.reverseHandler
 	if config.Auth.Enabled {
 		bucketHandler, objectHandler = wrapHandler(p.bucketHandler, p.checkHTTPAuth),
 			wrapHandler(p.objectHandler, p.checkHTTPAuth)
 		dsortHandler, downloadHandler = wrapHandler(dsort.ProxySortHandler, p.checkHTTPAuth),
 			wrapHandler(p.downloadHandler, p.checkHTTPAuth)
+		daemonHandler, clusterHandler = wrapHandler(p.daemonHandler, p.checkHTTPAuthAdmin),
+			wrapHandler(p.clusterHandler, p.checkHTTPAuthAdmin)
+		reverseHandler = wrapHandler(p.reverseHandler, p.checkHTTPAuthAdmin)
+	}
+	if audit.Enabled() {
+		auditWrap := auditHandler(p.authn)
+		bucketHandler, objectHandler = auditWrap(bucketHandler), auditWrap(objectHandler)
+		dsortHandler, downloadHandler = auditWrap(dsortHandler), auditWrap(downloadHandler)
+		daemonHandler, clusterHandler, tokenHandler = auditWrap(daemonHandler), auditWrap(clusterHandler),
+			auditWrap(tokenHandler)
+		reverseHandler = auditWrap(reverseHandler)
+	}
+	if config.Auth.Enabled || audit.Enabled() {
+		// verify intra-cluster signatures (see isIntraCall)
+		bucketHandler, objectHandler = intraCallHandler(bucketHandler), intraCallHandler(objectHandler)
+		dsortHandler, downloadHandler = intraCallHandler(dsortHandler), intraCallHandler(downloadHandler)
+		daemonHandler, clusterHandler = intraCallHandler(daemonHandler), intraCallHandler(clusterHandler)
+		tokenHandler, reverseHandler = intraCallHandler(tokenHandler), intraCallHandler(reverseHandler)
 	}
 	networkHandlers := []networkHandler{
-		{r: cmn.Reverse, h: p.reverseHandler, net: []string{cmn.NetworkPublic}},
+		{r: cmn.Reverse, h: reverseHandler, net: []string{cmn.NetworkPublic}},
 
 		{r: cmn.Buckets, h: bucketHandler, net: []string{cmn.NetworkPublic}},
 		{r: cmn.Objects, h: objectHandler, net: []string{cmn.NetworkPublic}},
 		{r: cmn.Download, h: downloadHandler, net: []string{cmn.NetworkPublic}},
-		{r: cmn.Daemon, h: p.daemonHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
-		{r: cmn.Cluster, h: p.clusterHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
-		{r: cmn.Tokens, h: p.tokenHandler, net: []string{cmn.NetworkPublic}},
+		{r: cmn.Daemon, h: daemonHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
+		{r: cmn.Cluster, h: clusterHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
+		{r: cmn.Tokens, h: tokenHandler, net: []string{cmn.NetworkPublic}},
+		{r: cmn.AuthNDB, h: p.authnDBHandler, net: []string{cmn.NetworkPublic}},
 		{r: cmn.Sort, h: dsortHandler, net: []string{cmn.NetworkPublic}},
 
 		{r: cmn.Metasync, h: p.metasyncHandler, net: []string{cmn.NetworkIntraControl}},
@@ -166,6 +195,7 @@ func (p *proxyrunner) Run() error {
 		{r: "/", h: cmn.InvalidHandler, net: []string{cmn.NetworkIntraControl, cmn.NetworkIntraData}},
 	}
 	p.registerNetworkHandlers(networkHandlers)
+	p.registerPublicNetHandler(cmn.URLPath(cmn.Metrics), p.metricsHandler)
 
 	glog.Infof("%s: [public net] listening on: %s", p.si, p.si.PublicNet.DirectURL)
 	if p.si.PublicNet.DirectURL != p.si.IntraControlNet.DirectURL {
@@ -684,6 +714,13 @@ ExtractBMD:
 		p.authn.updateRevokedList(revokedTokens)
 	}
 
+	authnDB, err := p.extractAuthNDB(payload)
+	if err != nil {
+		errs = append(errs, err)
+	} else if authnDB != nil {
+		p.receiveAuthNDB(authnDB)
+	}
+
 	if len(errs) > 0 {
 		msg := fmt.Sprintf("%v", errs)
 		p.invalmsghdlr(w, r, msg)
@@ -712,6 +749,20 @@ func (p *proxyrunner) healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// GET /metrics (Prometheus)
+func (p *proxyrunner) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		p.invalmsghdlr(w, r, "invalid method for /metrics path", http.StatusBadRequest)
+		return
+	}
+	pw := stats.NewPromWriter(p.si.ID())
+	getproxystatsrunner().Prometheus(pw)
+	w.Header().Set("Content-Type", stats.PromContentType)
+	if _, err := pw.WriteTo(w); err != nil {
+		glog.Errorf("%s: failed to write metrics, err: %v", p.si, err)
+	}
+}
+
 func (p *proxyrunner) undoCreateBucket(msg *cmn.ActionMsg, bck *cluster.Bck) {
 	p.owner.bmd.Lock()
 	clone := p.owner.bmd.get().clone()
@@ -821,15 +872,8 @@ func (p *proxyrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 	if cmn.ReadJSON(w, r, &msg) != nil {
 		return
 	}
-	guestAccess := r.Header.Get(cmn.HeaderGuestAccess) == "1"
-
 	// 1. "all buckets"
 	if len(apiItems) == 0 {
-		if guestAccess {
-			p.invalmsghdlr(w, r, guestError, http.StatusUnauthorized)
-			return
-		}
-
 		switch msg.Action {
 		case cmn.ActRecoverBck:
 			p.recoverBuckets(w, r, &msg)
@@ -861,11 +905,6 @@ func (p *proxyrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 	}
 	config := cmn.GCO.Get()
 
-	if msg.Action != cmn.ActListObjects && guestAccess {
-		p.invalmsghdlr(w, r, guestError, http.StatusUnauthorized)
-		return
-	}
-
 	// 3. createlb
 	if msg.Action == cmn.ActCreateLB {
 		if err = cmn.ValidateBckName(bucket); err != nil {
@@ -997,21 +1036,25 @@ func (p *proxyrunner) listBucketAndCollectStats(w http.ResponseWriter, r *http.R
 	if id != "" {
 		smsg.TaskID = id
 	}
+	ctx, span := tracing.StartAt(tracing.FromRequest(context.Background(), r), "list objects",
+		tracing.KindServer, started)
+	span.SetAttr("bucket", bck.String())
 	if bck.IsAIS() || smsg.Cached {
-		bckList, taskID, err = p.listAISBucket(bck, smsg)
+		bckList, taskID, err = p.listAISBucket(ctx, bck, smsg)
 	} else {
 		var status int
 		//
 		// NOTE: for async tasks, user must check for StatusAccepted and use returned TaskID
 		//
-		bckList, taskID, status, err = p.listCloudBucket(bck, smsg)
+		bckList, taskID, status, err = p.listCloudBucket(ctx, bck, smsg)
 		if status == http.StatusGone {
 			// at this point we know that this cloud bucket exists and is offline
 			smsg.Cached = true
 			smsg.TaskID = ""
-			bckList, taskID, err = p.listAISBucket(bck, smsg)
+			bckList, taskID, err = p.listAISBucket(ctx, bck, smsg)
 		}
 	}
+	span.End(err)
 	if err != nil {
 		p.invalmsghdlr(w, r, err.Error())
 		return
@@ -1799,6 +1842,16 @@ func (p *proxyrunner) redirectURL(r *http.Request, si *cluster.Snode, ts time.Ti
 	query.Add(cmn.URLParamProxyID, p.si.ID())
 	query.Add(cmn.URLParamBMDVersion, bmd.vstr)
 	query.Add(cmn.URLParamUnixTime, strconv.FormatInt(ts.UnixNano(), 10))
+	if tracing.Enabled() {
+		// the target's span becomes a child of the (zero-duration) redirect span
+		ctx, span := tracing.StartAt(tracing.FromRequest(context.Background(), r), r.Method+" redirect",
+			tracing.KindServer, ts)
+		span.SetAttr("target", si.ID())
+		if traceparent := tracing.Traceparent(ctx); traceparent != "" {
+			query.Add(cmn.URLParamTraceparent, traceparent)
+		}
+		span.End(nil)
+	}
 	redirect += query.Encode()
 	return
 }
@@ -1922,7 +1975,7 @@ func (p *proxyrunner) checkBckTaskResp(taskID string, results chan callResult) (
 //      * the list of objects if the aync task finished (taskID is 0 in this case)
 //      * non-zero taskID if the task is still running
 //      * error
-func (p *proxyrunner) listAISBucket(bck *cluster.Bck, msg cmn.SelectMsg) (
+func (p *proxyrunner) listAISBucket(ctx context.Context, bck *cluster.Bck, msg cmn.SelectMsg) (
 	allEntries *cmn.BucketList, taskID string, err error) {
 	// Start new async task if client did not provide taskID (neither in headers nor in SelectMsg).
 	isNew, q := p.initAsyncQuery(&msg)
@@ -1937,6 +1990,7 @@ func (p *proxyrunner) listAISBucket(bck *cluster.Bck, msg cmn.SelectMsg) (
 				Body:  body,
 			},
 			timeout: cmn.GCO.Get().Timeout.ListBucket,
+			ctx:     ctx,
 		}
 	)
 
@@ -2012,7 +2066,7 @@ func (p *proxyrunner) listAISBucket(bck *cluster.Bck, msg cmn.SelectMsg) (
 //      * non-zero taskID if the task is still running
 //      * error
 //
-func (p *proxyrunner) listCloudBucket(bck *cluster.Bck, msg cmn.SelectMsg) (
+func (p *proxyrunner) listCloudBucket(ctx context.Context, bck *cluster.Bck, msg cmn.SelectMsg) (
 	allEntries *cmn.BucketList, taskID string, status int, err error) {
 	if msg.PageSize > cmn.DefaultListPageSize {
 		glog.Warningf("list-bucket page size %d for bucket %s exceeds the default maximum %d ",
@@ -2042,6 +2096,7 @@ func (p *proxyrunner) listCloudBucket(bck *cluster.Bck, msg cmn.SelectMsg) (
 			Body:   body,
 		},
 		timeout: reqTimeout,
+		ctx:     ctx,
 	}
 
 	var results chan callResult
@@ -2056,6 +2111,7 @@ func (p *proxyrunner) listCloudBucket(bck *cluster.Bck, msg cmn.SelectMsg) (
 				network: cmn.NetworkIntraControl,
 				timeout: reqTimeout,
 				nodes:   []cluster.NodeMap{{si.ID(): si}},
+				ctx:     ctx,
 			})
 			break
 		}
@@ -2287,33 +2343,14 @@ func (p *proxyrunner) httpTokenDelete(w http.ResponseWriter, r *http.Request) {
 // Header format:
 //		'Authorization: Bearer <token>'
 func (p *proxyrunner) validateToken(r *http.Request) (*authRec, error) {
-	authToken := r.Header.Get(cmn.HeaderAuthorization)
-	if authToken == "" && cmn.GCO.Get().Auth.AllowGuest {
-		return guestAcc, nil
-	}
-
-	s := strings.SplitN(authToken, " ", 2)
-	if len(s) != 2 || s[0] != cmn.HeaderBearer {
-		return nil, fmt.Errorf("invalid request")
-	}
-
-	if p.authn == nil {
-		return nil, fmt.Errorf("invalid credentials")
-	}
-
-	auth, err := p.authn.validateToken(s[1])
-	if err != nil {
-		glog.Errorf("Invalid token: %v", err)
-		return nil, fmt.Errorf("invalid token")
-	}
-
-	return auth, nil
+	return p.authn.validateRequest(r)
 }
 
 // A wrapper to check any request before delegating the request to real handler
 // If authentication is disabled, it does nothing.
 // If authentication is enabled, it looks for token in request header and
-// makes sure that it is valid
+// makes sure that it is valid and that the token's permissions allow the request
+// (see cmn.AuthGrant and requiredPerms)
 func (p *proxyrunner) checkHTTPAuth(h http.HandlerFunc) http.HandlerFunc {
 	wrappedFunc := func(w http.ResponseWriter, r *http.Request) {
 		var (
@@ -2327,18 +2364,15 @@ func (p *proxyrunner) checkHTTPAuth(h http.HandlerFunc) http.HandlerFunc {
 				p.invalmsghdlr(w, r, "Not authorized", http.StatusUnauthorized)
 				return
 			}
-			if auth.isGuest && r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/"+cmn.Buckets+"/") {
-				// get bucket objects is POST request, it cannot check the action
-				// here because it "emties" payload and httpbckpost gets nothing.
-				// So, we just set flag 'Guest' for httpbckpost to use
-				r.Header.Set(cmn.HeaderGuestAccess, "1")
-			} else if auth.isGuest && r.Method != http.MethodGet && r.Method != http.MethodHead {
-				p.invalmsghdlr(w, r, guestError, http.StatusUnauthorized)
+			if err = p.checkPermissions(r, auth); err != nil {
+				p.invalmsghdlr(w, r, fmt.Sprintf("%s: %v", auth.userID, err), http.StatusForbidden)
 				return
 			}
 			if glog.FastV(4, glog.SmoduleAIS) {
 				if auth.isGuest {
 					glog.Info("Guest access granted")
+				} else if auth.apiKey != "" {
+					glog.Infof("Logged as %s (API key %s)", auth.userID, auth.apiKey)
 				} else {
 					glog.Infof("Logged as %s", auth.userID)
 				}
@@ -2350,6 +2384,114 @@ func (p *proxyrunner) checkHTTPAuth(h http.HandlerFunc) http.HandlerFunc {
 	return wrappedFunc
 }
 
+// Same as above for the cluster management (cluster, daemon, and reverse) API
+// (see checkAuthAdmin)
+func (p *proxyrunner) checkHTTPAuthAdmin(h http.HandlerFunc) http.HandlerFunc {
+	return p.checkAuthAdmin(p.authn, h)
+}
+
+func (p *proxyrunner) checkPermissions(r *http.Request, auth *authRec) error {
+	for _, req := range p.requiredPerms(r) {
+		if err := cmn.CheckPerm(auth.grants, req.bck, req.perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type permReq struct {
+	bck  *cmn.Bck // nil: cluster-wide
+	perm string
+}
+
+// permissions required to execute a given bucket, object, download, or dsort request
+// (none - any authenticated user); when the request cannot be parsed the strictest
+// permissions are required
+func (p *proxyrunner) requiredPerms(r *http.Request) []permReq {
+	items, err := cmn.MatchRESTItems(r.URL.Path, 0, true, cmn.Version)
+	if err != nil || len(items) == 0 {
+		return []permReq{{nil, cmn.PermAdmin}}
+	}
+	switch items[0] {
+	case cmn.Objects, cmn.Buckets:
+	default: // download, dsort
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			return nil
+		}
+		return []permReq{{nil, cmn.PermWrite}}
+	}
+	var bck *cmn.Bck
+	if len(items) > 1 && items[1] != "*" {
+		bck = p.authBck(items[1], r.URL.Query())
+	}
+	if items[0] == cmn.Objects {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			return []permReq{{bck, cmn.PermRead}}
+		case http.MethodDelete:
+			return []permReq{{bck, cmn.PermDelete}}
+		case http.MethodPost:
+			msg, err := peekActionMsg(r)
+			if err != nil || (msg != nil && msg.Action == cmn.ActRename) {
+				return []permReq{{bck, cmn.PermWrite}, {bck, cmn.PermDelete}}
+			}
+			return []permReq{{bck, cmn.PermWrite}}
+		default:
+			return []permReq{{bck, cmn.PermWrite}}
+		}
+	}
+
+	// buckets
+	var action string
+	msg, err := peekActionMsg(r)
+	if err != nil {
+		return []permReq{{bck, cmn.PermAdmin}}
+	}
+	if msg != nil {
+		action = msg.Action
+	}
+	if bck == nil {
+		switch {
+		case r.Method == http.MethodGet || r.Method == http.MethodHead || action == cmn.ActSummaryBucket:
+			return nil // list or summarize all buckets
+		default: // e.g., recover buckets
+			return []permReq{{nil, cmn.PermAdmin}}
+		}
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		return []permReq{{bck, cmn.PermRead}}
+	case http.MethodPut, http.MethodPatch: // bucket props
+		return []permReq{{bck, cmn.PermAdmin}}
+	}
+	switch action {
+	case cmn.ActListObjects, cmn.ActSummaryBucket:
+		return []permReq{{bck, cmn.PermRead}}
+	case cmn.ActPrefetch:
+		return []permReq{{bck, cmn.PermWrite}}
+	case cmn.ActDelete, cmn.ActEvictObjects:
+		return []permReq{{bck, cmn.PermDelete}}
+	case cmn.ActCopyBucket, cmn.ActRenameLB:
+		bckTo := *bck
+		bckTo.Name = msg.Name
+		return []permReq{{bck, cmn.PermAdmin}, {&bckTo, cmn.PermAdmin}}
+	default: // create, destroy, evict, and more
+		return []permReq{{bck, cmn.PermAdmin}}
+	}
+}
+
+// bucket to check the permissions for (NOTE: may not exist)
+func (p *proxyrunner) authBck(bucket string, query url.Values) *cmn.Bck {
+	bck, err := newBckFromQuery(bucket, query)
+	if err != nil {
+		return &cmn.Bck{Name: bucket, Provider: query.Get(cmn.URLParamProvider)}
+	}
+	if err := bck.Init(p.owner.bmd, p.si); err != nil || bck.Provider == "" {
+		bck.Provider = cmn.ProviderAIS
+	}
+	return &bck.Bck
+}
+
 func (p *proxyrunner) reverseHandler(w http.ResponseWriter, r *http.Request) {
 	apiItems, err := p.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Reverse)
 	if err != nil {
@@ -2908,6 +3050,10 @@ func (p *proxyrunner) httpcluget(w http.ResponseWriter, r *http.Request) {
 		p.invokeHTTPGetXaction(w, r)
 	case cmn.GetWhatMountpaths:
 		p.invokeHTTPGetClusterMountpaths(w, r)
+	case cmn.GetWhatRebPlan:
+		p.invokeHTTPGetRebPlan(w, r)
+	case cmn.GetWhatBucketStats:
+		p.invokeHTTPGetBucketStats(w, r)
 	default:
 		s := fmt.Sprintf("unexpected GET request, invalid param 'what': [%s]", getWhat)
 		cmn.InvalidHandlerWithMsg(w, r, s)
@@ -3038,6 +3184,88 @@ func (p *proxyrunner) invokeHTTPGetClusterMountpaths(w http.ResponseWriter, r *h
 	return ok
 }
 
+// per-bucket stats of all targets merged together
+func (p *proxyrunner) invokeHTTPGetBucketStats(w http.ResponseWriter, r *http.Request) bool {
+	targetStats, ok := p.invokeHTTPSelectMsgOnTargets(w, r, false /*not silent*/)
+	if !ok {
+		return false
+	}
+	out := make(stats.BucketStatsMap, 8)
+	for tid, raw := range targetStats {
+		bstats := make(stats.BucketStatsMap)
+		if err := jsoniter.Unmarshal(raw, &bstats); err != nil {
+			p.invalmsghdlr(w, r, fmt.Sprintf("failed to unmarshal %s bucket stats, err: %v", tid, err))
+			return false
+		}
+		out.Merge(bstats)
+	}
+	body := cmn.MustMarshal(out)
+	return p.writeJSON(w, r, body, "HttpGetBucketStats")
+}
+
+// rebalance dry-run: apply the requested changes to a copy of the current
+// cluster map and have each target evaluate it against its local objects
+func (p *proxyrunner) invokeHTTPGetRebPlan(w http.ResponseWriter, r *http.Request) bool {
+	var (
+		msg  cmn.RebPlanMsg
+		smap = p.owner.smap.get()
+	)
+	if cmn.ReadJSON(w, r, &msg) != nil {
+		return false
+	}
+	if len(msg.Add) == 0 && len(msg.Remove) == 0 {
+		p.invalmsghdlr(w, r, "rebalance plan: no targets to add or remove")
+		return false
+	}
+	tmap := make(cluster.NodeMap, len(smap.Tmap)+len(msg.Add))
+	for id, si := range smap.Tmap {
+		tmap[id] = si
+	}
+	for _, id := range msg.Add {
+		if _, ok := tmap[id]; ok {
+			p.invalmsghdlr(w, r, fmt.Sprintf("rebalance plan: target %s is already registered", id))
+			return false
+		}
+		tmap[id] = &cluster.Snode{DaemonID: id, DaemonType: cmn.Target}
+	}
+	for _, id := range msg.Remove {
+		if _, ok := tmap[id]; !ok {
+			p.invalmsghdlr(w, r, fmt.Sprintf("rebalance plan: target %s is not present in the %s", id, smap))
+			return false
+		}
+		delete(tmap, id)
+	}
+	if len(tmap) == 0 {
+		p.invalmsghdlr(w, r, "rebalance plan: cannot remove all targets")
+		return false
+	}
+	results := p.bcastTo(bcastArgs{
+		req: cmn.ReqArgs{
+			Method: r.Method,
+			Path:   cmn.URLPath(cmn.Version, cmn.Daemon),
+			Query:  r.URL.Query(),
+			Body:   cmn.MustMarshal(tmap),
+		},
+		smap:    smap,
+		timeout: cmn.LongTimeout,
+	})
+	out := make(cmn.ClusterRebPlan, len(results))
+	for res := range results {
+		if res.err != nil {
+			p.invalmsghdlr(w, r, res.details)
+			return false
+		}
+		plan := &cmn.RebPlan{}
+		if err := jsoniter.Unmarshal(res.outjson, plan); err != nil {
+			p.invalmsghdlr(w, r, err.Error())
+			return false
+		}
+		out[res.si.ID()] = plan
+	}
+	body := cmn.MustMarshal(out)
+	return p.writeJSON(w, r, body, "HttpGetRebPlan")
+}
+
 // register|keepalive target|proxy
 // start|stop xaction
 func (p *proxyrunner) httpclupost(w http.ResponseWriter, r *http.Request) {
@@ -3234,6 +3462,9 @@ func (p *proxyrunner) httpclupost(w http.ResponseWriter, r *http.Request) {
 		if len(tokens.Tokens) > 0 {
 			pairs = append(pairs, revsPair{tokens, msgInt})
 		}
+		if db := p.authn.getDB(); db != nil {
+			pairs = append(pairs, revsPair{db, msgInt})
+		}
 		p.metasyncer.sync(false, pairs...)
 	}(nsi)
 }
@@ -3305,6 +3536,60 @@ func (p *proxyrunner) httpcludel(w http.ResponseWriter, r *http.Request) {
 	p.metasyncer.sync(true, revsPair{clone, msgInt})
 }
 
+// decommission target: mark the target as leaving - from this point on it
+// does not own any objects and does not receive new ones - and request it to
+// evacuate its objects; upon successful evacuation the target itself requests
+// to be unregistered (see evacuator.run). The progress and the outcome are
+// reported by the target's decommission xaction.
+func (p *proxyrunner) decommissionTarget(si *cluster.Snode, msg *cmn.ActionMsg) (xactID string, err error) {
+	if err = p.setLeaving(si.ID(), true, msg); err != nil {
+		return
+	}
+	res := p.call(callArgs{
+		si: si,
+		req: cmn.ReqArgs{
+			Method: http.MethodPut,
+			Path:   cmn.URLPath(cmn.Version, cmn.Daemon),
+			Body:   cmn.MustMarshal(&cmn.ActionMsg{Action: cmn.ActDecommission}),
+		},
+		timeout: cmn.GCO.Get().Timeout.CplaneOperation,
+	})
+	if res.err != nil {
+		err = fmt.Errorf("failed to start %s of %s: %v(%s)", msg.Action, si, res.err, res.details)
+		if errUnmark := p.setLeaving(si.ID(), false, msg); errUnmark != nil {
+			glog.Errorf("%s: %v", p.si, errUnmark)
+		}
+		return
+	}
+	glog.Infof("%s: %s %s - evacuating...", p.si, msg.Action, si)
+	return string(res.outjson), nil
+}
+
+// setLeaving marks (unmarks) the target as being decommissioned
+func (p *proxyrunner) setLeaving(sid string, leaving bool, msg *cmn.ActionMsg) error {
+	clone, err := p.owner.smap.modify(func(clone *smapX) error {
+		if clone.GetTarget(sid) == nil {
+			return fmt.Errorf("unknown target %s", sid)
+		}
+		if leaving {
+			if clone.Leaving == nil {
+				clone.Leaving = make(cmn.SimpleKVs, 1)
+			}
+			clone.Leaving[sid] = ""
+		} else {
+			delete(clone.Leaving, sid)
+		}
+		clone.Version++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	msgInt := p.newActionMsgInternal(msg, clone, nil)
+	p.metasyncer.sync(true, revsPair{clone, msgInt})
+	return nil
+}
+
 func (p *proxyrunner) unregisterNode(sid string) (clone *smapX, status int, err error) {
 	node := p.owner.smap.get().GetNode(sid)
 	if node == nil {
@@ -3461,6 +3746,33 @@ func (p *proxyrunner) httpcluput(w http.ResponseWriter, r *http.Request) {
 		p.setGlobRebID(smap, msgInt, true)
 		p.owner.smap.Unlock()
 		p.metasyncer.sync(false, revsPair{smap, msgInt})
+	case cmn.ActDecommission, cmn.ActDecommCancel:
+		smap := p.owner.smap.get()
+		si := smap.GetTarget(msg.Name)
+		if si == nil {
+			p.invalmsghdlr(w, r, fmt.Sprintf("%s: unknown target %q", msg.Action, msg.Name), http.StatusNotFound)
+			return
+		}
+		if msg.Action == cmn.ActDecommCancel {
+			if err := p.setLeaving(si.ID(), false, msg); err != nil {
+				p.invalmsghdlr(w, r, err.Error())
+			}
+			return
+		}
+		if smap.IsLeaving(si.ID()) {
+			p.invalmsghdlr(w, r, fmt.Sprintf("%s: %s is already being decommissioned", msg.Action, si))
+			return
+		}
+		if smap.CountTargets()-len(smap.Leaving) < 2 {
+			p.invalmsghdlr(w, r, fmt.Sprintf("%s: cannot decommission the last target %s", msg.Action, si))
+			return
+		}
+		xactID, err := p.decommissionTarget(si, msg)
+		if err != nil {
+			p.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		w.Write([]byte(xactID))
 	case cmn.ActXactStart, cmn.ActXactStop:
 		body := cmn.MustMarshal(msg)
 		results := p.bcastTo(bcastArgs{
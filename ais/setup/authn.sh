@@ -20,7 +20,9 @@ cat > $CONFFILE <<EOL
 		"secret": "$SECRETKEY",
 		"username": "${AUTHN_SU_NAME:-admin}",
 		"password": "${AUTHN_SU_PASS:-admin}",
-		"expiration_time": "${AUTHN_TTL:-24h}"
+		"expiration_time": "${AUTHN_TTL:-24h}",
+		"cluster_db": ${AUTHN_CLUSTER_DB:-false},
+		"db_secret": "$AUTHN_DB_SECRET"
 	},
 	"timeout": {
 		"default_timeout": "30s"
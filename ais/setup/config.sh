@@ -77,6 +77,19 @@ cat > $CONFFILE <<EOL
 		"block_size": ${BLOCK_SIZE:-262144},
 		"checksum":   ${CHECKSUM:-false}
 	},
+	"tracing": {
+		"exporter":     "${TRACING_EXPORTER:-otlp}",
+		"endpoint":     "${TRACING_ENDPOINT:-}",
+		"sample_ratio": ${TRACING_SAMPLE_RATIO:-1.0},
+		"enabled":      ${TRACING_ENABLED:-false}
+	},
+	"audit": {
+		"dir":       "${AUDIT_DIR:-}",
+		"max_size":  67108864,
+		"max_files": 8,
+		"targets":   ${AUDIT_TARGETS:-false},
+		"enabled":   ${AUDIT_ENABLED:-false}
+	},
 	"versioning": {
 		"enabled":           true,
 		"validate_warm_get": false
@@ -105,10 +118,12 @@ cat > $CONFFILE <<EOL
 			"rproxy":		"",
 			"server_certificate":	"server.crt",
 			"server_key":		"server.key",
+			"ca_certificate":	"${CA_CERTIFICATE:-ca.crt}",
 			"write_buffer_size":	${HTTP_WRITE_BUFFER_SIZE:-0},
 			"read_buffer_size":	${HTTP_READ_BUFFER_SIZE:-0},
 			"rproxy_cache":		true,
 			"use_https":		${USE_HTTPS:-false},
+			"use_intra_tls":	${USE_INTRA_TLS:-false},
 			"chunked_transfer":	${CHUNKED_TRANSFER:-true}
 		}
 	},
@@ -121,7 +136,18 @@ cat > $CONFFILE <<EOL
 		"secret":  "$SECRETKEY",
 		"enabled": ${AUTHENABLED:-false},
 		"creddir": "$CREDDIR",
-		"allow_guest": ${ALLOW_GUEST:-false}
+		"allow_guest": ${ALLOW_GUEST:-false},
+		"oidc": {
+			"issuer":        "${OIDC_ISSUER}",
+			"client_id":     "${OIDC_CLIENT_ID}",
+			"jwks_url":      "",
+			"user_claim":    "sub",
+			"groups_claim":  "groups",
+			"default_roles": [],
+			"group_roles":   {},
+			"group_grants":  {},
+			"jwks_refresh":  "1h"
+		}
 	},
 	"keepalivetracker": {
 		"proxy": {
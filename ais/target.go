@@ -20,6 +20,7 @@ import (
 
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/audit"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/dsort"
@@ -28,6 +29,7 @@ import (
 	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/reb"
 	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/tracing"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/xaction"
 	jsoniter "github.com/json-iterator/go"
@@ -83,6 +85,7 @@ type (
 		}
 		regstate       regstate // the state of being registered with the primary, can be (en/dis)abled via API
 		clusterStarted atomic.Bool
+		putsInFlight   atomic.Int64 // (see evacuator.quiesce)
 	}
 )
 
@@ -252,8 +255,10 @@ func (t *targetrunner) Run() error {
 	t.authn = &authManager{
 		tokens:        make(map[string]*authRec),
 		revokedTokens: make(map[string]bool),
+		revokedIDs:    make(map[string]time.Time),
 		version:       1,
 	}
+	t.authn.loadRevokedIDs(revokedIDsPath())
 
 	// transactions
 	t.transactions.init(t)
@@ -268,10 +273,26 @@ func (t *targetrunner) Run() error {
 	if config.Net.UseIntraData {
 		transport.SetMux(cmn.NetworkIntraData, t.intraDataServer.mux)
 	}
+	bucketHandler, objectHandler, daemonHandler := t.bucketHandler, t.objectHandler, t.daemonHandler
+	if config.Auth.Enabled {
+		daemonHandler = t.checkAuthAdmin(t.authn, daemonHandler)
+	}
+	if audit.Enabled() {
+		auditWrap := auditHandler(t.authn)
+		objectHandler = auditWrap(objectHandler)
+		if config.Audit.Targets {
+			bucketHandler, daemonHandler = auditWrap(bucketHandler), auditWrap(daemonHandler)
+		}
+	}
+	if config.Auth.Enabled || audit.Enabled() {
+		// verify intra-cluster signatures (see isIntraCall)
+		bucketHandler, objectHandler = intraCallHandler(bucketHandler), intraCallHandler(objectHandler)
+		daemonHandler = intraCallHandler(daemonHandler)
+	}
 	networkHandlers := []networkHandler{
-		{r: cmn.Buckets, h: t.bucketHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl, cmn.NetworkIntraData}},
-		{r: cmn.Objects, h: t.objectHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraData}},
-		{r: cmn.Daemon, h: t.daemonHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
+		{r: cmn.Buckets, h: bucketHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl, cmn.NetworkIntraData}},
+		{r: cmn.Objects, h: objectHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraData}},
+		{r: cmn.Daemon, h: daemonHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
 		{r: cmn.Tokens, h: t.tokenHandler, net: []string{cmn.NetworkPublic}},
 
 		{r: cmn.Download, h: t.downloadHandler, net: []string{cmn.NetworkIntraControl}},
@@ -284,6 +305,7 @@ func (t *targetrunner) Run() error {
 		{r: "/", h: cmn.InvalidHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl, cmn.NetworkIntraData}},
 	}
 	t.registerNetworkHandlers(networkHandlers)
+	t.registerPublicNetHandler(cmn.URLPath(cmn.Metrics), t.metricsHandler)
 
 	t.rebManager = reb.NewManager(t, config, getstorstatsrunner())
 	ec.Init(t, xaction.Registry)
@@ -324,6 +346,7 @@ func (t *targetrunner) registerStats() {
 	t.statsT.Register(stats.ErrCksumSize, stats.KindCounter)
 	t.statsT.Register(stats.ErrMetadataCount, stats.KindCounter)
 	t.statsT.Register(stats.ErrIOCount, stats.KindCounter)
+	t.statsT.Register(stats.PutSize, stats.KindCounter)
 	t.statsT.Register(stats.GetRedirLatency, stats.KindLatency)
 	t.statsT.Register(stats.PutRedirLatency, stats.KindLatency)
 	// download
@@ -483,18 +506,23 @@ func (t *targetrunner) httpobjget(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx, span := tracing.StartAt(t.contextWithAuth(r), "GET object", tracing.KindServer, started)
+	span.SetAttr("object", lom.String())
 	goi := &getObjInfo{
 		started: started,
 		t:       t,
 		lom:     lom,
 		w:       w,
-		ctx:     t.contextWithAuth(r.Header),
+		ctx:     ctx,
 		offset:  rangeOff,
 		length:  rangeLen,
 		isGFN:   isGFNRequest,
 		chunked: config.Net.HTTP.Chunked,
 	}
-	if err, errCode := goi.getObject(); err != nil {
+	err, errCode := goi.getObject()
+	span.End(err)
+	if err != nil {
+		t.statsT.AddBucket(lom.Bck().Bck, stats.NamedVal64{Name: stats.ErrGetCount, Value: 1})
 		if cmn.IsErrConnectionReset(err) {
 			glog.Errorf("GET %s: %v", lom, err)
 		} else {
@@ -542,6 +570,13 @@ func (t *targetrunner) httpobjput(w http.ResponseWriter, r *http.Request) {
 	if !t.verifyProxyRedirection(w, r) {
 		return
 	}
+	// NOTE: increment prior to checking the Smap (see evacuator.quiesce)
+	t.putsInFlight.Inc()
+	defer t.putsInFlight.Dec()
+	if smap := t.owner.smap.get(); smap.IsLeaving(t.si.ID()) {
+		t.invalmsghdlr(w, r, fmt.Sprintf("%s is being decommissioned", t.si), http.StatusServiceUnavailable)
+		return
+	}
 	config := cmn.GCO.Get()
 	if capInfo := t.AvgCapUsed(config); capInfo.OOS {
 		t.invalmsghdlr(w, r, capInfo.Err.Error())
@@ -578,10 +613,12 @@ func (t *targetrunner) httpobjput(w http.ResponseWriter, r *http.Request) {
 	lom.SetAtimeUnix(started.UnixNano())
 	if appendTy == "" {
 		if err, errCode := t.doPut(r, lom, started); err != nil {
+			t.statsT.AddBucket(lom.Bck().Bck, stats.NamedVal64{Name: stats.ErrPutCount, Value: 1})
 			t.invalmsghdlr(w, r, err.Error(), errCode)
 		}
 	} else {
 		if filePath, err, errCode := t.doAppend(r, lom, started); err != nil {
+			t.statsT.AddBucket(lom.Bck().Bck, stats.NamedVal64{Name: stats.ErrPutCount, Value: 1})
 			t.invalmsghdlr(w, r, err.Error(), errCode)
 		} else {
 			handle := combineAppendHandle(t.si.ID(), filePath)
@@ -652,7 +689,7 @@ func (t *targetrunner) httpbckdelete(w http.ResponseWriter, r *http.Request) {
 		)
 
 		args := &xaction.EvictDeleteArgs{
-			Ctx:   t.contextWithAuth(r.Header),
+			Ctx:   t.contextWithAuth(r),
 			Evict: msgInt.Action == cmn.ActEvictObjects,
 		}
 		if err = cmn.TryUnmarshal(msgInt.Value, &rangeMsg); err == nil {
@@ -726,8 +763,12 @@ func (t *targetrunner) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		t.invalmsghdlr(w, r, err.Error())
 		return
 	}
-	err = t.objDelete(t.contextWithAuth(r.Header), lom, evict)
+	ctx, span := tracing.StartAt(t.contextWithAuth(r), "DELETE object", tracing.KindServer, started)
+	span.SetAttr("object", lom.String())
+	err = t.objDelete(ctx, lom, evict)
+	span.End(err)
 	if err != nil {
+		t.statsT.AddBucket(lom.Bck().Bck, stats.NamedVal64{Name: stats.ErrDeleteCount, Value: 1})
 		if cmn.IsObjNotExist(err) {
 			t.invalmsghdlrsilent(w, r, fmt.Sprintf("object %s/%s doesn't exist", lom.Bck(), lom.Objname), http.StatusNotFound)
 		} else {
@@ -735,6 +776,9 @@ func (t *targetrunner) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
+	if !evict {
+		t.statsT.AddBucket(lom.Bck().Bck, stats.NamedVal64{Name: stats.DeleteCount, Value: 1})
+	}
 	// EC cleanup if EC is enabled
 	ec.ECM.CleanupObject(lom)
 	if glog.FastV(4, glog.SmoduleAIS) {
@@ -1025,7 +1069,7 @@ func (t *targetrunner) httpbckhead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// + cloud
-	bucketProps, err, code = t.cloud.headBucket(t.contextWithAuth(r.Header), bucket)
+	bucketProps, err, code = t.cloud.headBucket(t.contextWithAuth(r), bucket)
 	if err != nil {
 		if !inBMD {
 			if code == http.StatusNotFound {
@@ -1044,6 +1088,7 @@ func (t *targetrunner) httpbckhead(w http.ResponseWriter, r *http.Request) {
 	for k, v := range bucketProps {
 		hdr.Set(k, v)
 	}
+	hdr.Set(cmn.HeaderCloudNs, bck.Ns.Uname())
 	t.bucketPropsToHdr(bck, hdr, config, bucketProps[cmn.HeaderBucketVerEnabled])
 }
 
@@ -1144,7 +1189,7 @@ func (t *targetrunner) httpobjhead(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		var objMeta cmn.SimpleKVs
-		objMeta, err, errCode = t.cloud.headObj(t.contextWithAuth(r.Header), lom)
+		objMeta, err, errCode = t.cloud.headObj(t.contextWithAuth(r), lom)
 		if err != nil {
 			errMsg := fmt.Sprintf("%s: failed to head metadata, err: %v", lom, err)
 			invalidHandler(w, r, errMsg, errCode)
@@ -1238,7 +1283,7 @@ func (t *targetrunner) getBucketNames(w http.ResponseWriter, r *http.Request, pr
 		bucketNames = t.getBucketNamesAIS(bmd)
 	}
 	if all || cmn.IsProviderCloud(bck, true /*acceptAnon*/) {
-		buckets, err, errcode := t.cloud.getBucketNames(t.contextWithAuth(r.Header))
+		buckets, err, errcode := t.cloud.getBucketNames(t.contextWithAuth(r))
 		if err != nil {
 			errMsg := fmt.Sprintf("failed to list all buckets, err: %v", err)
 			t.invalmsghdlr(w, r, errMsg, errcode)
@@ -1282,14 +1327,17 @@ func (t *targetrunner) doPut(r *http.Request, lom *cluster.LOM, started time.Tim
 		cksumType  = header.Get(cmn.HeaderObjCksumType)
 		cksumValue = header.Get(cmn.HeaderObjCksumVal)
 		cksum      = cmn.NewCksum(cksumType, cksumValue)
+		ctx, span  = tracing.StartAt(t.contextWithAuth(r), "PUT object", tracing.KindServer, started)
 	)
+	span.SetAttr("object", lom.String())
+	defer func() { span.End(err) }()
 	poi := &putObjInfo{
 		started:      started,
 		t:            t,
 		lom:          lom,
 		r:            r.Body,
 		cksumToCheck: cksum,
-		ctx:          t.contextWithAuth(header),
+		ctx:          ctx,
 		workFQN:      fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut),
 	}
 	sizeStr := header.Get("Content-Length")
@@ -1301,7 +1349,7 @@ func (t *targetrunner) doPut(r *http.Request, lom *cluster.LOM, started time.Tim
 	return poi.putObject()
 }
 
-func (t *targetrunner) putMirror(lom *cluster.LOM) {
+func (t *targetrunner) putMirror(ctx context.Context, lom *cluster.LOM) {
 	const retries = 2
 	var (
 		err      error
@@ -1320,7 +1368,7 @@ func (t *targetrunner) putMirror(lom *cluster.LOM) {
 		if xputlrep == nil {
 			return
 		}
-		err = xputlrep.Repl(lom)
+		err = xputlrep.Repl(ctx, lom)
 		if cmn.IsErrXactExpired(err) {
 			break
 		}
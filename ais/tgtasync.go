@@ -61,7 +61,7 @@ func (t *targetrunner) doAsync(w http.ResponseWriter, r *http.Request, action st
 		query      = r.URL.Query()
 		taskAction = query.Get(cmn.URLParamTaskAction)
 		silent, _  = cmn.ParseBool(query.Get(cmn.URLParamSilent))
-		ctx        = t.contextWithAuth(r.Header)
+		ctx        = t.contextWithAuth(r)
 		// create task call
 	)
 	if taskAction == cmn.TaskStart {
@@ -5,6 +5,7 @@
 package ais
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -107,7 +108,7 @@ func (ri *replicInfo) copyObject(lom *cluster.LOM, objnameTo string) (copied boo
 			//
 			// TODO -- FIXME: reuse poi.finalize()
 			//
-			ri.t.putMirror(dst)
+			ri.t.putMirror(context.Background(), dst)
 		}
 	}
 	return
@@ -152,10 +153,17 @@ func (ri *replicInfo) putRemote(lom *cluster.LOM, objnameTo string, si *cluster.
 	req.Header.Set(cmn.HeaderObjVersion, lom.Version())
 	timeInt := lom.AtimeUnix()
 	req.Header.Set(cmn.HeaderObjAtime, strconv.FormatInt(timeInt, 10))
+	req.Header.Set(cmn.HeaderCallerID, ri.t.si.ID())
+	req.Header.Set(cmn.HeaderCallerName, ri.t.si.Name())
+	signCall(req, ri.t.si.ID(), intraUnsignedBody) // object content is not signed
 
 	resp, err1 := ri.t.httpclientGetPut.Do(req)
 	if err1 != nil {
 		err = fmt.Errorf("failed to PUT to %s, err: %v", reqArgs.URL(), err1)
+		return
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		err = fmt.Errorf("failed to PUT to %s, status %d", reqArgs.URL(), resp.StatusCode)
 	} else {
 		copied = true
 	}
@@ -31,6 +31,7 @@ import (
 	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/reb"
 	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/tracing"
 	"github.com/NVIDIA/aistore/xaction"
 	jsoniter "github.com/json-iterator/go"
 )
@@ -204,6 +205,13 @@ func (t *targetrunner) httpdaeput(w http.ResponseWriter, r *http.Request) {
 		}
 	case cmn.ActShutdown:
 		_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+	case cmn.ActDecommission:
+		xactID, err := t.decommission()
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		w.Write([]byte(xactID))
 	case cmn.ActXactStart, cmn.ActXactStop:
 		var (
 			bck    *cluster.Bck
@@ -305,7 +313,8 @@ func (t *targetrunner) httpdaeget(w http.ResponseWriter, r *http.Request) {
 	getWhat := r.URL.Query().Get(cmn.URLParamWhat)
 	httpdaeWhat := "httpdaeget-" + getWhat
 	switch getWhat {
-	case cmn.GetWhatConfig, cmn.GetWhatSmap, cmn.GetWhatBMD, cmn.GetWhatSmapVote, cmn.GetWhatSnode:
+	case cmn.GetWhatConfig, cmn.GetWhatSmap, cmn.GetWhatBMD, cmn.GetWhatSmapVote, cmn.GetWhatSnode,
+		cmn.GetWhatAudit:
 		t.httprunner.httpdaeget(w, r)
 	case cmn.GetWhatSysInfo:
 		body := cmn.MustMarshal(cmn.TSysInfo{SysInfo: daemon.gmm.FetchSysInfo(), FSInfo: fs.Mountpaths.FetchFSInfo()})
@@ -402,6 +411,28 @@ func (t *targetrunner) httpdaeget(w http.ResponseWriter, r *http.Request) {
 		diskStats := fs.Mountpaths.GetSelectedDiskStats()
 		body := cmn.MustMarshal(diskStats)
 		t.writeJSON(w, r, body, httpdaeWhat)
+	case cmn.GetWhatBucketStats:
+		query := r.URL.Query()
+		bck, err := newBckFromQuery(query.Get(cmn.URLParamBucket), query)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body := cmn.MustMarshal(getstorstatsrunner().GetBucketStats(bck.Bck))
+		t.writeJSON(w, r, body, httpdaeWhat)
+	case cmn.GetWhatRebPlan:
+		smap := &cluster.Smap{}
+		if cmn.ReadJSON(w, r, &smap.Tmap) != nil {
+			return
+		}
+		smap.InitDigests()
+		plan, err := t.rebManager.Plan(smap)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		body := cmn.MustMarshal(plan)
+		t.writeJSON(w, r, body, httpdaeWhat)
 	default:
 		t.httprunner.httpdaeget(w, r)
 	}
@@ -563,15 +594,16 @@ func (t *targetrunner) userFromRequest(header http.Header) (*authRec, error) {
 // (at this moment userID is enough) from HTTP request header: looks for
 // 'Authorization' header and decrypts it.
 // Extracted user information is put to context that is passed to all consumers
-func (t *targetrunner) contextWithAuth(header http.Header) context.Context {
-	ct := context.Background()
+// (along with the caller's trace context, if any - see tracing.FromRequest)
+func (t *targetrunner) contextWithAuth(r *http.Request) context.Context {
+	ct := tracing.FromRequest(context.Background(), r)
 	config := cmn.GCO.Get()
 
 	if config.Auth.CredDir == "" || !config.Auth.Enabled {
 		return ct
 	}
 
-	user, err := t.userFromRequest(header)
+	user, err := t.userFromRequest(r.Header)
 	if err != nil {
 		glog.Errorf("Failed to extract token: %v", err)
 		return ct
@@ -816,6 +848,18 @@ func (t *targetrunner) _recvBMD(newBMD *bucketMD, msgInt *actionMsgInternal, tag
 
 	t.owner.bmd.Unlock() // unlocked ================
 
+	// per-bucket stats of all providers: drop destroyed and evicted buckets
+	goneBcks := make([]cmn.Bck, 0, 4)
+	bmd.Range(nil, nil, func(obck *cluster.Bck) bool {
+		if !newBMD.Exists(obck, obck.Props.BID) {
+			goneBcks = append(goneBcks, obck.Bck)
+		}
+		return false
+	})
+	if len(goneBcks) > 0 {
+		getstorstatsrunner().DelBuckets(goneBcks...)
+	}
+
 	if destroyErrs != "" {
 		glog.Errorf("%s: %s - destroy err: %s", t.si, failed, destroyErrs)
 		err = errors.New(createErrs)
@@ -1212,6 +1256,23 @@ func (t *targetrunner) healthHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GET /metrics (Prometheus)
+func (t *targetrunner) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		t.invalmsghdlr(w, r, "invalid method for /metrics path", http.StatusBadRequest)
+		return
+	}
+	pw := stats.NewPromWriter(t.si.ID())
+	getstorstatsrunner().Prometheus(pw)
+	if xacts, err := xaction.Registry.GetStats("", nil, false); err == nil {
+		pw.AddXactStats(xacts)
+	}
+	w.Header().Set("Content-Type", stats.PromContentType)
+	if _, err := pw.WriteTo(w); err != nil {
+		glog.Errorf("%s: failed to write metrics, err: %v", t.si, err)
+	}
+}
+
 func (t *targetrunner) pollClusterStarted(timeout time.Duration) {
 	for i := 1; ; i++ {
 		time.Sleep(time.Duration(i) * time.Second)
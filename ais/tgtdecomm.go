@@ -0,0 +1,330 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/xaction"
+)
+
+// Graceful decommission: prior to requesting the target to evacuate its data
+// the primary marks it as leaving in the Smap. A leaving target remains a
+// member of the cluster and keeps serving the objects it still has (see GFN
+// in tryRestoreObject) but it does not own any objects and rejects PUTs.
+//
+// The target pushes its objects to their owners and verifies each copy (size
+// and checksum). Objects PUT while the first traversal is in progress are
+// picked up by the next pass - each subsequent pass starts after all PUTs
+// admitted before the Smap update have completed. The evacuation succeeds when
+// a pass finds nothing to send, and fails if the last of decommMaxPasses does.
+// Upon success the target requests the primary to unregister it; upon failure -
+// to cancel the decommission. Either way, the outcome is reported by the
+// decommission xaction (`ais show xaction decommission`).
+//
+// Cloud buckets are not evacuated - cached objects can always be cold-GET
+// again; EC-enabled buckets are restored by EC rebalance.
+
+const (
+	decommMaxPasses = 3
+	decommQuiesce   = time.Minute // max time to wait for PUTs in progress
+)
+
+// decommission xaction stages
+const (
+	decommStageEvacuate   = "evacuating"
+	decommStageUnregister = "unregistering"
+	decommStageDone       = "done"
+)
+
+type (
+	evacuator struct {
+		t        *targetrunner
+		tname    string
+		xact     *xaction.Decommission
+		since    time.Time                         // non-zero: send only objects modified at or after
+		pass     func() (*cmn.RebPlan, error)      // one traversal of all mountpaths (see traverse)
+		quiesce  func(timeout time.Duration) error // wait for PUTs in progress
+		finalize func(ok bool) error               // request the primary to unregister (or cancel)
+	}
+	evacJogger struct {
+		ev     *evacuator
+		ri     *replicInfo
+		plan   *cmn.RebPlan
+		errCnt atomic.Int64
+	}
+)
+
+// decommission starts evacuating the data and returns the ID of the xaction
+func (t *targetrunner) decommission() (string, error) {
+	smap := t.owner.smap.get()
+	if smap.GetTarget(t.si.ID()) == nil {
+		return "", fmt.Errorf("%s: not present in the %s", t.si, smap)
+	}
+	if !smap.IsLeaving(t.si.ID()) {
+		return "", fmt.Errorf("%s: not marked as leaving in the %s", t.si, smap)
+	}
+	xact := xaction.Registry.RenewDecommission()
+	if xact == nil {
+		return "", fmt.Errorf("%s: %s is already in progress", t.si, cmn.ActDecommission)
+	}
+	ev := newEvacuator(t, xact)
+	go ev.run()
+	return xact.ID(), nil
+}
+
+func newEvacuator(t *targetrunner, xact *xaction.Decommission) *evacuator {
+	ev := &evacuator{t: t, tname: t.si.String(), xact: xact}
+	ev.pass, ev.quiesce, ev.finalize = ev.traverse, ev.waitPuts, ev.requestPrimary
+	return ev
+}
+
+func (ev *evacuator) run() {
+	ev.xact.SetStage(decommStageEvacuate)
+	plan, err := ev.evacuate()
+	if err != nil {
+		glog.Errorf("%s: %s failed: %v", ev.tname, ev.xact, err)
+		ev.xact.Fail(err)
+		if errCancel := ev.finalize(false); errCancel != nil {
+			glog.Errorf("%s: failed to cancel %s, err: %v", ev.tname, cmn.ActDecommission, errCancel)
+		}
+		return
+	}
+	glog.Infof("%s: evacuated %d objects (%s)", ev.tname, plan.Total.Objects, cmn.B2S(plan.Total.Bytes, 2))
+
+	// NOTE: the xaction must finish prior to the unregistration that aborts
+	// all running xactions (see handleUnregisterReq)
+	ev.xact.SetStage(decommStageUnregister)
+	ev.xact.EndTime(time.Now())
+	if err := ev.finalize(true); err != nil {
+		glog.Errorf("%s: failed to unregister, err: %v", ev.tname, err)
+		ev.xact.Fail(fmt.Errorf("evacuated all objects but failed to unregister: %v", err))
+		return
+	}
+	ev.xact.SetStage(decommStageDone)
+}
+
+func (ev *evacuator) evacuate() (*cmn.RebPlan, error) {
+	total := cmn.NewRebPlan()
+	for pass := 1; pass <= decommMaxPasses; pass++ {
+		if pass > 1 {
+			if err := ev.quiesce(decommQuiesce); err != nil {
+				return nil, err
+			}
+		}
+		started := time.Now()
+		plan, err := ev.pass()
+		if err != nil {
+			return nil, err
+		}
+		total.Merge(plan)
+		glog.Infof("%s: %s pass #%d - sent %d objects (%s)", ev.tname, ev.xact,
+			pass, plan.Total.Objects, cmn.B2S(plan.Total.Bytes, 2))
+		if plan.Total.Objects == 0 {
+			return total, nil
+		}
+		ev.since = started
+	}
+	return nil, fmt.Errorf("%s: objects remain after the last (#%d) pass - still being modified?",
+		ev.tname, decommMaxPasses)
+}
+
+// waitPuts waits for the PUTs admitted prior to the Smap update that marked
+// this target as leaving (see httpobjput) - any later PUT is rejected.
+func (ev *evacuator) waitPuts(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for ev.t.putsInFlight.Load() > 0 {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s: timed out waiting for %d PUT(s) in progress", ev.tname, ev.t.putsInFlight.Load())
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
+
+func (ev *evacuator) requestPrimary(ok bool) error {
+	var (
+		smap = ev.t.owner.smap.get()
+		args = callArgs{si: smap.ProxySI, timeout: cmn.GCO.Get().Timeout.CplaneOperation}
+	)
+	if ok {
+		args.req = cmn.ReqArgs{
+			Method: http.MethodDelete,
+			Path:   cmn.URLPath(cmn.Version, cmn.Cluster, cmn.Daemon, ev.t.si.ID()),
+		}
+	} else {
+		args.req = cmn.ReqArgs{
+			Method: http.MethodPut,
+			Path:   cmn.URLPath(cmn.Version, cmn.Cluster),
+			Body:   cmn.MustMarshal(&cmn.ActionMsg{Action: cmn.ActDecommCancel, Name: ev.t.si.ID()}),
+		}
+	}
+	res := ev.t.call(args)
+	if res.err != nil {
+		return fmt.Errorf("%v(%s)", res.err, res.details)
+	}
+	return nil
+}
+
+func (ev *evacuator) traverse() (*cmn.RebPlan, error) {
+	var (
+		wg       = &sync.WaitGroup{}
+		smap     = ev.t.owner.smap.get()
+		paths, _ = fs.Mountpaths.Get()
+		joggers  = make([]*evacJogger, 0, len(paths))
+		plan     = cmn.NewRebPlan()
+		errCnt   int64
+	)
+	// canceled in the meantime
+	if !smap.IsLeaving(ev.t.si.ID()) {
+		return nil, fmt.Errorf("%s: no longer marked as leaving in the %s", ev.tname, smap)
+	}
+	for _, mpathInfo := range paths {
+		ej := &evacJogger{
+			ev:   ev,
+			ri:   &replicInfo{t: ev.t, smap: smap, localOnly: false},
+			plan: cmn.NewRebPlan(),
+		}
+		joggers = append(joggers, ej)
+		wg.Add(1)
+		go ej.jog(mpathInfo, wg)
+	}
+	wg.Wait()
+	for _, ej := range joggers {
+		plan.Merge(ej.plan)
+		errCnt += ej.errCnt.Load()
+	}
+	if ev.xact.Aborted() {
+		return nil, cmn.NewAbortedErrorDetails("traversal", ev.xact.String())
+	}
+	if errCnt > 0 {
+		return nil, fmt.Errorf("%s: failed to evacuate %d object(s)", ev.tname, errCnt)
+	}
+	return plan, nil
+}
+
+func (ej *evacJogger) jog(mpathInfo *fs.MountpathInfo, wg *sync.WaitGroup) {
+	defer wg.Done()
+	opts := &fs.Options{
+		Mpath:    mpathInfo,
+		Bck:      cmn.Bck{Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal},
+		CTs:      []string{fs.ObjectType},
+		Callback: ej.walk,
+		Sorted:   false,
+	}
+	if err := fs.Walk(opts); err != nil {
+		glog.Errorf("%s: failed to traverse %s, err: %v", ej.ev.tname, mpathInfo, err)
+		ej.errCnt.Inc()
+	}
+}
+
+func (ej *evacJogger) walk(fqn string, de fs.DirEntry) error {
+	if ej.ev.xact.Aborted() {
+		return cmn.NewAbortedErrorDetails("traversal", ej.ev.xact.String())
+	}
+	if !ej.ev.t.owner.smap.get().IsLeaving(ej.ev.t.si.ID()) {
+		return fmt.Errorf("%s: %s canceled", ej.ev.tname, cmn.ActDecommission)
+	}
+	if de.IsDir() {
+		return nil
+	}
+	lom := &cluster.LOM{T: ej.ev.t, FQN: fqn}
+	if err := lom.Init(cmn.Bck{}); err != nil {
+		if cmn.IsErrBucketLevel(err) {
+			return err
+		}
+		if glog.FastV(4, glog.SmoduleAIS) {
+			glog.Warningf("%s, err %s - skipping...", lom, err)
+		}
+		return nil
+	}
+	if lom.Bck().Props.EC.Enabled {
+		return filepath.SkipDir
+	}
+	if !ej.ev.since.IsZero() {
+		finfo, err := os.Stat(fqn)
+		if err != nil || finfo.ModTime().Before(ej.ev.since) {
+			return nil
+		}
+	}
+	if err := lom.Load(); err != nil {
+		return nil
+	}
+	// mirrored copies are restored by the new owner (see putMirror)
+	if lom.IsCopy() {
+		return nil
+	}
+	tsi, err := cluster.HrwTarget(lom.Uname(), &ej.ri.smap.Smap)
+	if err != nil {
+		return err
+	}
+	ej.ri.bckTo = lom.Bck()
+	copied, err := ej.ri.copyObject(lom, lom.Objname)
+	if err == nil && copied {
+		err = ej.verify(lom, tsi)
+	}
+	if err != nil {
+		if cmn.IsObjNotExist(err) { // deleted in the meantime
+			return nil
+		}
+		glog.Errorf("%s: failed to evacuate %s => %s, err: %v", ej.ev.tname, lom, tsi, err)
+		ej.errCnt.Inc()
+		return nil
+	}
+	if copied {
+		ej.plan.Add(lom.Bck().Bck.String(), tsi.ID(), lom.Size())
+		ej.ev.xact.ObjectsInc()
+		ej.ev.xact.BytesAdd(lom.Size())
+	}
+	return nil
+}
+
+// verify makes sure the new owner has the object intact
+func (ej *evacJogger) verify(lom *cluster.LOM, tsi *cluster.Snode) error {
+	query := cmn.AddBckToQuery(nil, lom.Bck().Bck)
+	if query == nil {
+		query = make(map[string][]string, 1)
+	}
+	query.Set(cmn.URLParamSilent, "true")
+	res := ej.ev.t.call(callArgs{
+		si: tsi,
+		req: cmn.ReqArgs{
+			Method: http.MethodHead,
+			Path:   cmn.URLPath(cmn.Version, cmn.Objects, lom.BckName(), lom.Objname),
+			Query:  query,
+		},
+		timeout: lom.Config().Timeout.CplaneOperation,
+	})
+	if res.err != nil {
+		return fmt.Errorf("failed to verify %s at %s: %v", lom, tsi, res.err)
+	}
+	return verifyEvacuated(lom, lom.Size(), lom.Cksum(), res.header)
+}
+
+// verifyEvacuated compares the size and the checksum of the local object with
+// those reported by the new owner (HEAD(object))
+func verifyEvacuated(lom fmt.Stringer, size int64, cksum *cmn.Cksum, hdr http.Header) error {
+	if remoteSize, err := strconv.ParseInt(hdr.Get(cmn.HeaderObjSize), 10, 64); err != nil || remoteSize != size {
+		return fmt.Errorf("%s: size mismatch (%d vs %q)", lom, size, hdr.Get(cmn.HeaderObjSize))
+	}
+	if cksum == nil || cksum.Value() == "" {
+		return nil
+	}
+	if remote := hdr.Get(cmn.HeaderObjCksumVal); remote != cksum.Value() {
+		return fmt.Errorf("%s: checksum mismatch (%s vs %q)", lom, cksum, remote)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+	"github.com/NVIDIA/aistore/xaction"
+)
+
+// stub evacuator: each pass "sends" the next number of objects from the list
+type stubEvac struct {
+	ev        *evacuator
+	sent      []int64
+	passes    int
+	quiesced  int
+	finalized []bool
+}
+
+func newStubEvac(sent ...int64) *stubEvac {
+	s := &stubEvac{sent: sent}
+	s.ev = &evacuator{
+		tname: "t[stub]",
+		xact:  &xaction.Decommission{XactBase: *cmn.NewXactBase("stub", cmn.ActDecommission)},
+	}
+	s.ev.pass = func() (*cmn.RebPlan, error) {
+		plan := cmn.NewRebPlan()
+		if s.passes < len(s.sent) {
+			for i := int64(0); i < s.sent[s.passes]; i++ {
+				plan.Add("ais/bck", "t2", 1024)
+			}
+		}
+		s.passes++
+		return plan, nil
+	}
+	s.ev.quiesce = func(time.Duration) error { s.quiesced++; return nil }
+	s.ev.finalize = func(ok bool) error { s.finalized = append(s.finalized, ok); return nil }
+	return s
+}
+
+func TestEvacuateConverges(t *testing.T) {
+	s := newStubEvac(10, 2, 0)
+	plan, err := s.ev.evacuate()
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, s.passes == 3, "expected 3 passes, got %d", s.passes)
+	tassert.Errorf(t, s.quiesced == 2, "expected to quiesce before passes #2 and #3, got %d", s.quiesced)
+	tassert.Errorf(t, plan.Total.Objects == 12, "expected 12 objects, got %d", plan.Total.Objects)
+	tassert.Errorf(t, !s.ev.since.IsZero(), "subsequent passes must be incremental")
+}
+
+func TestEvacuateObjectsRemain(t *testing.T) {
+	s := newStubEvac(10, 2, 1)
+	_, err := s.ev.evacuate()
+	tassert.Errorf(t, err != nil, "expected error when the last pass still sends objects")
+	tassert.Errorf(t, s.passes == decommMaxPasses, "expected %d passes, got %d", decommMaxPasses, s.passes)
+}
+
+func TestEvacuateQuiesceFails(t *testing.T) {
+	s := newStubEvac(10, 0)
+	s.ev.quiesce = func(time.Duration) error { return errors.New("PUTs in progress") }
+	_, err := s.ev.evacuate()
+	tassert.Errorf(t, err != nil, "expected error when PUTs do not complete")
+	tassert.Errorf(t, s.passes == 1, "expected no pass after failing to quiesce, got %d", s.passes)
+}
+
+func TestEvacuatorRun(t *testing.T) {
+	s := newStubEvac(5, 0)
+	s.ev.run()
+	stage, errStr := s.ev.xact.Status()
+	tassert.Errorf(t, stage == decommStageDone && errStr == "", "unexpected status: %q, %q", stage, errStr)
+	tassert.Errorf(t, len(s.finalized) == 1 && s.finalized[0], "expected to unregister, got %v", s.finalized)
+	tassert.Errorf(t, s.ev.xact.Finished() && !s.ev.xact.Aborted(), "expected xaction to finish")
+
+	s = newStubEvac(5, 5, 5)
+	s.ev.run()
+	_, errStr = s.ev.xact.Status()
+	tassert.Errorf(t, errStr != "", "expected the failure to be reported")
+	tassert.Errorf(t, len(s.finalized) == 1 && !s.finalized[0], "expected to cancel, got %v", s.finalized)
+	tassert.Errorf(t, s.ev.xact.Aborted(), "expected xaction to abort")
+}
+
+func TestVerifyEvacuated(t *testing.T) {
+	var (
+		cksum = cmn.NewCksum(cmn.ChecksumXXHash, "0123456789abcdef")
+		hdr   = make(http.Header)
+	)
+	hdr.Set(cmn.HeaderObjSize, "1024")
+	hdr.Set(cmn.HeaderObjCksumVal, "0123456789abcdef")
+	tassert.CheckFatal(t, verifyEvacuated(cmn.Bck{Name: "obj"}, 1024, cksum, hdr))
+	tassert.CheckFatal(t, verifyEvacuated(cmn.Bck{Name: "obj"}, 1024, nil, hdr))
+
+	tassert.Errorf(t, verifyEvacuated(cmn.Bck{Name: "obj"}, 1000, cksum, hdr) != nil, "expected size mismatch")
+
+	hdr.Set(cmn.HeaderObjCksumVal, "fedcba9876543210")
+	tassert.Errorf(t, verifyEvacuated(cmn.Bck{Name: "obj"}, 1024, cksum, hdr) != nil, "expected checksum mismatch")
+
+	hdr.Del(cmn.HeaderObjSize)
+	tassert.Errorf(t, verifyEvacuated(cmn.Bck{Name: "obj"}, 1024, nil, hdr) != nil, "expected missing size to fail")
+}
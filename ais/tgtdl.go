@@ -190,7 +190,7 @@ func (t *targetrunner) parseStartDownloadRequest(r *http.Request, id string) (do
 		}
 
 		baseJob := downloader.NewBaseDlJob(id, bck, cloudPayload.Timeout, payload.Description)
-		return downloader.NewCloudBucketDlJob(t.contextWithAuth(r.Header), t, baseJob, cloudPayload.Prefix, cloudPayload.Suffix)
+		return downloader.NewCloudBucketDlJob(t.contextWithAuth(r), t, baseJob, cloudPayload.Prefix, cloudPayload.Suffix)
 	} else {
 		return nil, errors.New("input does not match any of the supported formats (single, range, multi, cloud)")
 	}
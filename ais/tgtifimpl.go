@@ -22,6 +22,7 @@ import (
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/reb"
 	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/tracing"
 	"github.com/NVIDIA/aistore/xaction"
 )
 
@@ -193,6 +194,15 @@ func (t *targetrunner) CopyObject(lom *cluster.LOM, bckTo *cluster.Bck, buf []by
 
 // FIXME: recomputes checksum if called with a bad one (optimize)
 func (t *targetrunner) GetCold(ct context.Context, lom *cluster.LOM, prefetch bool) (err error, errCode int) {
+	var span *tracing.Span
+	ct, span = tracing.Start(ct, "cold GET", tracing.KindInternal)
+	span.SetAttr("object", lom.String())
+	span.SetAttr("prefetch", prefetch)
+	defer func() {
+		if err != cmn.ErrSkip {
+			span.End(err)
+		}
+	}()
 	if prefetch {
 		if !lom.TryLock(true) {
 			glog.Infof("prefetch: cold GET race: %s - skipping", lom)
@@ -246,6 +256,12 @@ func (t *targetrunner) GetCold(ct context.Context, lom *cluster.LOM, prefetch bo
 				stats.NamedVal64{Name: stats.GetColdSize, Value: lom.Size()},
 			)
 		}
+		if vchanged || !crace {
+			t.statsT.AddBucket(lom.Bck().Bck,
+				stats.NamedVal64{Name: stats.GetColdCount, Value: 1},
+				stats.NamedVal64{Name: stats.GetColdSize, Value: lom.Size()},
+			)
+		}
 		lom.DowngradeLock()
 	}
 	return
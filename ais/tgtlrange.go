@@ -233,7 +233,7 @@ func (t *targetrunner) listOperation(r *http.Request, bck *cluster.Bck, listMsg
 
 		// Asynchronously perform function
 		go func() {
-			err := f(t.contextWithAuth(r.Header), objs, bck, listMsg.Deadline, done)
+			err := f(t.contextWithAuth(r), objs, bck, listMsg.Deadline, done)
 			if err != nil {
 				glog.Errorf("Error performing list function: %v", err)
 				t.statsT.Add(stats.ErrListCount, 1)
@@ -257,7 +257,7 @@ func (t *targetrunner) iterateBucketListPages(r *http.Request, bck *cluster.Bck,
 		bucketListPage *cmn.BucketList
 		err            error
 		prefix         = rangeMsg.Prefix
-		ctx            = t.contextWithAuth(r.Header)
+		ctx            = t.contextWithAuth(r)
 		msg            = &cmn.SelectMsg{Prefix: prefix, Props: cmn.GetPropsStatus}
 	)
 
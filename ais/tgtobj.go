@@ -26,6 +26,7 @@ import (
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/reb"
 	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/tracing"
 	"github.com/OneOfOne/xxhash"
 )
 
@@ -121,10 +122,13 @@ func (poi *putObjInfo) putObject() (err error, errCode int) {
 	}
 	if !poi.migrated && !poi.cold {
 		delta := time.Since(poi.started)
-		poi.t.statsT.AddMany(
-			stats.NamedVal64{Name: stats.PutCount, Value: 1},
-			stats.NamedVal64{Name: stats.PutLatency, Value: int64(delta)},
-		)
+		nvs := []stats.NamedVal64{
+			{Name: stats.PutCount, Value: 1},
+			{Name: stats.PutSize, Value: lom.Size()},
+			{Name: stats.PutLatency, Value: int64(delta)},
+		}
+		poi.t.statsT.AddMany(nvs...)
+		poi.t.statsT.AddBucket(lom.Bck().Bck, nvs...)
 		if glog.FastV(4, glog.SmoduleAIS) {
 			glog.Infof("PUT %s: %d µs", lom, int64(delta/time.Microsecond))
 		}
@@ -151,7 +155,7 @@ func (poi *putObjInfo) finalize() (err error, errCode int) {
 		return
 	}
 
-	poi.t.putMirror(poi.lom)
+	poi.t.putMirror(poi.ctx, poi.lom)
 	return
 }
 
@@ -430,7 +434,7 @@ do:
 		if err, errCode := goi.t.GetCold(goi.ctx, goi.lom, false); err != nil {
 			return err, errCode
 		}
-		goi.t.putMirror(goi.lom)
+		goi.t.putMirror(goi.ctx, goi.lom)
 	}
 
 	// 4. get locally and stream back
@@ -481,6 +485,16 @@ func (goi *getObjInfo) tryRestoreObject() (doubleCheck bool, err error, errCode
 	// an object was sliced, neither will ecmanager.RestoreObject(lom)
 	enoughECRestoreTargets := goi.lom.Bprops().EC.RequiredRestoreTargets() <= goi.t.owner.smap.Get().CountTargets()
 
+	// not yet evacuated from the target that is being decommissioned
+	if !ecEnabled {
+		for sid := range smap.Leaving {
+			if lsi := smap.GetTarget(sid); lsi != nil && sid != goi.t.si.ID() && goi.t.LookupRemoteSingle(goi.lom, lsi) {
+				gfnNode = lsi
+				goto gfn
+			}
+		}
+	}
+
 	// cluster-wide lookup ("get from neighbor")
 	aborted, running = reb.IsRebalancing(cmn.ActGlobalReb)
 	if running {
@@ -508,7 +522,7 @@ gfn:
 	}
 
 	// restore from existing EC slices if possible
-	if ecErr := ec.ECM.RestoreObject(goi.lom); ecErr == nil {
+	if ecErr := ec.ECM.RestoreObject(goi.ctx, goi.lom); ecErr == nil {
 		if glog.FastV(4, glog.SmoduleAIS) {
 			glog.Infof("%s: EC-recovered %s", tname, goi.lom)
 		}
@@ -543,6 +557,7 @@ func (goi *getObjInfo) getFromNeighbor(lom *cluster.LOM, tsi *cluster.Snode) (ok
 		return
 	}
 	defer cancel()
+	tracing.Inject(goi.ctx, req.Header)
 
 	resp, err := goi.t.httpclientGetPut.Do(req) // nolint:bodyclose // closed by `poi.putObject`
 	if err != nil {
@@ -630,10 +645,12 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 			return
 		}
 		delta := time.Since(goi.started)
-		goi.t.statsT.AddMany(
-			stats.NamedVal64{Name: stats.GetCount, Value: 1},
-			stats.NamedVal64{Name: stats.GetLatency, Value: int64(delta)},
-		)
+		nvs := []stats.NamedVal64{
+			{Name: stats.GetCount, Value: 1},
+			{Name: stats.GetLatency, Value: int64(delta)},
+		}
+		goi.t.statsT.AddMany(nvs...)
+		goi.t.statsT.AddBucket(goi.lom.Bck().Bck, nvs...)
 		return
 	}
 
@@ -718,11 +735,13 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 		}
 		glog.Infoln(s)
 	}
-	goi.t.statsT.AddMany(
-		stats.NamedVal64{Name: stats.GetThroughput, Value: written},
-		stats.NamedVal64{Name: stats.GetLatency, Value: int64(delta)},
-		stats.NamedVal64{Name: stats.GetCount, Value: 1},
-	)
+	nvs := []stats.NamedVal64{
+		{Name: stats.GetThroughput, Value: written},
+		{Name: stats.GetLatency, Value: int64(delta)},
+		{Name: stats.GetCount, Value: 1},
+	}
+	goi.t.statsT.AddMany(nvs...)
+	goi.t.statsT.AddBucket(goi.lom.Bck().Bck, nvs...)
 	return
 }
 
@@ -780,10 +799,12 @@ func (aoi *appendObjInfo) appendObject() (filePath string, err error, errCode in
 	}
 
 	delta := time.Since(aoi.started)
-	aoi.t.statsT.AddMany(
-		stats.NamedVal64{Name: stats.AppendCount, Value: 1},
-		stats.NamedVal64{Name: stats.AppendLatency, Value: int64(delta)},
-	)
+	nvs := []stats.NamedVal64{
+		{Name: stats.AppendCount, Value: 1},
+		{Name: stats.AppendLatency, Value: int64(delta)},
+	}
+	aoi.t.statsT.AddMany(nvs...)
+	aoi.t.statsT.AddBucket(aoi.lom.Bck().Bck, nvs...)
 	if glog.FastV(4, glog.SmoduleAIS) {
 		glog.Infof("PUT %s: %d µs", aoi.lom, int64(delta/time.Microsecond))
 	}
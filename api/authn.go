@@ -3,6 +3,7 @@ package api
 import (
 	"errors"
 	"net/http"
+	"net/url"
 
 	"github.com/NVIDIA/aistore/cmn"
 	jsoniter "github.com/json-iterator/go"
@@ -13,11 +14,22 @@ type AuthnSpec struct {
 	AdminPassword string
 	UserName      string
 	UserPassword  string
+	Roles         []string        // see cmn.PredefinedRoles
+	Grants        []cmn.AuthGrant // permissions in addition to the roles
+	Service       bool            // service account (no password, uses API keys)
 }
 
 type userRec struct {
-	Name     string `json:"name"`
-	Password string `json:"password"`
+	Name     string          `json:"name"`
+	Password string          `json:"password,omitempty"`
+	Roles    []string        `json:"roles,omitempty"`
+	Grants   []cmn.AuthGrant `json:"grants,omitempty"`
+	Service  bool            `json:"service,omitempty"`
+}
+
+type permsRec struct {
+	Roles  []string        `json:"roles"`
+	Grants []cmn.AuthGrant `json:"grants"`
 }
 
 type loginRec struct {
@@ -29,7 +41,7 @@ type AuthCreds struct {
 }
 
 func AddUser(baseParams BaseParams, spec AuthnSpec) error {
-	req := userRec{Name: spec.UserName, Password: spec.UserPassword}
+	req := userRec{Name: spec.UserName, Password: spec.UserPassword, Roles: spec.Roles, Grants: spec.Grants, Service: spec.Service}
 	msg, err := jsoniter.Marshal(req)
 	if err != nil {
 		return err
@@ -42,6 +54,56 @@ func AddUser(baseParams BaseParams, spec AuthnSpec) error {
 	return err
 }
 
+// UpdateUser replaces user's roles and grants; user's token, if any, gets revoked
+func UpdateUser(baseParams BaseParams, spec AuthnSpec) error {
+	msg, err := jsoniter.Marshal(permsRec{Roles: spec.Roles, Grants: spec.Grants})
+	if err != nil {
+		return err
+	}
+
+	baseParams.Method = http.MethodPut
+	path := cmn.URLPath(cmn.Version, cmn.Users, spec.UserName)
+	optParams := OptionalParams{User: spec.AdminName, Password: spec.AdminPassword}
+	_, err = DoHTTPRequest(baseParams, path, msg, optParams)
+	return err
+}
+
+// ListRoles returns predefined and custom AuthN roles
+func ListRoles(baseParams BaseParams, spec AuthnSpec) ([]*cmn.AuthRole, error) {
+	baseParams.Method = http.MethodGet
+	path := cmn.URLPath(cmn.Version, cmn.Roles)
+	optParams := OptionalParams{User: spec.AdminName, Password: spec.AdminPassword}
+	b, err := DoHTTPRequest(baseParams, path, nil, optParams)
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]*cmn.AuthRole, 0, len(cmn.PredefinedRoles))
+	err = jsoniter.Unmarshal(b, &roles)
+	return roles, err
+}
+
+// AddRole creates a new custom AuthN role or updates an existing one
+func AddRole(baseParams BaseParams, spec AuthnSpec, role *cmn.AuthRole) error {
+	msg, err := jsoniter.Marshal(role)
+	if err != nil {
+		return err
+	}
+	baseParams.Method = http.MethodPost
+	path := cmn.URLPath(cmn.Version, cmn.Roles)
+	optParams := OptionalParams{User: spec.AdminName, Password: spec.AdminPassword}
+	_, err = DoHTTPRequest(baseParams, path, msg, optParams)
+	return err
+}
+
+// DeleteRole deletes a custom AuthN role that is not assigned to any user
+func DeleteRole(baseParams BaseParams, spec AuthnSpec, name string) error {
+	baseParams.Method = http.MethodDelete
+	path := cmn.URLPath(cmn.Version, cmn.Roles, name)
+	optParams := OptionalParams{User: spec.AdminName, Password: spec.AdminPassword}
+	_, err := DoHTTPRequest(baseParams, path, nil, optParams)
+	return err
+}
+
 func DeleteUser(baseParams BaseParams, spec AuthnSpec) error {
 	baseParams.Method = http.MethodDelete
 	path := cmn.URLPath(cmn.Version, cmn.Users, spec.UserName)
@@ -79,3 +141,50 @@ func LoginUser(baseParams BaseParams, spec AuthnSpec) (*AuthCreds, error) {
 	}
 	return token, nil
 }
+
+// CreateAPIKey issues a long-lived token for a service account (see AuthnSpec.Service)
+func CreateAPIKey(baseParams BaseParams, spec AuthnSpec, req *cmn.AuthAPIKeyMsg) (*cmn.AuthAPIKey, error) {
+	msg, err := jsoniter.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	baseParams.Method = http.MethodPost
+	path := cmn.URLPath(cmn.Version, cmn.APIKeys)
+	optParams := OptionalParams{User: spec.AdminName, Password: spec.AdminPassword}
+	b, err := DoHTTPRequest(baseParams, path, msg, optParams)
+	if err != nil {
+		return nil, err
+	}
+	key := &cmn.AuthAPIKey{}
+	if err = jsoniter.Unmarshal(b, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ListAPIKeys returns API keys of a given service account (all accounts if empty);
+// the keys' tokens are not included
+func ListAPIKeys(baseParams BaseParams, spec AuthnSpec, account string) ([]*cmn.AuthAPIKey, error) {
+	baseParams.Method = http.MethodGet
+	path := cmn.URLPath(cmn.Version, cmn.APIKeys)
+	optParams := OptionalParams{User: spec.AdminName, Password: spec.AdminPassword}
+	if account != "" {
+		optParams.Query = url.Values{cmn.URLParamUser: []string{account}}
+	}
+	b, err := DoHTTPRequest(baseParams, path, nil, optParams)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*cmn.AuthAPIKey, 0, 8)
+	err = jsoniter.Unmarshal(b, &keys)
+	return keys, err
+}
+
+// RevokeAPIKey deletes API key and invalidates its token cluster-wide
+func RevokeAPIKey(baseParams BaseParams, spec AuthnSpec, id string) error {
+	baseParams.Method = http.MethodDelete
+	path := cmn.URLPath(cmn.Version, cmn.APIKeys, id)
+	optParams := OptionalParams{User: spec.AdminName, Password: spec.AdminPassword}
+	_, err := DoHTTPRequest(baseParams, path, nil, optParams)
+	return err
+}
@@ -72,6 +72,25 @@ func ResetBucketProps(baseParams BaseParams, bck cmn.Bck, query ...url.Values) e
 // Converts the string type fields returned from the HEAD request to their
 // corresponding counterparts in the BucketProps struct
 func HeadBucket(baseParams BaseParams, bck cmn.Bck, query ...url.Values) (p cmn.BucketProps, err error) {
+	p, _, err = headBucket(baseParams, bck, query...)
+	return
+}
+
+// HeadCloudBucket API
+//
+// Returns the properties of a cloud bucket along with the bucket's actual
+// provider and namespace (that is, the ones configured in the cluster if the
+// specified provider is cmn.Cloud)
+func HeadCloudBucket(baseParams BaseParams, bck cmn.Bck) (p cmn.BucketProps, actual cmn.Bck, err error) {
+	p, hdr, err := headBucket(baseParams, bck)
+	if err != nil {
+		return
+	}
+	actual = cmn.Bck{Name: bck.Name, Provider: p.CloudProvider, Ns: cmn.ParseNsUname(hdr.Get(cmn.HeaderCloudNs))}
+	return
+}
+
+func headBucket(baseParams BaseParams, bck cmn.Bck, query ...url.Values) (p cmn.BucketProps, hdr http.Header, err error) {
 	var (
 		path      = cmn.URLPath(cmn.Version, cmn.Buckets, bck.Name)
 		optParams = OptionalParams{}
@@ -91,10 +110,7 @@ func HeadBucket(baseParams BaseParams, bck cmn.Bck, query ...url.Values) (p cmn.
 	err = cmn.IterFields(&p, func(tag string, field cmn.IterField) (error, bool) {
 		return field.SetValue(r.Header.Get(tag), true /*force*/), false
 	})
-	if err != nil {
-		return p, err
-	}
-	return
+	return p, r.Header, err
 }
 
 // GetBucketNames API
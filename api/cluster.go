@@ -129,6 +129,36 @@ func GetClusterStats(baseParams BaseParams) (clusterStats stats.ClusterStats, er
 	return clusterStats, nil
 }
 
+// GetBucketStats API
+//
+// GetBucketStats retrieves per-bucket stats aggregated across all targets. Buckets are
+// selected by name, provider, and namespace; empty fields match all buckets.
+func GetBucketStats(baseParams BaseParams, bck cmn.Bck) (bucketStats stats.BucketStatsMap, err error) {
+	baseParams.Method = http.MethodGet
+	query := url.Values{cmn.URLParamWhat: []string{cmn.GetWhatBucketStats}}
+	if bck.Name != "" {
+		query.Set(cmn.URLParamBucket, bck.Name)
+	}
+	query = cmn.AddBckToQuery(query, bck)
+	path := cmn.URLPath(cmn.Version, cmn.Cluster)
+	params := OptionalParams{Query: query}
+
+	resp, err := doHTTPRequestGetResp(baseParams, path, nil, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err = jsoniter.Unmarshal(body, &bucketStats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bucket stats, err: %v", err)
+	}
+	return bucketStats, nil
+}
+
 func GetTargetDiskStats(baseParams BaseParams, targetID string) (map[string]*ios.SelectedDiskStats, error) {
 	baseParams.Method = http.MethodGet
 	path := cmn.URLPath(cmn.Version, cmn.Reverse, cmn.Daemon)
@@ -156,6 +186,34 @@ func GetTargetDiskStats(baseParams BaseParams, targetID string) (map[string]*ios
 	return diskStats, nil
 }
 
+// GetRebalancePlan API
+//
+// GetRebalancePlan computes, without moving anything, the number of objects and
+// bytes that global rebalance would migrate if the given targets were added to
+// and/or removed from the current cluster map
+func GetRebalancePlan(baseParams BaseParams, msg *cmn.RebPlanMsg) (plan cmn.ClusterRebPlan, err error) {
+	baseParams.Method = http.MethodGet
+	path := cmn.URLPath(cmn.Version, cmn.Cluster)
+	params := OptionalParams{Query: url.Values{cmn.URLParamWhat: []string{cmn.GetWhatRebPlan}}}
+	body := cmn.MustMarshal(msg)
+
+	resp, err := doHTTPRequestGetResp(baseParams, path, body, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	err = jsoniter.Unmarshal(respBody, &plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rebalance plan, err: %v", err)
+	}
+	return plan, nil
+}
+
 // RegisterNode API
 //
 // Registers an existing node to the clustermap.
@@ -180,6 +238,27 @@ func UnregisterNode(baseParams BaseParams, unregisterSID string) error {
 	return err
 }
 
+// DecommissionNode API
+//
+// Gracefully removes a target from the clustermap: the target stops receiving
+// new objects and migrates all its objects to the remaining targets; it is
+// unregistered only upon successful (and verified) completion of the migration.
+// The call returns the ID of the target's decommission xaction as soon as the
+// decommission is started - use the xaction stats to poll for its outcome.
+func DecommissionNode(baseParams BaseParams, sid string) (xactID string, err error) {
+	msg, err := jsoniter.Marshal(cmn.ActionMsg{Action: cmn.ActDecommission, Name: sid})
+	if err != nil {
+		return "", err
+	}
+	baseParams.Method = http.MethodPut
+	path := cmn.URLPath(cmn.Version, cmn.Cluster)
+	respBody, err := DoHTTPRequest(baseParams, path, msg)
+	if err != nil {
+		return "", err
+	}
+	return string(respBody), nil
+}
+
 // SetPrimaryProxy API
 //
 // Given a daemonID, it sets that corresponding proxy as the primary proxy of the cluster
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/NVIDIA/aistore/audit"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/stats"
@@ -206,3 +207,25 @@ func SetDaemonConfig(baseParams BaseParams, nodeID string, nvs cmn.SimpleKVs) er
 	_, err := DoHTTPRequest(baseParams, path, nil, optParams)
 	return err
 }
+
+// GetAuditLog API
+//
+// Returns the records of the audit log of a given node (proxy or, if enabled, target)
+// that match the query, oldest first
+func GetAuditLog(baseParams BaseParams, nodeID string, query *audit.Query) (recs []*audit.Record, err error) {
+	baseParams.Method = http.MethodGet
+	path := cmn.URLPath(cmn.Version, cmn.Reverse, cmn.Daemon)
+	q := query.Values()
+	q.Set(cmn.URLParamWhat, cmn.GetWhatAudit)
+	params := OptionalParams{
+		Query:  q,
+		Header: http.Header{cmn.HeaderNodeID: []string{nodeID}},
+	}
+
+	b, err := DoHTTPRequest(baseParams, path, nil, params)
+	if err != nil {
+		return nil, err
+	}
+	err = jsoniter.Unmarshal(b, &recs)
+	return
+}
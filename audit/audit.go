@@ -0,0 +1,209 @@
+// Package audit provides structured (JSONL) audit log of the user-initiated
+// control-plane and data-plane actions, with size-based rotation and queries.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package audit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Audit log is a set of files in the configured directory:
+//   audit.<node ID>.log                - current log
+//   audit.<node ID>.<rotated at>.log   - rotated logs (UTC timestamp), oldest first lexicographically
+// Each line is a JSON-encoded Record.
+
+const (
+	filePrefix = "audit."
+	fileSuffix = ".log"
+	rotatedFmt = "20060102-150405.000000"
+)
+
+type (
+	// Record is a single audited request
+	Record struct {
+		Time     time.Time `json:"time"`
+		Node     string    `json:"node"`
+		User     string    `json:"user,omitempty"`    // as per AuthN token (empty when AuthN is disabled)
+		ClientIP string    `json:"client_ip"`         // remote address of the request
+		Method   string    `json:"method"`            // HTTP method
+		Path     string    `json:"path"`              // URL path
+		Action   string    `json:"action,omitempty"`  // cmn.ActionMsg.Action, if any
+		Bucket   *cmn.Bck  `json:"bucket,omitempty"`  // bucket, if any
+		Object   string    `json:"object,omitempty"`  // object name, if any
+		Status   int       `json:"status"`            // HTTP status of the response
+		Error    string    `json:"error,omitempty"`   // error message (when failed)
+		Latency  int64     `json:"latency_ns,string"` // time to execute the request
+	}
+
+	logger struct {
+		mu       sync.Mutex
+		dir      string
+		node     string
+		fh       *os.File
+		size     int64
+		maxSize  int64
+		maxFiles int
+	}
+)
+
+var (
+	// NOTE: initialized once at startup, prior to serving requests; Stop
+	// may race with the requests in progress
+	lg atomic.Pointer // *logger
+)
+
+func current() *logger { return (*logger)(lg.Load()) }
+
+func Init(conf *cmn.AuditConf, node string) (err error) {
+	if !conf.Enabled {
+		return
+	}
+	if err = cmn.CreateDir(conf.Dir); err != nil {
+		return
+	}
+	l := &logger{dir: conf.Dir, node: node, maxSize: conf.MaxSize, maxFiles: conf.MaxFiles}
+	if err = l.open(); err != nil {
+		return
+	}
+	lg.Store(unsafe.Pointer(l))
+	glog.Infof("audit: %s => %s", node, l.fname())
+	return
+}
+
+func Stop() {
+	l := current()
+	if l == nil {
+		return
+	}
+	lg.Store(nil)
+	l.mu.Lock()
+	if l.fh != nil {
+		if err := l.fh.Close(); err != nil {
+			glog.Errorf("audit: %v", err)
+		}
+		l.fh = nil
+	}
+	l.mu.Unlock()
+}
+
+func Enabled() bool { return current() != nil }
+
+// Add appends the record to the log; failure to write is logged but otherwise ignored
+func Add(rec *Record) {
+	l := current()
+	if l == nil {
+		return
+	}
+	rec.Node = l.node
+	b, err := jsoniter.Marshal(rec)
+	cmn.AssertNoErr(err)
+	b = append(b, '\n')
+
+	l.mu.Lock()
+	if l.fh == nil { // stopped
+		l.mu.Unlock()
+		return
+	}
+	n, err := l.fh.Write(b)
+	l.size += int64(n)
+	if err == nil && l.size >= l.maxSize {
+		err = l.rotate()
+	}
+	l.mu.Unlock()
+	if err != nil {
+		glog.Errorf("audit: %v", err)
+	}
+}
+
+//
+// logger
+//
+
+func (l *logger) fname() string { return filepath.Join(l.dir, filePrefix+l.node+fileSuffix) }
+
+func (l *logger) open() error {
+	fh, err := os.OpenFile(l.fname(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	finfo, err := fh.Stat()
+	if err != nil {
+		fh.Close()
+		return err
+	}
+	l.fh, l.size = fh, finfo.Size()
+	return nil
+}
+
+// under lock
+func (l *logger) rotate() error {
+	if err := l.fh.Close(); err != nil {
+		return err
+	}
+	l.fh = nil
+	rotated := filepath.Join(l.dir, filePrefix+l.node+"."+time.Now().UTC().Format(rotatedFmt)+fileSuffix)
+	errRename := os.Rename(l.fname(), rotated)
+	if err := l.open(); err != nil {
+		return err
+	}
+	if errRename != nil {
+		return errRename
+	}
+	rotatedNames, err := l.rotatedNames()
+	if err != nil {
+		return err
+	}
+	for len(rotatedNames) > l.maxFiles {
+		if err := os.Remove(filepath.Join(l.dir, rotatedNames[0])); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		rotatedNames = rotatedNames[1:]
+	}
+	return nil
+}
+
+// rotated logs, oldest first
+func (l *logger) rotatedNames() (names []string, err error) {
+	var (
+		finfos  []os.FileInfo
+		prefix  = filePrefix + l.node + "."
+		current = filePrefix + l.node + fileSuffix
+	)
+	if finfos, err = ioutil.ReadDir(l.dir); err != nil {
+		return
+	}
+	for _, finfo := range finfos {
+		name := finfo.Name()
+		if name == current || finfo.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// rotation time from the rotated log's name
+func (l *logger) rotatedAt(name string) (time.Time, error) {
+	ts := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix+l.node+"."), fileSuffix)
+	t, err := time.Parse(rotatedFmt, ts)
+	if err != nil {
+		return t, fmt.Errorf("invalid rotated audit log name %q", name)
+	}
+	return t, nil
+}
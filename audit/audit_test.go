@@ -0,0 +1,125 @@
+// Package audit provides structured (JSONL) audit log of the user-initiated
+// control-plane and data-plane actions, with size-based rotation and queries.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package audit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestRotateAndFind(t *testing.T) {
+	const (
+		numRecs  = 200
+		maxFiles = 2
+	)
+	dir, err := ioutil.TempDir("", "audit")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+	conf := &cmn.AuditConf{Enabled: true, Dir: dir, MaxSize: 4 * cmn.KiB, MaxFiles: maxFiles}
+	tassert.CheckFatal(t, Init(conf, "p1"))
+	defer Stop()
+
+	started := time.Now()
+	for i := 0; i < numRecs; i++ {
+		rec := &Record{
+			Time:     time.Now(),
+			User:     "alice",
+			ClientIP: "10.0.0.1",
+			Method:   http.MethodDelete,
+			Path:     "/v1/objects/abc/obj",
+			Action:   cmn.ActDelete,
+			Bucket:   &cmn.Bck{Name: "abc", Provider: cmn.ProviderAIS},
+			Object:   fmt.Sprintf("obj-%d", i),
+			Status:   http.StatusOK,
+		}
+		if i%2 == 1 {
+			rec.User, rec.Bucket.Name = "bob", "xyz"
+		}
+		Add(rec)
+	}
+
+	names, err := current().rotatedNames()
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(names) == maxFiles, "expected %d rotated logs, got %d", maxFiles, len(names))
+
+	// most recent records survive the rotation
+	recs, err := Find(&Query{Limit: 10})
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(recs) == 10, "expected 10 records, got %d", len(recs))
+	tassert.Errorf(t, recs[9].Object == fmt.Sprintf("obj-%d", numRecs-1), "expected the last record, got %s", recs[9].Object)
+	tassert.Errorf(t, recs[0].Node == "p1", "expected node ID, got %q", recs[0].Node)
+
+	recs, err = Find(&Query{User: "bob", Bucket: "xyz", Since: started})
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(recs) > 0, "expected some records")
+	for i, rec := range recs {
+		tassert.Errorf(t, rec.User == "bob" && rec.Bucket.Name == "xyz", "unexpected record %+v", rec)
+		if i > 0 {
+			tassert.Errorf(t, !rec.Time.Before(recs[i-1].Time), "expected records in chronological order")
+		}
+	}
+
+	recs, err = Find(&Query{Until: started})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(recs) == 0, "expected no records, got %d", len(recs))
+}
+
+func TestQueryValues(t *testing.T) {
+	q := &Query{
+		Since:    time.Now().Add(-time.Hour).Round(0),
+		Until:    time.Now().Round(0),
+		User:     "alice",
+		Action:   cmn.ActDestroyLB,
+		Bucket:   "abc",
+		Provider: cmn.ProviderAIS,
+		Limit:    100,
+	}
+	q2, err := QueryFromValues(q.Values())
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, q.Since.Equal(q2.Since) && q.Until.Equal(q2.Until), "time interval mismatch: %+v vs %+v", q, q2)
+	q2.Since, q2.Until = q.Since, q.Until
+	tassert.Errorf(t, *q == *q2, "expected %+v, got %+v", q, q2)
+
+	_, err = QueryFromValues(map[string][]string{cmn.URLParamLimit: {"-1"}})
+	tassert.Errorf(t, err != nil, "expected invalid limit")
+}
+
+func TestDisabled(t *testing.T) {
+	Add(&Record{Time: time.Now()}) // no-op
+	_, err := Find(&Query{})
+	tassert.Errorf(t, err == ErrDisabled, "expected %v, got %v", ErrDisabled, err)
+}
+
+func TestStopWhileAdding(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+	conf := &cmn.AuditConf{Enabled: true, Dir: dir, MaxSize: cmn.MiB, MaxFiles: 1}
+	tassert.CheckFatal(t, Init(conf, "p1"))
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				Add(&Record{Time: time.Now(), Method: http.MethodPut})
+			}
+		}()
+	}
+	time.Sleep(time.Millisecond)
+	Stop()
+	wg.Wait()
+	tassert.Errorf(t, !Enabled(), "expected audit log to be stopped")
+}
@@ -0,0 +1,192 @@
+// Package audit provides structured (JSONL) audit log of the user-initiated
+// control-plane and data-plane actions, with size-based rotation and queries.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package audit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const maxLineSize = 64 * cmn.KiB
+
+// Query selects audit records; empty (zero) fields match all records
+type Query struct {
+	Since    time.Time
+	Until    time.Time
+	User     string
+	Action   string
+	Bucket   string // bucket name
+	Provider string // bucket provider (along with the bucket name)
+	Limit    int    // max number of the most recent records to return
+}
+
+var ErrDisabled = errors.New("audit log is disabled")
+
+// Values encodes the query as URL query parameters (see QueryFromValues)
+func (q *Query) Values() url.Values {
+	values := url.Values{}
+	if !q.Since.IsZero() {
+		values.Set(cmn.URLParamSince, q.Since.Format(time.RFC3339Nano))
+	}
+	if !q.Until.IsZero() {
+		values.Set(cmn.URLParamUntil, q.Until.Format(time.RFC3339Nano))
+	}
+	if q.User != "" {
+		values.Set(cmn.URLParamUser, q.User)
+	}
+	if q.Action != "" {
+		values.Set(cmn.URLParamAction, q.Action)
+	}
+	if q.Bucket != "" {
+		values.Set(cmn.URLParamBucket, q.Bucket)
+	}
+	if q.Provider != "" {
+		values.Set(cmn.URLParamProvider, q.Provider)
+	}
+	if q.Limit > 0 {
+		values.Set(cmn.URLParamLimit, strconv.Itoa(q.Limit))
+	}
+	return values
+}
+
+func QueryFromValues(values url.Values) (q *Query, err error) {
+	q = &Query{
+		User:     values.Get(cmn.URLParamUser),
+		Action:   values.Get(cmn.URLParamAction),
+		Bucket:   values.Get(cmn.URLParamBucket),
+		Provider: values.Get(cmn.URLParamProvider),
+	}
+	if s := values.Get(cmn.URLParamSince); s != "" {
+		if q.Since, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			return nil, fmt.Errorf("invalid %q: %v", cmn.URLParamSince, err)
+		}
+	}
+	if s := values.Get(cmn.URLParamUntil); s != "" {
+		if q.Until, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			return nil, fmt.Errorf("invalid %q: %v", cmn.URLParamUntil, err)
+		}
+	}
+	if s := values.Get(cmn.URLParamLimit); s != "" {
+		if q.Limit, err = strconv.Atoi(s); err != nil || q.Limit < 0 {
+			return nil, fmt.Errorf("invalid %q: %s", cmn.URLParamLimit, s)
+		}
+	}
+	return q, nil
+}
+
+func (q *Query) match(rec *Record) bool {
+	if !q.Since.IsZero() && rec.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && rec.Time.After(q.Until) {
+		return false
+	}
+	if q.User != "" && rec.User != q.User {
+		return false
+	}
+	if q.Action != "" && rec.Action != q.Action {
+		return false
+	}
+	if q.Bucket != "" && (rec.Bucket == nil || rec.Bucket.Name != q.Bucket) {
+		return false
+	}
+	if q.Provider != "" && (rec.Bucket == nil || rec.Bucket.Provider != q.Provider) {
+		return false
+	}
+	return true
+}
+
+// Find returns the records that match the query, oldest first
+func Find(q *Query) (recs []*Record, err error) {
+	var (
+		fhs []*os.File
+		l   = current()
+	)
+	if l == nil {
+		return nil, ErrDisabled
+	}
+	// open all the logs upfront so that concurrent rotation won't interfere
+	l.mu.Lock()
+	fhs, err = l.openAll(q)
+	l.mu.Unlock()
+	if err != nil {
+		return
+	}
+	recs = make([]*Record, 0, 16)
+	for _, fh := range fhs {
+		if err == nil {
+			recs, err = q.scan(fh, recs)
+		}
+		fh.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if q.Limit > 0 && len(recs) > q.Limit {
+		recs = recs[len(recs)-q.Limit:]
+	}
+	return
+}
+
+// under lock
+func (l *logger) openAll(q *Query) (fhs []*os.File, err error) {
+	names, err := l.rotatedNames()
+	if err != nil {
+		return
+	}
+	names = append(names, filepath.Base(l.fname()))
+	for i, name := range names {
+		if i < len(names)-1 && !q.Since.IsZero() {
+			// skip the logs rotated prior to the queried interval
+			if rotatedAt, err := l.rotatedAt(name); err == nil && rotatedAt.Before(q.Since) {
+				continue
+			}
+		}
+		fh, errOpen := os.Open(filepath.Join(l.dir, name))
+		if errOpen != nil {
+			for _, fh := range fhs {
+				fh.Close()
+			}
+			return nil, errOpen
+		}
+		fhs = append(fhs, fh)
+	}
+	return
+}
+
+func (q *Query) scan(fh *os.File, recs []*Record) ([]*Record, error) {
+	scanner := bufio.NewScanner(fh)
+	scanner.Buffer(make([]byte, 0, 4*cmn.KiB), maxLineSize)
+	for scanner.Scan() {
+		rec := &Record{}
+		if err := jsoniter.Unmarshal(scanner.Bytes(), rec); err != nil {
+			// e.g., partially written last line
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("audit: skipping invalid record in %s: %v", fh.Name(), err)
+			}
+			continue
+		}
+		if !q.match(rec) {
+			continue
+		}
+		recs = append(recs, rec)
+		// keep at most 2x limit in memory
+		if q.Limit > 0 && len(recs) >= 2*q.Limit {
+			recs = append(recs[:0], recs[len(recs)-q.Limit:]...)
+		}
+	}
+	return recs, scanner.Err()
+}
@@ -0,0 +1,100 @@
+// Authorization server for AIStore. See /authn/README.md for more info.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Issues a new API key for a service account. API key is a regular (JWT) token
+// that carries the account's permissions, so proxies validate it locally
+func (m *userManager) createAPIKey(msg *cmn.AuthAPIKeyMsg) (*cmn.AuthAPIKey, error) {
+	var (
+		ttl time.Duration
+		err error
+	)
+	if msg.ExpiresIn != "" {
+		if ttl, err = time.ParseDuration(msg.ExpiresIn); err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid API key expiration time %q", msg.ExpiresIn)
+		}
+	}
+
+	var cpy cmn.AuthAPIKey
+	err = m.update(func() error {
+		user, ok := m.Users[msg.Account]
+		if !ok {
+			return fmt.Errorf("service account %s %s", msg.Account, cmn.DoesNotExist)
+		}
+		if !user.Service {
+			return fmt.Errorf("%s is not a service account", msg.Account)
+		}
+
+		key := &cmn.AuthAPIKey{
+			ID:      cmn.GenUUID(),
+			Name:    msg.Name,
+			Account: msg.Account,
+			Created: time.Now(),
+		}
+		expires := key.Created.Add(foreverTokenTime)
+		if ttl != 0 {
+			key.Expires = key.Created.Add(ttl)
+			expires = key.Expires
+		}
+		token, err := m.genToken(user, key.Created, expires, "", key.ID)
+		if err != nil {
+			return err
+		}
+		// the token itself is not stored: the key is revoked by its ID
+		user.Keys = append(user.Keys, key)
+		cpy = *key
+		cpy.Token = token
+		return m.saveUsers()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cpy, nil
+}
+
+// Returns API keys (without tokens) of a given service account or, if the
+// account is empty, of all service accounts
+func (m *userManager) listAPIKeys(account string) []*cmn.AuthAPIKey {
+	keys := make([]*cmn.AuthAPIKey, 0, 8)
+	m.mtx.Lock()
+	for _, user := range m.Users {
+		if account != "" && user.UserID != account {
+			continue
+		}
+		for _, key := range user.Keys {
+			cpy := *key
+			cpy.Token = ""
+			keys = append(keys, &cpy)
+		}
+	}
+	m.mtx.Unlock()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Created.Before(keys[j].Created) })
+	return keys
+}
+
+// Deletes API key and adds its ID to the list of revoked ones (see revoke.go)
+func (m *userManager) revokeAPIKey(id string) error {
+	return m.update(func() error {
+		for _, user := range m.Users {
+			for i, key := range user.Keys {
+				if key.ID != id {
+					continue
+				}
+				user.Keys = append(user.Keys[:i], user.Keys[i+1:]...)
+				m.revoke(nil, map[string]time.Time{key.ID: keyExpires(key)})
+				return m.saveUsers()
+			}
+		}
+		return fmt.Errorf("API key %s %s", id, cmn.DoesNotExist)
+	})
+}
@@ -81,7 +81,15 @@ func main() {
 	proxy := newProxy(smapFile, conf.Proxy.URL)
 
 	dbPath := filepath.Join(conf.ConfDir, userListFile)
-	srv := newAuthServ(newUserManager(dbPath, proxy))
+	mgr := newUserManager(dbPath, proxy)
+	if conf.Auth.ClusterDB {
+		if err := mgr.initClusterDB(); err != nil {
+			glog.Errorf("Failed to sync user list with the cluster, using local copy: %v", err)
+		}
+		go mgr.runDBSync()
+	}
+	go mgr.runRevoker()
+	srv := newAuthServ(mgr)
 	if err := srv.run(); err != nil {
 		glog.Fatalf(err.Error())
 	}
@@ -6,12 +6,22 @@
 package main
 
 import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/NVIDIA/aistore/ais"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/jsp"
+	jwt "github.com/dgrijalva/jwt-go"
+	jsoniter "github.com/json-iterator/go"
 )
 
 const (
@@ -40,7 +50,7 @@ func createUsers(mgr *userManager, t *testing.T) {
 	}
 
 	for idx := range users {
-		err = mgr.addUser(users[idx], passs[idx])
+		err = mgr.addUser(users[idx], passs[idx], nil, nil)
 		if err != nil {
 			t.Errorf("Failed to create a user %s: %v", users[idx], err)
 		}
@@ -72,10 +82,12 @@ func deleteUsers(mgr *userManager, skipNotExist bool, t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
+	os.Remove(mgr.rolesPath())
+	os.Remove(mgr.revokedPath())
 }
 
 func testInvalidUser(mgr *userManager, t *testing.T) {
-	err := mgr.addUser(users[0], passs[1])
+	err := mgr.addUser(users[0], passs[1], nil, nil)
 	if err == nil || !strings.Contains(err.Error(), "already registered") {
 		t.Errorf("User with the existing name %s was created: %v", users[0], err)
 	}
@@ -116,7 +128,7 @@ func testUserDelete(mgr *userManager, t *testing.T) {
 		username = "newuser"
 		userpass = "newpass"
 	)
-	err := mgr.addUser(username, userpass)
+	err := mgr.addUser(username, userpass, nil, nil)
 	if err != nil {
 		t.Errorf("Failed to create a user %s: %v", username, err)
 	}
@@ -293,6 +305,43 @@ func TestManager(t *testing.T) {
 	deleteUsers(mgr, false, t)
 }
 
+func TestLegacyPasswords(t *testing.T) {
+	const (
+		username = "legacy"
+		userpass = "legacypass"
+	)
+	dir, err := ioutil.TempDir("", "authn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fpath := filepath.Join(dir, userListFile)
+	legacy := map[string]*userInfo{
+		username: {UserID: username, Password: base64.StdEncoding.EncodeToString([]byte(userpass))},
+	}
+	if err := jsp.Save(fpath, legacy, jsp.Plain()); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := newUserManager(fpath, &proxy{})
+	if hash := mgr.Users[username].Password; !strings.HasPrefix(hash, "$2") {
+		t.Errorf("Expected password hash, got %q", hash)
+	}
+	if _, err := mgr.issueToken(username, userpass); err != nil {
+		t.Errorf("Failed to log in with legacy password: %v", err)
+	}
+	if _, err := mgr.issueToken(username, "wrong"); err == nil {
+		t.Error("Logged in with invalid password")
+	}
+	saved := make(map[string]*userInfo)
+	if err := jsp.Load(fpath, &saved, jsp.Plain()); err != nil {
+		t.Fatal(err)
+	}
+	if saved[username].Password != mgr.Users[username].Password {
+		t.Error("Expected password hash to be saved")
+	}
+}
+
 func TestToken(t *testing.T) {
 	var (
 		err   error
@@ -329,8 +378,18 @@ func TestToken(t *testing.T) {
 		t.Errorf("Some token generated for incorrect user creds: %v", tokenInval)
 	}
 
+	// the token itself is not stored
+	if strings.Contains(string(cmn.MustMarshal(mgr.tokens)), token) {
+		t.Error("Token must not be stored")
+	}
+
 	// expired token test
-	tokeninfo, ok := mgr.tokens[users[1]]
+	claims, err := tokenClaims(token)
+	if err != nil {
+		t.Fatalf("Invalid token: %v", err)
+	}
+	tokenID, _ := claims["jti"].(string)
+	tokeninfo, ok := mgr.tokens[tokenID]
 	if !ok || tokeninfo == nil {
 		t.Errorf("No token found for %s", users[1])
 	}
@@ -339,7 +398,7 @@ func TestToken(t *testing.T) {
 	}
 	info, err = mgr.userByToken(token)
 	if info != nil || err == nil {
-		t.Errorf("Token %s expected to be expired[%x]: %v", token, info, err)
+		t.Errorf("Token %s expected to be expired[%v]: %v", token, info, err)
 	} else if !strings.Contains(err.Error(), "expire") {
 		t.Errorf("Invalid error(must be 'token expired'): %v", err)
 	}
@@ -365,3 +424,464 @@ func TestToken(t *testing.T) {
 
 	deleteUsers(mgr, false, t)
 }
+
+func tokenGrants(token string, t *testing.T) []cmn.AuthGrant {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(conf.Auth.Secret), nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to parse token: %v", err)
+	}
+	grants := make([]cmn.AuthGrant, 0)
+	if err := jsoniter.Unmarshal(cmn.MustMarshal(claims["perms"]), &grants); err != nil {
+		t.Fatalf("Failed to parse permissions: %v", err)
+	}
+	return grants
+}
+
+func TestPerms(t *testing.T) {
+	const (
+		username = "rbacuser"
+		userpass = "rbacpass"
+		rolename = "bucket-abc-writer"
+	)
+	var (
+		proxy = &proxy{}
+		mgr   = newUserManager(dbPath, proxy)
+		abc   = &cmn.Bck{Name: "abc", Provider: cmn.ProviderAIS}
+		xyz   = &cmn.Bck{Name: "xyz", Provider: cmn.ProviderAIS}
+	)
+	if mgr == nil {
+		t.Fatal("Manager has not been created")
+	}
+	createUsers(mgr, t)
+
+	// default role is read-only
+	if err := mgr.addUser(username, userpass, nil, nil); err != nil {
+		t.Fatalf("Failed to create a user %s: %v", username, err)
+	}
+	token, err := mgr.issueToken(username, userpass)
+	if err != nil {
+		t.Fatalf("Failed to generate token for %s: %v", username, err)
+	}
+	grants := tokenGrants(token, t)
+	if cmn.CheckPerm(grants, abc, cmn.PermRead) != nil || cmn.CheckPerm(grants, abc, cmn.PermWrite) == nil {
+		t.Errorf("Expected read-only permissions, got %+v", grants)
+	}
+
+	// custom role: write to a single bucket
+	role := &cmn.AuthRole{
+		Name:   rolename,
+		Grants: []cmn.AuthGrant{{Bucket: abc.Name, Provider: abc.Provider, Perms: []string{cmn.PermWrite}}},
+	}
+	if err := mgr.addRole(role); err != nil {
+		t.Fatalf("Failed to add role: %v", err)
+	}
+	if err := mgr.updateUserPerms(username, []string{cmn.RoleReadOnly, rolename}, nil); err != nil {
+		t.Fatalf("Failed to update permissions: %v", err)
+	}
+	if _, err := mgr.userByToken(token); err == nil {
+		t.Error("Token must be revoked after updating permissions")
+	}
+	token, err = mgr.issueToken(username, userpass)
+	if err != nil {
+		t.Fatalf("Failed to generate token for %s: %v", username, err)
+	}
+	grants = tokenGrants(token, t)
+	if cmn.CheckPerm(grants, abc, cmn.PermWrite) != nil || cmn.CheckPerm(grants, xyz, cmn.PermWrite) == nil {
+		t.Errorf("Expected write permission for %s only, got %+v", abc, grants)
+	}
+
+	// invalid requests
+	if err := mgr.updateUserPerms(username, []string{"nonexisting"}, nil); err == nil {
+		t.Error("Non-existing role was assigned")
+	}
+	badGrant := cmn.AuthGrant{Bucket: abc.Name, Perms: []string{cmn.PermClusterAdmin}}
+	if err := mgr.updateUserPerms(username, nil, []cmn.AuthGrant{badGrant}); err == nil {
+		t.Error("Bucket-level cluster-admin permission was granted")
+	}
+	if err := mgr.delRole(rolename); err == nil {
+		t.Errorf("Role %q in use was deleted", rolename)
+	}
+	if err := mgr.delRole(cmn.RoleReadOnly); err == nil {
+		t.Error("Predefined role was deleted")
+	}
+
+	if err := mgr.delUser(username); err != nil {
+		t.Errorf("Failed to delete user %s: %v", username, err)
+	}
+	if err := mgr.delRole(rolename); err != nil {
+		t.Errorf("Failed to delete role %q: %v", rolename, err)
+	}
+	deleteUsers(mgr, false, t)
+}
+
+func TestAPIKeys(t *testing.T) {
+	const (
+		account = "ci"
+		keyName = "jenkins"
+	)
+	var (
+		proxy = &proxy{}
+		mgr   = newUserManager(dbPath, proxy)
+		abc   = &cmn.Bck{Name: "abc", Provider: cmn.ProviderAIS}
+	)
+	if mgr == nil {
+		t.Fatal("Manager has not been created")
+	}
+	createUsers(mgr, t)
+
+	if err := mgr.addServiceAccount(account, []string{cmn.RoleReadWrite}, nil); err != nil {
+		t.Fatalf("Failed to create service account %s: %v", account, err)
+	}
+	if token, err := mgr.issueToken(account, ""); err == nil || token != "" {
+		t.Error("Service account must not be able to log in")
+	}
+	if _, err := mgr.createAPIKey(&cmn.AuthAPIKeyMsg{Account: users[0]}); err == nil {
+		t.Errorf("API key was created for regular user %s", users[0])
+	}
+	if _, err := mgr.createAPIKey(&cmn.AuthAPIKeyMsg{Account: account, ExpiresIn: "forever"}); err == nil {
+		t.Error("API key with invalid expiration time was created")
+	}
+
+	key, err := mgr.createAPIKey(&cmn.AuthAPIKeyMsg{Account: account, Name: keyName, ExpiresIn: "24h"})
+	if err != nil {
+		t.Fatalf("Failed to create API key: %v", err)
+	}
+	if key.Token == "" || key.Expires.Sub(key.Created) != 24*time.Hour {
+		t.Errorf("Invalid API key: %+v", key)
+	}
+	grants := tokenGrants(key.Token, t)
+	if cmn.CheckPerm(grants, abc, cmn.PermWrite) != nil || cmn.CheckPerm(grants, abc, cmn.PermAdmin) == nil {
+		t.Errorf("Expected read-write permissions, got %+v", grants)
+	}
+	if stored := mgr.Users[account].Keys; len(stored) != 1 || stored[0].Token != "" {
+		t.Errorf("API key token must not be stored: %+v", stored)
+	}
+	never, err := mgr.createAPIKey(&cmn.AuthAPIKeyMsg{Account: account})
+	if err != nil || !never.Expires.IsZero() {
+		t.Errorf("Failed to create non-expiring API key: %+v, %v", never, err)
+	}
+
+	keys := mgr.listAPIKeys(account)
+	if len(keys) != 2 || keys[0].ID != key.ID || keys[0].Name != keyName || keys[0].Token != "" {
+		t.Errorf("Invalid API key list: %+v", keys)
+	}
+	if keys := mgr.listAPIKeys(users[0]); len(keys) != 0 {
+		t.Errorf("Expected no API keys for %s, got %d", users[0], len(keys))
+	}
+
+	if err := mgr.revokeAPIKey(key.ID); err != nil {
+		t.Errorf("Failed to revoke API key: %v", err)
+	}
+	if err := mgr.revokeAPIKey(key.ID); err == nil {
+		t.Error("API key was revoked twice")
+	}
+	if expires, ok := mgr.revoked.IDs[key.ID]; !ok || !expires.Equal(key.Expires) {
+		t.Errorf("API key %s must be in the revoked list", key.ID)
+	}
+	if keys := mgr.listAPIKeys(""); len(keys) != 1 || keys[0].ID != never.ID {
+		t.Errorf("Invalid API key list: %+v", keys)
+	}
+
+	// changing permissions revokes all the account's keys
+	if err := mgr.updateUserPerms(account, []string{cmn.RoleReadOnly}, nil); err != nil {
+		t.Fatalf("Failed to update permissions: %v", err)
+	}
+	if keys := mgr.listAPIKeys(account); len(keys) != 0 {
+		t.Errorf("Expected no API keys after updating permissions, got %d", len(keys))
+	}
+
+	if err := mgr.delUser(account); err != nil {
+		t.Errorf("Failed to delete service account %s: %v", account, err)
+	}
+	deleteUsers(mgr, false, t)
+}
+
+func TestRevokeRetry(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		fail     = true
+		received *ais.TokenList
+	)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received = &ais.TokenList{}
+		if err := jsoniter.NewDecoder(r.Body).Decode(received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer primary.Close()
+	dir, err := ioutil.TempDir("", "authn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fpath := filepath.Join(dir, userListFile)
+	mgr := newUserManager(fpath, &proxy{URL: primary.URL})
+	key := &cmn.AuthAPIKey{ID: "key-id", Created: time.Now()}
+	mgr.mtx.Lock()
+	mgr.revoke([]string{"token"}, map[string]time.Time{key.ID: keyExpires(key)})
+	mgr.commitRevoked()
+	mgr.mtx.Unlock()
+	if err := mgr.sendRevoked(); err == nil {
+		t.Fatal("Expected error when the primary fails")
+	}
+
+	// pending revocations survive restart
+	mgr = newUserManager(fpath, &proxy{URL: primary.URL})
+	mtx.Lock()
+	fail = false
+	mtx.Unlock()
+	if err := mgr.sendRevoked(); err != nil {
+		t.Fatalf("Failed to send revoked list: %v", err)
+	}
+	if received == nil || len(received.Tokens) != 1 || received.Tokens[0] != "token" {
+		t.Fatalf("Expected the revoked token, got %+v", received)
+	}
+	if expires, ok := received.IDs[key.ID]; !ok || !expires.Equal(keyExpires(key)) {
+		t.Errorf("Expected the revoked API key, got %+v", received.IDs)
+	}
+	if len(mgr.revoked.Tokens) != 0 {
+		t.Errorf("Acknowledged tokens must be removed, got %v", mgr.revoked.Tokens)
+	}
+
+	// nothing to send
+	received = nil
+	if err := mgr.sendRevoked(); err != nil || received != nil {
+		t.Errorf("Expected no request, got %+v, %v", received, err)
+	}
+}
+
+// stub of the primary proxy that keeps AuthN DB (see ais/authndb.go)
+type stubPrimary struct {
+	*httptest.Server
+	mtx       sync.Mutex
+	db        *ais.AuthNDB
+	conflicts int // the number of updates to reject as concurrent
+	fetched   int // the number of times the database has been sent
+}
+
+func newStubPrimary() *stubPrimary {
+	p := &stubPrimary{}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mtx.Lock()
+		defer p.mtx.Unlock()
+		db := p.db
+		switch r.Method {
+		case http.MethodGet:
+			if db == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if r.Header.Get(cmn.HeaderIfNoneMatch) == ais.ETagAuthNDB(db.Version) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			p.fetched++
+			w.Write(cmn.MustMarshal(db))
+		case http.MethodPut:
+			next := &ais.AuthNDB{}
+			if err := jsoniter.NewDecoder(r.Body).Decode(next); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			var cur int64
+			if db != nil {
+				cur = db.Version
+			}
+			if p.conflicts > 0 {
+				p.conflicts--
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			if next.Version != cur+1 {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			p.db = next
+		}
+	}))
+	return p
+}
+
+func (p *stubPrimary) set(conflicts int) (fetched int) {
+	p.mtx.Lock()
+	p.conflicts = conflicts
+	fetched = p.fetched
+	p.mtx.Unlock()
+	return
+}
+
+func TestClusterDB(t *testing.T) {
+	const (
+		username = "hauser"
+		userpass = "hapass"
+	)
+	conf.Auth.ClusterDB, conf.Auth.DBSecret = true, "db-secret"
+	defer func() { conf.Auth.ClusterDB, conf.Auth.DBSecret = false, "" }()
+	primary := newStubPrimary()
+	defer primary.Close()
+	dir, err := ioutil.TempDir("", "authn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// two AuthN instances sharing the database
+	mgr1 := newUserManager(filepath.Join(dir, "1", userListFile), &proxy{URL: primary.URL})
+	if err := mgr1.initClusterDB(); err != nil {
+		t.Fatalf("Failed to upload user list: %v", err)
+	}
+	if err := mgr1.addUser(username, userpass, nil, nil); err != nil {
+		t.Fatalf("Failed to create user %s: %v", username, err)
+	}
+	// the cluster keeps the database encrypted
+	resp, err := http.Get(primary.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || strings.Contains(string(body), username) {
+		t.Errorf("Expected encrypted AuthN DB, got %s (%v)", body, err)
+	}
+	mgr2 := newUserManager(filepath.Join(dir, "2", userListFile), &proxy{URL: primary.URL})
+	if err := mgr2.initClusterDB(); err != nil {
+		t.Fatalf("Failed to download user list: %v", err)
+	}
+	if _, ok := mgr2.Users[username]; !ok {
+		t.Fatalf("User %s was not replicated", username)
+	}
+
+	// tokens are shared
+	token, err := mgr2.issueToken(username, userpass)
+	if err != nil {
+		t.Fatalf("Failed to issue token: %v", err)
+	}
+	if err := mgr1.syncDB(); err != nil {
+		t.Fatalf("Failed to pull user list: %v", err)
+	}
+	if _, err := mgr1.userByToken(token); err != nil {
+		t.Errorf("Expected the token to be known to the other instance: %v", err)
+	}
+
+	// changes made by one instance revoke the token on the other one
+	if err := mgr1.updateUserPerms(username, []string{cmn.RoleClusterAdmin}, nil); err != nil {
+		t.Fatalf("Failed to update permissions: %v", err)
+	}
+	if err := mgr2.syncDB(); err != nil {
+		t.Fatalf("Failed to pull user list: %v", err)
+	}
+	if _, err := mgr2.userByToken(token); err == nil {
+		t.Error("Expected the token to be revoked after updating permissions")
+	}
+
+	// update based on an outdated version is rejected
+	mgr2.mtx.Lock()
+	current := mgr2.dbVersion
+	mgr2.dbVersion--
+	err = mgr2.pushDB()
+	mgr2.dbVersion = current
+	mgr2.mtx.Unlock()
+	if err != errDBConflict {
+		t.Errorf("Expected conflict, got %v", err)
+	}
+
+	// read-only requests do not contact the cluster, and the database is not
+	// sent again unless it has changed
+	token, err = mgr2.issueToken(username, userpass)
+	if err != nil {
+		t.Fatalf("Failed to issue token: %v", err)
+	}
+	fetched := primary.set(0)
+	mgr1.userList()
+	if _, err := mgr1.userByToken(token); err == nil {
+		t.Error("Expected the token to be unknown to the other instance until the next pull")
+	}
+	if n := primary.set(0) - fetched; n != 0 {
+		t.Errorf("Expected AuthN DB not to be fetched by read-only requests, got %d", n)
+	}
+	for i := 0; i < 2; i++ {
+		if err := mgr1.syncDB(); err != nil {
+			t.Fatalf("Failed to pull user list: %v", err)
+		}
+	}
+	if n := primary.set(0) - fetched; n != 1 {
+		t.Errorf("Expected AuthN DB to be fetched once, got %d", n)
+	}
+	if _, err := mgr1.userByToken(token); err != nil {
+		t.Errorf("Expected the token to be known to the other instance: %v", err)
+	}
+
+	// concurrent update: the change is retried
+	primary.set(dbMaxRetries - 1)
+	if err := mgr2.addRole(&cmn.AuthRole{Name: "role"}); err != nil {
+		t.Fatalf("Failed to add role after conflicts: %v", err)
+	}
+	// ...or, if conflicts persist, fails without side effects
+	primary.set(dbMaxRetries)
+	if err := mgr2.updateUserPerms(username, []string{"role"}, nil); err == nil {
+		t.Fatal("Expected update to fail")
+	}
+	if user := mgr2.Users[username]; len(user.Roles) != 1 || user.Roles[0] != cmn.RoleClusterAdmin {
+		t.Errorf("Expected failed update to be rolled back, got %v", user.Roles)
+	}
+	if _, err := mgr2.userByToken(token); err != nil {
+		t.Errorf("Expected the token to survive failed update: %v", err)
+	}
+	claims, _ := tokenClaims(token)
+	if _, ok := mgr2.revoked.IDs[claims["jti"].(string)]; ok {
+		t.Error("Expected the token not to be revoked by failed update")
+	}
+	primary.set(dbMaxRetries)
+	if _, err := mgr2.issueToken(username, userpass); err == nil {
+		t.Error("Expected login to fail if the token cannot be shared")
+	}
+	primary.set(0)
+
+	if err := mgr2.delUser(username); err != nil {
+		t.Errorf("Failed to delete user %s: %v", username, err)
+	}
+	if err := mgr1.syncDB(); err != nil {
+		t.Fatalf("Failed to pull user list: %v", err)
+	}
+	for _, user := range mgr1.userList() {
+		if user.UserID == username {
+			t.Errorf("User %s was not deleted on the other instance", username)
+		}
+	}
+}
+
+func TestDBSecret(t *testing.T) {
+	c := config{Auth: authConfig{ExpirePeriodStr: "1h", Secret: "secret", ClusterDB: true}}
+	if err := c.validate(); err == nil {
+		t.Error("Expected error: cluster_db without db_secret")
+	}
+	c.Auth.DBSecret = c.Auth.Secret
+	if err := c.validate(); err == nil {
+		t.Error("Expected error: db_secret is the same as secret")
+	}
+
+	// the database cannot be decrypted with the secret shared with the cluster
+	conf.Auth.DBSecret = "db-secret"
+	defer func() { conf.Auth.DBSecret = "" }()
+	data, err := sealDB(&clusterDB{Users: map[string]*userInfo{"user": {UserID: "user"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cdb, err := openDB(data); err != nil || cdb.Users["user"] == nil {
+		t.Fatalf("Failed to decrypt AuthN DB: %v", err)
+	}
+	conf.Auth.DBSecret = conf.Auth.Secret
+	if _, err := openDB(data); err == nil {
+		t.Error("Expected error: AuthN DB decrypted with a different secret")
+	}
+}
@@ -0,0 +1,391 @@
+// Authorization server for AIStore. See /authn/README.md for more info.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// AuthN database replicated by the cluster (auth.cluster_db = true).
+// Users, custom roles, and IDs of issued tokens are stored by the primary proxy
+// as a single versioned blob (see ais/authndb.go) and metasync-ed to all proxies.
+// The blob is encrypted with the key derived from the secret shared by AuthN
+// instances only (auth.db_secret), so that the proxies cannot read it - unlike
+// auth.secret that every node uses to verify user tokens.
+// Every AuthN instance pulls the database before a change and pushes it back
+// after the change; read-only requests are served from the local copy that is
+// pulled periodically in the background (the primary returns the database only
+// if the version has changed).
+// The primary accepts only the next version, so a concurrent update by another
+// instance is rejected: the change is then rolled back and retried on top of
+// the newer version. Side effects of a change (revocations) take place only
+// after the change is accepted.
+// Local users.json and roles.json are kept up to date as a fallback.
+
+const (
+	clusterTokenTTL = time.Hour // lifetime of the token AuthN uses to access the database
+	dbMaxRetries    = 3         // the number of attempts to apply a change upon conflicts
+	dbSyncInterval  = 10 * time.Second
+)
+
+type clusterDB struct {
+	Users  map[string]*userInfo     `json:"users"`
+	Roles  map[string]*cmn.AuthRole `json:"roles"` // custom roles only
+	Tokens map[string]*tokenInfo    `json:"tokens"`
+}
+
+var errDBConflict = errors.New("AuthN database was concurrently updated by another instance")
+
+// Acquires the lock and brings the database up to date with the cluster's copy
+// before a change (see update)
+func (m *userManager) lock() {
+	m.mtx.Lock()
+	if !conf.Auth.ClusterDB {
+		return
+	}
+	if _, err := m.pullDB(); err != nil {
+		glog.Errorf("Failed to pull AuthN DB, using local copy: %v", err)
+	}
+}
+
+// Applies a change to users, roles, or tokens: acquires the lock, runs the change
+// and, if the change succeeds, pushes the database to the cluster and then
+// performs the revocations requested by the change (see revoke). If the push
+// fails, the change is rolled back; upon a conflict it is retried
+func (m *userManager) update(change func() error) (err error) {
+	for i := 0; i < dbMaxRetries; i++ {
+		m.lock()
+		err = m.apply(change)
+		m.mtx.Unlock()
+		if err != errDBConflict {
+			return
+		}
+		glog.Warningf("%v - retrying", err)
+	}
+	return
+}
+
+// Must be called under lock
+func (m *userManager) apply(change func() error) error {
+	if !conf.Auth.ClusterDB {
+		err := change()
+		m.commitRevoked() // in-memory state has been changed regardless
+		return err
+	}
+	snap := m.snapshot()
+	err := change()
+	dirty := m.dbDirty
+	m.dbDirty = false
+	if err == nil && dirty {
+		err = m.pushDB()
+	}
+	if err != nil {
+		m.rollback(snap)
+		return err
+	}
+	if dirty {
+		if err := m.storeUsers(); err != nil {
+			glog.Error(err)
+		}
+		if err := m.storeRoles(); err != nil {
+			glog.Error(err)
+		}
+	}
+	m.commitRevoked()
+	return nil
+}
+
+// Returns a copy of the current state to roll back a failed change
+// Must be called under lock
+func (m *userManager) snapshot() []byte {
+	return cmn.MustMarshal(&clusterDB{Users: m.savedUsers(), Roles: m.customRoles(), Tokens: m.tokens})
+}
+
+// Must be called under lock
+func (m *userManager) rollback(snap []byte) {
+	cdb := &clusterDB{}
+	cmn.AssertNoErr(jsoniter.Unmarshal(snap, cdb))
+	m.setDB(cdb)
+	m.revoking = revokedList{}
+}
+
+// Called once at startup: the first AuthN instance uploads its local
+// database if the cluster does not have one yet
+func (m *userManager) initClusterDB() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	found, err := m.pullDB()
+	if err != nil || found {
+		return err
+	}
+	glog.Infof("Cluster has no AuthN DB - uploading local user list")
+	return m.pushDB()
+}
+
+// Runs in its own goroutine and pulls the database, so that read-only requests
+// see the changes made by other instances. The request to the primary is sent
+// without holding the lock
+func (m *userManager) runDBSync() {
+	ticker := time.NewTicker(dbSyncInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := m.syncDB(); err != nil {
+			glog.Errorf("Failed to pull AuthN DB, using local copy: %v", err)
+		}
+	}
+}
+
+func (m *userManager) syncDB() error {
+	m.mtx.Lock()
+	token, err := m.clusterToken()
+	version := m.dbVersion
+	m.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	db, _, err := m.getDB(token, version)
+	if err != nil || db == nil {
+		return err
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if db.Version <= m.dbVersion {
+		return nil // updated in the meantime
+	}
+	return m.installDB(db)
+}
+
+// Replaces local state with the cluster's copy if the latter is newer.
+// Returns false if the cluster does not have the database.
+// Must be called under lock
+func (m *userManager) pullDB() (found bool, err error) {
+	token, err := m.clusterToken()
+	if err != nil {
+		return false, err
+	}
+	db, found, err := m.getDB(token, m.dbVersion)
+	if err != nil || db == nil || db.Version <= m.dbVersion {
+		return found, err
+	}
+	return true, m.installDB(db)
+}
+
+// Returns the cluster's copy of the database, or nil if the cluster does not
+// have it (found = false) or it has not changed since the given version
+func (m *userManager) getDB(token string, version int64) (db *ais.AuthNDB, found bool, err error) {
+	status, body, err := m.clusterDBRequest(http.MethodGet, token, version, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	switch status {
+	case http.StatusNotFound:
+		return nil, false, nil
+	case http.StatusNotModified:
+		return nil, true, nil
+	case http.StatusOK:
+		db = &ais.AuthNDB{}
+		return db, true, jsoniter.Unmarshal(body, db)
+	default:
+		return nil, false, fmt.Errorf("failed to get AuthN DB: %s", body)
+	}
+}
+
+// Must be called under lock
+func (m *userManager) installDB(db *ais.AuthNDB) error {
+	cdb, err := openDB(db.Data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal AuthN DB v%d: %v", db.Version, err)
+	}
+	m.setDB(cdb)
+	m.dbVersion = db.Version
+	glog.Infof("Installed AuthN DB v%d", db.Version)
+
+	if err := m.storeUsers(); err != nil {
+		glog.Error(err)
+	}
+	if err := m.storeRoles(); err != nil {
+		glog.Error(err)
+	}
+	return nil
+}
+
+// Replaces users (except the superuser), custom roles, and tokens.
+// Must be called under lock
+func (m *userManager) setDB(cdb *clusterDB) {
+	users := make(map[string]*userInfo, len(cdb.Users)+1)
+	for name, info := range cdb.Users {
+		if info.Creds == nil {
+			info.Creds = make(map[string]string, 10)
+		}
+		users[name] = info
+	}
+	if su, ok := m.Users[conf.Auth.Username]; ok {
+		users[conf.Auth.Username] = su
+	}
+	roles := make(map[string]*cmn.AuthRole, len(cmn.PredefinedRoles)+len(cdb.Roles))
+	for _, role := range cmn.PredefinedRoles {
+		roles[role.Name] = role
+	}
+	for name, role := range cdb.Roles {
+		if !isPredefinedRole(name) {
+			roles[name] = role
+		}
+	}
+	if cdb.Tokens == nil {
+		cdb.Tokens = make(map[string]*tokenInfo, 10)
+	}
+	m.Users, m.Roles, m.tokens = users, roles, cdb.Tokens
+}
+
+// Uploads the next version of the database. Returns errDBConflict if another
+// instance has updated it in the meantime.
+// Must be called under lock
+func (m *userManager) pushDB() error {
+	cdb := &clusterDB{
+		Users:  m.savedUsers(),
+		Roles:  m.customRoles(),
+		Tokens: make(map[string]*tokenInfo, len(m.tokens)),
+	}
+	now := time.Now()
+	for id, token := range m.tokens {
+		if token.Expires.After(now) {
+			cdb.Tokens[id] = token
+		}
+	}
+	data, err := sealDB(cdb)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt AuthN DB: %v", err)
+	}
+	token, err := m.clusterToken()
+	if err != nil {
+		return err
+	}
+	db := &ais.AuthNDB{Version: m.dbVersion + 1, Data: data}
+	status, body, err := m.clusterDBRequest(http.MethodPut, token, 0, cmn.MustMarshal(db))
+	if err != nil {
+		return fmt.Errorf("failed to update AuthN DB: %v", err)
+	}
+	switch status {
+	case http.StatusOK:
+		m.dbVersion = db.Version
+		return nil
+	case http.StatusConflict:
+		return errDBConflict
+	default:
+		return fmt.Errorf("failed to update AuthN DB: %s", body)
+	}
+}
+
+// Sends a request to the primary proxy; GET returns 304 if the version has not
+// changed. If the primary does not respond, the request is retried once after
+// detecting the new primary
+func (m *userManager) clusterDBRequest(method, token string, version int64, body []byte) (status int, resp []byte, err error) {
+	for retried := false; ; retried = true {
+		var (
+			req      *http.Request
+			response *http.Response
+			url      = m.proxy.URL + cmn.URLPath(cmn.Version, cmn.AuthNDB)
+		)
+		if req, err = http.NewRequest(method, url, bytes.NewReader(body)); err != nil {
+			return 0, nil, err
+		}
+		req.Header.Set(cmn.HeaderAuthorization, cmn.HeaderBearer+" "+token)
+		req.Header.Set("Content-Type", "application/json")
+		if method == http.MethodGet && version != 0 {
+			req.Header.Set(cmn.HeaderIfNoneMatch, ais.ETagAuthNDB(version))
+		}
+		if response, err = m.client.Do(req); err == nil {
+			resp, err = ioutil.ReadAll(response.Body)
+			response.Body.Close()
+			return response.StatusCode, resp, err
+		}
+		if retried || m.proxy.detectPrimary() != nil {
+			return 0, nil, err
+		}
+	}
+}
+
+// Returns a token with cluster-admin permissions that AuthN uses to access
+// the database. The token is renewed shortly before it expires.
+// Must be called under lock
+func (m *userManager) clusterToken() (string, error) {
+	now := time.Now()
+	if m.clusterTok != "" && m.clusterTokExpires.Sub(now) > clusterTokenTTL/10 {
+		return m.clusterTok, nil
+	}
+	su := &userInfo{UserID: conf.Auth.Username, Roles: []string{cmn.RoleClusterAdmin}}
+	expires := now.Add(clusterTokenTTL)
+	token, err := m.genToken(su, now, expires, "", "")
+	if err != nil {
+		return "", err
+	}
+	m.clusterTok, m.clusterTokExpires = token, expires
+	return token, nil
+}
+
+func dbKey() []byte {
+	key := sha256.Sum256([]byte("authn-db:" + conf.Auth.DBSecret))
+	return key[:]
+}
+
+// Encrypts the database (AES-GCM); the result is a JSON string
+func sealDB(cdb *clusterDB) ([]byte, error) {
+	block, err := aes.NewCipher(dbKey())
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, cmn.MustMarshal(cdb), nil)
+	return jsoniter.Marshal(sealed)
+}
+
+// Decrypts the database (see sealDB)
+func openDB(data []byte) (*clusterDB, error) {
+	var (
+		cdb    = &clusterDB{}
+		sealed []byte
+	)
+	if err := jsoniter.Unmarshal(data, &sealed); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(dbKey())
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("invalid encrypted data")
+	}
+	nonce, sealed := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt (different db_secret?): %v", err)
+	}
+	return cdb, jsoniter.Unmarshal(plain, cdb)
+}
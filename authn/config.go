@@ -40,6 +40,8 @@ type authConfig struct {
 	Password        string        `json:"password"`
 	ExpirePeriodStr string        `json:"expiration_time"`
 	ExpirePeriod    time.Duration `json:"-"`
+	ClusterDB       bool          `json:"cluster_db"` // store user DB in the cluster (to run multiple AuthN instances)
+	DBSecret        string        `json:"db_secret"`  // encrypts user DB stored in the cluster; known only to AuthN
 }
 type timeoutConfig struct {
 	DefaultStr string        `json:"default_timeout"`
@@ -50,6 +52,9 @@ func (c *config) validate() (err error) {
 	if c.Auth.ExpirePeriod, err = time.ParseDuration(c.Auth.ExpirePeriodStr); err != nil {
 		return fmt.Errorf("invalid expire time format %s, err: %v", c.Auth.ExpirePeriodStr, err)
 	}
+	if c.Auth.ClusterDB && (c.Auth.DBSecret == "" || c.Auth.DBSecret == c.Auth.Secret) {
+		return fmt.Errorf("cluster_db requires db_secret that differs from secret (the latter is shared with the cluster)")
+	}
 
 	return nil
 }
@@ -0,0 +1,153 @@
+// Authorization server for AIStore. See /authn/README.md for more info.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/jsp"
+)
+
+// Revoked tokens and API keys (identified by their IDs; tokens issued by older
+// AuthN versions do not have IDs) are sent to the primary proxy that, in turn,
+// broadcasts them to the cluster. Sending is retried until the primary
+// acknowledges it. Pending revocations and the IDs of revoked tokens and API
+// keys (that may never expire) are persisted, so that AuthN restart does not
+// lose them.
+
+const revokedListFile = "revoked.json"
+
+type revokedList struct {
+	Tokens []string             `json:"tokens,omitempty"` // not yet acknowledged by the cluster
+	IDs    map[string]time.Time `json:"ids,omitempty"`    // token or API key ID => expiration time
+}
+
+func (m *userManager) revokedPath() string {
+	return filepath.Join(filepath.Dir(m.Path), revokedListFile)
+}
+
+// Called once at startup: the loaded revocations are resent to the cluster
+func (m *userManager) loadRevoked() {
+	if err := jsp.Load(m.revokedPath(), &m.revoked, jsp.Plain()); err != nil {
+		if !os.IsNotExist(err) {
+			glog.Errorf("Failed to load revoked list: %v", err)
+		}
+	}
+	if m.revoked.IDs == nil {
+		m.revoked.IDs = make(map[string]time.Time, 4)
+	}
+	if len(m.revoked.Tokens) > 0 || len(m.revoked.IDs) > 0 {
+		m.revokedGen++
+	}
+}
+
+func (m *userManager) storeRevoked() {
+	now := time.Now()
+	for id, expires := range m.revoked.IDs {
+		if expires.Before(now) {
+			delete(m.revoked.IDs, id)
+		}
+	}
+	if err := jsp.Save(m.revokedPath(), &m.revoked, jsp.Plain()); err != nil {
+		glog.Errorf("Failed to save revoked list: %v", err)
+	}
+}
+
+// Requests revocation of tokens, and IDs of tokens and API keys. The revocation
+// takes place only when the change that requests it succeeds (see update).
+// Must be called under lock
+func (m *userManager) revoke(tokens []string, ids map[string]time.Time) {
+	m.revoking.Tokens = append(m.revoking.Tokens, tokens...)
+	if len(ids) != 0 && m.revoking.IDs == nil {
+		m.revoking.IDs = make(map[string]time.Time, len(ids))
+	}
+	for id, expires := range ids {
+		m.revoking.IDs[id] = expires
+	}
+}
+
+// Adds the requested revocations to the list of revoked ones and wakes up
+// the sender. Must be called under lock
+func (m *userManager) commitRevoked() {
+	tokens, ids := m.revoking.Tokens, m.revoking.IDs
+	m.revoking = revokedList{}
+	if len(tokens) == 0 && len(ids) == 0 {
+		return
+	}
+	m.revoked.Tokens = append(m.revoked.Tokens, tokens...)
+	for id, expires := range ids {
+		m.revoked.IDs[id] = expires
+	}
+	m.revokedGen++
+	m.storeRevoked()
+	select {
+	case m.revokeCh <- struct{}{}:
+	default:
+	}
+}
+
+func keyExpires(key *cmn.AuthAPIKey) time.Time {
+	if key.Expires.IsZero() {
+		return key.Created.Add(foreverTokenTime)
+	}
+	return key.Expires
+}
+
+// Runs in its own goroutine and sends revocations until the primary proxy
+// acknowledges them
+func (m *userManager) runRevoker() {
+	ticker := time.NewTicker(proxyRetryTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.revokeCh:
+		case <-ticker.C:
+		}
+		if err := m.sendRevoked(); err != nil {
+			glog.Errorf("Failed to send revoked list (will retry): %v", err)
+		}
+	}
+}
+
+// Sends all pending tokens and revoked API keys to the primary proxy
+func (m *userManager) sendRevoked() error {
+	m.mtx.Lock()
+	if m.revokedSent == m.revokedGen {
+		m.mtx.Unlock()
+		return nil
+	}
+	var (
+		gen   = m.revokedGen
+		tlist = &ais.TokenList{
+			Tokens: append([]string{}, m.revoked.Tokens...),
+			IDs:    make(map[string]time.Time, len(m.revoked.IDs)),
+		}
+	)
+	for id, expires := range m.revoked.IDs {
+		tlist.IDs[id] = expires
+	}
+	m.mtx.Unlock()
+
+	if m.proxy.URL == "" {
+		return errors.New("primary proxy is not defined")
+	}
+	if err := m.proxyRequest(http.MethodDelete, cmn.Tokens, cmn.MustMarshal(tlist)); err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	m.revoked.Tokens = m.revoked.Tokens[len(tlist.Tokens):] // keep the ones added in the meantime
+	m.revokedSent = gen
+	m.storeRevoked()
+	m.mtx.Unlock()
+	return nil
+}
@@ -22,6 +22,8 @@ import (
 const (
 	pathUsers  = "users"
 	pathTokens = "tokens"
+	pathRoles  = "roles"
+	pathKeys   = "apikeys"
 	smapConfig = "smap.json"
 )
 
@@ -42,6 +44,14 @@ type tokenMsg struct {
 	Token string `json:"token"`
 }
 
+// a message to update user's roles and permissions
+// PUT: <version>/<pathUsers>/<username>
+//		Body: <permsMsg>
+type permsMsg struct {
+	Roles  []string        `json:"roles"`
+	Grants []cmn.AuthGrant `json:"grants"`
+}
+
 //-------------------------------------
 // global functions (borrowed from ais)
 //-------------------------------------
@@ -126,6 +136,8 @@ func (a *authServ) registerHandler(path string, handler func(http.ResponseWriter
 func (a *authServ) registerPublicHandlers() {
 	a.registerHandler(cmn.URLPath(cmn.Version, pathUsers), a.userHandler)
 	a.registerHandler(cmn.URLPath(cmn.Version, pathTokens), a.tokenHandler)
+	a.registerHandler(cmn.URLPath(cmn.Version, pathRoles), a.roleHandler)
+	a.registerHandler(cmn.URLPath(cmn.Version, pathKeys), a.keyHandler)
 }
 
 func (a *authServ) userHandler(w http.ResponseWriter, r *http.Request) {
@@ -136,11 +148,39 @@ func (a *authServ) userHandler(w http.ResponseWriter, r *http.Request) {
 		a.httpUserPost(w, r)
 	case http.MethodPut:
 		a.httpUserPut(w, r)
+	case http.MethodGet:
+		a.httpUserGet(w, r)
 	default:
 		cmn.InvalidHandlerWithMsg(w, r, "Unsupported method for /users handler")
 	}
 }
 
+func (a *authServ) roleHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.httpRoleGet(w, r)
+	case http.MethodPost:
+		a.httpRolePost(w, r)
+	case http.MethodDelete:
+		a.httpRoleDel(w, r)
+	default:
+		cmn.InvalidHandlerWithMsg(w, r, "Unsupported method for /roles handler")
+	}
+}
+
+func (a *authServ) keyHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.httpKeyGet(w, r)
+	case http.MethodPost:
+		a.httpKeyPost(w, r)
+	case http.MethodDelete:
+		a.httpKeyDel(w, r)
+	default:
+		cmn.InvalidHandlerWithMsg(w, r, "Unsupported method for /apikeys handler")
+	}
+}
+
 func (a *authServ) tokenHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodDelete:
@@ -196,11 +236,22 @@ func (a *authServ) httpUserPost(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Updates user credentials
-// If user did not have credentials before updating or the credentials changes
-//   then new user list is saved and sent to the proxy to update the cluster
+// Returns the list of users along with their roles and permissions
+func (a *authServ) httpUserGet(w http.ResponseWriter, r *http.Request) {
+	if _, err := checkRESTItems(w, r, 0, cmn.Version, pathUsers); err != nil {
+		return
+	}
+	if err := a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	a.writeJSON(w, r, cmn.MustMarshal(a.users.userList()), "list users")
+}
+
+// Updates user's roles and permissions (one item after <pathUsers>)
+// or user's credentials (two items: user ID and cloud provider)
 func (a *authServ) httpUserPut(w http.ResponseWriter, r *http.Request) {
-	apiItems, err := checkRESTItems(w, r, 2, cmn.Version, pathUsers)
+	apiItems, err := checkRESTItems(w, r, 1, cmn.Version, pathUsers)
 	if err != nil {
 		return
 	}
@@ -208,7 +259,34 @@ func (a *authServ) httpUserPut(w http.ResponseWriter, r *http.Request) {
 		glog.Errorf("Not authorized: %v\n", err)
 		return
 	}
+	if len(apiItems) == 1 {
+		a.userUpdatePerms(w, r, apiItems[0])
+		return
+	}
+	a.userUpdateCredentials(w, r, apiItems)
+}
+
+// Replaces user's roles and grants and revokes user's token, if any
+func (a *authServ) userUpdatePerms(w http.ResponseWriter, r *http.Request, userID string) {
+	msg := &permsMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		glog.Errorf("Failed to read request body: %v\n", err)
+		return
+	}
+	if err := a.users.updateUserPerms(userID, msg.Roles, msg.Grants); err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, fmt.Sprintf("Failed to update permissions: %v", err))
+		return
+	}
+	if glog.V(4) {
+		glog.Infof("Updated permissions for %s: roles %v, grants %v\n", userID, msg.Roles, msg.Grants)
+	}
+	a.writeJSON(w, r, []byte("Permissions updated successfully"), "update permissions")
+}
 
+// Updates user credentials
+// If user did not have credentials before updating or the credentials changes
+//   then new user list is saved and sent to the proxy to update the cluster
+func (a *authServ) userUpdateCredentials(w http.ResponseWriter, r *http.Request, apiItems []string) {
 	userID := apiItems[0]
 	provider := apiItems[1]
 
@@ -243,7 +321,13 @@ func (a *authServ) userAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := a.users.addUser(info.UserID, info.Password); err != nil {
+	var err error
+	if info.Service {
+		err = a.users.addServiceAccount(info.UserID, info.Roles, info.Grants)
+	} else {
+		err = a.users.addUser(info.UserID, info.Password, info.Roles, info.Grants)
+	}
+	if err != nil {
 		cmn.InvalidHandlerWithMsg(w, r, fmt.Sprintf("Failed to add user: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -369,3 +453,104 @@ func (a *authServ) userRemoveCredentials(w http.ResponseWriter, r *http.Request)
 
 	a.writeJSON(w, r, []byte("Credentials updated successfully"), "update credentials")
 }
+
+// Returns the list of predefined and custom roles
+func (a *authServ) httpRoleGet(w http.ResponseWriter, r *http.Request) {
+	if _, err := checkRESTItems(w, r, 0, cmn.Version, pathRoles); err != nil {
+		return
+	}
+	if err := a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	a.writeJSON(w, r, cmn.MustMarshal(a.users.roleList()), "list roles")
+}
+
+// Adds a new custom role or updates an existing one
+func (a *authServ) httpRolePost(w http.ResponseWriter, r *http.Request) {
+	if _, err := checkRESTItems(w, r, 0, cmn.Version, pathRoles); err != nil {
+		return
+	}
+	if err := a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	role := &cmn.AuthRole{}
+	if err := cmn.ReadJSON(w, r, role); err != nil {
+		glog.Errorf("Failed to read request body: %v\n", err)
+		return
+	}
+	if err := a.users.addRole(role); err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, fmt.Sprintf("Failed to add role: %v", err))
+		return
+	}
+	a.writeJSON(w, r, []byte("Role updated successfully"), "update role")
+}
+
+// Deletes a custom role
+func (a *authServ) httpRoleDel(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := checkRESTItems(w, r, 1, cmn.Version, pathRoles)
+	if err != nil {
+		return
+	}
+	if err := a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	if err := a.users.delRole(apiItems[0]); err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, fmt.Sprintf("Failed to delete role: %v", err))
+	}
+}
+
+// Returns API keys (without tokens), optionally filtered by service account
+func (a *authServ) httpKeyGet(w http.ResponseWriter, r *http.Request) {
+	if _, err := checkRESTItems(w, r, 0, cmn.Version, pathKeys); err != nil {
+		return
+	}
+	if err := a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	keys := a.users.listAPIKeys(r.URL.Query().Get(cmn.URLParamUser))
+	a.writeJSON(w, r, cmn.MustMarshal(keys), "list API keys")
+}
+
+// Issues a new API key for a service account
+func (a *authServ) httpKeyPost(w http.ResponseWriter, r *http.Request) {
+	if _, err := checkRESTItems(w, r, 0, cmn.Version, pathKeys); err != nil {
+		return
+	}
+	if err := a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	msg := &cmn.AuthAPIKeyMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		glog.Errorf("Failed to read request body: %v\n", err)
+		return
+	}
+	key, err := a.users.createAPIKey(msg)
+	if err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, fmt.Sprintf("Failed to create API key: %v", err))
+		return
+	}
+	if glog.V(4) {
+		glog.Infof("Created API key %s for %s\n", key.ID, key.Account)
+	}
+	a.writeJSON(w, r, cmn.MustMarshal(key), "create API key")
+}
+
+// Revokes an API key
+func (a *authServ) httpKeyDel(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := checkRESTItems(w, r, 1, cmn.Version, pathKeys)
+	if err != nil {
+		return
+	}
+	if err := a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	if err := a.users.revokeAPIKey(apiItems[0]); err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, fmt.Sprintf("Failed to revoke API key: %v", err), http.StatusNotFound)
+	}
+}
@@ -12,18 +12,22 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
-	"github.com/NVIDIA/aistore/ais"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/jsp"
 	jwt "github.com/dgrijalva/jwt-go"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const (
 	userListFile     = "users.json"
+	roleListFile     = "roles.json"
 	proxyTimeout     = 2 * time.Minute           // maximum time for syncing Authn data with primary proxy
 	proxyRetryTime   = 5 * time.Second           // an interval between primary proxy detection attempts
 	foreverTokenTime = 24 * 365 * 20 * time.Hour // kind of never-expired token
@@ -31,48 +35,79 @@ const (
 
 type (
 	userInfo struct {
-		UserID          string            `json:"name"`
-		Password        string            `json:"password,omitempty"`
-		Creds           map[string]string `json:"creds,omitempty"`
-		passwordDecoded string
-	}
+		UserID   string            `json:"name"`
+		Password string            `json:"password,omitempty"` // bcrypt hash
+		Creds    map[string]string `json:"creds,omitempty"`
+		Roles    []string          `json:"roles,omitempty"`
+		Grants   []cmn.AuthGrant   `json:"grants,omitempty"`
+		Service  bool              `json:"service,omitempty"` // service account: cannot log in, uses API keys
+		Keys     []*cmn.AuthAPIKey `json:"keys,omitempty"`
+	}
+	// issued token; the token itself is not stored - it is identified
+	// (and revoked) by its ID ("jti" claim)
 	tokenInfo struct {
 		UserID  string    `json:"username"`
 		Issued  time.Time `json:"issued"`
 		Expires time.Time `json:"expires"`
-		Token   string    `json:"token"`
 	}
 	userManager struct {
 		mtx    sync.Mutex
-		Path   string               `json:"-"`
-		Users  map[string]*userInfo `json:"users"`
-		tokens map[string]*tokenInfo
+		Path   string                   `json:"-"`
+		Users  map[string]*userInfo     `json:"users"`
+		Roles  map[string]*cmn.AuthRole `json:"-"` // predefined and custom roles
+		tokens map[string]*tokenInfo    // token ID => token
 		client *http.Client
 		proxy  *proxy
+		// database replicated by the cluster (see clusterdb.go)
+		dbVersion         int64
+		dbDirty           bool // the change must be pushed to the cluster (see update)
+		clusterTok        string
+		clusterTokExpires time.Time
+		// revocations sent to the cluster (see revoke.go)
+		revoked     revokedList
+		revoking    revokedList // requested by the change in progress
+		revokedGen  int64       // incremented upon every revocation
+		revokedSent int64       // the last generation acknowledged by the primary
+		revokeCh    chan struct{}
 	}
 )
 
 // Creates a new user manager. If user DB exists, it loads the data from the
 // file and decrypts passwords
 func newUserManager(dbPath string, proxy *proxy) *userManager {
-	var (
-		err   error
-		bytes []byte
-	)
+	var err error
 	client := cmn.NewClient(cmn.TransportArgs{
 		Timeout: conf.Timeout.Default,
 	})
 	mgr := &userManager{
-		Path:   dbPath,
-		Users:  make(map[string]*userInfo, 10),
-		tokens: make(map[string]*tokenInfo, 10),
-		client: client,
-		proxy:  proxy,
+		Path:     dbPath,
+		Users:    make(map[string]*userInfo, 10),
+		Roles:    make(map[string]*cmn.AuthRole, len(cmn.PredefinedRoles)),
+		tokens:   make(map[string]*tokenInfo, 10),
+		client:   client,
+		proxy:    proxy,
+		revokeCh: make(chan struct{}, 1),
+	}
+	mgr.loadRevoked()
+	for _, role := range cmn.PredefinedRoles {
+		mgr.Roles[role.Name] = role
+	}
+	// add a superuser to the list to allow the superuser to login
+	superuser := &userInfo{
+		UserID: conf.Auth.Username,
+		Roles:  []string{cmn.RoleClusterAdmin},
+	}
+	if superuser.Password, err = hashPassword(conf.Auth.Password); err != nil {
+		glog.Fatalf("Failed to hash superuser password: %v\n", err)
 	}
 	if _, err = os.Stat(dbPath); err != nil {
 		if !os.IsNotExist(err) {
 			glog.Fatalf("Failed to load user list: %v\n", err)
 		}
+		// new user DB: create the role list right away (see loadRoles)
+		if err = mgr.storeRoles(); err != nil {
+			glog.Error(err)
+		}
 		return mgr
 	}
 
@@ -87,207 +122,511 @@ func newUserManager(dbPath string, proxy *proxy) *userManager {
 		}
 	}
 
-	for _, info := range mgr.Users {
-		if bytes, err = base64.StdEncoding.DecodeString(info.Password); err != nil {
-			glog.Fatalf("Failed to read user list: %v\n", err)
+	migrated, err := hashPasswords(mgr.Users)
+	if err != nil {
+		glog.Fatalf("Failed to read user list: %v\n", err)
+	}
+	if migrated {
+		if err = mgr.storeUsers(); err != nil {
+			glog.Fatal(err)
 		}
-		info.passwordDecoded = string(bytes)
 	}
 
-	// add a superuser to the list to allow the superuser to login
-	mgr.Users[conf.Auth.Username] = &userInfo{
-		UserID:          conf.Auth.Username,
-		passwordDecoded: conf.Auth.Password,
-	}
+	mgr.loadRoles()
+	mgr.Users[conf.Auth.Username] = superuser
 
 	return mgr
 }
 
-// save new user list to file
+func (m *userManager) rolesPath() string { return filepath.Join(filepath.Dir(m.Path), roleListFile) }
+
+func hashPassword(password string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(b), err
+}
+
+// Replaces passwords saved by older AuthN versions (base64-encoded) with their
+// hashes. Returns true if any password has been replaced
+func hashPasswords(users map[string]*userInfo) (migrated bool, err error) {
+	for name, info := range users {
+		if info.Password == "" || strings.HasPrefix(info.Password, "$2") {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(info.Password)
+		if err != nil {
+			return false, fmt.Errorf("invalid password of user %s", name)
+		}
+		if info.Password, err = hashPassword(string(b)); err != nil {
+			return false, err
+		}
+		migrated = true
+	}
+	return migrated, nil
+}
+
+// Loads custom roles. Missing role list means that the user DB was created by
+// AuthN prior to RBAC: existing users keep their full access (cluster-admin)
+func (m *userManager) loadRoles() {
+	custom := make(map[string]*cmn.AuthRole, 10)
+	if err := jsp.Load(m.rolesPath(), &custom, jsp.Plain()); err == nil {
+		for name, role := range custom {
+			if _, ok := m.Roles[name]; ok {
+				glog.Warningf("Custom role %q overrides predefined one - skipping", name)
+				continue
+			}
+			m.Roles[name] = role
+		}
+		return
+	} else if !os.IsNotExist(err) {
+		glog.Fatalf("Failed to load role list: %v\n", err)
+	}
+	for _, info := range m.Users {
+		if len(info.Roles) == 0 && len(info.Grants) == 0 {
+			glog.Warningf("User %s: no roles, assigning %q", info.UserID, cmn.RoleClusterAdmin)
+			info.Roles = []string{cmn.RoleClusterAdmin}
+		}
+	}
+	if err := m.storeUsers(); err != nil {
+		glog.Fatal(err)
+	}
+	if err := m.storeRoles(); err != nil {
+		glog.Fatal(err)
+	}
+}
+
+// save new user list to file or, if enabled, to the cluster (see update)
 // It is called from functions of this module that acquire lock, so this
 //    function needs no locks
-func (m *userManager) saveUsers() (err error) {
-	// copy users to avoid saving admin to the file
+func (m *userManager) saveUsers() error {
+	if conf.Auth.ClusterDB {
+		m.dbDirty = true
+		return nil
+	}
+	return m.storeUsers()
+}
+
+// save custom roles to file or, if enabled, to the cluster (see update)
+// Must be called under lock
+func (m *userManager) saveRoles() error {
+	if conf.Auth.ClusterDB {
+		m.dbDirty = true
+		return nil
+	}
+	return m.storeRoles()
+}
+
+// issued tokens are saved only to the cluster (see update)
+// Must be called under lock
+func (m *userManager) saveTokens() {
+	m.dbDirty = conf.Auth.ClusterDB
+}
+
+func (m *userManager) storeUsers() (err error) {
+	filtered := m.savedUsers()
+	if err = jsp.Save(m.Path, &filtered, jsp.Plain()); err != nil {
+		err = fmt.Errorf("UserManager: Failed to save user list: %v", err)
+	}
+	return err
+}
+
+func (m *userManager) storeRoles() (err error) {
+	custom := m.customRoles()
+	if err = jsp.Save(m.rolesPath(), &custom, jsp.Plain()); err != nil {
+		err = fmt.Errorf("UserManager: Failed to save role list: %v", err)
+	}
+	return err
+}
+
+// copy users to avoid saving admin
+func (m *userManager) savedUsers() map[string]*userInfo {
 	filtered := make(map[string]*userInfo, len(m.Users))
 	for k, v := range m.Users {
 		if k != conf.Auth.Username {
 			filtered[k] = v
 		}
 	}
+	return filtered
+}
 
-	if err = jsp.Save(m.Path, &filtered, jsp.Plain()); err != nil {
-		err = fmt.Errorf("UserManager: Failed to save user list: %v", err)
+// predefined roles are not saved
+func (m *userManager) customRoles() map[string]*cmn.AuthRole {
+	custom := make(map[string]*cmn.AuthRole, len(m.Roles))
+	for name, role := range m.Roles {
+		if !isPredefinedRole(name) {
+			custom[name] = role
+		}
 	}
-	return err
+	return custom
 }
 
-// Registers a new user
-func (m *userManager) addUser(userID, userPass string) error {
+func isPredefinedRole(name string) bool { return cmn.PredefinedRole(name) != nil }
+
+// Must be called under lock
+func (m *userManager) validatePerms(roles []string, grants []cmn.AuthGrant) error {
+	for _, name := range roles {
+		if _, ok := m.Roles[name]; !ok {
+			return fmt.Errorf("role %q %s", name, cmn.DoesNotExist)
+		}
+	}
+	for i := range grants {
+		if err := grants[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns all user's permissions: the grants of the user's roles followed by
+// the user's own grants. Must be called under lock
+func (m *userManager) userGrants(user *userInfo) []cmn.AuthGrant {
+	grants := make([]cmn.AuthGrant, 0, len(user.Roles)+len(user.Grants))
+	for _, name := range user.Roles {
+		if role, ok := m.Roles[name]; ok {
+			grants = append(grants, role.Grants...)
+		}
+	}
+	return append(grants, user.Grants...)
+}
+
+// Invalidates the user's token (if any) so that the user logs in again
+// and gets a token with up-to-date permissions. API keys of a service account
+// are revoked as well (and must be recreated). Must be called under lock
+func (m *userManager) revokeUserToken(userID string) {
+	ids := m.delUserTokens(userID)
+	if user, ok := m.Users[userID]; ok {
+		for _, key := range user.Keys {
+			ids[key.ID] = keyExpires(key)
+		}
+		user.Keys = nil
+	}
+	m.revoke(nil, ids)
+}
+
+// Forgets all the tokens issued to the user and returns their IDs.
+// Must be called under lock
+func (m *userManager) delUserTokens(userID string) map[string]time.Time {
+	ids := make(map[string]time.Time, 2)
+	for id, token := range m.tokens {
+		if token.UserID == userID {
+			ids[id] = token.Expires
+			delete(m.tokens, id)
+		}
+	}
+	return ids
+}
+
+// Registers a new user. A user without roles and grants is read-only
+func (m *userManager) addUser(userID, userPass string, roles []string, grants []cmn.AuthGrant) error {
 	if userID == "" || userPass == "" {
 		return fmt.Errorf("invalid credentials")
 	}
+	if len(roles) == 0 && len(grants) == 0 {
+		roles = []string{cmn.RoleReadOnly}
+	}
 
-	m.mtx.Lock()
-	defer m.mtx.Unlock()
+	hash, err := hashPassword(userPass)
+	if err != nil {
+		return err
+	}
 
-	if _, ok := m.Users[userID]; ok {
-		return fmt.Errorf("user '%s' already registered", userID)
+	return m.update(func() error {
+		if _, ok := m.Users[userID]; ok {
+			return fmt.Errorf("user '%s' already registered", userID)
+		}
+		if err := m.validatePerms(roles, grants); err != nil {
+			return err
+		}
+		m.Users[userID] = &userInfo{
+			UserID:   userID,
+			Password: hash,
+			Creds:    make(map[string]string, 10),
+			Roles:    roles,
+			Grants:   grants,
+		}
+		return m.saveUsers()
+	})
+}
+
+// Registers a new service account. Service account cannot log in - instead,
+// it gets API keys (see createAPIKey)
+func (m *userManager) addServiceAccount(name string, roles []string, grants []cmn.AuthGrant) error {
+	if name == "" {
+		return fmt.Errorf("invalid service account name")
 	}
-	m.Users[userID] = &userInfo{
-		UserID:          userID,
-		passwordDecoded: userPass,
-		Password:        base64.StdEncoding.EncodeToString([]byte(userPass)),
-		Creds:           make(map[string]string, 10),
+	if len(roles) == 0 && len(grants) == 0 {
+		roles = []string{cmn.RoleReadOnly}
 	}
 
-	return m.saveUsers()
+	return m.update(func() error {
+		if _, ok := m.Users[name]; ok {
+			return fmt.Errorf("user '%s' already registered", name)
+		}
+		if err := m.validatePerms(roles, grants); err != nil {
+			return err
+		}
+		m.Users[name] = &userInfo{
+			UserID:  name,
+			Creds:   make(map[string]string, 10),
+			Roles:   roles,
+			Grants:  grants,
+			Service: true,
+		}
+		return m.saveUsers()
+	})
 }
 
-// Deletes an existing user
-func (m *userManager) delUser(userID string) error {
+// Replaces user's roles and grants
+func (m *userManager) updateUserPerms(userID string, roles []string, grants []cmn.AuthGrant) error {
 	if userID == conf.Auth.Username {
-		return errors.New("Super user cannot be deleted")
+		return errors.New("Super user cannot be modified")
 	}
+	return m.update(func() error {
+		user, ok := m.Users[userID]
+		if !ok {
+			return fmt.Errorf("user %s %s", userID, cmn.DoesNotExist)
+		}
+		if err := m.validatePerms(roles, grants); err != nil {
+			return err
+		}
+		user.Roles, user.Grants = roles, grants
+		m.revokeUserToken(userID)
+		return m.saveUsers()
+	})
+}
+
+// Returns the list of users (without passwords and credentials)
+func (m *userManager) userList() []*userInfo {
 	m.mtx.Lock()
-	if _, ok := m.Users[userID]; !ok {
-		m.mtx.Unlock()
-		return fmt.Errorf("user %s %s", userID, cmn.DoesNotExist)
-	}
-	delete(m.Users, userID)
-	token, ok := m.tokens[userID]
-	delete(m.tokens, userID)
-	err := m.saveUsers()
+	list := make([]*userInfo, 0, len(m.Users))
+	for _, user := range m.Users {
+		list = append(list, &userInfo{UserID: user.UserID, Roles: user.Roles, Grants: user.Grants, Service: user.Service})
+	}
 	m.mtx.Unlock()
+	return list
+}
 
-	if ok {
-		go m.sendRevokedTokensToProxy(token.Token)
+// Adds a new custom role or updates an existing one. If the role changes,
+// tokens of all the users that have the role get revoked
+func (m *userManager) addRole(role *cmn.AuthRole) error {
+	if role.Name == "" {
+		return errors.New("role name is empty")
+	}
+	if isPredefinedRole(role.Name) {
+		return fmt.Errorf("predefined role %q cannot be modified", role.Name)
 	}
 
-	return err
+	return m.update(func() error {
+		if err := m.validatePerms(nil, role.Grants); err != nil {
+			return err
+		}
+		if _, ok := m.Roles[role.Name]; ok {
+			for _, user := range m.Users {
+				if cmn.StringInSlice(role.Name, user.Roles) {
+					m.revokeUserToken(user.UserID)
+				}
+			}
+			if err := m.saveUsers(); err != nil { // API keys could have been revoked
+				return err
+			}
+		}
+		m.Roles[role.Name] = role
+		return m.saveRoles()
+	})
 }
 
-// Generates a token for a user if user credentials are valid. If the token is
-// already generated and is not expired yet the existing token is returned.
-// Token includes information about userID, AWS/GCP creds and expire token time.
-// If a new token was generated then it sends the proxy a new valid token list
-func (m *userManager) issueToken(userID, pwd string) (string, error) {
-	var (
-		user    *userInfo
-		token   *tokenInfo
-		ok      bool
-		err     error
-		expires time.Time
-	)
+// Deletes a custom role that is not assigned to any user
+func (m *userManager) delRole(name string) error {
+	if isPredefinedRole(name) {
+		return fmt.Errorf("predefined role %q cannot be deleted", name)
+	}
+	return m.update(func() error {
+		if _, ok := m.Roles[name]; !ok {
+			return fmt.Errorf("role %q %s", name, cmn.DoesNotExist)
+		}
+		for _, user := range m.Users {
+			if cmn.StringInSlice(name, user.Roles) {
+				return fmt.Errorf("role %q is assigned to user %s", name, user.UserID)
+			}
+		}
+		delete(m.Roles, name)
+		return m.saveRoles()
+	})
+}
 
-	// check user name and pass in DB
+// Returns the list of all roles sorted by name
+func (m *userManager) roleList() []*cmn.AuthRole {
 	m.mtx.Lock()
-	defer m.mtx.Unlock()
-	if user, ok = m.Users[userID]; !ok {
-		return "", fmt.Errorf("invalid credentials")
+	list := make([]*cmn.AuthRole, 0, len(m.Roles))
+	for _, role := range m.Roles {
+		list = append(list, role)
 	}
-	passwordDecoded := user.passwordDecoded
-	creds := user.Creds
+	m.mtx.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
 
-	if passwordDecoded != pwd {
-		return "", fmt.Errorf("invalid username or password")
+// Deletes an existing user
+func (m *userManager) delUser(userID string) error {
+	if userID == conf.Auth.Username {
+		return errors.New("Super user cannot be deleted")
 	}
+	return m.update(func() error {
+		if _, ok := m.Users[userID]; !ok {
+			return fmt.Errorf("user %s %s", userID, cmn.DoesNotExist)
+		}
+		m.revokeUserToken(userID)
+		delete(m.Users, userID)
+		return m.saveUsers()
+	})
+}
 
-	// check if a user is already has got token. If existing token expired then
-	// delete it and reissue a new token
-	if token, ok = m.tokens[userID]; ok {
-		if token.Expires.After(time.Now()) {
-			return token.Token, nil
+// Generates a token for a user if user credentials are valid. Every login
+// gets a new token that includes information about userID, AWS/GCP creds and
+// expire token time. Only the token's ID is stored (to revoke the token later).
+// With the cluster database, login fails if the token cannot be shared with
+// other AuthN instances
+func (m *userManager) issueToken(userID, pwd string) (tokenString string, err error) {
+	err = m.update(func() (err error) {
+		var expires time.Time
+
+		// check user name and pass in DB
+		user, ok := m.Users[userID]
+		if !ok || user.Service {
+			return fmt.Errorf("invalid credentials")
+		}
+		if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(pwd)) != nil {
+			return fmt.Errorf("invalid username or password")
 		}
-		delete(m.tokens, userID)
-	}
 
-	// generate token
-	issued := time.Now()
-	if conf.Auth.ExpirePeriod == 0 {
-		expires = issued.Add(foreverTokenTime)
-	} else {
-		expires = issued.Add(conf.Auth.ExpirePeriod)
+		// generate token
+		issued := time.Now()
+		if conf.Auth.ExpirePeriod == 0 {
+			expires = issued.Add(foreverTokenTime)
+		} else {
+			expires = issued.Add(conf.Auth.ExpirePeriod)
+		}
+
+		id := cmn.GenUUID()
+		if tokenString, err = m.genToken(user, issued, expires, id, ""); err != nil {
+			return err
+		}
+
+		m.tokens[id] = &tokenInfo{
+			UserID:  userID,
+			Issued:  issued,
+			Expires: expires,
+		}
+		m.saveTokens()
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
+	return tokenString, nil
+}
 
-	// put all useful info into token: who owns the token, when it was issued,
-	// when it expires and credentials to log in AWS, GCP etc
-	t := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+// Puts all useful info into token: who owns the token, when it was issued,
+// when it expires, credentials to log in AWS, GCP etc, and the user's roles
+// along with the resulting permissions (enforced by proxies).
+// Must be called under lock
+func (m *userManager) genToken(user *userInfo, issued, expires time.Time, tokenID, keyID string) (string, error) {
+	claims := jwt.MapClaims{
 		"issued":   issued.Format(time.RFC822),
 		"expires":  expires.Format(time.RFC822),
-		"username": userID,
-		"creds":    creds,
-	})
+		"username": user.UserID,
+		"creds":    user.Creds,
+		"roles":    user.Roles,
+		"perms":    m.userGrants(user),
+	}
+	if tokenID != "" {
+		claims["jti"] = tokenID
+	}
+	if keyID != "" {
+		claims["apikey"] = keyID
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := t.SignedString([]byte(conf.Auth.Secret))
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token: %v", err)
 	}
-
-	token = &tokenInfo{
-		UserID:  userID,
-		Issued:  issued,
-		Expires: expires,
-		Token:   tokenString,
-	}
-	m.tokens[userID] = token
-
 	return tokenString, nil
 }
 
 // Delete existing token, a.k.a log out
-// If the token was removed successfully then it sends the proxy a new valid token list
+// The token gets revoked (on proxies) by its ID. Tokens issued by older AuthN
+// versions do not have IDs and are revoked as is
 func (m *userManager) revokeToken(token string) {
-	m.mtx.Lock()
-	for id, info := range m.tokens {
-		if info.Token == token {
-			delete(m.tokens, id)
-			break
+	var (
+		tokens      []string
+		ids         map[string]time.Time
+		claims, err = tokenClaims(token)
+		id, _       = claims["jti"].(string)
+	)
+	if err != nil || id == "" {
+		// revoke the token in all case to allow an admin to revoke
+		// an existing token even after cluster restart
+		tokens = []string{token}
+	} else {
+		expires := time.Now().Add(foreverTokenTime)
+		if s, ok := claims["expires"].(string); ok {
+			if t, err := time.Parse(time.RFC822, s); err == nil {
+				expires = t
+			}
+		}
+		err = m.update(func() error {
+			if info, ok := m.tokens[id]; ok {
+				expires = info.Expires
+				delete(m.tokens, id)
+				m.saveTokens()
+			}
+			return nil
+		})
+		if err != nil {
+			glog.Errorf("Failed to update AuthN DB: %v", err)
 		}
+		ids = map[string]time.Time{id: expires}
 	}
+	// the token is revoked even if the database has not been updated
+	m.mtx.Lock()
+	m.revoke(tokens, ids)
+	m.commitRevoked()
 	m.mtx.Unlock()
-
-	// send the token in all case to allow an admin to revoke
-	// an existing token even after cluster restart
-	go m.sendRevokedTokensToProxy(token)
 }
 
-// update list of valid token on a proxy
-func (m *userManager) sendRevokedTokensToProxy(tokens ...string) {
-	if len(tokens) == 0 {
-		return
-	}
-	if m.proxy.URL == "" {
-		glog.Warning("Primary proxy is not defined")
-		return
-	}
-
-	tokenList := ais.TokenList{Tokens: tokens}
-	body := cmn.MustMarshal(tokenList)
-	if err := m.proxyRequest(http.MethodDelete, cmn.Tokens, body); err != nil {
-		glog.Errorf("Failed to send token list: %v", err)
-	}
+// Validates the token issued by AuthN and returns its claims
+func tokenClaims(token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(conf.Auth.Secret), nil
+	})
+	return claims, err
 }
 
 func (m *userManager) userByToken(token string) (*userInfo, error) {
+	claims, err := tokenClaims(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token")
+	}
+	id, _ := claims["jti"].(string)
+
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
-
-	for id, info := range m.tokens {
-		if info.Token == token {
-			if info.Expires.Before(time.Now()) {
-				delete(m.tokens, id)
-				return nil, fmt.Errorf("token expired")
-			}
-
-			user, ok := m.Users[id]
-			if !ok {
-				return nil, fmt.Errorf("invalid token")
-			}
-
-			return user, nil
-		}
+	info, ok := m.tokens[id]
+	if !ok {
+		return nil, fmt.Errorf("token not found")
 	}
-
-	return nil, fmt.Errorf("token not found")
+	if info.Expires.Before(time.Now()) {
+		delete(m.tokens, id)
+		return nil, fmt.Errorf("token expired")
+	}
+	user, ok := m.Users[info.UserID]
+	if !ok {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return user, nil
 }
 
 // Generic function to send everything to primary proxy
@@ -331,57 +670,49 @@ func (m *userManager) proxyRequest(method, path string, injson []byte) error {
 	}
 }
 
-func (m *userManager) updateCredentials(userID, provider, userCreds string) (bool, error) {
-	m.mtx.Lock()
-	defer m.mtx.Unlock()
-
+func (m *userManager) updateCredentials(userID, provider, userCreds string) (changed bool, err error) {
 	if !cmn.IsValidProvider(provider) {
 		return false, fmt.Errorf("invalid cloud provider: %s", provider)
 	}
 
-	user, ok := m.Users[userID]
-	if !ok {
-		err := fmt.Errorf("user %s %s", userID, cmn.DoesNotExist)
-		return false, err
-	}
-
-	changed := user.Creds[provider] != userCreds
-	if changed {
-		user.Creds[provider] = userCreds
-		if token, ok := m.tokens[userID]; ok {
-			delete(m.tokens, userID)
-			go m.sendRevokedTokensToProxy(token.Token)
+	err = m.update(func() error {
+		user, ok := m.Users[userID]
+		if !ok {
+			return fmt.Errorf("user %s %s", userID, cmn.DoesNotExist)
 		}
-	}
 
-	if changed {
-		if err := m.saveUsers(); err != nil {
-			glog.Errorf("Delete credentials failed to save user list: %v", err)
+		changed = user.Creds[provider] != userCreds
+		if !changed {
+			return nil
 		}
+		user.Creds[provider] = userCreds
+		m.revoke(nil, m.delUserTokens(userID))
+		return m.saveUsers()
+	})
+	if err != nil {
+		return false, err
 	}
-
 	return changed, nil
 }
 
-func (m *userManager) deleteCredentials(userID, provider string) (bool, error) {
-	m.mtx.Lock()
-	defer m.mtx.Unlock()
-
+func (m *userManager) deleteCredentials(userID, provider string) (deleted bool, err error) {
 	if !cmn.IsValidProvider(provider) {
 		return false, fmt.Errorf("invalid cloud provider: %s", provider)
 	}
 
-	user, ok := m.Users[userID]
-	if !ok {
-		return false, fmt.Errorf("user %s %s", userID, cmn.DoesNotExist)
-	}
-	if _, ok = user.Creds[provider]; ok {
-		delete(user.Creds, provider)
-		if err := m.saveUsers(); err != nil {
-			glog.Errorf("Delete credentials failed to save user list: %v", err)
+	err = m.update(func() error {
+		user, ok := m.Users[userID]
+		if !ok {
+			return fmt.Errorf("user %s %s", userID, cmn.DoesNotExist)
 		}
-		return true, nil
+		if _, deleted = user.Creds[provider]; !deleted {
+			return nil
+		}
+		delete(user.Creds, provider)
+		return m.saveUsers()
+	})
+	if err != nil {
+		return false, err
 	}
-
-	return false, nil
+	return deleted, nil
 }
@@ -0,0 +1,370 @@
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+
+// distributed runs
+
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/bench/aisloader/stats"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Theory of operation
+//
+// Agents (`AIS_LOADER_AGENT_TOKEN=... aisloader -agent=0.0.0.0:9090`) are
+// started once on the client machines and wait for runs. An agent executes
+// whatever run it is sent, so it listens on localhost unless the host is
+// specified, and an agent that accepts remote connections requires the token
+// shared with the coordinator (AIS_LOADER_AGENT_TOKEN environment variable;
+// not a command line option so that it is not visible in the list of
+// processes).
+//
+// The coordinator (`aisloader -agents=host1:9090,...` along with the usual
+// options and the same AIS_LOADER_AGENT_TOKEN) lists the bucket, splits the
+// object names between the agents and sends each of them its share together
+// with the command line and the time to start at. An agent executes the run
+// as a child aisloader process, which reads the names from the file (instead
+// of listing the bucket), waits for the start time (clocks are assumed to be
+// in sync, e.g., by NTP) and writes its final stats to the report file. The
+// report, containing latency histograms, is returned to the coordinator which
+// merges the reports into the aggregated one.
+//
+// Object names generated by the agents do not collide: the coordinator
+// assigns each agent its loaderid (and loadernum, unless names are random).
+// Total PUT size and number of PUTs, if limited, are split equally.
+
+const (
+	agentRunPath  = "/v1/run"
+	agentTokenEnv = "AIS_LOADER_AGENT_TOKEN"
+	startDelay    = 5 * time.Second // for the agents to prepare the run
+)
+
+type (
+	// runRequest is sent by the coordinator to each agent.
+	runRequest struct {
+		Args  []string  `json:"args"`
+		Names []string  `json:"names"` // nil - the agent lists the bucket
+		Start time.Time `json:"start"`
+	}
+
+	opReport struct {
+		Req stats.HTTPReq    `json:"req"`
+		Lat *stats.Latencies `json:"latencies"`
+	}
+
+	// runReport is the final stats of the run on an agent.
+	runReport struct {
+		Start time.Time            `json:"start"`
+		End   time.Time            `json:"end"`
+		Ops   map[string]*opReport `json:"ops"`
+	}
+)
+
+///////////
+// agent //
+///////////
+
+func runAgent(addr string) {
+	var (
+		busy  atomic.Bool
+		token = os.Getenv(agentTokenEnv)
+	)
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		cmn.ExitInfof("invalid agent address %q: %v", addr, err)
+	}
+	if host == "" {
+		host = "localhost"
+		addr = net.JoinHostPort(host, port)
+	}
+	if token == "" && !isLoopback(host) {
+		cmn.ExitInfof("agent listening on %s requires the token shared with the coordinator: set %s", addr, agentTokenEnv)
+	}
+	http.HandleFunc(agentRunPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "invalid method "+r.Method, http.StatusMethodNotAllowed)
+			return
+		}
+		if !validAgentToken(r, token) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		if !busy.CAS(false, true) {
+			http.Error(w, "agent is busy with another run", http.StatusConflict)
+			return
+		}
+		defer busy.Store(false)
+
+		var req runRequest
+		if err := jsoniter.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		report, err := req.run()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(cmn.MustMarshal(report))
+	})
+
+	fmt.Printf("Agent listening on %s\n", addr)
+	if isLoopback(host) {
+		fmt.Println("Specify the host (e.g., -agent=0.0.0.0:" + port + ") to accept runs from other machines")
+	}
+	if err := http.ListenAndServe(addr, nil); err != nil {
+		cmn.ExitInfof("%s", err)
+	}
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func validAgentToken(r *http.Request, token string) bool {
+	if token == "" {
+		return true
+	}
+	auth := r.Header.Get(cmn.HeaderAuthorization)
+	return subtle.ConstantTimeCompare([]byte(auth), []byte(cmn.HeaderBearer+" "+token)) == 1
+}
+
+// run executes the run as a child process and returns its report.
+func (req *runRequest) run() (*runReport, error) {
+	dir, err := ioutil.TempDir("", "aisloader")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		reportFile = filepath.Join(dir, "report.json")
+		args       = append(req.Args, "-report="+reportFile, "-starttime="+req.Start.Format(time.RFC3339Nano))
+	)
+	if req.Names != nil {
+		namesFile := filepath.Join(dir, "names")
+		if err := ioutil.WriteFile(namesFile, []byte(strings.Join(req.Names, "\n")), 0600); err != nil {
+			return nil, err
+		}
+		args = append(args, "-objnames="+namesFile)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("%s Starting run: %v\n", prettyTimeStamp(), req.Args)
+	cmd := exec.Command(exe, args...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("run %v failed: %v", req.Args, err)
+	}
+
+	b, err := ioutil.ReadFile(reportFile)
+	if err != nil {
+		return nil, err
+	}
+	report := &runReport{}
+	if err := jsoniter.Unmarshal(b, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// writeReport writes final stats of the run (executed by the agent).
+func writeReport(start, end time.Time) error {
+	report := runReport{Start: start, End: end, Ops: make(map[string]*opReport, len(allOpStats))}
+	for op, s := range allOpStats {
+		report.Ops[opNames[op]] = &opReport{Req: s.req, Lat: s.lat}
+	}
+	return ioutil.WriteFile(runParams.reportFile, cmn.MustMarshal(report), 0600)
+}
+
+/////////////////
+// coordinator //
+/////////////////
+
+// Command line arguments to pass to the agents: without -agents.
+func agentArgs() []string {
+	args := make([]string, 0, len(cmdArgs))
+	for i := 0; i < len(cmdArgs); i++ {
+		arg := strings.TrimLeft(cmdArgs[i], "-")
+		if arg == "agents" {
+			i++ // the value follows
+			continue
+		}
+		if strings.HasPrefix(arg, "agents=") {
+			continue
+		}
+		args = append(args, cmdArgs[i])
+	}
+	return args
+}
+
+func newRunRequests(agents []string, start time.Time) []*runRequest {
+	var (
+		n     = len(agents)
+		names []string
+		reqs  = make([]*runRequest, n)
+	)
+	if bucketObjsNames != nil {
+		names = bucketObjsNames.Names()
+	}
+	for i := range agents {
+		req := &runRequest{Start: start}
+		req.Args = append(agentArgs(), "-cleanup=false", "-loaderid="+strconv.Itoa(i))
+		if !useRandomObjName {
+			req.Args = append(req.Args, "-loadernum="+strconv.Itoa(n))
+		}
+		if runParams.putSizeUpperBound > 0 {
+			req.Args = append(req.Args, "-totalputsize="+strconv.FormatInt(cmn.DivCeil(runParams.putSizeUpperBound, int64(n)), 10))
+		}
+		if runParams.maxputs > 0 {
+			req.Args = append(req.Args, "-maxputs="+strconv.FormatUint((runParams.maxputs+uint64(n)-1)/uint64(n), 10))
+		}
+
+		switch {
+		case bucketObjsNames == nil:
+		case len(names) < n:
+			// too few to split
+			req.Names = names
+		default:
+			req.Names = names[i*len(names)/n : (i+1)*len(names)/n]
+		}
+		reqs[i] = req
+	}
+	return reqs
+}
+
+func postRun(agent string, req *runRequest) (*runReport, error) {
+	hreq, err := http.NewRequest(http.MethodPost, "http://"+agent+agentRunPath, bytes.NewReader(cmn.MustMarshal(req)))
+	if err != nil {
+		return nil, err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	if token := os.Getenv(agentTokenEnv); token != "" {
+		hreq.Header.Set(cmn.HeaderAuthorization, cmn.HeaderBearer+" "+token)
+	}
+	resp, err := http.DefaultClient.Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+	report := &runReport{}
+	if err := jsoniter.NewDecoder(resp.Body).Decode(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// runCoordinator drives the run on the agents and reports the aggregated stats.
+func runCoordinator() error {
+	var (
+		agents  = strings.Split(runParams.agents, ",")
+		start   = time.Now().Add(startDelay)
+		reqs    = newRunRequests(agents, start)
+		reports = make([]*runReport, len(agents))
+		errs    = make([]error, len(agents))
+		wg      = &sync.WaitGroup{}
+	)
+	fmt.Printf("%s Starting run on %d agents at %s\n", prettyTimeStamp(), len(agents), start.Format("15:04:05"))
+	for i, agent := range agents {
+		wg.Add(1)
+		go func(i int, agent string) {
+			reports[i], errs[i] = postRun(agent, reqs[i])
+			wg.Done()
+		}(i, agent)
+	}
+	wg.Wait()
+
+	var (
+		end    = start
+		failed int
+	)
+	allOpStats = make(map[int]*opStats)
+	fmt.Println()
+	for i, report := range reports {
+		if errs[i] != nil {
+			fmt.Printf("Agent %s: run failed: %v\n", agents[i], errs[i])
+			failed++
+			continue
+		}
+		// validate the agent's latencies first so that a bad report is not counted partially
+		lats := make(map[string]*stats.Latencies, len(report.Ops))
+		for name, r := range report.Ops {
+			lat := stats.NewLatencies()
+			if errs[i] = lat.Aggregate(r.Lat); errs[i] != nil {
+				errs[i] = fmt.Errorf("%s: %v", name, errs[i])
+				break
+			}
+			lats[name] = lat
+		}
+		if errs[i] != nil {
+			fmt.Printf("Agent %s: invalid report: %v\n", agents[i], errs[i])
+			failed++
+			continue
+		}
+		var cnt, bytes, errCnt int64
+		for name, r := range report.Ops {
+			cnt += r.Req.Total()
+			bytes += r.Req.TotalBytes()
+			errCnt += r.Req.TotalErrs()
+			op := opByName(name)
+			if _, ok := allOpStats[op]; !ok {
+				allOpStats[op] = &opStats{req: stats.NewHTTPReq(start), lat: stats.NewLatencies()}
+			}
+			allOpStats[op].req.Aggregate(r.Req)
+			cmn.AssertNoErr(allOpStats[op].lat.Aggregate(lats[name]))
+		}
+		if report.End.After(end) {
+			end = report.End
+		}
+		fmt.Printf("Agent %s: %s ops, %s, %s errors in %v\n", agents[i],
+			prettyNumber(cnt), prettyBytes(bytes), prettyNumber(errCnt), report.End.Sub(report.Start).Round(time.Millisecond))
+	}
+	if failed == len(agents) {
+		return fmt.Errorf("run failed on all agents")
+	}
+
+	fmt.Printf("\nAggregated stats of %d agents:\n", len(agents)-failed)
+	writeOpStats(os.Stdout, runParams.jsonFormat, end)
+	fmt.Println()
+	return nil
+}
+
+func opByName(name string) int {
+	for op, n := range opNames {
+		if n == name {
+			return op
+		}
+	}
+	return -1
+}
@@ -29,6 +29,15 @@
 //    aisloader -getloaderid (0x0)
 //    aisloader -loaderid=10 -getloaderid (0xa)
 //    aisloader -loaderid=loaderstring -loaderidhashlen=8 -getloaderid (0xdb)
+// 10. Replay recorded trace twice as fast, PUT objects it reads beforehand:
+//    aisloader -bucket=nvais -trace=/tmp/trace.csv -tracespeed=2 -traceprepare
+// 11. Run the mix of operations over multiple buckets described by the workload spec (see workload.go):
+//    aisloader -workload=/tmp/workload.yaml -duration 10m -numworkers=64
+// 12. Distributed run: start agents on client machines, then coordinate the run (see distributed.go):
+//    AIS_LOADER_AGENT_TOKEN=secret aisloader -agent=0.0.0.0:9090
+//    AIS_LOADER_AGENT_TOKEN=secret aisloader -agents=client1:9090,client2:9090 -bucket=nvais -duration 10m -numworkers=64 -pctput=20
+// 13. Open-loop GETs sweeping the target rate from 1000 to 10000 req/s, each rate for 1m (see rate.go):
+//    aisloader -bucket=nvais -pctput=0 -numworkers=256 -ratesweep=1000:10000:1000 -ratestep=1m
 
 package main
 
@@ -36,6 +45,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"math/rand"
 	"net/http"
@@ -54,6 +64,7 @@ import (
 	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/bench/aisloader/namegetter"
 	"github.com/NVIDIA/aistore/bench/aisloader/stats"
+	"github.com/NVIDIA/aistore/bench/aisloader/trace"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/containers"
 	"github.com/NVIDIA/aistore/stats/statsd"
@@ -66,6 +77,12 @@ const (
 	opPut = iota
 	opGet
 	opConfig
+	opRangeGet
+	opAppend
+	opHead
+	opDelete
+	opList
+	opRename
 
 	myName           = "loader"
 	dockerEnvFile    = "/tmp/docker_ais/deploy.env" // filepath of Docker deployment config
@@ -75,25 +92,45 @@ const (
 var (
 	version = "1.0"
 	build   string
+
+	opNames = map[int]string{
+		opPut:      http.MethodPut,
+		opGet:      http.MethodGet,
+		opConfig:   "CONFIG",
+		opRangeGet: "RANGE-GET",
+		opAppend:   "APPEND",
+		opHead:     http.MethodHead,
+		opDelete:   http.MethodDelete,
+		opList:     "LIST",
+		opRename:   "RENAME",
+	}
 )
 
 type (
 	workOrder struct {
-		op        int
-		proxyURL  string
-		bck       cmn.Bck
-		objName   string // In the format of 'virtual dir' + "/" + objName
-		size      int64
-		err       error
-		start     time.Time
-		end       time.Time
-		latencies tutils.HTTPLatencies
+		op         int
+		proxyURL   string
+		bck        cmn.Bck
+		objName    string // In the format of 'virtual dir' + "/" + objName
+		size       int64
+		readOff    int64 // GET range
+		readLen    int64
+		newName    string      // RENAME
+		pageMarker string      // LIST
+		spec       *bucketSpec // workload bucket (if any)
+		err        error
+		scheduled  time.Time // open-loop: time to send at (latency is measured from)
+		step       int       // open-loop: step of the rate schedule
+		start      time.Time
+		end        time.Time
+		latencies  tutils.HTTPLatencies
 	}
 
 	// nolint:maligned // no performance critical code
 	params struct {
 		seed              int64 // random seed; UnixNano() if omitted
 		putSizeUpperBound int64
+		traceSpeed        float64 // 1 - original timing, 0 - as fast as possible
 		minSize           int64
 		maxSize           int64
 		readOff           int64 // read offset
@@ -116,6 +153,20 @@ type (
 		readOffStr           string // read offset
 		readLenStr           string // read length
 		subDir               string
+		traceFile            string // replay the trace instead of generating the workload
+		traceFormat          string
+		workloadFile         string // run the mix of operations described by the spec
+		agent                string // listen address of the agent
+		agents               string // addresses of the agents driven by the coordinator
+		objNamesFile         string // object names to use instead of listing the bucket
+		reportFile           string // final stats of the run on the agent
+		startTimeStr         string
+		startTime            time.Time // synchronized start of the run on the agents
+		rateStr              string    // open-loop target rate
+		rateStartStr         string
+		rateSweep            string
+		rateRamp             time.Duration
+		rateStep             time.Duration
 		duration             cmn.DurationExt // stop after the run for at least that much
 		cleanUp              cmn.BoolExt
 
@@ -141,6 +192,7 @@ type (
 		statsdRequired bool
 		dryRun         bool // true: print configuration and parameters that aisloader will use at runtime
 		traceHTTP      bool // true: trace http latencies as per tutils.HTTPLatencies & https://golang.org/pkg/net/http/httptrace
+		tracePrepare   bool // true: PUT objects that the trace reads before writing
 	}
 
 	// sts records accumulated puts/gets information.
@@ -174,8 +226,8 @@ var (
 	bucketObjsNames  namegetter.ObjectNameGetter
 	statsPrintHeader = "%-10s%-6s%-22s\t%-22s\t%-36s\t%-22s\t%-10s\n"
 	statsdC          statsd.Client
-	getPending       int64
-	putPending       int64
+	getPending       atomic.Int64
+	putPending       atomic.Int64
 	traceHTTPSig     atomic.Bool
 
 	flagUsage   bool
@@ -192,28 +244,30 @@ var (
 
 	numGets atomic.Int64
 
+	cmdArgs []string // command line arguments (passed on to the agents)
+
+	// Work orders posted by a separate goroutine (trace replay, open-loop
+	// rate) rather than upon completion of the previous ones.
+	postStop     chan struct{}
+	postDone     chan struct{} // nil once posting has ended
+	postEnded    bool
+	postedCnt    atomic.Int64
+	completedCnt int64      // updated by the main loop only
+	genMu        sync.Mutex // serializes generating work orders with completing them
+
 	envVars      = tutils.ParseEnvVariables(dockerEnvFile) // Gets the fields from the .env file from which the docker was deployed
 	dockerHostIP = envVars["PRIMARY_HOST_IP"]              // Host IP of primary cluster
 	dockerPort   = envVars["PORT"]
 )
 
 func (wo *workOrder) String() string {
-	var errstr, opName string
-	switch wo.op {
-	case opGet:
-		opName = http.MethodGet
-	case opPut:
-		opName = http.MethodPut
-	case opConfig:
-		opName = "CONFIG"
-	}
-
+	var errstr string
 	if wo.err != nil {
 		errstr = ", error: " + wo.err.Error()
 	}
 
 	return fmt.Sprintf("WO: %s/%s, start:%s end:%s, size: %d, type: %s%s",
-		wo.bck, wo.objName, wo.start.Format(time.StampMilli), wo.end.Format(time.StampMilli), wo.size, opName, errstr)
+		wo.bck, wo.objName, wo.start.Format(time.StampMilli), wo.end.Format(time.StampMilli), wo.size, opNames[wo.op], errstr)
 }
 
 func loaderMaskFromTotalLoaders(totalLoaders uint64) uint {
@@ -290,6 +344,38 @@ func parseCmdLine() (params, error) {
 	f.BoolVar(&p.dryRun, "dry-run", false, "true: show the configuration and parameters that aisloader will use for benchmark")
 	f.BoolVar(&p.traceHTTP, "trace-http", false, "true: trace HTTP latencies") // see tutils.HTTPLatencies
 
+	//
+	// trace replay
+	//
+	f.StringVar(&p.traceFile, "trace", "", "Replay the workload recorded in this file (instead of generating one; runs until the end of the trace unless duration is specified)")
+	f.StringVar(&p.traceFormat, "traceformat", trace.FormatCSV,
+		fmt.Sprintf("Format of the trace: %s (timestamp,op,bucket,object[,size]) | %s (AIS proxy log, verbosity 4)", trace.FormatCSV, trace.FormatAISLog))
+	f.Float64Var(&p.traceSpeed, "tracespeed", 1, "Trace replay speed: 1 - original timing, 2 - twice as fast, etc.; 0 - as fast as possible")
+	f.BoolVar(&p.tracePrepare, "traceprepare", false, "true: prior to replay, create missing ais buckets and PUT objects that the trace reads before writing")
+
+	//
+	// workload spec
+	//
+	f.StringVar(&p.workloadFile, "workload", "", "YAML spec of weighted operations over multiple buckets to run (instead of -bucket and -pctput)")
+
+	//
+	// distributed runs
+	//
+	f.StringVar(&p.agent, "agent", "", "Run as agent: listen on this address (e.g. 0.0.0.0:9090; localhost if the host is omitted) and execute runs on behalf of the coordinator")
+	f.StringVar(&p.agents, "agents", "", "Coordinate the run executed by the agents: comma-separated addresses (host:port) of the agents")
+	f.StringVar(&p.objNamesFile, "objnames", "", "File with names of objects (one per line) to use instead of listing the bucket (set by the agent)")
+	f.StringVar(&p.startTimeStr, "starttime", "", "Time (RFC3339) to start the run at (set by the agent)")
+	f.StringVar(&p.reportFile, "report", "", "File to write final stats to, in the format merged by the coordinator (set by the agent)")
+
+	//
+	// open-loop target rate
+	//
+	f.StringVar(&p.rateStr, "rate", "", "Open-loop: issue requests at this target rate regardless of their completion - requests per second or, with size suffix (e.g. 100MB), bytes per second")
+	f.StringVar(&p.rateStartStr, "ratestart", "0", "Open-loop: target rate to linearly ramp from (to -rate) during -rateramp")
+	f.DurationVar(&p.rateRamp, "rateramp", 0, "Open-loop: duration of the linear ramp from -ratestart to -rate (0 - no ramp)")
+	f.StringVar(&p.rateSweep, "ratesweep", "", "Open-loop: target rates to run, each for -ratestep, to produce throughput vs latency curve - comma-separated or start:end:step")
+	f.DurationVar(&p.rateStep, "ratestep", time.Minute, "Open-loop: duration of each rate of -ratesweep")
+
 	f.Parse(os.Args[1:])
 
 	if len(os.Args[1:]) == 0 {
@@ -297,6 +383,7 @@ func parseCmdLine() (params, error) {
 		os.Exit(0)
 	}
 
+	cmdArgs = os.Args[1:]
 	os.Args = []string{os.Args[0]}
 	flag.Parse() // Called so that imported packages don't complain
 
@@ -339,6 +426,55 @@ func parseCmdLine() (params, error) {
 		p.maxSize = cmn.GiB
 	}
 
+	if p.agents != "" {
+		if p.agent != "" || p.traceFile != "" || p.workloadFile != "" || p.rateStr != "" || p.rateSweep != "" {
+			return params{}, fmt.Errorf("coordinator cannot be used together with agent, trace, workload spec or target rate")
+		}
+		if p.loaderIDHashLen > 0 {
+			return params{}, fmt.Errorf("coordinator assigns loaderids, loaderidhashlen cannot be used")
+		}
+	}
+	if p.startTimeStr != "" {
+		if p.startTime, err = time.Parse(time.RFC3339Nano, p.startTimeStr); err != nil {
+			return params{}, fmt.Errorf("invalid start time %q: %v", p.startTimeStr, err)
+		}
+	}
+
+	if p.workloadFile != "" {
+		if p.traceFile != "" || p.getConfig || p.numEpochs > 0 {
+			return params{}, fmt.Errorf("workload spec cannot be used together with trace, getconfig or epochs")
+		}
+	}
+
+	if p.traceFile != "" {
+		if p.traceFormat != trace.FormatCSV && p.traceFormat != trace.FormatAISLog {
+			return params{}, fmt.Errorf("invalid option: trace format %q", p.traceFormat)
+		}
+		if p.traceSpeed < 0 {
+			return params{}, fmt.Errorf("invalid option: trace speed %v", p.traceSpeed)
+		}
+		if p.getConfig || p.numEpochs > 0 {
+			return params{}, fmt.Errorf("trace replay cannot be used together with getconfig or epochs")
+		}
+		if !p.duration.IsSet {
+			// run until the end of the trace
+			p.duration.Val = time.Duration(math.MaxInt64)
+		}
+	}
+
+	if p.rateStr != "" || p.rateSweep != "" {
+		if p.traceFile != "" {
+			return params{}, fmt.Errorf("target rate cannot be used together with trace")
+		}
+		if rateSched, err = newRateSched(&p); err != nil {
+			return params{}, err
+		}
+		if rateSched.sweep && !p.duration.IsSet {
+			// run until the end of the sweep
+			p.duration.Val = time.Duration(math.MaxInt64)
+		}
+	}
+
 	if !p.duration.IsSet && p.putSizeUpperBound != 0 {
 		// user specified putSizeUpperBound, but not duration, override default 1 minute
 		// and run aisloader until putSizeUpperBound is reached
@@ -475,7 +611,9 @@ func parseCmdLine() (params, error) {
 	}
 
 	if !p.cleanUp.IsSet {
-		p.cleanUp.Val = cmn.IsProviderAIS(p.bck)
+		// replayed objects are not tracked, only the bucket would be destroyed;
+		// workload buckets are never destroyed unless asked to
+		p.cleanUp.Val = cmn.IsProviderAIS(p.bck) && p.traceFile == "" && p.workloadFile == ""
 	}
 
 	// For Dry-Run on Docker
@@ -608,6 +746,11 @@ func main() {
 		cmn.ExitInfof("%s", err)
 	}
 
+	if runParams.agent != "" {
+		runAgent(runParams.agent)
+		return
+	}
+
 	if runParams.getLoaderID {
 		fmt.Printf("0x%x\n", suffixID)
 		if useRandomObjName {
@@ -617,10 +760,10 @@ func main() {
 	}
 
 	// If neither duration nor put upper bound is specified, it is a no op.
-	// Note that stoppable prevents being a no op
+	// Note that stoppable (as well as trace replay) prevents being a no op
 	// This can be used as a cleaup only run (no put no get).
 	if runParams.duration.Val == 0 {
-		if runParams.putSizeUpperBound == 0 && !runParams.stoppable {
+		if runParams.putSizeUpperBound == 0 && !runParams.stoppable && runParams.traceFile == "" {
 			if runParams.cleanUp.Val {
 				cleanUp()
 			}
@@ -639,11 +782,32 @@ func main() {
 		}
 	}
 
-	if err := setupBucket(&runParams); err != nil {
+	if runParams.workloadFile != "" {
+		if err := loadWorkload(runParams.workloadFile); err != nil {
+			cmn.ExitInfof("%s", err)
+		}
+		for _, b := range workload.Buckets {
+			p := runParams
+			p.bck = b.bck
+			if err := setupBucket(&p); err != nil {
+				cmn.ExitInfof("%s", err)
+			}
+		}
+	} else if err := setupBucket(&runParams); err != nil {
 		cmn.ExitInfof("%s", err)
 	}
 
-	if !runParams.getConfig {
+	if runParams.workloadFile != "" {
+		if err := workload.bootstrap(); err != nil {
+			cmn.ExitInfof("%s", err)
+		}
+	} else if runParams.traceFile != "" {
+		if runParams.tracePrepare && !runParams.dryRun {
+			if err := prepareTrace(); err != nil {
+				cmn.ExitInfof("%s", err)
+			}
+		}
+	} else if !runParams.getConfig {
 		err = bootStrap()
 		if err != nil {
 			return
@@ -664,7 +828,25 @@ func main() {
 	}
 
 	if runParams.cleanUp.Val {
-		fmt.Printf("BEWARE: cleanup is enabled, bucket %s will be destroyed after the run!\n", runParams.bck)
+		if runParams.workloadFile != "" {
+			fmt.Println("BEWARE: cleanup is enabled, ais buckets of the workload will be destroyed after the run!")
+		} else {
+			fmt.Printf("BEWARE: cleanup is enabled, bucket %s will be destroyed after the run!\n", runParams.bck)
+		}
+	}
+
+	if runParams.agents != "" {
+		if err := runCoordinator(); err != nil {
+			cmn.ExitInfof("%s", err)
+		}
+		if runParams.cleanUp.Val {
+			// objects written by the agents are not known
+			if !runParams.getConfig && bootStrap() != nil {
+				return
+			}
+			cleanUp()
+		}
+		return
 	}
 
 	host, err := os.Hostname()
@@ -691,6 +873,11 @@ func main() {
 		go worker(workOrders, workOrderResults, wg, &numGets)
 	}
 
+	if !runParams.startTime.IsZero() {
+		fmt.Printf("Starting at %s\n", runParams.startTime.Format("15:04:05.000"))
+		time.Sleep(time.Until(runParams.startTime))
+	}
+
 	timer := time.NewTimer(runParams.duration.Val)
 
 	var statsTicker *time.Ticker
@@ -724,10 +911,17 @@ func main() {
 
 	preWriteStats(statsWriter, runParams.jsonFormat)
 
-	// Get the workers started
-	for i := 0; i < runParams.numWorkers; i++ {
-		if err = postNewWorkOrder(); err != nil {
-			break
+	switch {
+	case runParams.traceFile != "":
+		startPosting(replayTrace)
+	case rateSched != nil:
+		startPosting(rateSched.run)
+	default:
+		// Get the workers started
+		for i := 0; i < runParams.numWorkers; i++ {
+			if err = postNewWorkOrder(); err != nil {
+				break
+			}
 		}
 	}
 
@@ -737,6 +931,10 @@ func main() {
 
 MainLoop:
 	for {
+		if postEnded && postedCnt.Load() == completedCnt {
+			break
+		}
+
 		if runParams.putSizeUpperBound != 0 &&
 			accumulatedStats.put.TotalBytes() >= runParams.putSizeUpperBound {
 			break
@@ -764,6 +962,8 @@ MainLoop:
 		select {
 		case <-timer.C:
 			break MainLoop
+		case <-postDone:
+			postEnded, postDone = true, nil
 		case wo := <-workOrderResults:
 			completeWorkOrder(wo)
 			if runParams.statsShowInterval == 0 && runParams.putSizeUpperBound != 0 {
@@ -771,6 +971,10 @@ MainLoop:
 				intervalStats = newStats(time.Now())
 			}
 
+			if postStop != nil {
+				// work orders are posted by the separate goroutine
+				break
+			}
 			if err := postNewWorkOrder(); err != nil {
 				_, _ = fmt.Fprint(os.Stderr, err.Error())
 				break MainLoop
@@ -803,17 +1007,39 @@ MainLoop:
 Done:
 	timer.Stop()
 	statsTicker.Stop()
+	if postStop != nil {
+		// must not post to the closed channel
+		close(postStop)
+		if postDone != nil {
+			<-postDone
+		}
+	}
 	close(workOrders)
-	wg.Wait() // wait until all workers are done (notified by closing work order channel)
+	go func() {
+		wg.Wait() // wait until all workers are done (notified by closing work order channel)
+		close(workOrderResults)
+	}()
 
-	// Process left over work orders
-	close(workOrderResults)
+	// Process left over work orders (when posted by the separate goroutine
+	// there may be more of them than the results channel can hold)
 	for wo := range workOrderResults {
 		completeWorkOrder(wo)
 	}
 
-	fmt.Printf("\nActual run duration: %v\n", time.Since(tsStart))
+	tsEnd := time.Now()
+	fmt.Printf("\nActual run duration: %v\n", tsEnd.Sub(tsStart))
+	if runParams.traceFile != "" {
+		printReplayStats()
+	}
+	if rateSched != nil {
+		printRateStats()
+	}
 	finalizeStats(statsWriter)
+	if runParams.reportFile != "" {
+		if err := writeReport(tsStart, tsEnd); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+		}
+	}
 	if runParams.cleanUp.Val {
 		cleanUp()
 	}
@@ -829,23 +1055,23 @@ func newPutWorkOrder() (*workOrder, error) {
 		return nil, err
 	}
 
-	var size int64
-	if runParams.maxSize == runParams.minSize {
-		size = runParams.minSize
-	} else {
-		size = rnd.Int63n(runParams.maxSize-runParams.minSize) + runParams.minSize
-	}
-
-	putPending++
+	putPending.Inc()
 	return &workOrder{
 		proxyURL: runParams.proxyURL,
 		bck:      runParams.bck,
 		op:       opPut,
 		objName:  objName,
-		size:     size,
+		size:     randomSize(),
 	}, nil
 }
 
+func randomSize() int64 {
+	if runParams.maxSize == runParams.minSize {
+		return runParams.minSize
+	}
+	return rnd.Int63n(runParams.maxSize-runParams.minSize) + runParams.minSize
+}
+
 func generatePutObjectName() (string, error) {
 	cnt := objNameCnt.Inc()
 	if runParams.maxputs != 0 && cnt-1 == runParams.maxputs {
@@ -885,12 +1111,14 @@ func newGetWorkOrder() (*workOrder, error) {
 		return nil, fmt.Errorf("no objects in bucket")
 	}
 
-	getPending++
+	getPending.Inc()
 	return &workOrder{
 		proxyURL: runParams.proxyURL,
 		bck:      runParams.bck,
 		op:       opGet,
 		objName:  bucketObjsNames.ObjName(),
+		readOff:  runParams.readOff,
+		readLen:  runParams.readLen,
 	}, nil
 }
 
@@ -901,30 +1129,48 @@ func newGetConfigWorkOrder() *workOrder {
 	}
 }
 
-func postNewWorkOrder() (err error) {
-	var wo *workOrder
-
+func newWorkOrder() (wo *workOrder, err error) {
 	if runParams.getConfig {
 		wo = newGetConfigWorkOrder()
+	} else if runParams.workloadFile != "" {
+		if wo, err = workload.newWorkOrder(); err != nil {
+			return nil, err
+		}
 	} else {
 		if rnd.Intn(99) < runParams.putPct {
 			if wo, err = newPutWorkOrder(); err != nil {
-				return err
+				return nil, err
 			}
 		} else {
 			if wo, err = newGetWorkOrder(); err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 
 	cmn.Assert(wo != nil)
+	return wo, nil
+}
+
+func postNewWorkOrder() error {
+	wo, err := newWorkOrder()
+	if err != nil {
+		return err
+	}
 	workOrders <- wo
 	return nil
 }
 
+// startPosting starts the goroutine that posts work orders (instead of the
+// main loop posting a new one upon completion of the previous).
+func startPosting(post func(stop <-chan struct{}, done chan<- struct{})) {
+	postStop = make(chan struct{})
+	postDone = make(chan struct{})
+	go post(postStop, postDone)
+}
+
 func validateWorkOrder(wo *workOrder, delta time.Duration) error {
-	if wo.op == opGet || wo.op == opPut {
+	if wo.op == opGet || wo.op == opRangeGet || wo.op == opPut {
 		if delta == 0 {
 			return fmt.Errorf("%s has the same start time as end time", wo)
 		}
@@ -933,7 +1179,11 @@ func validateWorkOrder(wo *workOrder, delta time.Duration) error {
 }
 
 func completeWorkOrder(wo *workOrder) {
-	delta := cmn.TimeDelta(wo.end, wo.start)
+	start := wo.start
+	if !wo.scheduled.IsZero() {
+		start = wo.scheduled
+	}
+	delta := cmn.TimeDelta(wo.end, start)
 
 	if wo.err == nil && traceHTTPSig.Load() {
 		var lat *statsd.MetricLatsAgg
@@ -958,15 +1208,27 @@ func completeWorkOrder(wo *workOrder) {
 		}
 	}
 
+	genMu.Lock()
+	defer genMu.Unlock()
+	if postStop != nil {
+		completedCnt++
+	}
+	if wo.spec != nil {
+		wo.spec.complete(wo)
+	}
+
 	if err := validateWorkOrder(wo, delta); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "[ERROR] %s", err.Error())
 		return
 	}
 
+	addOpStats(wo, delta)
+	if rateSched != nil {
+		rateSched.complete(wo, delta)
+	}
 	switch wo.op {
-	case opGet:
-		getPending--
-		intervalStats.statsd.Get.AddPending(getPending)
+	case opGet, opRangeGet:
+		intervalStats.statsd.Get.AddPending(getPending.Dec())
 		if wo.err == nil {
 			intervalStats.get.Add(wo.size, delta)
 			intervalStats.statsd.Get.Add(wo.size, delta)
@@ -976,10 +1238,11 @@ func completeWorkOrder(wo *workOrder) {
 			intervalStats.get.AddErr()
 		}
 	case opPut:
-		putPending--
-		intervalStats.statsd.Put.AddPending(putPending)
+		intervalStats.statsd.Put.AddPending(putPending.Dec())
 		if wo.err == nil {
-			bucketObjsNames.AddObjName(wo.objName)
+			if bucketObjsNames != nil {
+				bucketObjsNames.AddObjName(wo.objName)
+			}
 			intervalStats.put.Add(wo.size, delta)
 			intervalStats.statsd.Put.Add(wo.size, delta)
 		} else {
@@ -996,7 +1259,9 @@ func completeWorkOrder(wo *workOrder) {
 			intervalStats.getConfig.AddErr()
 		}
 	default:
-		// Should not be here
+		if wo.err != nil {
+			fmt.Printf("%s failed: %v\n", opNames[wo.op], wo.err)
+		}
 	}
 }
 
@@ -1019,8 +1284,14 @@ func cleanUp() {
 		wg.Wait()
 	}
 
-	if cmn.IsProviderAIS(runParams.bck) {
-		baseParams := tutils.BaseAPIParams(runParams.proxyURL)
+	baseParams := tutils.BaseAPIParams(runParams.proxyURL)
+	if runParams.workloadFile != "" {
+		for _, b := range workload.Buckets {
+			if cmn.IsProviderAIS(b.bck) {
+				api.DestroyBucket(baseParams, b.bck)
+			}
+		}
+	} else if cmn.IsProviderAIS(runParams.bck) {
 		api.DestroyBucket(baseParams, runParams.bck)
 	}
 	fmt.Println(prettyTimeStamp() + " Done")
@@ -1063,7 +1334,15 @@ func _cleanup(objs []string, wg *sync.WaitGroup) {
 
 // bootStrap boot straps existing objects in the bucket
 func bootStrap() error {
-	names, err := tutils.ListObjectsFast(runParams.proxyURL, runParams.bck, "")
+	var (
+		names []string
+		err   error
+	)
+	if runParams.objNamesFile != "" {
+		names, err = readObjNames(runParams.objNamesFile)
+	} else {
+		names, err = tutils.ListObjectsFast(runParams.proxyURL, runParams.bck, "")
+	}
 	if err != nil {
 		fmt.Printf("Failed to list bucket %s, proxy %s, err: %v\n",
 			runParams.bck, runParams.proxyURL, err)
@@ -1101,3 +1380,14 @@ func bootStrap() error {
 	bucketObjsNames.Init(names, rnd)
 	return err
 }
+
+func readObjNames(path string) ([]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return []string{}, nil
+	}
+	return strings.Split(string(b), "\n"), nil
+}
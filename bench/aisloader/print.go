@@ -67,6 +67,25 @@ var examples = `
 	$ aisloader -loaderid=10 -getloaderid (0xa)
 	$ aisloader -loaderid=loaderstring -loaderidhashlen=8 -getloaderid (0xdb)
 
+12. Replay recorded trace twice as fast; beforehand, PUT objects that the trace reads before writing:
+
+	$ aisloader -bucket=nvais -trace=/tmp/trace.csv -tracespeed=2 -traceprepare
+
+13. Run the mix of operations over multiple buckets described by the workload spec (see README):
+
+	$ aisloader -workload=/tmp/workload.yaml -duration 10m -numworkers=64
+
+14. Distributed run: start agents on the client machines, then coordinate the run with the merged report (see README):
+
+	$ AIS_LOADER_AGENT_TOKEN=secret aisloader -agent=0.0.0.0:9090
+	$ AIS_LOADER_AGENT_TOKEN=secret aisloader -agents=client1:9090,client2:9090 -bucket=nvais -duration 10m -numworkers=64 -pctput=20
+
+15. Open-loop GETs at the target rate ramping up from 100 to 5000 req/s during the first 5m, then sweeping the rates
+    to get throughput vs latency curve (see README):
+
+	$ aisloader -bucket=nvais -pctput=0 -numworkers=256 -duration 30m -ratestart=100 -rate=5000 -rateramp=5m
+	$ aisloader -bucket=nvais -pctput=0 -numworkers=256 -ratesweep=1000:10000:1000 -ratestep=1m
+
 `
 
 func printUsage(f *flag.FlagSet) {
@@ -159,6 +178,18 @@ func postWriteStats(to io.Writer, jsonFormat bool) {
 func finalizeStats(to io.Writer) {
 	accumulatedStats.aggregate(intervalStats)
 	writeStats(to, runParams.jsonFormat, true /* final */, intervalStats, accumulatedStats)
+	if runParams.workloadFile != "" || runParams.traceFile != "" || rateSched != nil {
+		if runParams.jsonFormat {
+			fmt.Fprint(to, ",")
+		}
+		writeOpStats(to, runParams.jsonFormat, time.Now())
+	}
+	if rateSched != nil {
+		if runParams.jsonFormat {
+			fmt.Fprint(to, ",")
+		}
+		rateSched.writeStats(to, runParams.jsonFormat)
+	}
 	postWriteStats(to, runParams.jsonFormat)
 
 	// reset gauges, otherwise they would stay at last send value
@@ -216,6 +247,67 @@ func writeStatsJSON(to io.Writer, s sts, withcomma ...bool) {
 	}
 }
 
+const opStatsPrintHeader = "%-10s%-12s%-10s%-12s%-14s%-11s%-11s%-11s%-11s%-11s%-10s\n"
+
+type jsonOpStats struct {
+	Cnt        int64 `json:"count,string"`
+	Bytes      int64 `json:"bytes,string"`
+	Errs       int64 `json:"errors"`
+	OpsPerSec  int64 `json:"ops_per_sec"`
+	Throughput int64 `json:"throughput,string"`
+	P50        int64 `json:"p50_latency"`
+	P90        int64 `json:"p90_latency"`
+	P99        int64 `json:"p99_latency"`
+	P999       int64 `json:"p99.9_latency"`
+	MaxLatency int64 `json:"max_latency"`
+}
+
+// writeOpStats writes throughput and latency percentiles of each operation
+// over the entire run (that ended at `end`).
+func writeOpStats(to io.Writer, jsonFormat bool, end time.Time) {
+	var (
+		ops = sortedOps()
+		all = make(map[string]*jsonOpStats, len(ops))
+	)
+	for _, op := range ops {
+		s := allOpStats[op]
+		var opsPerSec int64
+		if d := end.Sub(s.req.Start()).Seconds(); d > 0 {
+			opsPerSec = int64(float64(s.req.Total()) / d)
+		}
+		all[opNames[op]] = &jsonOpStats{
+			Cnt:        s.req.Total(),
+			Bytes:      s.req.TotalBytes(),
+			Errs:       s.req.TotalErrs(),
+			OpsPerSec:  opsPerSec,
+			Throughput: s.req.Throughput(s.req.Start(), end),
+			P50:        int64(s.lat.Percentile(50)),
+			P90:        int64(s.lat.Percentile(90)),
+			P99:        int64(s.lat.Percentile(99)),
+			P999:       int64(s.lat.Percentile(99.9)),
+			MaxLatency: s.req.MaxLatency(),
+		}
+	}
+
+	if jsonFormat {
+		fmt.Fprintf(to, "\n%s", cmn.MustMarshal(struct {
+			Ops map[string]*jsonOpStats `json:"ops"`
+		}{all}))
+		return
+	}
+
+	pn := prettyNumber
+	pd := prettyDuration
+	fmt.Fprintln(to)
+	fmt.Fprintf(to, opStatsPrintHeader, "OP", "Count", "Ops/s", "Size", "Throughput",
+		"p50", "p90", "p99", "p99.9", "Max", "Errors")
+	for _, op := range ops {
+		s := all[opNames[op]]
+		fmt.Fprintf(to, opStatsPrintHeader, opNames[op], pn(s.Cnt), pn(s.OpsPerSec), prettyBytes(s.Bytes),
+			prettySpeed(s.Throughput), pd(s.P50), pd(s.P90), pd(s.P99), pd(s.P999), pd(s.MaxLatency), pn(s.Errs))
+	}
+}
+
 func writeHumanReadibleIntervalStats(to io.Writer, s, t sts) {
 	p := fmt.Fprintf
 	pn := prettyNumber
@@ -232,7 +324,7 @@ func writeHumanReadibleIntervalStats(to io.Writer, s, t sts) {
 	}
 	if s.put.Total() != 0 {
 		p(to, statsPrintHeader, pt(), "PUT",
-			pn(s.put.Total())+" ("+pn(t.put.Total())+" "+pn(putPending)+" "+pn(workOrderResLen)+")",
+			pn(s.put.Total())+" ("+pn(t.put.Total())+" "+pn(putPending.Load())+" "+pn(workOrderResLen)+")",
 			pb(s.put.TotalBytes())+" ("+pb(t.put.TotalBytes())+")",
 			pl(s.put.MinLatency(), s.put.AvgLatency(), s.put.MaxLatency()),
 			ps(s.put.Throughput(s.put.Start(), time.Now()))+" ("+ps(t.put.Throughput(t.put.Start(), time.Now()))+")",
@@ -244,7 +336,7 @@ func writeHumanReadibleIntervalStats(to io.Writer, s, t sts) {
 	}
 	if s.get.Total() != 0 {
 		p(to, statsPrintHeader, pt(), "GET",
-			pn(s.get.Total())+" ("+pn(t.get.Total())+" "+pn(getPending)+" "+pn(workOrderResLen)+")",
+			pn(s.get.Total())+" ("+pn(t.get.Total())+" "+pn(getPending.Load())+" "+pn(workOrderResLen)+")",
 			pb(s.get.TotalBytes())+" ("+pb(t.get.TotalBytes())+")",
 			pl(s.get.MinLatency(), s.get.AvgLatency(), s.get.MaxLatency()),
 			ps(s.get.Throughput(s.get.Start(), time.Now()))+" ("+ps(t.get.Throughput(t.get.Start(), time.Now()))+")",
@@ -316,6 +408,7 @@ func printRunParams(p params) {
 		StatsInterval string `json:"stats interval"`
 		Backing       string `json:"backed by"`
 		Cleanup       bool   `json:"cleanup"`
+		Trace         string `json:"trace,omitempty"`
 	}{
 		Seed:          p.seed,
 		URL:           p.proxyURL,
@@ -331,6 +424,7 @@ func printRunParams(p params) {
 		StatsInterval: (time.Duration(runParams.statsShowInterval) * time.Second).String(),
 		Backing:       p.readerType,
 		Cleanup:       p.cleanUp.Val,
+		Trace:         p.traceFile,
 	}, "", "   ")
 
 	fmt.Printf("Runtime configuration:\n%s\n\n", string(b))
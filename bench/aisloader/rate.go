@@ -0,0 +1,330 @@
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+
+// open-loop target rate
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// In the open-loop mode work orders are posted at the target rate regardless
+// of whether the previous ones have completed. The rate is either fixed
+// (`-rate`), ramps linearly from `-ratestart` to `-rate` during `-rateramp`,
+// or is swept over a number of steps, each lasting `-ratestep` (`-ratesweep`).
+//
+// Latency of each request is measured from the time it was scheduled to be
+// sent (and not from the time it was actually sent) - otherwise, when the
+// cluster (or the workers) cannot keep up, the requests that would have been
+// sent during the stall are not accounted for ("coordinated omission").
+//
+// Rates given with the size suffix (e.g. 100MB) are in bytes per second and
+// are converted to requests per second using the mean object size.
+
+const maxRateSteps = 1000
+
+type (
+	// rateSeg is a part of the schedule during which the rate changes
+	// linearly from r0 to r1 (the rate is fixed if the two are equal).
+	rateSeg struct {
+		offset time.Duration // since the start of the run
+		dur    time.Duration // 0 - until the end of the run
+		r0, r1 float64       // requests per second
+	}
+
+	rateStep struct {
+		seg   rateSeg
+		stats *opStats // of the requests scheduled during the step
+	}
+
+	rateSchedule struct {
+		steps  []*rateStep
+		sweep  bool
+		start  time.Time
+		ended  time.Time
+		maxLag atomic.Int64
+	}
+
+	jsonRateStep struct {
+		TargetStart float64 `json:"target_rate_start"`
+		Target      float64 `json:"target_rate"`
+		Achieved    float64 `json:"achieved_rate"`
+		Cnt         int64   `json:"count,string"`
+		Errs        int64   `json:"errors"`
+		Throughput  int64   `json:"throughput,string"`
+		P50         int64   `json:"p50_latency"`
+		P90         int64   `json:"p90_latency"`
+		P99         int64   `json:"p99_latency"`
+		P999        int64   `json:"p99.9_latency"`
+		MaxLatency  int64   `json:"max_latency"`
+	}
+)
+
+var rateSched *rateSchedule // nil - closed-loop mode
+
+// parseRate parses requests per second or, if the size suffix is present,
+// bytes per second.
+func parseRate(s string, meanSize int64) (float64, error) {
+	s = strings.TrimSpace(s)
+	if r, err := strconv.ParseFloat(s, 64); err == nil {
+		if r < 0 {
+			return 0, fmt.Errorf("invalid rate %q", s)
+		}
+		return r, nil
+	}
+	n, err := cmn.S2B(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid rate %q", s)
+	}
+	if meanSize == 0 {
+		return 0, fmt.Errorf("rate %q in bytes per second requires non-zero object size", s)
+	}
+	return float64(n) / float64(meanSize), nil
+}
+
+// parseSweep parses comma-separated rates or start:end:step.
+func parseSweep(s string, meanSize int64) (rates []float64, err error) {
+	if parts := strings.Split(s, ":"); len(parts) == 3 {
+		var r [3]float64
+		for i, part := range parts {
+			if r[i], err = parseRate(part, meanSize); err != nil {
+				return nil, err
+			}
+		}
+		start, end, step := r[0], r[1], r[2]
+		if step == 0 || end < start {
+			return nil, fmt.Errorf("invalid rate sweep %q", s)
+		}
+		if (end-start)/step >= maxRateSteps {
+			return nil, fmt.Errorf("rate sweep %q has too many steps (max %d)", s, maxRateSteps)
+		}
+		for i := 0; start+float64(i)*step <= end*(1+1e-9); i++ {
+			rates = append(rates, start+float64(i)*step)
+		}
+	} else {
+		for _, part := range strings.Split(s, ",") {
+			r, err := parseRate(part, meanSize)
+			if err != nil {
+				return nil, err
+			}
+			rates = append(rates, r)
+		}
+	}
+	for _, r := range rates {
+		if r == 0 {
+			return nil, fmt.Errorf("invalid rate sweep %q: rates must be positive", s)
+		}
+	}
+	return rates, nil
+}
+
+func newRateSched(p *params) (*rateSchedule, error) {
+	var (
+		meanSize = (p.minSize + p.maxSize) / 2
+		rs       = &rateSchedule{}
+	)
+	if p.rateSweep != "" {
+		if p.rateStr != "" || p.rateRamp != 0 {
+			return nil, fmt.Errorf("rate sweep cannot be used together with rate or rate ramp")
+		}
+		if p.rateStep <= 0 {
+			return nil, fmt.Errorf("invalid option: rate step %v", p.rateStep)
+		}
+		rates, err := parseSweep(p.rateSweep, meanSize)
+		if err != nil {
+			return nil, err
+		}
+		rs.sweep = true
+		for i, r := range rates {
+			rs.addStep(rateSeg{offset: time.Duration(i) * p.rateStep, dur: p.rateStep, r0: r, r1: r})
+		}
+		return rs, nil
+	}
+
+	r1, err := parseRate(p.rateStr, meanSize)
+	if err != nil {
+		return nil, err
+	}
+	if r1 == 0 {
+		return nil, fmt.Errorf("invalid option: rate %q", p.rateStr)
+	}
+	if p.rateRamp < 0 {
+		return nil, fmt.Errorf("invalid option: rate ramp %v", p.rateRamp)
+	}
+	if p.rateRamp > 0 {
+		r0, err := parseRate(p.rateStartStr, meanSize)
+		if err != nil {
+			return nil, err
+		}
+		rs.addStep(rateSeg{dur: p.rateRamp, r0: r0, r1: r1})
+	}
+	rs.addStep(rateSeg{offset: p.rateRamp, r0: r1, r1: r1})
+	return rs, nil
+}
+
+func (rs *rateSchedule) addStep(seg rateSeg) {
+	rs.steps = append(rs.steps, &rateStep{seg: seg, stats: newOpStats(time.Now())})
+}
+
+// at returns the time (since the start of the segment) to send k-th request
+// at, or false if it does not fall within the segment.
+func (seg *rateSeg) at(k int) (time.Duration, bool) {
+	var t float64 // seconds
+	if seg.r0 == seg.r1 {
+		if seg.r1 == 0 {
+			return 0, false
+		}
+		t = float64(k) / seg.r1
+	} else {
+		// the number of requests sent by t is r0*t + a*t^2/2
+		a := (seg.r1 - seg.r0) / seg.dur.Seconds()
+		d := seg.r0*seg.r0 + 2*a*float64(k)
+		if d < 0 {
+			return 0, false
+		}
+		t = (math.Sqrt(d) - seg.r0) / a
+	}
+	if seg.dur > 0 && t >= seg.dur.Seconds() {
+		return 0, false
+	}
+	return time.Duration(t * float64(time.Second)), true
+}
+
+// run posts work orders as per schedule until the schedule ends (when it
+// closes `done`) or the `stop` channel is closed.
+func (rs *rateSchedule) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	rs.start = time.Now()
+	defer func() { rs.ended = time.Now() }()
+
+	for i, step := range rs.steps {
+		segStart := rs.start.Add(step.seg.offset)
+		for k := 0; ; k++ {
+			t, ok := step.seg.at(k)
+			if !ok {
+				break
+			}
+			scheduled := segStart.Add(t)
+			if wait := time.Until(scheduled); wait > 0 {
+				timer.Reset(wait)
+				select {
+				case <-timer.C:
+				case <-stop:
+					timer.Stop()
+					return
+				}
+			}
+
+			genMu.Lock()
+			wo, err := newWorkOrder()
+			genMu.Unlock()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return
+			}
+			wo.scheduled, wo.step = scheduled, i
+			select {
+			case workOrders <- wo:
+			case <-stop:
+				return
+			}
+			if lag := int64(time.Since(scheduled)); lag > rs.maxLag.Load() {
+				rs.maxLag.Store(lag)
+			}
+			postedCnt.Inc()
+		}
+	}
+}
+
+// complete accounts the completed work order to the step it was scheduled in.
+func (rs *rateSchedule) complete(wo *workOrder, delta time.Duration) {
+	rs.steps[wo.step].stats.add(wo, delta)
+}
+
+// duration returns how long the step actually lasted.
+func (rs *rateSchedule) duration(step *rateStep) time.Duration {
+	var (
+		start = rs.start.Add(step.seg.offset)
+		end   = rs.ended
+	)
+	if step.seg.dur > 0 && start.Add(step.seg.dur).Before(end) {
+		end = start.Add(step.seg.dur)
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+func printRateStats() {
+	fmt.Printf("Posted %d operations at the target rate, maximum lag: %v\n",
+		postedCnt.Load(), time.Duration(rateSched.maxLag.Load()))
+}
+
+const rateStatsPrintHeader = "%-18s%-12s%-12s%-14s%-11s%-11s%-11s%-11s%-11s%-10s\n"
+
+// writeStats writes achieved rate, throughput and latency percentiles of each
+// step of the schedule - throughput vs latency curve when sweeping the rates.
+func (rs *rateSchedule) writeStats(to io.Writer, jsonFormat bool) {
+	all := make([]*jsonRateStep, 0, len(rs.steps))
+	for _, step := range rs.steps {
+		var (
+			s        = step.stats
+			d        = rs.duration(step)
+			achieved float64
+			tput     int64
+		)
+		if d > 0 {
+			achieved = float64(s.req.Total()) / d.Seconds()
+			tput = int64(float64(s.req.TotalBytes()) / d.Seconds())
+		}
+		all = append(all, &jsonRateStep{
+			TargetStart: step.seg.r0,
+			Target:      step.seg.r1,
+			Achieved:    achieved,
+			Cnt:         s.req.Total(),
+			Errs:        s.req.TotalErrs(),
+			Throughput:  tput,
+			P50:         int64(s.lat.Percentile(50)),
+			P90:         int64(s.lat.Percentile(90)),
+			P99:         int64(s.lat.Percentile(99)),
+			P999:        int64(s.lat.Percentile(99.9)),
+			MaxLatency:  s.req.MaxLatency(),
+		})
+	}
+
+	if jsonFormat {
+		fmt.Fprintf(to, "\n%s", cmn.MustMarshal(struct {
+			Steps []*jsonRateStep `json:"rate_steps"`
+		}{all}))
+		return
+	}
+
+	pn := prettyNumber
+	pd := prettyDuration
+	fmt.Fprintln(to)
+	fmt.Fprintf(to, rateStatsPrintHeader, "Target (req/s)", "Achieved", "Count", "Throughput",
+		"p50", "p90", "p99", "p99.9", "Max", "Errors")
+	for _, s := range all {
+		target := fmt.Sprintf("%.1f", s.Target)
+		if s.TargetStart != s.Target {
+			target = fmt.Sprintf("%.1f-%.1f", s.TargetStart, s.Target)
+		}
+		fmt.Fprintf(to, rateStatsPrintHeader, target, fmt.Sprintf("%.1f", s.Achieved), pn(s.Cnt),
+			prettySpeed(s.Throughput), pd(s.P50), pd(s.P90), pd(s.P99), pd(s.P999), pd(s.MaxLatency), pn(s.Errs))
+	}
+}
@@ -0,0 +1,235 @@
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+
+// trace replay
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/bench/aisloader/trace"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils"
+)
+
+// Replaying a trace, work orders are generated from the trace records (and
+// not from the completed ones): each is posted at the time of its record
+// relative to the first one, divided by `-tracespeed`. When workers cannot
+// keep up the replay falls behind - the maximum lag is reported at the end.
+//
+// GET, PUT, APPEND, HEAD and DELETE are replayed, other operations (if any)
+// are counted as skipped.
+
+type replayStats struct {
+	skipped atomic.Int64
+	maxLag  atomic.Int64
+}
+
+var replayed replayStats
+
+func openTrace() (*os.File, *trace.Reader, error) {
+	f, err := os.Open(runParams.traceFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	tr, err := trace.NewReader(f, runParams.traceFormat)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, tr, nil
+}
+
+func traceBck(rec *trace.Record) cmn.Bck {
+	if rec.Bucket == "" {
+		return runParams.bck
+	}
+	return cmn.Bck{Name: rec.Bucket, Provider: runParams.bck.Provider}
+}
+
+func traceWorkOrder(rec *trace.Record) *workOrder {
+	wo := &workOrder{
+		proxyURL: runParams.proxyURL,
+		bck:      traceBck(rec),
+		objName:  rec.Object,
+	}
+	switch rec.Op {
+	case trace.OpGet:
+		wo.op = opGet
+		wo.readOff, wo.readLen = runParams.readOff, runParams.readLen
+		getPending.Inc()
+	case trace.OpPut, trace.OpAppend:
+		wo.op = opPut
+		wo.size = rec.Size
+		if wo.size == 0 {
+			wo.size = randomSize()
+		}
+		if rec.Op == trace.OpAppend {
+			wo.op = opAppend
+		} else {
+			putPending.Inc()
+		}
+	case trace.OpHead:
+		wo.op = opHead
+	case trace.OpDelete:
+		wo.op = opDelete
+	default:
+		return nil
+	}
+	return wo
+}
+
+// prepareTrace creates missing ais buckets and PUTs objects that the trace
+// reads before (if ever) writing them, so that the GETs do not fail.
+func prepareTrace() error {
+	f, tr, err := openTrace()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var (
+		seen  = make(map[cmn.Bck]cmn.StringSet)
+		toPut []*workOrder
+	)
+	for {
+		rec, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		bck := traceBck(rec)
+		if _, ok := seen[bck]; !ok {
+			seen[bck] = make(cmn.StringSet)
+		}
+		if seen[bck].Contains(rec.Object) {
+			continue
+		}
+		seen[bck].Add(rec.Object)
+		if rec.Op == trace.OpGet || rec.Op == trace.OpHead {
+			toPut = append(toPut, &workOrder{
+				op:       opPut,
+				proxyURL: runParams.proxyURL,
+				bck:      bck,
+				objName:  rec.Object,
+				size:     randomSize(),
+			})
+		}
+	}
+
+	baseParams := tutils.BaseAPIParams(runParams.proxyURL)
+	for bck := range seen {
+		if !cmn.IsProviderAIS(bck) {
+			continue
+		}
+		exists, err := api.DoesBucketExist(baseParams, bck)
+		if err != nil {
+			return fmt.Errorf("failed to check bucket %s, err: %v", bck, err)
+		}
+		if !exists {
+			if err := api.CreateBucket(baseParams, bck); err != nil {
+				return fmt.Errorf("failed to create bucket %s, err: %v", bck, err)
+			}
+		}
+	}
+
+	fmt.Printf("Preparing trace: PUT %d objects\n", len(toPut))
+	var (
+		wg   = &sync.WaitGroup{}
+		wos  = make(chan *workOrder, runParams.numWorkers)
+		errs atomic.Int64
+	)
+	for i := 0; i < runParams.numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for wo := range wos {
+				if doPut(wo); wo.err != nil {
+					errs.Inc()
+				}
+			}
+		}()
+	}
+	for _, wo := range toPut {
+		wos <- wo
+	}
+	close(wos)
+	wg.Wait()
+	if n := errs.Load(); n > 0 {
+		return fmt.Errorf("failed to PUT %d (out of %d) objects", n, len(toPut))
+	}
+	return nil
+}
+
+// replayTrace posts work orders generated from the trace until the trace ends
+// (when it closes `done`) or the `stop` channel is closed.
+func replayTrace(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	f, tr, err := openTrace()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open trace: %v\n", err)
+		return
+	}
+	defer f.Close()
+
+	var (
+		first, target time.Time
+		start         = time.Now()
+	)
+	for {
+		rec, err := tr.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read trace: %v\n", err)
+			return
+		}
+		if first.IsZero() {
+			first = rec.Time
+		}
+
+		if runParams.traceSpeed > 0 {
+			offset := time.Duration(float64(rec.Time.Sub(first)) / runParams.traceSpeed)
+			target = start.Add(offset)
+			if wait := time.Until(target); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-stop:
+					return
+				}
+			}
+		}
+
+		wo := traceWorkOrder(rec)
+		if wo == nil {
+			replayed.skipped.Inc()
+			continue
+		}
+		select {
+		case workOrders <- wo:
+		case <-stop:
+			return
+		}
+		if lag := int64(time.Since(target)); !target.IsZero() && lag > replayed.maxLag.Load() {
+			replayed.maxLag.Store(lag)
+		}
+		postedCnt.Inc()
+	}
+}
+
+func printReplayStats() {
+	fmt.Printf("Replayed %d operations (skipped %d unsupported), maximum lag: %v\n",
+		postedCnt.Load(), replayed.skipped.Load(), time.Duration(replayed.maxLag.Load()))
+}
@@ -0,0 +1,93 @@
+// Package stats provides various structs for collecting stats
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// Relative width of the latency buckets: percentiles are precise to +/- 1%.
+	latencyBase = 1.02
+	// Latencies above (about 1 hour) are counted in the last bucket.
+	numLatencyBuckets = 1460
+)
+
+var logLatencyBase = math.Log(latencyBase)
+
+// Latencies is a histogram of request latencies used to compute percentiles.
+// Unlike raw samples, histograms take constant space and can be merged
+// (e.g., across intervals or aisloader instances).
+// Assume single threaded access, it doesn't provide any locking on updates.
+type Latencies struct {
+	Buckets []int64 `json:"buckets"` // i-th bucket counts latencies in [base^i, base^(i+1)) ns
+}
+
+// NewLatencies returns an empty histogram
+func NewLatencies() *Latencies {
+	return &Latencies{Buckets: make([]int64, numLatencyBuckets)}
+}
+
+func latencyBucket(d time.Duration) int {
+	if d <= 1 {
+		return 0
+	}
+	idx := int(math.Log(float64(d)) / logLatencyBase)
+	if idx >= numLatencyBuckets {
+		idx = numLatencyBuckets - 1
+	}
+	return idx
+}
+
+// Add adds a request's latency to the histogram
+func (l *Latencies) Add(d time.Duration) {
+	l.Buckets[latencyBucket(d)]++
+}
+
+// Count returns the number of latencies in the histogram.
+func (l *Latencies) Count() (cnt int64) {
+	for _, n := range l.Buckets {
+		cnt += n
+	}
+	return
+}
+
+// Percentile returns the latency below which p (0 < p <= 100) percent of
+// latencies fall; 0 if the histogram is empty.
+func (l *Latencies) Percentile(p float64) time.Duration {
+	cnt := l.Count()
+	if cnt == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(p / 100 * float64(cnt)))
+	if rank < 1 {
+		rank = 1
+	}
+	for idx, n := range l.Buckets {
+		if rank -= n; rank <= 0 {
+			// geometric middle of the bucket
+			return time.Duration(math.Pow(latencyBase, float64(idx)+0.5))
+		}
+	}
+	return time.Duration(math.Pow(latencyBase, numLatencyBuckets))
+}
+
+// Aggregate adds another histogram to self. Histograms with a different number
+// of buckets (e.g., received from a different aisloader version) are rejected
+// and self is left unchanged.
+func (l *Latencies) Aggregate(other *Latencies) error {
+	if other == nil {
+		return fmt.Errorf("missing latency histogram")
+	}
+	if len(other.Buckets) != len(l.Buckets) {
+		return fmt.Errorf("latency histogram has %d buckets, expected %d", len(other.Buckets), len(l.Buckets))
+	}
+	for idx, n := range other.Buckets {
+		l.Buckets[idx] += n
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
 )
 
 // HTTPReq is used for keeping track of http requests stats including number of ops, latency, throughput, etc.
@@ -109,3 +110,44 @@ func (s *HTTPReq) Aggregate(other HTTPReq) {
 	s.minLatency = cmn.MinDuration(s.minLatency, other.minLatency)
 	s.maxLatency = cmn.MaxDuration(s.maxLatency, other.maxLatency)
 }
+
+type httpReqJSON struct {
+	Start      time.Time     `json:"start"`
+	Cnt        int64         `json:"count,string"`
+	Bytes      int64         `json:"bytes,string"`
+	Errs       int64         `json:"errors,string"`
+	Latency    time.Duration `json:"latency"`
+	MinLatency time.Duration `json:"min_latency"`
+	MaxLatency time.Duration `json:"max_latency"`
+}
+
+// MarshalJSON encodes the stats (e.g., to be aggregated by another aisloader)
+func (s HTTPReq) MarshalJSON() ([]byte, error) {
+	return jsoniter.Marshal(httpReqJSON{
+		Start:      s.start,
+		Cnt:        s.cnt,
+		Bytes:      s.bytes,
+		Errs:       s.errs,
+		Latency:    s.latency,
+		MinLatency: s.minLatency,
+		MaxLatency: s.maxLatency,
+	})
+}
+
+// UnmarshalJSON decodes the stats encoded with MarshalJSON
+func (s *HTTPReq) UnmarshalJSON(b []byte) error {
+	var j httpReqJSON
+	if err := jsoniter.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	*s = HTTPReq{
+		start:      j.Start,
+		cnt:        j.Cnt,
+		bytes:      j.Bytes,
+		errs:       j.Errs,
+		latency:    j.Latency,
+		minLatency: j.MinLatency,
+		maxLatency: j.MaxLatency,
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/NVIDIA/aistore/bench/aisloader/stats"
+	jsoniter "github.com/json-iterator/go"
 )
 
 func verify(t *testing.T, msg string, exp, act int64) {
@@ -69,3 +70,82 @@ func TestStats(t *testing.T) {
 	verify(t, "Max latency", 100000000, total.MaxLatency())
 	verify(t, "Throughput", 5, total.Throughput(start, start.Add(70*time.Second)))
 }
+
+func TestStatsJSON(t *testing.T) {
+	start := time.Now()
+	s := stats.NewHTTPReq(start)
+	s.Add(100, 100*time.Millisecond)
+	s.Add(200, 20*time.Millisecond)
+	s.AddErr()
+
+	b, err := jsoniter.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded stats.HTTPReq
+	if err := jsoniter.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	verify(t, "Total", 2, decoded.Total())
+	verify(t, "Total bytes", 300, decoded.TotalBytes())
+	verify(t, "Min latency", 20000000, decoded.MinLatency())
+	verify(t, "Avg latency", 60000000, decoded.AvgLatency())
+	verify(t, "Max latency", 100000000, decoded.MaxLatency())
+	verify(t, "Failed", 1, decoded.TotalErrs())
+	if !decoded.Start().Equal(start) {
+		t.Fatalf("Error: start, expected = %v, actual = %v", start, decoded.Start())
+	}
+
+	// empty stats are aggregated as such
+	empty := stats.NewHTTPReq(start)
+	if b, err = jsoniter.Marshal(empty); err != nil {
+		t.Fatal(err)
+	}
+	if err := jsoniter.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	s.Aggregate(decoded)
+	verify(t, "Aggregated min latency", 20000000, s.MinLatency())
+}
+
+func verifyPercentile(t *testing.T, l *stats.Latencies, p float64, exp time.Duration) {
+	act := l.Percentile(p)
+	if act < exp*99/100 || act > exp*101/100 {
+		t.Fatalf("Error: p%v, expected = %v, actual = %v", p, exp, act)
+	}
+}
+
+func TestLatencies(t *testing.T) {
+	l := stats.NewLatencies()
+	verify(t, "Empty", 0, int64(l.Percentile(50)))
+
+	for i := 1; i <= 1000; i++ {
+		l.Add(time.Duration(i) * time.Millisecond)
+	}
+	verify(t, "Count", 1000, l.Count())
+	verifyPercentile(t, l, 50, 500*time.Millisecond)
+	verifyPercentile(t, l, 99, 990*time.Millisecond)
+	verifyPercentile(t, l, 100, time.Second)
+
+	other := stats.NewLatencies()
+	for i := 0; i < 1000; i++ {
+		other.Add(2 * time.Second)
+	}
+	if err := l.Aggregate(other); err != nil {
+		t.Fatal(err)
+	}
+	verify(t, "Aggregated count", 2000, l.Count())
+	verifyPercentile(t, l, 50, time.Second)
+	verifyPercentile(t, l, 75, 2*time.Second)
+
+	// e.g., a report from an agent with a different histogram layout
+	bad := &stats.Latencies{Buckets: make([]int64, len(l.Buckets)+1)}
+	bad.Buckets[len(bad.Buckets)-1] = 1
+	if err := l.Aggregate(bad); err == nil {
+		t.Error("expected error aggregating histogram with extra buckets")
+	}
+	if err := l.Aggregate(nil); err == nil {
+		t.Error("expected error aggregating missing histogram")
+	}
+	verify(t, "Count after rejected aggregation", 2000, l.Count())
+}
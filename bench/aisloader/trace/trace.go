@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+
+// Package trace reads recorded workloads to be replayed by aisloader.
+package trace
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported formats of the trace
+const (
+	// CSV file with the following columns (size is optional, lines starting
+	// with '#' are ignored):
+	//   timestamp,op,bucket,object,size
+	// where timestamp is either RFC3339 time or (fractional) number of seconds
+	// (absolute Unix time or relative to any point in time).
+	FormatCSV = "csv"
+	// Log of AIS proxy (requires verbosity level of at least 4), e.g.:
+	//   I 14:03:01.123456 proxy.go:441 GET nvais/imagenet/001.tar => t[Xskr8080]
+	FormatAISLog = "aislog"
+)
+
+// Operations
+const (
+	OpGet    = "GET"
+	OpPut    = "PUT"
+	OpAppend = "APPEND"
+	OpHead   = "HEAD"
+	OpDelete = "DELETE"
+)
+
+type (
+	Record struct {
+		Time   time.Time
+		Op     string
+		Bucket string
+		Object string
+		Size   int64 // 0 - unknown
+	}
+
+	Reader struct {
+		format string
+		csv    *csv.Reader
+		sc     *bufio.Scanner
+		line   int // number of the record (CSV) or line (AIS log)
+
+		// AIS logs contain time of day only.
+		day, last time.Time
+	}
+)
+
+var (
+	// Bucket names cannot contain ':' nor '@' - this rules out messages about
+	// buckets (e.g. "GET ais://name => t[...]") which are not object requests.
+	logRegex = regexp.MustCompile(
+		`^[IWEF] (\d{2}:\d{2}:\d{2}\.\d{6}) \S+ (?:reverse-proxy: )?(GET|PUT|HEAD|DELETE) ([^/\s:@]+)/(\S+) (?:=>|<=) \S+( \(append: true\))?`,
+	)
+	epoch = time.Unix(0, 0).UTC()
+)
+
+func NewReader(r io.Reader, format string) (*Reader, error) {
+	tr := &Reader{format: format}
+	switch format {
+	case FormatCSV:
+		tr.csv = csv.NewReader(r)
+		tr.csv.Comment = '#'
+		tr.csv.FieldsPerRecord = -1
+		tr.csv.TrimLeadingSpace = true
+	case FormatAISLog:
+		tr.sc = bufio.NewScanner(r)
+		tr.day = epoch
+	default:
+		return nil, fmt.Errorf("invalid trace format %q (expected %q or %q)", format, FormatCSV, FormatAISLog)
+	}
+	return tr, nil
+}
+
+// Next returns the next record of the trace, or io.EOF at the end of it.
+func (tr *Reader) Next() (*Record, error) {
+	if tr.format == FormatCSV {
+		return tr.nextCSV()
+	}
+	return tr.nextLog()
+}
+
+func (tr *Reader) nextCSV() (*Record, error) {
+	fields, err := tr.csv.Read()
+	if err != nil {
+		return nil, err
+	}
+	tr.line++
+	if len(fields) < 4 || len(fields) > 5 {
+		return nil, fmt.Errorf("record %d: expected 4 or 5 fields, got %d", tr.line, len(fields))
+	}
+	rec := &Record{
+		Op:     strings.ToUpper(fields[1]),
+		Bucket: fields[2],
+		Object: fields[3],
+	}
+	if rec.Time, err = parseTime(fields[0]); err != nil {
+		return nil, fmt.Errorf("record %d: %v", tr.line, err)
+	}
+	if len(fields) == 5 && fields[4] != "" {
+		if rec.Size, err = strconv.ParseInt(fields[4], 10, 64); err != nil || rec.Size < 0 {
+			return nil, fmt.Errorf("record %d: invalid size %q", tr.line, fields[4])
+		}
+	}
+	if rec.Object == "" {
+		return nil, fmt.Errorf("record %d: empty object name", tr.line)
+	}
+	return rec, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	secs, err := strconv.ParseFloat(s, 64)
+	if err != nil || secs < 0 {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+	whole, frac := math.Modf(secs)
+	return time.Unix(int64(whole), int64(frac*float64(time.Second))).UTC(), nil
+}
+
+// Lines that are not requests (other log messages) are skipped.
+func (tr *Reader) nextLog() (*Record, error) {
+	for tr.sc.Scan() {
+		tr.line++
+		m := logRegex.FindStringSubmatch(tr.sc.Text())
+		if m == nil {
+			continue
+		}
+		tod, err := time.Parse("15:04:05.000000", m[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid time %q", tr.line, m[1])
+		}
+		t := tr.day.Add(tod.Sub(tod.Truncate(24 * time.Hour)))
+		if t.Before(tr.last.Add(-12 * time.Hour)) {
+			// Past midnight.
+			tr.day = tr.day.Add(24 * time.Hour)
+			t = t.Add(24 * time.Hour)
+		}
+		tr.last = t
+
+		rec := &Record{Time: t, Op: m[2], Bucket: m[3], Object: m[4]}
+		if m[5] != "" {
+			rec.Op = OpAppend
+		}
+		return rec, nil
+	}
+	if err := tr.sc.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
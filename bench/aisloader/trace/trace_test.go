@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package trace_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/bench/aisloader/trace"
+)
+
+func readAll(t *testing.T, format, s string) []*trace.Record {
+	tr, err := trace.NewReader(strings.NewReader(s), format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recs []*trace.Record
+	for {
+		rec, err := tr.Next()
+		if err == io.EOF {
+			return recs
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		recs = append(recs, rec)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	recs := readAll(t, trace.FormatCSV, `# timestamp,op,bucket,object,size
+1580000000.5,get,bck,a/b,
+2020-01-26T00:53:21Z,PUT,bck,c,1024
+1580000002,HEAD,,d
+`)
+	if len(recs) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(recs))
+	}
+	if recs[0].Op != trace.OpGet || recs[0].Object != "a/b" || recs[0].Size != 0 {
+		t.Errorf("unexpected record: %+v", recs[0])
+	}
+	if d := recs[1].Time.Sub(recs[0].Time); d != 500*time.Millisecond {
+		t.Errorf("expected 500ms between records, got %v", d)
+	}
+	if recs[1].Op != trace.OpPut || recs[1].Size != 1024 {
+		t.Errorf("unexpected record: %+v", recs[1])
+	}
+	if recs[2].Bucket != "" || recs[2].Op != trace.OpHead {
+		t.Errorf("unexpected record: %+v", recs[2])
+	}
+}
+
+func TestCSVInvalid(t *testing.T) {
+	for _, s := range []string{
+		"1,GET,bck",
+		"yesterday,GET,bck,obj",
+		"1,PUT,bck,obj,-1",
+		"1,GET,bck,",
+	} {
+		tr, _ := trace.NewReader(strings.NewReader(s), trace.FormatCSV)
+		if _, err := tr.Next(); err == nil || err == io.EOF {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+	if _, err := trace.NewReader(strings.NewReader(""), "json"); err == nil {
+		t.Error("expected error for invalid format")
+	}
+}
+
+func TestAISLog(t *testing.T) {
+	recs := readAll(t, trace.FormatAISLog, `Log file created at: 2020/01/26 23:59:59
+I 23:59:59.500000 proxy.go:441 GET bck/a/b => t[Xskr8080]
+I 23:59:59.600000 proxy.go:1258 GET ais://bck => t[Xskr8080]
+I 23:59:59.700000 proxy.go:502 PUT bck/c => t[Xskr8080] (append: false)
+I 00:00:00.100000 proxy.go:502 PUT bck/c => t[Xskr8080] (append: true)
+I 00:00:00.200000 proxy.go:434 reverse-proxy: GET bck/d <= t[Xskr8080]
+I 00:00:00.300000 proxy.go:121 smap v5 ...
+`)
+	if len(recs) != 4 {
+		t.Fatalf("expected 4 records, got %d", len(recs))
+	}
+	ops := []string{trace.OpGet, trace.OpPut, trace.OpAppend, trace.OpGet}
+	objs := []string{"a/b", "c", "c", "d"}
+	for i, rec := range recs {
+		if rec.Op != ops[i] || rec.Bucket != "bck" || rec.Object != objs[i] {
+			t.Errorf("unexpected record %d: %+v", i, rec)
+		}
+	}
+	if d := recs[2].Time.Sub(recs[0].Time); d != 600*time.Millisecond {
+		t.Errorf("expected 600ms across midnight, got %v", d)
+	}
+}
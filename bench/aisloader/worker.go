@@ -13,15 +13,21 @@ import (
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/tutils"
 )
 
-func doPut(wo *workOrder) {
+// newPutReader returns reader of the generated object (or its part) and
+// the function to be called when the reader is no longer needed.
+func newPutReader(objName string, size int64) (tutils.Reader, func(), error) {
 	var sgl *memsys.SGL
 	if runParams.usingSG {
-		sgl = tutils.MMSA.NewSGL(wo.size)
-		defer func() {
+		sgl = tutils.MMSA.NewSGL(size)
+	}
+	free := func() {
+		if sgl != nil {
 			// FIXME: due to critical bug (https://github.com/golang/go/issues/30597)
 			// we need to postpone `sgl.Free` to a little bit later time, otherwise
 			// we will experience 'read after free'. Sleep time is number taken
@@ -30,25 +36,33 @@ func doPut(wo *workOrder) {
 				time.Sleep(4 * time.Second)
 				sgl.Free()
 			}()
-		}()
+		}
+		if runParams.readerType == tutils.ReaderTypeFile {
+			os.Remove(path.Join(runParams.tmpDir, objName))
+		}
 	}
 
 	r, err := tutils.NewReader(tutils.ParamReader{
 		Type: runParams.readerType,
 		SGL:  sgl,
 		Path: runParams.tmpDir,
-		Name: wo.objName,
-		Size: wo.size,
+		Name: objName,
+		Size: size,
 	})
-
-	if runParams.readerType == tutils.ReaderTypeFile {
-		defer os.Remove(path.Join(runParams.tmpDir, wo.objName))
+	if err != nil {
+		free()
+		return nil, nil, err
 	}
+	return r, free, nil
+}
 
+func doPut(wo *workOrder) {
+	r, free, err := newPutReader(wo.objName, wo.size)
 	if err != nil {
 		wo.err = err
 		return
 	}
+	defer free()
 	if !traceHTTPSig.Load() {
 		wo.err = tutils.Put(wo.proxyURL, wo.bck, wo.objName, r.XXHash(), r)
 	} else {
@@ -59,10 +73,10 @@ func doPut(wo *workOrder) {
 func doGet(wo *workOrder) {
 	if !traceHTTPSig.Load() {
 		wo.size, wo.err = tutils.GetDiscard(wo.proxyURL, wo.bck,
-			wo.objName, runParams.verifyHash, runParams.readOff, runParams.readLen)
+			wo.objName, runParams.verifyHash, wo.readOff, wo.readLen)
 	} else {
 		wo.size, wo.latencies, wo.err = tutils.GetTraceDiscard(wo.proxyURL, wo.bck,
-			wo.objName, runParams.verifyHash, runParams.readOff, runParams.readLen)
+			wo.objName, runParams.verifyHash, wo.readOff, wo.readLen)
 	}
 }
 
@@ -70,6 +84,60 @@ func doGetConfig(wo *workOrder) {
 	wo.latencies, wo.err = tutils.GetConfig(wo.proxyURL)
 }
 
+// doAppend creates the object with a series of appends (the size is split
+// into equal parts), followed by flush.
+func doAppend(wo *workOrder) {
+	var (
+		handle string
+		parts  = int64(cmn.Max(workload.AppendParts, 1))
+		args   = api.AppendArgs{
+			BaseParams: tutils.BaseAPIParams(wo.proxyURL),
+			Bck:        wo.bck,
+			Object:     wo.objName,
+		}
+	)
+	for i := int64(0); i < parts; i++ {
+		size := wo.size / parts
+		if i == parts-1 {
+			size += wo.size % parts
+		}
+		r, free, err := newPutReader(wo.objName, size)
+		if err != nil {
+			wo.err = err
+			return
+		}
+		args.Handle, args.Reader, args.Size = handle, r, size
+		handle, err = api.AppendObject(args)
+		free()
+		if err != nil {
+			wo.err = err
+			return
+		}
+	}
+	args.Handle = handle
+	wo.err = api.FlushObject(args)
+}
+
+func doHead(wo *workOrder) {
+	_, wo.err = api.HeadObject(tutils.BaseAPIParams(wo.proxyURL), wo.bck, wo.objName)
+}
+
+func doDelete(wo *workOrder) {
+	wo.err = api.DeleteObject(tutils.BaseAPIParams(wo.proxyURL), wo.bck, wo.objName)
+}
+
+// doList lists the page following `wo.pageMarker` and updates the marker.
+func doList(wo *workOrder) {
+	msg := &cmn.SelectMsg{PageMarker: wo.pageMarker, PageSize: workload.ListPageSize, Fast: true}
+	if _, wo.err = api.ListBucketPage(tutils.BaseAPIParams(wo.proxyURL), wo.bck, msg); wo.err == nil {
+		wo.pageMarker = msg.PageMarker
+	}
+}
+
+func doRename(wo *workOrder) {
+	wo.err = api.RenameObject(tutils.BaseAPIParams(wo.proxyURL), wo.bck, wo.objName, wo.newName)
+}
+
 func worker(wos <-chan *workOrder, results chan<- *workOrder, wg *sync.WaitGroup, numGets *atomic.Int64) {
 	defer wg.Done()
 
@@ -84,11 +152,21 @@ func worker(wos <-chan *workOrder, results chan<- *workOrder, wg *sync.WaitGroup
 		switch wo.op {
 		case opPut:
 			doPut(wo)
-		case opGet:
+		case opGet, opRangeGet:
 			doGet(wo)
 			numGets.Inc()
 		case opConfig:
 			doGetConfig(wo)
+		case opAppend:
+			doAppend(wo)
+		case opHead:
+			doHead(wo)
+		case opDelete:
+			doDelete(wo)
+		case opList:
+			doList(wo)
+		case opRename:
+			doRename(wo)
 		default:
 			// Should not come here
 		}
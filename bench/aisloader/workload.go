@@ -0,0 +1,456 @@
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+
+// workload spec
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/NVIDIA/aistore/bench/aisloader/stats"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils"
+	"gopkg.in/yaml.v2"
+)
+
+// Workload spec (YAML) describes a mix of weighted operations over multiple
+// buckets, each with its own distribution of object sizes, for example:
+//
+//   buckets:
+//     - name: small
+//       weight: 3                            # share of operations (default 1)
+//       sizes:                               # default: -minsize to -maxsize
+//         - {min: 4KiB, max: 64KiB, weight: 9}
+//         - {min: 1MiB, max: 4MiB, weight: 1}
+//     - name: large
+//       provider: ais                        # default: -provider
+//       sizes: [{min: 64MiB, max: 256MiB}]
+//   ops:                                     # weights of operations
+//     get: 60
+//     rangeget: 10
+//     put: 15
+//     append: 2
+//     head: 5
+//     delete: 3
+//     list: 1
+//     rename: 4
+//   rangelen: 64KiB                          # length of range GETs
+//   appendparts: 4                           # number of appends per APPEND
+//   listpage: 1000                           # size of listed pages
+//
+// Operations that need an existing object (GET, range GET, HEAD, DELETE,
+// RENAME) pick it at random from the names listed at startup and written
+// since. Range GETs read at random offset within the smallest object size
+// of the bucket. APPEND creates the object with a series of appends followed
+// by flush. LIST reads the next page of the bucket (wrapping around at
+// the end). RENAME is supported by ais buckets only.
+
+type (
+	sizeRange struct {
+		MinStr string `yaml:"min"`
+		MaxStr string `yaml:"max"`
+		Weight int    `yaml:"weight"`
+
+		min, max int64
+	}
+
+	bucketSpec struct {
+		Name     string      `yaml:"name"`
+		Provider string      `yaml:"provider"`
+		Weight   int         `yaml:"weight"`
+		Sizes    []sizeRange `yaml:"sizes"`
+
+		bck        cmn.Bck
+		objs       objPool
+		pageMarker string // of the next LIST
+	}
+
+	workloadSpec struct {
+		Buckets      []*bucketSpec  `yaml:"buckets"`
+		Ops          map[string]int `yaml:"ops"`
+		RangeLenStr  string         `yaml:"rangelen"`
+		AppendParts  int            `yaml:"appendparts"`
+		ListPageSize int            `yaml:"listpage"`
+
+		rangeLen int64
+		ops      []int // ops with non-zero weight (sorted)
+		weights  []int
+	}
+
+	// objPool is the set of object names that allows picking a random one.
+	objPool struct {
+		names []string
+		idx   map[string]int
+	}
+
+	// opStats accumulates stats of the operation over the entire run.
+	opStats struct {
+		req stats.HTTPReq
+		lat *stats.Latencies
+	}
+)
+
+var (
+	workload = workloadSpec{
+		RangeLenStr:  "64KiB",
+		AppendParts:  4,
+		ListPageSize: cmn.DefaultListPageSize,
+	}
+
+	specOps = map[string]int{
+		"get":      opGet,
+		"rangeget": opRangeGet,
+		"put":      opPut,
+		"append":   opAppend,
+		"head":     opHead,
+		"delete":   opDelete,
+		"list":     opList,
+		"rename":   opRename,
+	}
+
+	allOpStats = make(map[int]*opStats)
+)
+
+/////////////
+// objPool //
+/////////////
+
+func (p *objPool) init(names []string) {
+	p.names = names
+	p.idx = make(map[string]int, len(names))
+	for i, name := range names {
+		p.idx[name] = i
+	}
+}
+
+func (p *objPool) len() int { return len(p.names) }
+
+func (p *objPool) add(name string) {
+	if _, ok := p.idx[name]; ok {
+		return
+	}
+	p.idx[name] = len(p.names)
+	p.names = append(p.names, name)
+}
+
+func (p *objPool) remove(name string) {
+	i, ok := p.idx[name]
+	if !ok {
+		return
+	}
+	last := len(p.names) - 1
+	p.names[i] = p.names[last]
+	p.idx[p.names[i]] = i
+	p.names = p.names[:last]
+	delete(p.idx, name)
+}
+
+func (p *objPool) random() string {
+	return p.names[rnd.Intn(len(p.names))]
+}
+
+//////////////////
+// workloadSpec //
+//////////////////
+
+func loadWorkload(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := yaml.UnmarshalStrict(b, &workload); err != nil {
+		return fmt.Errorf("failed to parse workload spec %q: %v", path, err)
+	}
+	if err := workload.validate(); err != nil {
+		return fmt.Errorf("invalid workload spec %q: %v", path, err)
+	}
+	return nil
+}
+
+func (w *workloadSpec) validate() (err error) {
+	if len(w.Buckets) == 0 {
+		return errors.New("no buckets")
+	}
+	for _, b := range w.Buckets {
+		if err := b.validate(); err != nil {
+			return err
+		}
+	}
+
+	weights := make(map[int]int, len(w.Ops))
+	for name, weight := range w.Ops {
+		op, ok := specOps[name]
+		if !ok {
+			return fmt.Errorf("unknown operation %q", name)
+		}
+		if weight < 0 {
+			return fmt.Errorf("negative weight of %q", name)
+		}
+		if weight > 0 {
+			w.ops = append(w.ops, op)
+			weights[op] = weight
+		}
+	}
+	if len(w.ops) == 0 {
+		return errors.New("no operations")
+	}
+	sort.Ints(w.ops)
+	for _, op := range w.ops {
+		w.weights = append(w.weights, weights[op])
+	}
+
+	if w.rangeLen, err = cmn.S2B(w.RangeLenStr); err != nil || w.rangeLen <= 0 {
+		return fmt.Errorf("invalid range length %q", w.RangeLenStr)
+	}
+	if w.AppendParts <= 0 {
+		return fmt.Errorf("invalid number of append parts %d", w.AppendParts)
+	}
+	if w.ListPageSize <= 0 {
+		return fmt.Errorf("invalid list page size %d", w.ListPageSize)
+	}
+	return nil
+}
+
+func (b *bucketSpec) validate() (err error) {
+	if err := cmn.ValidateBckName(b.Name); err != nil {
+		return err
+	}
+	if b.Provider == "" {
+		b.Provider = runParams.bck.Provider
+	}
+	b.bck = cmn.Bck{Name: b.Name, Provider: b.Provider}
+	if b.Weight < 0 {
+		return fmt.Errorf("negative weight of bucket %s", b.bck)
+	}
+	if b.Weight == 0 {
+		b.Weight = 1
+	}
+	if len(b.Sizes) == 0 {
+		b.Sizes = []sizeRange{{min: runParams.minSize, max: runParams.maxSize}}
+	}
+	for i := range b.Sizes {
+		sr := &b.Sizes[i]
+		if sr.MinStr != "" {
+			if sr.min, err = cmn.S2B(sr.MinStr); err != nil {
+				return fmt.Errorf("bucket %s: invalid min size %q", b.bck, sr.MinStr)
+			}
+		}
+		if sr.MaxStr != "" {
+			if sr.max, err = cmn.S2B(sr.MaxStr); err != nil {
+				return fmt.Errorf("bucket %s: invalid max size %q", b.bck, sr.MaxStr)
+			}
+		}
+		if sr.max == 0 {
+			sr.max = sr.min
+		}
+		if sr.min < 0 || sr.max < sr.min {
+			return fmt.Errorf("bucket %s: invalid sizes (%d, %d)", b.bck, sr.min, sr.max)
+		}
+		if sr.Weight < 0 {
+			return fmt.Errorf("bucket %s: negative weight of sizes", b.bck)
+		}
+		if sr.Weight == 0 {
+			sr.Weight = 1
+		}
+	}
+	return nil
+}
+
+// Picks index at random, proportionally to the weights; -1 if all are zero.
+func pickWeighted(n int, weight func(i int) int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		total += weight(i)
+	}
+	if total == 0 {
+		return -1
+	}
+	r := rnd.Intn(total)
+	for i := 0; i < n; i++ {
+		if r -= weight(i); r < 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *bucketSpec) randomSize() int64 {
+	sr := b.Sizes[pickWeighted(len(b.Sizes), func(i int) int { return b.Sizes[i].Weight })]
+	if sr.min == sr.max {
+		return sr.min
+	}
+	return rnd.Int63n(sr.max-sr.min) + sr.min
+}
+
+func (b *bucketSpec) minSize() int64 {
+	min := b.Sizes[0].min
+	for _, sr := range b.Sizes[1:] {
+		min = cmn.MinI64(min, sr.min)
+	}
+	return min
+}
+
+func (b *bucketSpec) canDo(op int) bool {
+	switch op {
+	case opRename:
+		return cmn.IsProviderAIS(b.bck) && b.objs.len() > 0
+	case opGet, opRangeGet, opHead, opDelete:
+		return b.objs.len() > 0
+	default:
+		return true
+	}
+}
+
+func (w *workloadSpec) opWeight(b *bucketSpec, i int) int {
+	if !b.canDo(w.ops[i]) {
+		return 0
+	}
+	return w.weights[i]
+}
+
+func (w *workloadSpec) bucketWeight(b *bucketSpec) int {
+	for i := range w.ops {
+		if w.opWeight(b, i) > 0 {
+			return b.Weight
+		}
+	}
+	return 0
+}
+
+// bootstrap lists objects of the buckets.
+func (w *workloadSpec) bootstrap() error {
+	total := 0
+	for _, b := range w.Buckets {
+		names, err := tutils.ListObjectsFast(runParams.proxyURL, b.bck, runParams.subDir)
+		if err != nil {
+			return fmt.Errorf("failed to list bucket %s, err: %v", b.bck, err)
+		}
+		b.objs.init(names)
+		total += len(names)
+	}
+	if pickWeighted(len(w.Buckets), func(i int) int { return w.bucketWeight(w.Buckets[i]) }) < 0 {
+		return errors.New("nothing to do, buckets are empty")
+	}
+	fmt.Printf("Found %d existing objects\n", total)
+	return nil
+}
+
+func (w *workloadSpec) newWorkOrder() (*workOrder, error) {
+	bi := pickWeighted(len(w.Buckets), func(i int) int { return w.bucketWeight(w.Buckets[i]) })
+	if bi < 0 {
+		return nil, errors.New("nothing to do, buckets are empty")
+	}
+	b := w.Buckets[bi]
+	op := w.ops[pickWeighted(len(w.ops), func(i int) int { return w.opWeight(b, i) })]
+
+	wo := &workOrder{
+		op:       op,
+		proxyURL: runParams.proxyURL,
+		bck:      b.bck,
+		spec:     b,
+	}
+	switch op {
+	case opGet:
+		wo.objName = b.objs.random()
+		wo.readOff, wo.readLen = runParams.readOff, runParams.readLen
+		getPending.Inc()
+	case opRangeGet:
+		wo.objName = b.objs.random()
+		wo.readLen = w.rangeLen
+		if max := b.minSize() - w.rangeLen; max > 0 {
+			wo.readOff = rnd.Int63n(max + 1)
+		}
+		getPending.Inc()
+	case opPut, opAppend:
+		objName, err := generatePutObjectName()
+		if err != nil {
+			return nil, err
+		}
+		wo.objName, wo.size = objName, b.randomSize()
+		if op == opPut {
+			putPending.Inc()
+		}
+	case opHead:
+		wo.objName = b.objs.random()
+	case opDelete:
+		// no longer available for other operations
+		wo.objName = b.objs.random()
+		b.objs.remove(wo.objName)
+	case opRename:
+		newName, err := generatePutObjectName()
+		if err != nil {
+			return nil, err
+		}
+		wo.objName, wo.newName = b.objs.random(), newName
+		b.objs.remove(wo.objName)
+	case opList:
+		wo.pageMarker = b.pageMarker
+	}
+	return wo, nil
+}
+
+// complete updates the bucket (of the workload) upon completion of the work order.
+func (b *bucketSpec) complete(wo *workOrder) {
+	switch wo.op {
+	case opPut, opAppend:
+		if wo.err == nil {
+			b.objs.add(wo.objName)
+		}
+	case opRename:
+		if wo.err == nil {
+			b.objs.add(wo.newName)
+		} else {
+			b.objs.add(wo.objName)
+		}
+	case opList:
+		if wo.err == nil {
+			b.pageMarker = wo.pageMarker
+		}
+	}
+}
+
+/////////////
+// opStats //
+/////////////
+
+func newOpStats(start time.Time) *opStats {
+	return &opStats{req: stats.NewHTTPReq(start), lat: stats.NewLatencies()}
+}
+
+func (s *opStats) add(wo *workOrder, delta time.Duration) {
+	if wo.err != nil {
+		s.req.AddErr()
+		return
+	}
+	var size int64
+	switch wo.op {
+	case opGet, opRangeGet, opPut, opAppend:
+		size = wo.size
+	}
+	s.req.Add(size, delta)
+	s.lat.Add(delta)
+}
+
+func addOpStats(wo *workOrder, delta time.Duration) {
+	s, ok := allOpStats[wo.op]
+	if !ok {
+		s = newOpStats(time.Now())
+		allOpStats[wo.op] = s
+	}
+	s.add(wo, delta)
+}
+
+func sortedOps() []int {
+	ops := make([]int, 0, len(allOpStats))
+	for op := range allOpStats {
+		ops = append(ops, op)
+	}
+	sort.Ints(ops)
+	return ops
+}
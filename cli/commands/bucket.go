@@ -268,6 +268,15 @@ func bucketDetailsSync(c *cli.Context, bck cmn.Bck) error {
 	return templates.DisplayOutput(summaries, c.App.Writer, tmpl)
 }
 
+// Shows per-bucket request and traffic statistics aggregated across all targets
+func bucketStats(c *cli.Context, bck cmn.Bck) error {
+	bucketStats, err := api.GetBucketStats(defaultAPIParams, bck)
+	if err != nil {
+		return err
+	}
+	return templates.DisplayOutput(bucketStats, c.App.Writer, templates.BucketStatsTmpl, flagIsSet(c, jsonFlag))
+}
+
 // replace user-friendly properties like `access=ro` with real values
 // like `aattrs = GET | HEAD`. All numbers are passed to API as is
 func reformatBucketProps(bck cmn.Bck, nvs cmn.SimpleKVs) error {
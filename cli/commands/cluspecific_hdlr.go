@@ -18,6 +18,7 @@ import (
 var (
 	cluSpecificCmdsFlags = map[string][]cli.Flag{
 		commandStatus: append(longRunFlags, jsonFlag, noHeaderFlag),
+		commandTop:    {refreshFlag},
 	}
 
 	registerCmdsFlags = map[string][]cli.Flag{
@@ -54,6 +55,12 @@ var (
 			Flags:        cluSpecificCmdsFlags[commandStatus],
 			BashComplete: daemonCompletions(true /* optional */, false /* omit proxies */),
 		},
+		{
+			Name:   commandTop,
+			Usage:  "display interactive, continuously updated view of the cluster",
+			Flags:  cluSpecificCmdsFlags[commandTop],
+			Action: topHandler,
+		},
 	}
 )
 
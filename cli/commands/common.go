@@ -37,6 +37,8 @@ const (
 	commandECEncode  = "ec-encode"
 	commandConcat    = "concat"
 	commandCat       = "cat"
+	commandSync      = "sync"
+	commandTop       = "top"
 
 	// Subcommands - preferably nouns
 	subcmdDsort     = cmn.DSortNameLowercase
@@ -71,6 +73,7 @@ const (
 	subcmdShowNode      = subcmdNode
 	subcmdShowXaction   = subcmdXaction
 	subcmdShowRebalance = subcmdRebalance
+	subcmdShowRebPlan   = subcmdRebalance + "-plan"
 
 	// Create subcommands
 	subcmdCreateBucket = subcmdBucket
@@ -141,6 +144,7 @@ const (
 	objectArgument           = "BUCKET_NAME/OBJECT_NAME"
 	optionalObjectsArgument  = "BUCKET_NAME/[OBJECT_NAME]..."
 	objectOldNewArgument     = "BUCKET_NAME/OBJECT_NAME NEW_OBJECT_NAME"
+	syncArgument             = "SOURCE DESTINATION"
 
 	// Daemons
 	daemonIDArgument           = "DAEMON_ID"
@@ -166,6 +170,10 @@ const (
 	// Auth
 	addUserArgument    = "USER_NAME USER_PASSWORD"
 	deleteUserArgument = "USER_NAME"
+	updateUserArgument = "USER_NAME"
+	createKeyArgument  = "SERVICE_ACCOUNT [KEY_NAME]"
+	listKeysArgument   = "[SERVICE_ACCOUNT]"
+	removeKeyArgument  = "KEY_ID"
 	userLoginArgument  = "USER_NAME USER_PASSWORD"
 )
 
@@ -200,9 +208,13 @@ var (
 	pagedFlag         = cli.BoolFlag{Name: "paged", Usage: "fetch and print the bucket list page by page, ignored in fast mode"}
 	showUnmatchedFlag = cli.BoolTFlag{Name: "show-unmatched", Usage: "list objects that were not matched by regex and template"}
 	activeFlag        = cli.BoolFlag{Name: "active", Usage: "show only running xactions"}
+	bucketStatsFlag   = cli.BoolFlag{Name: "stats", Usage: "show the buckets' request and traffic statistics accumulated by all targets"}
 
 	// Daeclu
-	countFlag = cli.IntFlag{Name: "count", Usage: "total number of generated reports", Value: countDefault}
+	countFlag         = cli.IntFlag{Name: "count", Usage: "total number of generated reports", Value: countDefault}
+	addTargetsFlag    = cli.StringFlag{Name: "add", Usage: "comma separated list of IDs of the targets to join the cluster"}
+	removeTargetsFlag = cli.StringFlag{Name: "remove", Usage: "comma separated list of IDs of the targets to leave the cluster"}
+	decommissionFlag  = cli.BoolFlag{Name: "decommission", Usage: "migrate all objects of the target to the rest of the cluster before removing it"}
 
 	// Download
 	descriptionFlag = cli.StringFlag{Name: "description,desc", Usage: "description of the job - can be useful when listing all downloads"}
@@ -236,6 +248,18 @@ var (
 	targetFlag     = cli.StringFlag{Name: "target", Usage: "ais target ID"}
 	yesFlag        = cli.BoolFlag{Name: "yes,y", Usage: "assume 'yes' for all questions"}
 
+	// Sync
+	deleteFlag       = cli.BoolFlag{Name: "delete", Usage: "delete files (objects) of the destination that are missing in the source"}
+	syncChecksumFlag = cli.BoolFlag{Name: "checksum", Usage: "fail if checksums cannot be compared (instead of comparing only size) to find files (objects) that differ"}
+	sizeOnlyFlag     = cli.BoolFlag{Name: "size-only", Usage: "compare only size (instead of size and checksum) to find files (objects) that differ"}
+	srcURLFlag       = cli.StringFlag{Name: "src-url", Usage: "URL of the cluster of the source bucket (default: the configured cluster)"}
+	dstURLFlag       = cli.StringFlag{Name: "dst-url", Usage: "URL of the cluster of the destination bucket (default: the configured cluster)"}
+
+	// Auth
+	roleFlag    = cli.StringSliceFlag{Name: "role", Usage: "user role, e.g. 'read-only', 'read-write', 'bucket-admin', 'cluster-admin' (can be repeated)"}
+	serviceFlag = cli.BoolFlag{Name: "service", Usage: "add a service account: no password, access with API keys only"}
+	expireFlag  = cli.DurationFlag{Name: "expire", Usage: "API key lifetime, e.g. '720h' (default: never expires)"}
+
 	longRunFlags = []cli.Flag{refreshFlag, countFlag}
 
 	baseLstRngFlags = []cli.Flag{
@@ -105,6 +105,23 @@ func clusterRemoveNode(c *cli.Context, daemonID string) (err error) {
 	return nil
 }
 
+func clusterDecommissionNode(c *cli.Context, daemonID string) (err error) {
+	if _, err = fillMap(); err != nil {
+		return
+	}
+	if _, ok := target[daemonID]; !ok {
+		return fmt.Errorf("invalid target ID (%s) - no such target", daemonID)
+	}
+	xactID, err := api.DecommissionNode(defaultAPIParams, daemonID)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(c.App.Writer, "Decommissioning target %s (xaction %s): the target will be removed from the cluster "+
+		"once all its objects are migrated and verified (see '%s %s %s')\n",
+		daemonID, xactID, commandShow, subcmdShowXaction, cmn.ActDecommission)
+	return
+}
+
 // Displays the stats of a daemon
 func daemonStats(c *cli.Context, daemonID string, useJSON bool) error {
 	if res, ok := proxy[daemonID]; ok {
@@ -358,3 +375,56 @@ func showGlobalRebalance(c *cli.Context, keepMonitoring bool, refreshRate time.D
 
 	return nil
 }
+
+func showRebalancePlan(c *cli.Context, msg *cmn.RebPlanMsg, useJSON bool) error {
+	plan, err := api.GetRebalancePlan(defaultAPIParams, msg)
+	if err != nil {
+		return err
+	}
+	if useJSON {
+		return templates.DisplayOutput(plan, c.App.Writer, "", true)
+	}
+
+	tw := &tabwriter.Writer{}
+	tw.Init(c.App.Writer, 0, 8, 2, ' ', 0)
+
+	sortedIDs := make([]string, 0, len(plan))
+	for daemonID := range plan {
+		sortedIDs = append(sortedIDs, daemonID)
+	}
+	sort.Strings(sortedIDs)
+
+	fmt.Fprintln(tw, "Source\tDestination\tObjects\tSize")
+	fmt.Fprintln(tw, strings.Repeat("======\t", 4 /* num of columns */))
+	for _, daemonID := range sortedIDs {
+		targets := plan[daemonID].Targets
+		destIDs := make([]string, 0, len(targets))
+		for destID := range targets {
+			destIDs = append(destIDs, destID)
+		}
+		sort.Strings(destIDs)
+		for _, destID := range destIDs {
+			st := targets[destID]
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", daemonID, destID, st.Objects, cmn.B2S(st.Bytes, 2))
+		}
+	}
+	tw.Flush()
+
+	total := plan.Total()
+	bcks := make([]string, 0, len(total.Buckets))
+	for bck := range total.Buckets {
+		bcks = append(bcks, bck)
+	}
+	sort.Strings(bcks)
+
+	fmt.Fprintln(c.App.Writer)
+	fmt.Fprintln(tw, "Bucket\tObjects\tSize")
+	fmt.Fprintln(tw, strings.Repeat("======\t", 3 /* num of columns */))
+	for _, bck := range bcks {
+		st := total.Buckets[bck]
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", bck, st.Objects, cmn.B2S(st.Bytes, 2))
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t%s\n", total.Total.Objects, cmn.B2S(total.Total.Bytes, 2))
+	tw.Flush()
+	return nil
+}
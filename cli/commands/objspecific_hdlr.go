@@ -48,6 +48,17 @@ var (
 			lengthFlag,
 			checksumFlag,
 		},
+		commandSync: {
+			deleteFlag,
+			syncChecksumFlag,
+			sizeOnlyFlag,
+			srcURLFlag,
+			dstURLFlag,
+			concurrencyFlag,
+			dryRunFlag,
+			verboseFlag,
+			yesFlag,
+		},
 	}
 
 	objectSpecificCmds = []cli.Command{
@@ -106,6 +117,13 @@ var (
 			Action:       catHandler,
 			BashComplete: bucketCompletions([]cli.BashCompleteFunc{}, false /* multiple */, true /* separator */),
 		},
+		{
+			Name:      commandSync,
+			Usage:     "synchronize the destination with the source (local directory or bucket), transferring only the files (objects) that differ",
+			ArgsUsage: syncArgument,
+			Flags:     objectSpecificCmdsFlags[commandSync],
+			Action:    syncHandler,
+		},
 	}
 )
 
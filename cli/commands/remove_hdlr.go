@@ -18,7 +18,7 @@ var (
 	removeCmdsFlags = map[string][]cli.Flag{
 		subcmdRemoveBucket:   {},
 		subcmdRemoveObject:   baseLstRngFlags,
-		subcmdRemoveNode:     {},
+		subcmdRemoveNode:     {decommissionFlag},
 		subcmdRemoveDownload: {},
 		subcmdRemoveDsort:    {},
 	}
@@ -121,6 +121,9 @@ func removeObjectHandler(c *cli.Context) (err error) {
 
 func removeNodeHandler(c *cli.Context) (err error) {
 	daemonID := c.Args().First()
+	if flagIsSet(c, decommissionFlag) {
+		return clusterDecommissionNode(c, daemonID)
+	}
 	return clusterRemoveNode(c, daemonID)
 }
 
@@ -34,6 +34,8 @@ var (
 			providerFlag,
 			fastDetailsFlag,
 			cachedFlag,
+			bucketStatsFlag,
+			jsonFlag,
 		},
 		subcmdShowDisk: append(
 			longRunFlags,
@@ -70,6 +72,11 @@ var (
 		subcmdShowRebalance: {
 			refreshFlag,
 		},
+		subcmdShowRebPlan: {
+			addTargetsFlag,
+			removeTargetsFlag,
+			jsonFlag,
+		},
 	}
 
 	showCmds = []cli.Command{
@@ -142,6 +149,14 @@ var (
 					Action:       showRebalanceHandler,
 					BashComplete: flagCompletions,
 				},
+				{
+					Name:         subcmdShowRebPlan,
+					Usage:        "show how much data global rebalance would move if targets were added or removed",
+					ArgsUsage:    noArguments,
+					Flags:        showCmdsFlags[subcmdShowRebPlan],
+					Action:       showRebPlanHandler,
+					BashComplete: flagCompletions,
+				},
 			},
 		},
 	}
@@ -155,6 +170,9 @@ func showBucketHandler(c *cli.Context) (err error) {
 	if bck, err = validateBucket(c, bck, "", true); err != nil {
 		return
 	}
+	if flagIsSet(c, bucketStatsFlag) {
+		return bucketStats(c, bck)
+	}
 	return bucketDetails(c, bck)
 }
 
@@ -301,3 +319,17 @@ func showObjectHandler(c *cli.Context) (err error) {
 func showRebalanceHandler(c *cli.Context) (err error) {
 	return showGlobalRebalance(c, flagIsSet(c, refreshFlag), calcRefreshRate(c))
 }
+
+func showRebPlanHandler(c *cli.Context) (err error) {
+	msg := &cmn.RebPlanMsg{}
+	if flagIsSet(c, addTargetsFlag) {
+		msg.Add = makeList(parseStrFlag(c, addTargetsFlag), ",")
+	}
+	if flagIsSet(c, removeTargetsFlag) {
+		msg.Remove = makeList(parseStrFlag(c, removeTargetsFlag), ",")
+	}
+	if len(msg.Add) == 0 && len(msg.Remove) == 0 {
+		return missingArgumentsError(c, "--"+addTargetsFlag.Name+" or --"+removeTargetsFlag.Name)
+	}
+	return showRebalancePlan(c, msg, flagIsSet(c, jsonFlag))
+}
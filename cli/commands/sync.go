@@ -0,0 +1,397 @@
+// Package commands provides the set of CLI commands used to communicate with the AIS cluster.
+// This specific file handles synchronization of buckets and local directories.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/urfave/cli"
+)
+
+type (
+	// syncEndpoint is either a local directory or a bucket (with an optional
+	// virtual directory) in the cluster reachable via `params`.
+	syncEndpoint struct {
+		bck    cmn.Bck // empty name - local directory
+		prefix string
+		dir    string
+		params api.BaseParams
+	}
+
+	// syncEntry is a file or an object; the name is relative to the endpoint.
+	// Objects do not have modification time (only access time, updated by
+	// reads), so the content is compared by checksums.
+	syncEntry struct {
+		name  string
+		path  string // local file
+		size  int64
+		cksum string // xxhash; computed lazily for local files
+	}
+
+	syncCompareFunc func(src, dst *syncEntry) (differ bool, err error)
+)
+
+func newSyncEndpoint(c *cli.Context, arg string, urlFlag cli.StringFlag) (ep *syncEndpoint, err error) {
+	ep = &syncEndpoint{params: defaultAPIParams}
+	if !strings.Contains(arg, cmn.BckProviderSeparator) {
+		if flagIsSet(c, urlFlag) {
+			return nil, incorrectUsageMsg(c, "flag %s cannot be used with local directory %q", urlFlag.Name, arg)
+		}
+		if ep.dir, err = filepath.Abs(cmn.ExpandPath(arg)); err != nil {
+			return nil, err
+		}
+		return ep, nil
+	}
+
+	ep.bck, ep.prefix = parseBckObjectURI(arg)
+	ep.bck.Name = cleanBucketName(ep.bck.Name)
+	if ep.bck.Name == "" {
+		return nil, incorrectUsageMsg(c, "'%s': missing bucket name", arg)
+	}
+	if ep.prefix != "" && !strings.HasSuffix(ep.prefix, "/") {
+		ep.prefix += "/"
+	}
+	if flagIsSet(c, urlFlag) {
+		ep.params = cliAPIParams(parseStrFlag(c, urlFlag))
+	}
+	return ep, nil
+}
+
+func (ep *syncEndpoint) isBucket() bool { return ep.bck.Name != "" }
+
+func (ep *syncEndpoint) String() string {
+	if ep.isBucket() {
+		return ep.bck.String() + "/" + ep.prefix
+	}
+	return ep.dir
+}
+
+func (ep *syncEndpoint) fullName(name string) string {
+	if ep.isBucket() {
+		return ep.bck.String() + "/" + ep.prefix + name
+	}
+	return filepath.Join(ep.dir, filepath.FromSlash(name))
+}
+
+// list returns all files (objects) of the endpoint; a missing local directory
+// has no files.
+func (ep *syncEndpoint) list() (map[string]*syncEntry, error) {
+	entries := make(map[string]*syncEntry)
+	if ep.isBucket() {
+		msg := &cmn.SelectMsg{
+			Props:  strings.Join([]string{cmn.GetPropsSize, cmn.GetPropsChecksum}, ","),
+			Prefix: ep.prefix,
+		}
+		objList, err := api.ListBucket(ep.params, ep.bck, msg, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range objList.Entries {
+			if !e.IsStatusOK() {
+				continue
+			}
+			entry := &syncEntry{name: strings.TrimPrefix(e.Name, ep.prefix), size: e.Size, cksum: e.Checksum}
+			entries[entry.name] = entry
+		}
+		return entries, nil
+	}
+
+	walk := func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == ep.dir {
+				return nil
+			}
+			return cmn.PathWalkErr(err)
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(ep.dir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		entries[name] = &syncEntry{name: name, path: path, size: info.Size()}
+		return nil
+	}
+	if err := filepath.Walk(ep.dir, walk); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (ep *syncEndpoint) checkBucket() (cksumType string, err error) {
+	props, err := api.HeadBucket(ep.params, ep.bck)
+	if err != nil {
+		if httpErr, ok := err.(*cmn.HTTPError); ok && httpErr.Status == http.StatusNotFound {
+			return "", fmt.Errorf("bucket with name %q does not exist", ep.bck)
+		}
+		return "", fmt.Errorf("could not reach %q bucket: %v", ep.bck, err)
+	}
+	return props.Cksum.Type, nil
+}
+
+func (e *syncEntry) checksum() (string, error) {
+	if e.cksum != "" || e.path == "" {
+		return e.cksum, nil
+	}
+	fh, err := os.Open(e.path)
+	if err != nil {
+		return "", err
+	}
+	defer fh.Close()
+	e.cksum, err = cmn.ComputeXXHash(fh, nil)
+	return e.cksum, err
+}
+
+func compareSize(src, dst *syncEntry) (bool, error) {
+	return src.size != dst.size, nil
+}
+
+func compareChecksum(src, dst *syncEntry) (bool, error) {
+	if src.size != dst.size {
+		return true, nil
+	}
+	srcCksum, err := src.checksum()
+	if err != nil {
+		return false, err
+	}
+	dstCksum, err := dst.checksum()
+	if err != nil {
+		return false, err
+	}
+	return srcCksum == "" || srcCksum != dstCksum, nil
+}
+
+// syncDiff returns the source entries to transfer and the destination entries
+// that are missing in the source, both sorted by name.
+func syncDiff(src, dst map[string]*syncEntry, compare syncCompareFunc) (transfer, extra []*syncEntry, err error) {
+	for name, s := range src {
+		d, ok := dst[name]
+		if !ok {
+			transfer = append(transfer, s)
+			continue
+		}
+		differ, err := compare(s, d)
+		if err != nil {
+			return nil, nil, err
+		}
+		if differ {
+			transfer = append(transfer, s)
+		}
+	}
+	for name, d := range dst {
+		if _, ok := src[name]; !ok {
+			extra = append(extra, d)
+		}
+	}
+	sort.Slice(transfer, func(i, j int) bool { return transfer[i].name < transfer[j].name })
+	sort.Slice(extra, func(i, j int) bool { return extra[i].name < extra[j].name })
+	return transfer, extra, nil
+}
+
+func syncHandler(c *cli.Context) (err error) {
+	if c.NArg() < 1 {
+		return missingArgumentsError(c, "source directory or bucket", "destination directory or bucket")
+	}
+	if c.NArg() < 2 {
+		return missingArgumentsError(c, "destination directory or bucket")
+	}
+	if c.NArg() > 2 {
+		return incorrectUsageMsg(c, "too many arguments")
+	}
+	if flagIsSet(c, syncChecksumFlag) && flagIsSet(c, sizeOnlyFlag) {
+		return incorrectUsageMsg(c, "flags %s and %s cannot be both set", syncChecksumFlag.Name, sizeOnlyFlag.Name)
+	}
+
+	src, err := newSyncEndpoint(c, c.Args().Get(0), srcURLFlag)
+	if err != nil {
+		return
+	}
+	dst, err := newSyncEndpoint(c, c.Args().Get(1), dstURLFlag)
+	if err != nil {
+		return
+	}
+	if !src.isBucket() && !dst.isBucket() {
+		return incorrectUsageMsg(c, "either source or destination must be a bucket (e.g. %s://BUCKET_NAME/[PREFIX])", cmn.ProviderAIS)
+	}
+	return syncEndpoints(c, src, dst)
+}
+
+func syncEndpoints(c *cli.Context, src, dst *syncEndpoint) error {
+	if !src.isBucket() {
+		if _, err := os.Stat(src.dir); err != nil {
+			return err
+		}
+	}
+	var cksumTypes []string
+	for _, ep := range []*syncEndpoint{src, dst} {
+		if !ep.isBucket() {
+			cksumTypes = append(cksumTypes, cmn.ChecksumXXHash)
+			continue
+		}
+		cksumType, err := ep.checkBucket()
+		if err != nil {
+			return err
+		}
+		cksumTypes = append(cksumTypes, cksumType)
+	}
+	compare := compareChecksum
+	switch {
+	case flagIsSet(c, sizeOnlyFlag):
+		compare = compareSize
+	case cksumTypes[0] != cksumTypes[1] || cksumTypes[0] == cmn.ChecksumNone:
+		if flagIsSet(c, syncChecksumFlag) {
+			return fmt.Errorf("cannot compare checksums of %s (%s) and %s (%s)", src, cksumTypes[0], dst, cksumTypes[1])
+		}
+		fmt.Fprintf(c.App.Writer, "Checksums of %s (%s) and %s (%s) cannot be compared: comparing sizes only\n",
+			src, cksumTypes[0], dst, cksumTypes[1])
+		compare = compareSize
+	}
+
+	srcEntries, err := src.list()
+	if err != nil {
+		return err
+	}
+	dstEntries, err := dst.list()
+	if err != nil {
+		return err
+	}
+	transfer, extra, err := syncDiff(srcEntries, dstEntries, compare)
+	if err != nil {
+		return err
+	}
+	if !flagIsSet(c, deleteFlag) {
+		extra = nil
+	}
+	var (
+		totalSize int64
+		upToDate  = len(srcEntries) - len(transfer)
+	)
+	for _, e := range transfer {
+		totalSize += e.size
+	}
+
+	if flagIsSet(c, dryRunFlag) {
+		fmt.Fprintln(c.App.Writer, dryRunHeader+" "+dryRunExplanation)
+		for _, e := range transfer {
+			fmt.Fprintf(c.App.Writer, "COPY %s => %s\n", src.fullName(e.name), dst.fullName(e.name))
+		}
+		for _, e := range extra {
+			fmt.Fprintf(c.App.Writer, "DELETE %s\n", dst.fullName(e.name))
+		}
+		fmt.Fprintf(c.App.Writer, "%d to copy (%s), %d to delete, %d up to date\n",
+			len(transfer), cmn.B2S(totalSize, 2), len(extra), upToDate)
+		return nil
+	}
+
+	if len(extra) > 0 && !flagIsSet(c, yesFlag) {
+		var input string
+		fmt.Fprintf(c.App.Writer, "Delete %d object(s) missing in %s from %s? [y/n]: ", len(extra), src, dst)
+		fmt.Scanln(&input)
+		if ok, _ := cmn.ParseBool(input); !ok {
+			return errors.New("Operation canceled")
+		}
+	}
+
+	var (
+		verbose  = flagIsSet(c, verboseFlag)
+		errCount atomic.Int32
+		wg       = &sync.WaitGroup{}
+		sema     = cmn.NewDynSemaphore(parseIntFlag(c, concurrencyFlag))
+	)
+	run := func(e *syncEntry, action string, do func(*syncEntry) error) {
+		sema.Acquire()
+		wg.Add(1)
+		go func() {
+			defer func() {
+				sema.Release()
+				wg.Done()
+			}()
+			if err := do(e); err != nil {
+				fmt.Fprintf(c.App.Writer, "Failed to %s %s: %v\n", strings.ToLower(action), dst.fullName(e.name), err)
+				errCount.Inc()
+			} else if verbose {
+				fmt.Fprintf(c.App.Writer, "%s %s\n", action, dst.fullName(e.name))
+			}
+		}()
+	}
+	for _, e := range transfer {
+		run(e, "COPY", func(e *syncEntry) error { return syncCopy(src, dst, e) })
+	}
+	for _, e := range extra {
+		run(e, "DELETE", dst.remove)
+	}
+	wg.Wait()
+
+	if failed := errCount.Load(); failed != 0 {
+		return fmt.Errorf("failed to sync %d object(s)", failed)
+	}
+	fmt.Fprintf(c.App.Writer, "Synced %s => %s: %d copied (%s), %d deleted, %d up to date\n",
+		src, dst, len(transfer), cmn.B2S(totalSize, 2), len(extra), upToDate)
+	return nil
+}
+
+// syncCopy copies the source file (object) to the destination; objects are
+// downloaded into temporary files first.
+func syncCopy(src, dst *syncEndpoint, e *syncEntry) error {
+	path := e.path
+	if src.isBucket() {
+		var tmpDir string
+		if !dst.isBucket() {
+			// next to the destination file, to rename it when done
+			tmpDir = filepath.Dir(dst.fullName(e.name))
+			if err := cmn.CreateDir(tmpDir); err != nil {
+				return err
+			}
+		}
+		f, err := ioutil.TempFile(tmpDir, ".ais-sync-")
+		if err != nil {
+			return err
+		}
+		_, err = api.GetObject(src.params, src.bck, src.prefix+e.name, api.GetObjectInput{Writer: f})
+		if err == nil {
+			err = f.Chmod(0644)
+		}
+		if errClose := f.Close(); err == nil {
+			err = errClose
+		}
+		if err == nil && !dst.isBucket() {
+			err = os.Rename(f.Name(), dst.fullName(e.name))
+		}
+		if err != nil || !dst.isBucket() {
+			os.Remove(f.Name())
+			return err
+		}
+		defer os.Remove(f.Name())
+		path = f.Name()
+	}
+
+	fh, err := cmn.NewFileHandle(path)
+	if err != nil {
+		return err
+	}
+	putArgs := api.PutObjectArgs{BaseParams: dst.params, Bck: dst.bck, Object: dst.prefix + e.name, Reader: fh}
+	return api.PutObject(putArgs)
+}
+
+func (ep *syncEndpoint) remove(e *syncEntry) error {
+	if ep.isBucket() {
+		return api.DeleteObject(ep.params, ep.bck, ep.prefix+e.name)
+	}
+	return os.Remove(e.path)
+}
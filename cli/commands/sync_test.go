@@ -0,0 +1,53 @@
+// Package commands provides the set of CLI commands used to communicate with the AIS cluster.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package commands
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSyncDiff(t *testing.T) {
+	var (
+		src = map[string]*syncEntry{
+			"same":  {name: "same", size: 10, cksum: "a"},
+			"size":  {name: "size", size: 10, cksum: "a"},
+			"cksum": {name: "cksum", size: 10, cksum: "b"},
+			"new":   {name: "new", size: 10},
+		}
+		dst = map[string]*syncEntry{
+			"same":  {name: "same", size: 10, cksum: "a"},
+			"size":  {name: "size", size: 20, cksum: "a"},
+			"cksum": {name: "cksum", size: 10, cksum: "a"},
+			"extra": {name: "extra", size: 10},
+		}
+	)
+	tests := []struct {
+		name     string
+		compare  syncCompareFunc
+		transfer []string
+	}{
+		{"size", compareSize, []string{"new", "size"}},
+		{"checksum", compareChecksum, []string{"cksum", "new", "size"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transfer, extra, err := syncDiff(src, dst, test.compare)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, 0, len(transfer))
+			for _, e := range transfer {
+				names = append(names, e.name)
+			}
+			if !reflect.DeepEqual(names, test.transfer) {
+				t.Errorf("expected to transfer %v, got %v", test.transfer, names)
+			}
+			if len(extra) != 1 || extra[0].name != "extra" {
+				t.Errorf("expected extra [extra], got %v", extra)
+			}
+		})
+	}
+}
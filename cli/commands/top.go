@@ -0,0 +1,731 @@
+// Package commands provides the set of CLI commands used to communicate with the AIS cluster.
+// This specific file handles the interactive cluster dashboard (`ais top`).
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/ios"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/urfave/cli"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// The dashboard periodically queries status of all the nodes, disk stats of
+// all the targets and the running xactions, and redraws the screen in place.
+// Throughput columns are computed from the differences of the (cumulative)
+// counters between two consecutive refreshes, while latency percentiles are
+// reported by the nodes themselves and cover all requests since node startup.
+// Queries run in the background (one round at a time), so that the dashboard
+// keeps responding to keys while the cluster is slow to respond.
+
+const (
+	topAlertPct        = 90               // capacity, disk utilization, CPU and memory
+	topAlertXactWindow = 10 * time.Minute // how long to keep reporting aborted xactions
+)
+
+// keyboard commands
+const (
+	topKeyQuit = iota + 1
+	topKeyPrev
+	topKeyNext
+	topKeyReverse
+)
+
+type (
+	// topSnapshot is the result of a single round of queries.
+	topSnapshot struct {
+		time     time.Time
+		smap     *cluster.Smap
+		status   map[string]*stats.DaemonStatus
+		disks    map[string]map[string]*ios.SelectedDiskStats
+		errs     map[string]error
+		xactions map[string][]*stats.BaseXactStatsExt
+		xactErr  error
+	}
+
+	topFetchResult struct {
+		snap *topSnapshot
+		err  error
+	}
+
+	topRow struct {
+		id       string
+		target   bool
+		err      error // node did not respond
+		hasRates bool  // false until the second refresh
+
+		getRate, putRate, errRate, putBps float64
+		errDelta                          int64
+
+		getP99, putP99 int64 // microseconds
+
+		diskRead, diskWrite int64 // bytes per second
+		diskUtil            int64 // max over all disks, %
+
+		cpu, mem, capPct float64
+	}
+
+	topColumn struct {
+		name  string
+		width int
+		value func(r *topRow) string
+		less  func(a, b *topRow) bool
+	}
+
+	topXact struct {
+		id, kind, bck      string
+		start              time.Time
+		running            int
+		objCount, bytesCnt int64
+	}
+
+	topView struct {
+		prev, cur *topSnapshot
+		err       error
+		rows      []*topRow
+		sortCol   int
+		reverse   bool
+	}
+)
+
+var topColumns = []topColumn{
+	{"NODE", 16,
+		func(r *topRow) string { return r.id },
+		func(a, b *topRow) bool { return a.id < b.id }},
+	{"TYPE", 6,
+		func(r *topRow) string {
+			if r.target {
+				return "target"
+			}
+			return "proxy"
+		},
+		func(a, b *topRow) bool { return !a.target && b.target }},
+	{"GET/s", 9,
+		func(r *topRow) string { return topRate(r, r.getRate) },
+		func(a, b *topRow) bool { return a.getRate < b.getRate }},
+	{"PUT/s", 9,
+		func(r *topRow) string { return topRate(r, r.putRate) },
+		func(a, b *topRow) bool { return a.putRate < b.putRate }},
+	{"PUT BW", 10,
+		func(r *topRow) string { return topSpeed(r, int64(r.putBps)) },
+		func(a, b *topRow) bool { return a.putBps < b.putBps }},
+	{"GET p99", 9,
+		func(r *topRow) string { return topLatency(r.getP99) },
+		func(a, b *topRow) bool { return a.getP99 < b.getP99 }},
+	{"PUT p99", 9,
+		func(r *topRow) string { return topLatency(r.putP99) },
+		func(a, b *topRow) bool { return a.putP99 < b.putP99 }},
+	{"DISK R", 10,
+		func(r *topRow) string { return topTargetSpeed(r, r.diskRead) },
+		func(a, b *topRow) bool { return a.diskRead < b.diskRead }},
+	{"DISK W", 10,
+		func(r *topRow) string { return topTargetSpeed(r, r.diskWrite) },
+		func(a, b *topRow) bool { return a.diskWrite < b.diskWrite }},
+	{"UTIL%", 6,
+		func(r *topRow) string { return topTargetPct(r, float64(r.diskUtil)) },
+		func(a, b *topRow) bool { return a.diskUtil < b.diskUtil }},
+	{"CAP%", 6,
+		func(r *topRow) string { return topTargetPct(r, r.capPct) },
+		func(a, b *topRow) bool { return a.capPct < b.capPct }},
+	{"CPU%", 6,
+		func(r *topRow) string { return topPct(r, r.cpu) },
+		func(a, b *topRow) bool { return a.cpu < b.cpu }},
+	{"MEM%", 6,
+		func(r *topRow) string { return topPct(r, r.mem) },
+		func(a, b *topRow) bool { return a.mem < b.mem }},
+	{"ERR/s", 7,
+		func(r *topRow) string { return topRate(r, r.errRate) },
+		func(a, b *topRow) bool { return a.errRate < b.errRate }},
+}
+
+func topHandler(c *cli.Context) (err error) {
+	var (
+		in, out     = int(os.Stdin.Fd()), int(os.Stdout.Fd())
+		refreshRate = calcRefreshRate(c)
+		view        = &topView{}
+	)
+	if !terminal.IsTerminal(in) || !terminal.IsTerminal(out) {
+		return errors.New("top requires an interactive terminal")
+	}
+	oldState, err := terminal.MakeRaw(in)
+	if err != nil {
+		return err
+	}
+	defer terminal.Restore(in, oldState)
+
+	// alternate screen buffer, hidden cursor
+	fmt.Fprint(c.App.Writer, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(c.App.Writer, "\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan []byte, 16)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			b := make([]byte, n)
+			copy(b, buf[:n])
+			keys <- b
+		}
+	}()
+
+	draw := func() {
+		width, height, err := terminal.GetSize(out)
+		if err != nil {
+			width, height = 120, 40
+		}
+		fmt.Fprint(c.App.Writer, view.render(width, height))
+	}
+
+	var (
+		results  = make(chan topFetchResult, 1) // buffered: the last round may complete after quitting
+		fetching bool
+	)
+	fetch := func() {
+		if fetching {
+			return // the previous round is still in progress
+		}
+		fetching = true
+		go func() {
+			snap, err := topFetch()
+			results <- topFetchResult{snap, err}
+		}()
+	}
+
+	fetch()
+	draw()
+	ticker := time.NewTicker(refreshRate)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			fetch()
+			continue
+		case res := <-results:
+			fetching = false
+			view.update(res.snap, res.err)
+		case b, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, key := range parseTopKeys(b) {
+				if key == topKeyQuit {
+					return nil
+				}
+				view.handleKey(key)
+			}
+		}
+		draw()
+	}
+}
+
+// parseTopKeys translates raw terminal input into keyboard commands.
+func parseTopKeys(b []byte) (keys []int) {
+	for i := 0; i < len(b); i++ {
+		switch b[i] {
+		case 'q', 'Q', 3 /* Ctrl-C */, 4 /* Ctrl-D */ :
+			keys = append(keys, topKeyQuit)
+		case '<', ',', 'h':
+			keys = append(keys, topKeyPrev)
+		case '>', '.', 'l':
+			keys = append(keys, topKeyNext)
+		case 'r', 'R':
+			keys = append(keys, topKeyReverse)
+		case 0x1b: // ESC [ C (right arrow) and ESC [ D (left arrow)
+			if i+2 < len(b) && b[i+1] == '[' {
+				switch b[i+2] {
+				case 'C':
+					keys = append(keys, topKeyNext)
+				case 'D':
+					keys = append(keys, topKeyPrev)
+				}
+				i += 2
+			} else if i+1 == len(b) {
+				keys = append(keys, topKeyQuit) // lone ESC
+			}
+		}
+	}
+	return
+}
+
+func topFetch() (*topSnapshot, error) {
+	smap, err := api.GetClusterMap(defaultAPIParams)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		snap = &topSnapshot{
+			smap:   smap,
+			status: make(map[string]*stats.DaemonStatus, smap.CountProxies()+smap.CountTargets()),
+			disks:  make(map[string]map[string]*ios.SelectedDiskStats, smap.CountTargets()),
+			errs:   make(map[string]error),
+		}
+		wg = &sync.WaitGroup{}
+		mu = &sync.Mutex{}
+	)
+	fetch := func(si *cluster.Snode, isTarget bool) {
+		defer wg.Done()
+		status, err := api.GetDaemonStatus(defaultAPIParams, si.ID())
+		var diskStats map[string]*ios.SelectedDiskStats
+		if err == nil && isTarget {
+			diskStats, err = api.GetTargetDiskStats(defaultAPIParams, si.ID())
+		}
+		mu.Lock()
+		if err != nil {
+			snap.errs[si.ID()] = err
+		} else {
+			snap.status[si.ID()] = status
+			if isTarget {
+				snap.disks[si.ID()] = diskStats
+			}
+		}
+		mu.Unlock()
+	}
+	for _, si := range smap.Pmap {
+		wg.Add(1)
+		go fetch(si, false)
+	}
+	for _, si := range smap.Tmap {
+		wg.Add(1)
+		go fetch(si, true)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		snap.xactions, snap.xactErr = api.MakeXactGetRequest(defaultAPIParams, cmn.Bck{}, "", commandStats, false /* all */)
+	}()
+	wg.Wait()
+	snap.time = time.Now()
+	return snap, nil
+}
+
+func (v *topView) update(snap *topSnapshot, err error) {
+	v.err = err
+	if err != nil {
+		return
+	}
+	v.prev, v.cur = v.cur, snap
+	v.rows = topRows(v.prev, v.cur)
+	v.sort()
+}
+
+func (v *topView) handleKey(key int) {
+	switch key {
+	case topKeyPrev:
+		v.sortCol = (v.sortCol + len(topColumns) - 1) % len(topColumns)
+	case topKeyNext:
+		v.sortCol = (v.sortCol + 1) % len(topColumns)
+	case topKeyReverse:
+		v.reverse = !v.reverse
+	}
+	v.sort()
+}
+
+func (v *topView) sort() { sortTopRows(v.rows, v.sortCol, v.reverse) }
+
+func sortTopRows(rows []*topRow, col int, reverse bool) {
+	less := topColumns[col].less
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if reverse {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return rows[i].id < rows[j].id
+	})
+}
+
+func topStat(status *stats.DaemonStatus, name string) int64 {
+	if status.Stats == nil {
+		return 0
+	}
+	if v, ok := status.Stats.Tracker[name]; ok && v != nil {
+		return v.Value
+	}
+	return 0
+}
+
+// topRows computes per-node rows of the current snapshot; rates require the
+// previous snapshot.
+func topRows(prev, cur *topSnapshot) []*topRow {
+	rows := make([]*topRow, 0, len(cur.status)+len(cur.errs))
+	add := func(nodeMap cluster.NodeMap, isTarget bool) {
+		for id := range nodeMap {
+			row := &topRow{id: id, target: isTarget}
+			rows = append(rows, row)
+			status, ok := cur.status[id]
+			if !ok {
+				row.err = cur.errs[id]
+				if row.err == nil {
+					row.err = errors.New("no response")
+				}
+				continue
+			}
+			row.getP99 = topStat(status, stats.GetLatency+".p99")
+			row.putP99 = topStat(status, stats.PutLatency+".p99")
+			row.cpu, row.mem = status.SysInfo.PctCPUUsed, status.SysInfo.PctMemUsed
+			var used, total uint64
+			for _, fsCap := range status.Capacity {
+				used += fsCap.Used
+				total += fsCap.Used + fsCap.Avail
+			}
+			if total > 0 {
+				row.capPct = float64(used) * 100 / float64(total)
+			}
+			for _, disk := range cur.disks[id] {
+				row.diskRead += disk.RBps
+				row.diskWrite += disk.WBps
+				row.diskUtil = cmn.MaxI64(row.diskUtil, disk.Util)
+			}
+
+			if prev == nil {
+				continue
+			}
+			prevStatus, ok := prev.status[id]
+			elapsed := cur.time.Sub(prev.time).Seconds()
+			if !ok || elapsed <= 0 {
+				continue
+			}
+			rate := func(name string) float64 {
+				delta := topStat(status, name) - topStat(prevStatus, name)
+				if delta < 0 { // node restarted
+					return 0
+				}
+				return float64(delta) / elapsed
+			}
+			row.hasRates = true
+			row.getRate = rate(stats.GetCount)
+			row.putRate = rate(stats.PutCount)
+			row.putBps = rate(stats.PutSize)
+			row.errRate = rate(stats.ErrCount)
+			row.errDelta = cmn.MaxI64(topStat(status, stats.ErrCount)-topStat(prevStatus, stats.ErrCount), 0)
+		}
+	}
+	add(cur.smap.Pmap, false)
+	add(cur.smap.Tmap, true)
+	return rows
+}
+
+// topXactions aggregates running xactions across all targets.
+func topXactions(snap *topSnapshot) []*topXact {
+	byID := make(map[string]*topXact)
+	for _, daemonStats := range snap.xactions {
+		for _, st := range daemonStats {
+			if !st.Running() {
+				continue
+			}
+			x, ok := byID[st.ID()]
+			if !ok {
+				x = &topXact{id: st.ID(), kind: st.Kind(), start: st.StartTime()}
+				if st.Bck().Name != "" {
+					x.bck = st.Bck().String()
+				}
+				byID[st.ID()] = x
+			}
+			x.running++
+			x.objCount += st.ObjCount()
+			x.bytesCnt += st.BytesCount()
+			if st.StartTime().Before(x.start) {
+				x.start = st.StartTime()
+			}
+		}
+	}
+	xacts := make([]*topXact, 0, len(byID))
+	for _, x := range byID {
+		xacts = append(xacts, x)
+	}
+	sort.Slice(xacts, func(i, j int) bool {
+		if !xacts[i].start.Equal(xacts[j].start) {
+			return xacts[i].start.Before(xacts[j].start)
+		}
+		return xacts[i].id < xacts[j].id
+	})
+	return xacts
+}
+
+// topRebalance summarizes the progress of the global rebalance, if any.
+func topRebalance(snap *topSnapshot) string {
+	var (
+		running, finished int
+		start             time.Time
+		ext               stats.ExtRebalanceStats
+	)
+	for _, daemonStats := range snap.xactions {
+		for _, st := range daemonStats {
+			if st.Kind() != cmn.ActGlobalReb {
+				continue
+			}
+			if !st.Running() {
+				finished++
+				continue
+			}
+			running++
+			if start.IsZero() || st.StartTime().Before(start) {
+				start = st.StartTime()
+			}
+			targetExt := &stats.ExtRebalanceStats{}
+			if err := cmn.TryUnmarshal(st.Ext, &targetExt); err == nil {
+				ext.TxRebCount += targetExt.TxRebCount
+				ext.TxRebSize += targetExt.TxRebSize
+				ext.RxRebCount += targetExt.RxRebCount
+				ext.RxRebSize += targetExt.RxRebSize
+				ext.GlobalRebID = cmn.MaxI64(ext.GlobalRebID, targetExt.GlobalRebID)
+			}
+		}
+	}
+	if running == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Rebalance g%d: %d/%d targets done, running %v, sent %d objects (%s), received %d objects (%s)",
+		ext.GlobalRebID, finished, running+finished, time.Since(start).Round(time.Second),
+		ext.TxRebCount, cmn.B2S(ext.TxRebSize, 1), ext.RxRebCount, cmn.B2S(ext.RxRebSize, 1))
+}
+
+func topAlerts(snap *topSnapshot, rows []*topRow) (alerts []string) {
+	for _, r := range rows {
+		if r.err != nil {
+			alerts = append(alerts, fmt.Sprintf("%s is unreachable: %v", r.id, r.err))
+			continue
+		}
+		if r.capPct >= topAlertPct {
+			alerts = append(alerts, fmt.Sprintf("%s: capacity %.0f%% used", r.id, r.capPct))
+		}
+		if r.cpu >= topAlertPct {
+			alerts = append(alerts, fmt.Sprintf("%s: CPU %.0f%% used", r.id, r.cpu))
+		}
+		if r.mem >= topAlertPct {
+			alerts = append(alerts, fmt.Sprintf("%s: memory %.0f%% used", r.id, r.mem))
+		}
+		if r.errDelta > 0 {
+			alerts = append(alerts, fmt.Sprintf("%s: %d new errors", r.id, r.errDelta))
+		}
+		if status := snap.status[r.id]; status.SmapVersion != snap.smap.Version {
+			alerts = append(alerts, fmt.Sprintf("%s: Smap v%d differs from the primary's v%d",
+				r.id, status.SmapVersion, snap.smap.Version))
+		}
+		disks := make([]string, 0, len(snap.disks[r.id]))
+		for disk := range snap.disks[r.id] {
+			disks = append(disks, disk)
+		}
+		sort.Strings(disks)
+		for _, disk := range disks {
+			if util := snap.disks[r.id][disk].Util; util >= topAlertPct {
+				alerts = append(alerts, fmt.Sprintf("%s: disk %s %d%% utilized", r.id, disk, util))
+			}
+		}
+	}
+	nodeAlerts := len(alerts)
+	for daemonID, daemonStats := range snap.xactions {
+		for _, st := range daemonStats {
+			if st.Aborted() && time.Since(st.EndTime()) < topAlertXactWindow {
+				alerts = append(alerts, fmt.Sprintf("%s: %s[%s] aborted %v ago",
+					daemonID, st.Kind(), st.ID(), time.Since(st.EndTime()).Round(time.Second)))
+			}
+		}
+	}
+	sort.Strings(alerts[nodeAlerts:])
+	if snap.xactErr != nil {
+		alerts = append(alerts, fmt.Sprintf("failed to get xactions: %v", snap.xactErr))
+	}
+	return
+}
+
+// render returns the escape sequences and text to redraw the whole screen.
+func (v *topView) render(width, height int) string {
+	var lines []string
+	addf := func(format string, a ...interface{}) { lines = append(lines, fmt.Sprintf(format, a...)) }
+
+	switch {
+	case v.err != nil && v.cur == nil:
+		addf("ais top - %s", time.Now().Format("15:04:05"))
+		addf("")
+		addf("Error: %v", v.err)
+	case v.cur == nil:
+		addf("ais top - loading...")
+	default:
+		snap := v.cur
+		addf("ais top - %s  Smap v%d  proxies: %d  targets: %d  primary: %s",
+			snap.time.Format("15:04:05"), snap.smap.Version, snap.smap.CountProxies(),
+			snap.smap.CountTargets(), snap.smap.ProxySI.ID())
+		if v.err != nil {
+			addf("Error: %v (showing the last successful refresh)", v.err)
+		}
+
+		var total topRow
+		total.hasRates = v.prev != nil
+		for _, r := range v.rows {
+			if r.target {
+				total.getRate += r.getRate
+				total.putRate += r.putRate
+				total.putBps += r.putBps
+				total.diskRead += r.diskRead
+				total.diskWrite += r.diskWrite
+			}
+			total.errRate += r.errRate
+		}
+		addf("Cluster: GET %s/s  PUT %s/s (%s)  disk read %s  disk write %s  errors %s/s",
+			topRate(&total, total.getRate), topRate(&total, total.putRate), topSpeed(&total, int64(total.putBps)),
+			cmn.B2S(total.diskRead, 1)+"/s", cmn.B2S(total.diskWrite, 1)+"/s", topRate(&total, total.errRate))
+		if reb := topRebalance(snap); reb != "" {
+			addf("%s", reb)
+		}
+		addf("")
+
+		// node table; the sort column is highlighted
+		header := make([]string, 0, len(topColumns))
+		for i, col := range topColumns {
+			name := col.name
+			if i == v.sortCol {
+				if v.reverse {
+					name += "v"
+				} else {
+					name += "^"
+				}
+				header = append(header, "\x1b[7m"+topPad(name, col.width)+"\x1b[0m")
+				continue
+			}
+			header = append(header, topPad(name, col.width))
+		}
+		addf("%s", strings.Join(header, " "))
+		for _, r := range v.rows {
+			values := make([]string, 0, len(topColumns))
+			for _, col := range topColumns {
+				values = append(values, topPad(col.value(r), col.width))
+			}
+			addf("%s", strings.Join(values, " "))
+		}
+
+		if xacts := topXactions(snap); len(xacts) > 0 {
+			addf("")
+			addf("%-22s %-16s %-20s %-8s %-12s %-10s %s", "XACTION", "KIND", "BUCKET", "TARGETS", "OBJECTS", "SIZE", "RUNNING")
+			for _, x := range xacts {
+				addf("%-22s %-16s %-20s %-8d %-12d %-10s %v", x.id, x.kind, x.bck, x.running, x.objCount,
+					cmn.B2S(x.bytesCnt, 1), time.Since(x.start).Round(time.Second))
+			}
+		}
+
+		if alerts := topAlerts(snap, v.rows); len(alerts) > 0 {
+			addf("")
+			addf("ALERTS")
+			for _, alert := range alerts {
+				addf("\x1b[31m%s\x1b[0m", alert)
+			}
+		}
+	}
+
+	// the last line is reserved for the help
+	if len(lines) > height-1 {
+		lines = lines[:cmn.Max(height-1, 0)]
+	}
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, "q: quit  </>: sort column  r: reverse order")
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("\x1b[H")
+	for i, line := range lines {
+		buf.WriteString(topTruncate(line, width))
+		buf.WriteString("\x1b[K")
+		if i < len(lines)-1 {
+			buf.WriteString("\r\n")
+		}
+	}
+	return buf.String()
+}
+
+func topRate(r *topRow, rate float64) string {
+	if r.err != nil || !r.hasRates {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", rate)
+}
+
+func topSpeed(r *topRow, bps int64) string {
+	if r.err != nil || !r.hasRates {
+		return "-"
+	}
+	return cmn.B2S(bps, 1) + "/s"
+}
+
+func topTargetSpeed(r *topRow, bps int64) string {
+	if r.err != nil || !r.target {
+		return "-"
+	}
+	return cmn.B2S(bps, 1) + "/s"
+}
+
+func topPct(r *topRow, pct float64) string {
+	if r.err != nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f", pct)
+}
+
+func topTargetPct(r *topRow, pct float64) string {
+	if !r.target {
+		return "-"
+	}
+	return topPct(r, pct)
+}
+
+func topLatency(us int64) string {
+	if us == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2fms", float64(us)/1000)
+}
+
+func topPad(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
+
+// topTruncate cuts the line to the screen width not counting the escape
+// sequences (the lines are ASCII otherwise).
+func topTruncate(line string, width int) string {
+	var (
+		visible int
+		inEsc   bool
+	)
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == 0x1b:
+			inEsc = true
+		case inEsc:
+			if line[i] >= '@' && line[i] <= '~' && line[i] != '[' {
+				inEsc = false
+			}
+		default:
+			if visible == width {
+				return line[:i] + "\x1b[0m"
+			}
+			visible++
+		}
+	}
+	return line
+}
@@ -0,0 +1,91 @@
+// Package commands provides the set of CLI commands used to communicate with the AIS cluster.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package commands
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/stats"
+	jsoniter "github.com/json-iterator/go"
+)
+
+func topTestSnapshot(t *testing.T, now time.Time, getCounts map[string]int64) *topSnapshot {
+	snap := &topSnapshot{
+		time:   now,
+		smap:   &cluster.Smap{Pmap: cluster.NodeMap{}, Tmap: cluster.NodeMap{}},
+		status: make(map[string]*stats.DaemonStatus),
+		errs:   make(map[string]error),
+	}
+	for id, cnt := range getCounts {
+		snap.smap.Tmap[id] = &cluster.Snode{DaemonID: id}
+		status := &stats.DaemonStatus{}
+		body := fmt.Sprintf(`{"daemon_stats": {"get.n": %d, "get.µs.p99": 1500}}`, cnt)
+		if err := jsoniter.Unmarshal([]byte(body), status); err != nil {
+			t.Fatal(err)
+		}
+		snap.status[id] = status
+	}
+	return snap
+}
+
+func TestTopRows(t *testing.T) {
+	var (
+		now  = time.Now()
+		prev = topTestSnapshot(t, now, map[string]int64{"t1": 100, "t2": 100, "t3": 100})
+		cur  = topTestSnapshot(t, now.Add(2*time.Second), map[string]int64{"t1": 120, "t2": 300, "t3": 100})
+	)
+	rows := topRows(prev, cur)
+	sortTopRows(rows, 0 /* NODE */, false)
+	rates := make([]float64, 0, len(rows))
+	for _, r := range rows {
+		if !r.hasRates || r.getP99 != 1500 {
+			t.Fatalf("unexpected row %+v", r)
+		}
+		rates = append(rates, r.getRate)
+	}
+	if expected := []float64{10, 100, 0}; !reflect.DeepEqual(rates, expected) {
+		t.Errorf("expected GET rates %v, got %v", expected, rates)
+	}
+
+	for i, col := range topColumns {
+		if col.name == "GET/s" {
+			sortTopRows(rows, i, true)
+		}
+	}
+	ids := make([]string, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.id)
+	}
+	if expected := []string{"t2", "t1", "t3"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected order %v, got %v", expected, ids)
+	}
+
+	if rows := topRows(nil, cur); rows[0].hasRates {
+		t.Error("expected no rates without the previous snapshot")
+	}
+}
+
+func TestParseTopKeys(t *testing.T) {
+	tests := []struct {
+		in   string
+		keys []int
+	}{
+		{"q", []int{topKeyQuit}},
+		{"\x03", []int{topKeyQuit}},
+		{"><r", []int{topKeyNext, topKeyPrev, topKeyReverse}},
+		{"\x1b[C\x1b[D", []int{topKeyNext, topKeyPrev}},
+		{"\x1b[A", nil},
+		{"\x1b", []int{topKeyQuit}},
+	}
+	for _, test := range tests {
+		if keys := parseTopKeys([]byte(test.in)); !reflect.DeepEqual(keys, test.keys) {
+			t.Errorf("%q: expected %v, got %v", test.in, test.keys, keys)
+		}
+	}
+}
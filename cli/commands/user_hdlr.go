@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cli/templates"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/jsp"
 	"github.com/urfave/cli"
@@ -30,9 +31,11 @@ const (
 
 	// TODO: fix and move AuthN subcommands to commands/common.go
 	subcmdAuthAdd    = "add"
+	subcmdAuthUpdate = "update"
 	subcmdAuthRemove = commandRemove
 	subcmdAuthLogin  = "login"
 	subcmdAuthLogout = "logout"
+	subcmdAuthKey    = "key"
 )
 
 var (
@@ -45,8 +48,16 @@ var (
 					Name:      subcmdAuthAdd,
 					Usage:     "add a new user",
 					ArgsUsage: addUserArgument,
+					Flags:     []cli.Flag{roleFlag, serviceFlag},
 					Action:    addUserHandler,
 				},
+				{
+					Name:      subcmdAuthUpdate,
+					Usage:     "replace roles of an existing user",
+					ArgsUsage: updateUserArgument,
+					Flags:     []cli.Flag{roleFlag},
+					Action:    updateUserHandler,
+				},
 				{
 					Name:      subcmdAuthRemove,
 					Usage:     "remove an existing user",
@@ -64,6 +75,32 @@ var (
 					Usage:  "log out",
 					Action: logoutUserHandler,
 				},
+				{
+					Name:  subcmdAuthKey,
+					Usage: "manage API keys of service accounts",
+					Subcommands: []cli.Command{
+						{
+							Name:      commandCreate,
+							Usage:     "issue a new API key for a service account",
+							ArgsUsage: createKeyArgument,
+							Flags:     []cli.Flag{expireFlag},
+							Action:    createKeyHandler,
+						},
+						{
+							Name:      commandList,
+							Usage:     "list API keys",
+							ArgsUsage: listKeysArgument,
+							Flags:     []cli.Flag{jsonFlag},
+							Action:    listKeysHandler,
+						},
+						{
+							Name:      commandRemove,
+							Usage:     "revoke an API key",
+							ArgsUsage: removeKeyArgument,
+							Action:    removeKeyHandler,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -134,11 +171,34 @@ func addUserHandler(c *cli.Context) (err error) {
 		AdminName:     cliAuthnAdminName(c),
 		AdminPassword: cliAuthnAdminPassword(c),
 		UserName:      cliAuthnUserName(c),
-		UserPassword:  cliAuthnUserPassword(c),
+		Roles:         c.StringSlice(roleFlag.Name),
+		Service:       flagIsSet(c, serviceFlag),
+	}
+	if !spec.Service {
+		spec.UserPassword = cliAuthnUserPassword(c)
 	}
 	return api.AddUser(baseParams, spec)
 }
 
+func updateUserHandler(c *cli.Context) (err error) {
+	authnURL := cliAuthnURL()
+	if authnURL == "" {
+		return fmt.Errorf("AuthN URL is not set")
+	}
+	if !flagIsSet(c, roleFlag) {
+		return missingArgumentsError(c, "--"+roleFlag.Name)
+	}
+	baseParams := cliAPIParams(authnURL)
+	baseParams.Token = "" // the request requires superuser credentials, not user's ones
+	spec := api.AuthnSpec{
+		AdminName:     cliAuthnAdminName(c),
+		AdminPassword: cliAuthnAdminPassword(c),
+		UserName:      cliAuthnUserName(c),
+		Roles:         c.StringSlice(roleFlag.Name),
+	}
+	return api.UpdateUser(baseParams, spec)
+}
+
 func deleteUserHandler(c *cli.Context) (err error) {
 	authnURL := cliAuthnURL()
 	if authnURL == "" {
@@ -202,3 +262,60 @@ func logoutUserHandler(c *cli.Context) (err error) {
 	}
 	return nil
 }
+
+func cliAuthnAdminSpec(c *cli.Context) (baseParams api.BaseParams, spec api.AuthnSpec, err error) {
+	authnURL := cliAuthnURL()
+	if authnURL == "" {
+		return baseParams, spec, fmt.Errorf("AuthN URL is not set")
+	}
+	baseParams = cliAPIParams(authnURL)
+	baseParams.Token = "" // the request requires superuser credentials, not user's ones
+	spec = api.AuthnSpec{
+		AdminName:     cliAuthnAdminName(c),
+		AdminPassword: cliAuthnAdminPassword(c),
+	}
+	return baseParams, spec, nil
+}
+
+func createKeyHandler(c *cli.Context) (err error) {
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "service account")
+	}
+	baseParams, spec, err := cliAuthnAdminSpec(c)
+	if err != nil {
+		return err
+	}
+	msg := &cmn.AuthAPIKeyMsg{Account: c.Args().Get(0), Name: c.Args().Get(1)}
+	if flagIsSet(c, expireFlag) {
+		msg.ExpiresIn = c.Duration(expireFlag.Name).String()
+	}
+	key, err := api.CreateAPIKey(baseParams, spec, msg)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.Writer, "API key %s created; the token is shown only once:\n%s\n", key.ID, key.Token)
+	return nil
+}
+
+func listKeysHandler(c *cli.Context) (err error) {
+	baseParams, spec, err := cliAuthnAdminSpec(c)
+	if err != nil {
+		return err
+	}
+	keys, err := api.ListAPIKeys(baseParams, spec, c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	return templates.DisplayOutput(keys, c.App.Writer, templates.APIKeyListTmpl, flagIsSet(c, jsonFlag))
+}
+
+func removeKeyHandler(c *cli.Context) (err error) {
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "API key ID")
+	}
+	baseParams, spec, err := cliAuthnAdminSpec(c)
+	if err != nil {
+		return err
+	}
+	return api.RevokeAPIKey(baseParams, spec, c.Args().Get(0))
+}
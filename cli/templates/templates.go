@@ -199,6 +199,8 @@ const (
 		" Certificate:\t{{$obj.HTTP.Certificate}}\n" +
 		" Key:\t{{$obj.HTTP.Key}}\n" +
 		" UseHTTPS:\t{{$obj.HTTP.UseHTTPS}}\n" +
+		" CA Certificate:\t{{$obj.HTTP.CACertificate}}\n" +
+		" UseIntraTLS:\t{{$obj.HTTP.UseIntraTLS}}\n" +
 		" Chunked Transfer:\t{{$obj.HTTP.Chunked}}\n\n" +
 		" L4\n" +
 		" Protocol:\t{{$obj.L4.Proto}}\n" +
@@ -275,6 +277,10 @@ const (
 		"{{end}}\t {{$value.NumErrors}}\t {{$value.Description}}\n"
 	DownloadListTmpl = DownloadListHeader + "{{ range $key, $value := . }}" + DownloadListBody + "{{end}}"
 
+	APIKeyListTmpl = "ID\t Name\t Account\t Created\t Expires\n" +
+		"{{ range $key := . }}{{$key.ID}}\t {{$key.Name}}\t {{$key.Account}}\t {{FormatTime $key.Created}}\t " +
+		"{{if (IsUnsetTime $key.Expires)}}never{{else}}{{FormatTime $key.Expires}}{{end}}\n{{end}}"
+
 	DSortListHeader = "JobID\t Status\t Start\t Finish\t Description\n"
 	DSortListBody   = "{{$value.ID}}\t " +
 		"{{if (eq $value.Aborted true) }}Aborted" +
@@ -317,6 +323,15 @@ const (
 	BucketsSummariesFastTmpl = "Name\tEst.Objects\tEst.Size\tEst.Used(%)\tProvider\n" + bucketsSummariesBody
 	BucketsSummariesTmpl     = "Name\tObjects\tSize\tUsed(%)\tProvider\n" + bucketsSummariesBody
 
+	BucketStatsTmpl = "Name\tGET\tPUT\tAPPEND\tDELETE\tCold GET\tErrors\tIn\tOut\t" +
+		"GET p50\tGET p99\tPUT p50\tPUT p99\n" +
+		"{{range $k, $v := . }}" +
+		"{{$k}}\t{{$v.GetCount}}\t{{$v.PutCount}}\t{{$v.AppendCount}}\t{{$v.DeleteCount}}\t{{$v.GetColdCount}}\t{{$v.ErrCount}}\t" +
+		"{{FormatBytesSigned $v.InSize 2}}\t{{FormatBytesSigned $v.OutSize 2}}\t" +
+		"{{FormatPercentile $v.GetLatency 0.5}}\t{{FormatPercentile $v.GetLatency 0.99}}\t" +
+		"{{FormatPercentile $v.PutLatency 0.5}}\t{{FormatPercentile $v.PutLatency 0.99}}\n" +
+		"{{end}}"
+
 	// For `object put` mass uploader. A caller adds to the template
 	// total count and size. That is why the template ends with \t
 	ExtensionTmpl = "Files to upload:\nExtension\tCount\tSize\n" +
@@ -368,6 +383,7 @@ var (
 		"FormatObjIsCached":   fmtObjIsCached,
 		"FormatDaemonID":      fmtDaemonID,
 		"FormatFloat":         func(f float64) string { return fmt.Sprintf("%.2f", f) },
+		"FormatPercentile":    fmtPercentile,
 	}
 )
 
@@ -500,6 +516,23 @@ func fmtDuration(d int64) string {
 	return dNano.Round(time.Second).String()
 }
 
+// latency percentile rounded to 3-4 significant digits
+func fmtPercentile(h *stats.Histogram, p float64) string {
+	if h == nil || h.Count == 0 {
+		return "-"
+	}
+	d := h.Percentile(p)
+	switch {
+	case d >= time.Second:
+		d = d.Round(10 * time.Millisecond)
+	case d >= time.Millisecond:
+		d = d.Round(10 * time.Microsecond)
+	case d >= time.Microsecond:
+		d = d.Round(10 * time.Nanosecond)
+	}
+	return d.String()
+}
+
 func fmtDaemonID(id string, smap cluster.Smap) string {
 	if id == smap.ProxySI.ID() {
 		return id + primarySuffix
@@ -34,6 +34,10 @@ func HrwTarget(uname string, smap *Smap) (si *Snode, err error) {
 		digest = xxhash.ChecksumString64S(uname, cmn.MLCG32)
 	)
 	for _, sinfo := range smap.Tmap {
+		// targets being decommissioned do not own objects
+		if len(smap.Leaving) > 0 && smap.IsLeaving(sinfo.ID()) {
+			continue
+		}
 		// Assumes that sinfo.idDigest is initialized
 		cs := xoshiro256.Hash(sinfo.idDigest ^ digest)
 		if cs >= max {
@@ -50,13 +50,14 @@ type (
 	NodeMap map[string]*Snode // map of Snodes: DaemonID => Snodes
 
 	Smap struct {
-		Tmap         NodeMap       `json:"tmap"`           // daemonID -> Snode
-		Pmap         NodeMap       `json:"pmap"`           // proxyID -> proxyInfo
-		NonElects    cmn.SimpleKVs `json:"non_electable"`  // non-electable proxies: DaemonID => [info]
-		ProxySI      *Snode        `json:"proxy_si"`       // primary
-		Version      int64         `json:"version,string"` // version
-		UUID         string        `json:"uuid"`           // UUID - assigned at creation time
-		CreationTime string        `json:"creation_time"`  // creation time
+		Tmap         NodeMap       `json:"tmap"`              // daemonID -> Snode
+		Pmap         NodeMap       `json:"pmap"`              // proxyID -> proxyInfo
+		NonElects    cmn.SimpleKVs `json:"non_electable"`     // non-electable proxies: DaemonID => [info]
+		Leaving      cmn.SimpleKVs `json:"leaving,omitempty"` // targets being decommissioned: DaemonID => ""
+		ProxySI      *Snode        `json:"proxy_si"`          // primary
+		Version      int64         `json:"version,string"`    // version
+		UUID         string        `json:"uuid"`              // UUID - assigned at creation time
+		CreationTime string        `json:"creation_time"`     // creation time
 	}
 )
 
@@ -186,6 +187,13 @@ func (m *Smap) GetTarget(sid string) *Snode {
 	return si
 }
 
+// IsLeaving returns true if the target is being decommissioned: it remains
+// in the map (and serves the objects it still has) but does not own any.
+func (m *Smap) IsLeaving(sid string) bool {
+	_, ok := m.Leaving[sid]
+	return ok
+}
+
 func (m *Smap) GetRandTarget() (si *Snode, err error) {
 	if m.CountTargets() == 0 {
 		return nil, ErrNoTargets
@@ -247,6 +255,10 @@ func (a *Smap) Compare(b *Smap) (sameOrigin, sameVersion, eq bool) {
 		eq = false
 		return
 	}
+	if len(a.Leaving) != len(b.Leaving) || (len(a.Leaving) > 0 && !reflect.DeepEqual(a.Leaving, b.Leaving)) {
+		eq = false
+		return
+	}
 	eq = mapsEq(a.Tmap, b.Tmap) && mapsEq(a.Pmap, b.Pmap)
 	return
 }
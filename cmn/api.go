@@ -47,6 +47,8 @@ var XactType = XactKindType{
 	ActPrefetch:  XactTypeGlobal,
 	ActDownload:  XactTypeGlobal,
 
+	ActDecommission: XactTypeGlobal,
+
 	// bucket's kinds
 	ActECGet:        XactTypeBck,
 	ActECPut:        XactTypeBck,
@@ -107,6 +109,78 @@ type MountpathList struct {
 	Disabled  []string `json:"disabled"`
 }
 
+// RebPlanMsg describes a hypothetical cluster map change (targets joining
+// and/or leaving) to be evaluated by the rebalance dry-run
+type RebPlanMsg struct {
+	Add    []string `json:"add,omitempty"`    // IDs of the targets to join the cluster
+	Remove []string `json:"remove,omitempty"` // IDs of the existing targets to leave the cluster
+}
+
+// RebPlanStats is the number of objects and bytes that would migrate
+type RebPlanStats struct {
+	Objects int64 `json:"objects,string"`
+	Bytes   int64 `json:"bytes,string"`
+}
+
+// RebPlan is a single target's share of the rebalance dry-run: objects
+// that this target would send away, grouped by bucket and by destination
+type RebPlan struct {
+	Total   RebPlanStats             `json:"total"`
+	Buckets map[string]*RebPlanStats `json:"buckets"` // bucket => outgoing
+	Targets map[string]*RebPlanStats `json:"targets"` // destination target ID => outgoing
+}
+
+// ClusterRebPlan is the rebalance dry-run for the entire cluster
+type ClusterRebPlan map[string]*RebPlan // source target ID => plan
+
+func NewRebPlan() *RebPlan {
+	return &RebPlan{
+		Buckets: make(map[string]*RebPlanStats),
+		Targets: make(map[string]*RebPlanStats),
+	}
+}
+
+// Add accounts for a single object of a given size that would be moved
+// to target `tid`
+func (p *RebPlan) Add(bck, tid string, size int64) {
+	p.Total.add(1, size)
+	p.stats(p.Buckets, bck).add(1, size)
+	p.stats(p.Targets, tid).add(1, size)
+}
+
+func (p *RebPlan) Merge(other *RebPlan) {
+	p.Total.add(other.Total.Objects, other.Total.Bytes)
+	for bck, st := range other.Buckets {
+		p.stats(p.Buckets, bck).add(st.Objects, st.Bytes)
+	}
+	for tid, st := range other.Targets {
+		p.stats(p.Targets, tid).add(st.Objects, st.Bytes)
+	}
+}
+
+func (p *RebPlan) stats(m map[string]*RebPlanStats, key string) *RebPlanStats {
+	st, ok := m[key]
+	if !ok {
+		st = &RebPlanStats{}
+		m[key] = st
+	}
+	return st
+}
+
+func (st *RebPlanStats) add(objects, bytes int64) {
+	st.Objects += objects
+	st.Bytes += bytes
+}
+
+// Total sums up the plans of all targets
+func (cp ClusterRebPlan) Total() *RebPlan {
+	total := NewRebPlan()
+	for _, plan := range cp {
+		total.Merge(plan)
+	}
+	return total
+}
+
 type XactionExtMsg struct {
 	Target string `json:"target,omitempty"`
 	Bck    Bck    `json:"bck"`
@@ -61,8 +61,11 @@ const (
 	ActRegProxy      = "regproxy"
 	ActUnregTarget   = "unregtarget"
 	ActUnregProxy    = "unregproxy"
+	ActDecommission  = "decommission" // evacuate target's data and only then unregister it
+	ActDecommCancel  = "decommcancel" // cancel decommission (the target stays in the cluster)
 	ActNewPrimary    = "newprimary"
 	ActRevokeToken   = "revoketoken"
+	ActUpdateAuthNDB = "updateauthndb"
 	ActElection      = "election"
 	ActPutCopies     = "putcopies"
 	ActMakeNCopies   = "makencopies"
@@ -104,7 +107,7 @@ const (
 const (
 	HeaderCloudProvider = "cloud_provider" // ProviderAmazon et al. - see cmn/bucket.go
 	HeaderCloudOffline  = "cloud.offline"  // when accessing cached cloud bucket with no Cloud connectivity
-	HeaderGuestAccess   = "guest"          // AuthN is enabled and proxy gets a request without token
+	HeaderCloudNs       = "cloud.ns"       // namespace of cloud bucket (e.g., UUID of remote AIS cluster)
 
 	// bucket props
 	HeaderBucketVerEnabled      = "versioning.enabled"           // Enable/disable object versioning in a bucket
@@ -127,6 +130,7 @@ const (
 	HeaderCallerID          = "caller.id"
 	HeaderCallerName        = "caller.name"
 	HeaderCallerSmapVersion = "caller.smap.ver"
+	HeaderCallerSig         = "caller.sig" // signs intra-cluster requests (see ais/intracall.go)
 
 	HeaderNodeID  = "node.id"
 	HeaderNodeURL = "node.url"
@@ -134,14 +138,20 @@ const (
 	// custom
 	HeaderAppendHandle = "append.handle"
 
+	// distributed tracing: W3C trace context (https://www.w3.org/TR/trace-context)
+	HeaderTraceparent = "traceparent"
+
 	// intra-cluster: streams
 	HeaderSessID   = "session.id"
-	HeaderCompress = "compress" // LZ4Compression, etc.
+	HeaderCompress = "compress"  // LZ4Compression, ZstdCompression
+	HeaderStreamID = "stream.id" // reliable streams: sender's stream ID that persists across sessions
+	HeaderSeqAck   = "seq.ack"   // reliable streams: receiver's cumulative acknowledgment (sequence number)
 )
 
 // supported compressions (alg-s)
 const (
-	LZ4Compression = "lz4"
+	LZ4Compression  = "lz4"
+	ZstdCompression = "zstd"
 )
 
 // URL Query "?name1=val1&name2=..."
@@ -172,10 +182,18 @@ const (
 	URLParamTaskID           = "tsk" // ID of a task to return its state/result
 	URLParamTaskAction       = "tac" // "start", "status", "result"
 	URLParamECMeta           = "ecm" // true: EC metadata request
+	URLParamTraceparent      = "trp" // trace context of the redirecting proxy (see HeaderTraceparent)
 
 	URLParamAppendType   = "appendty"
 	URLParamAppendHandle = "handle"
 
+	// audit log query
+	URLParamSince  = "since"  // RFC3339 time
+	URLParamUntil  = "until"  // ditto
+	URLParamUser   = "user"   // AuthN user ID
+	URLParamAction = "action" // cmn.ActionMsg.Action
+	URLParamLimit  = "limit"  // max number of (most recent) records
+
 	// dsort
 	URLParamTotalCompressedSize       = "tcs"
 	URLParamTotalInputShardsExtracted = "tise"
@@ -213,6 +231,9 @@ const (
 	GetWhatSysInfo      = "sysinfo"
 	GetWhatDiskStats    = "disk"
 	GetWhatDaemonStatus = "status"
+	GetWhatRebPlan      = "rebplan"
+	GetWhatBucketStats  = "bucketstats"
+	GetWhatAudit        = "audit"
 )
 
 // SelectMsg.TimeFormat enum
@@ -270,7 +291,10 @@ const (
 	Reverse   = "reverse"
 	Rebalance = "rebalance"
 	// l2 AuthN
-	Users = "users"
+	Users   = "users"
+	Roles   = "roles"
+	APIKeys = "apikeys"
+	AuthNDB = "authndb" // AuthN database replicated by the cluster
 
 	// l3
 	SyncSmap     = "syncsmap"
@@ -324,12 +348,15 @@ const (
 	CompressAlways = "always"
 	CompressNever  = "never"
 	CompressRatio  = "ratio=%d" // adaptive: min ratio that warrants compression
+	CompressZstd   = "zstd"     // always, with zstd instead of (default) lz4: higher ratio at the cost of CPU
 )
 
 // AuthN consts
 const (
 	HeaderAuthorization = "Authorization"
 	HeaderBearer        = "Bearer"
+	HeaderETag          = "ETag"          // AuthN database version
+	HeaderIfNoneMatch   = "If-None-Match" // to get AuthN database only if it has changed
 )
 
 // timeouts for intra-cluster requests
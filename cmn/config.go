@@ -225,6 +225,9 @@ var (
 	_ Validator = &FSPathsConf{}
 	_ Validator = &TestfspathConf{}
 	_ Validator = &CompressionConf{}
+	_ Validator = &TracingConf{}
+	_ Validator = &AuditConf{}
+	_ Validator = &AuthConf{}
 
 	_ PropsValidator = &CksumConf{}
 	_ PropsValidator = &LRUConf{}
@@ -269,6 +272,8 @@ type Config struct {
 	Downloader       DownloaderConf  `json:"downloader"`
 	DSort            DSortConf       `json:"distributed_sort"`
 	Compression      CompressionConf `json:"compression"`
+	Tracing          TracingConf     `json:"tracing"`
+	Audit            AuditConf       `json:"audit"`
 }
 
 type CloudConf struct {
@@ -484,7 +489,10 @@ type HTTPConf struct {
 	WriteBufferSize int    `json:"write_buffer_size"`  // http.Transport.WriteBufferSize; if zero, a default (currently 4KB) is used
 	ReadBufferSize  int    `json:"read_buffer_size"`   // http.Transport.ReadBufferSize; if zero, a default (currently 4KB) is used
 	RevProxyCache   bool   `json:"rproxy_cache"`       // RevProxy caches or work as transparent proxy
+	CACertificate   string `json:"ca_certificate"`     // intra-cluster mutual TLS: CA that signs all node certificates
+	IntraProto      string `json:"-"`                  // http or https (runtime; intra-control and intra-data)
 	UseHTTPS        bool   `json:"use_https"`          // use HTTPS instead of HTTP
+	UseIntraTLS     bool   `json:"use_intra_tls"`      // mutual TLS for intra-control and intra-data networks
 	Chunked         bool   `json:"chunked_transfer"`   // https://tools.ietf.org/html/rfc7230#page-36
 }
 
@@ -495,10 +503,26 @@ type FSHCConf struct {
 }
 
 type AuthConf struct {
-	Secret     string `json:"secret"`
-	CredDir    string `json:"creddir"`
-	Enabled    bool   `json:"enabled"`
-	AllowGuest bool   `json:"allow_guest"`
+	Secret     string   `json:"secret"`
+	CredDir    string   `json:"creddir"`
+	Enabled    bool     `json:"enabled"`
+	AllowGuest bool     `json:"allow_guest"`
+	OIDC       OIDCConf `json:"oidc"`
+}
+
+// OIDCConf configures validation of ID tokens issued by an external OpenID
+// Connect provider; the token's groups map to AIS roles and permissions
+type OIDCConf struct {
+	Issuer         string                 `json:"issuer"`       // empty: OIDC is disabled
+	ClientID       string                 `json:"client_id"`    // expected audience of ID tokens
+	JWKSURL        string                 `json:"jwks_url"`     // empty: discover via <issuer>/.well-known/openid-configuration
+	UserClaim      string                 `json:"user_claim"`   // claim that identifies user ("sub" by default)
+	GroupsClaim    string                 `json:"groups_claim"` // claim that lists user's groups ("groups" by default)
+	DefaultRoles   []string               `json:"default_roles"`
+	GroupRoles     map[string][]string    `json:"group_roles"`  // group => predefined roles
+	GroupGrants    map[string][]AuthGrant `json:"group_grants"` // group => grants
+	JWKSRefreshStr string                 `json:"jwks_refresh"` // how often to refetch the issuer's keys
+	JWKSRefresh    time.Duration          `json:"-"`            // (runtime)
 }
 
 // config for one keepalive tracker
@@ -540,8 +564,31 @@ type FSPathsConf struct {
 
 // lz4 block and frame formats: http://fastcompression.blogspot.com/2013/04/lz4-streaming-format-final.html
 type CompressionConf struct {
-	BlockMaxSize int  `json:"block_size"` // *uncompressed* block max size
-	Checksum     bool `json:"checksum"`   // true: checksum lz4 frames
+	BlockMaxSize int  `json:"block_size"` // *uncompressed* block max size (lz4)
+	Checksum     bool `json:"checksum"`   // true: checksum lz4 frames (zstd: frame CRC)
+}
+
+// tracing exporters
+const (
+	TracingExporterOTLP = "otlp" // OTLP/HTTP (JSON encoding) collector
+	TracingExporterFile = "file" // local file, one OTLP/JSON export request per line
+
+	TracingDefaultOTLPEndpoint = "http://localhost:4318/v1/traces"
+)
+
+type TracingConf struct {
+	Exporter    string  `json:"exporter"`     // one of the tracing exporters (above)
+	Endpoint    string  `json:"endpoint"`     // collector URL or file name, depending on the exporter
+	SampleRatio float64 `json:"sample_ratio"` // fraction of the (root) requests to trace
+	Enabled     bool    `json:"enabled"`
+}
+
+type AuditConf struct {
+	Dir      string `json:"dir"`       // audit log directory (default: log.dir)
+	MaxSize  int64  `json:"max_size"`  // size that triggers audit log rotation
+	MaxFiles int    `json:"max_files"` // max number of rotated audit logs to keep
+	Targets  bool   `json:"targets"`   // true: targets log the (client) requests they execute as well
+	Enabled  bool   `json:"enabled"`
 }
 
 //==============================
@@ -574,7 +621,8 @@ func (c *Config) Validate() error {
 		&c.Cloud,
 		&c.Disk, &c.LRU, &c.Mirror, &c.Cksum, &c.Versioning,
 		&c.Timeout, &c.Periodic, &c.Rebalance, &c.KeepaliveTracker, &c.Net,
-		&c.Downloader, &c.DSort, &c.TestFSP, &c.FSpaths, &c.Compression,
+		&c.Downloader, &c.DSort, &c.TestFSP, &c.FSpaths, &c.Compression, &c.Tracing,
+		&c.Audit, &c.Auth,
 	}
 	for _, validator := range validators {
 		if err := validator.Validate(c); err != nil {
@@ -920,6 +968,11 @@ func (c *NetConf) Validate(_ *Config) (err error) {
 				c.HTTP.RevProxy, RevProxyCloud, RevProxyTarget)
 		}
 	}
+	if c.HTTP.UseIntraTLS {
+		if c.HTTP.Certificate == "" || c.HTTP.Key == "" || c.HTTP.CACertificate == "" {
+			return fmt.Errorf("intra-cluster TLS requires server_certificate, server_key, and ca_certificate")
+		}
+	}
 	if !c.HTTP.Chunked {
 		glog.Warningln("disabled chunked transfer may cause a slow down (see also: Content-Length)")
 	}
@@ -1058,6 +1111,84 @@ func (c *CompressionConf) Validate(_ *Config) (err error) {
 	return nil
 }
 
+func (c *TracingConf) Validate(_ *Config) (err error) {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.Exporter {
+	case TracingExporterOTLP:
+		if c.Endpoint == "" {
+			c.Endpoint = TracingDefaultOTLPEndpoint
+		}
+	case TracingExporterFile:
+		if c.Endpoint == "" {
+			return fmt.Errorf("tracing.endpoint (file name) must be specified for the %q exporter", c.Exporter)
+		}
+	default:
+		return fmt.Errorf("invalid tracing.exporter %q (expecting %q or %q)",
+			c.Exporter, TracingExporterOTLP, TracingExporterFile)
+	}
+	if c.SampleRatio <= 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("invalid tracing.sample_ratio %v (expecting (0, 1])", c.SampleRatio)
+	}
+	return nil
+}
+
+func (c *AuthConf) Validate(_ *Config) (err error) {
+	oidc := &c.OIDC
+	if oidc.Issuer == "" {
+		return nil
+	}
+	if oidc.ClientID == "" {
+		return errors.New("auth.oidc.client_id is required")
+	}
+	if oidc.UserClaim == "" {
+		oidc.UserClaim = "sub"
+	}
+	if oidc.GroupsClaim == "" {
+		oidc.GroupsClaim = "groups"
+	}
+	if oidc.JWKSRefreshStr == "" {
+		oidc.JWKSRefreshStr = "1h"
+	}
+	if oidc.JWKSRefresh, err = time.ParseDuration(oidc.JWKSRefreshStr); err != nil || oidc.JWKSRefresh <= 0 {
+		return fmt.Errorf("invalid auth.oidc.jwks_refresh %q", oidc.JWKSRefreshStr)
+	}
+	roles := append([]string{}, oidc.DefaultRoles...)
+	for _, groupRoles := range oidc.GroupRoles {
+		roles = append(roles, groupRoles...)
+	}
+	for _, role := range roles {
+		if PredefinedRole(role) == nil {
+			return fmt.Errorf("auth.oidc: %q is not a predefined role", role)
+		}
+	}
+	for group, grants := range oidc.GroupGrants {
+		for i := range grants {
+			if err := grants[i].Validate(); err != nil {
+				return fmt.Errorf("auth.oidc: group %q: %v", group, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *AuditConf) Validate(config *Config) (err error) {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Dir == "" {
+		c.Dir = config.Log.Dir
+	}
+	if c.MaxSize <= 0 {
+		return fmt.Errorf("invalid audit.max_size %d (expecting positive)", c.MaxSize)
+	}
+	if c.MaxFiles < 0 {
+		return fmt.Errorf("invalid audit.max_files %d (expecting non-negative)", c.MaxFiles)
+	}
+	return nil
+}
+
 // setGLogVModule sets glog's vmodule flag
 // sets 'v' as is, no verificaton is done here
 // syntax for v: target=5,proxy=1, p*=3, etc
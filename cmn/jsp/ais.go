@@ -87,6 +87,18 @@ func _loadConfig(clivars *ConfigCLI) (config *cmn.Config, changed bool, err erro
 		config.Net.UseIntraData = true
 	}
 
+	// mutual TLS is enforced on the intra-cluster networks only, and so they must be separate
+	config.Net.HTTP.IntraProto = config.Net.HTTP.Proto
+	if config.Net.HTTP.UseIntraTLS {
+		if !config.Net.UseIntraControl || !config.Net.UseIntraData {
+			return nil, false, fmt.Errorf("intra-cluster TLS requires separate intra-control and intra-data networks")
+		}
+		if _, err := cmn.NewIntraTLSConfig(&config.Net.HTTP); err != nil {
+			return nil, false, err
+		}
+		config.Net.HTTP.IntraProto = "https"
+	}
+
 	// CLI override
 	if clivars.StatsTime != 0 {
 		config.Periodic.StatsTime = clivars.StatsTime
@@ -6,7 +6,10 @@ package cmn
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"strconv"
@@ -34,6 +37,7 @@ type (
 		WriteBufferSize  int
 		ReadBufferSize   int
 		UseHTTPS         bool
+		UseIntraTLS      bool // mutual TLS if enabled in the config (see NewIntraTLSConfig)
 		UseHTTPProxyEnv  bool
 	}
 )
@@ -85,6 +89,11 @@ func NewTransport(args TransportArgs) *http.Transport {
 	if args.UseHTTPS {
 		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	}
+	if conf := &GCO.Get().Net.HTTP; args.UseIntraTLS && conf.UseIntraTLS {
+		tlsConf, err := NewIntraTLSConfig(conf)
+		AssertNoErr(err) // validated at startup
+		transport.TLSClientConfig = tlsConf
+	}
 	if args.UseHTTPProxyEnv {
 		transport.Proxy = defaultTransport.Proxy
 	}
@@ -100,6 +109,63 @@ func NewClient(args TransportArgs) *http.Client {
 	return client
 }
 
+// NewIntraTLSConfig returns the mutual-TLS configuration shared by servers and clients
+// of the intra-control and intra-data networks. Each node presents its own
+// certificate (server_certificate), which must therefore allow both server and client
+// authentication, and requires the peer's certificate to be signed by the cluster
+// CA (ca_certificate). Peers are addressed by IP, and so the hostnames are not verified.
+func NewIntraTLSConfig(conf *HTTPConf) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(conf.Certificate, conf.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load intra-cluster certificate %q, err: %v", conf.Certificate, err)
+	}
+	caPEM, err := ioutil.ReadFile(conf.CACertificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate %q, err: %v", conf.CACertificate, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no valid certificates in %q", conf.CACertificate)
+	}
+	verify := func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		return verifyPeer(rawCerts, pool)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+		// client side: the standard verification is replaced with verifyPeer
+		// that checks the chain but not the hostname
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: verify,
+	}, nil
+}
+
+func verifyPeer(rawCerts [][]byte, roots *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return errors.New("peer did not present a certificate")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(opts)
+	return err
+}
+
 func (args *TransportArgs) ConnControl(c syscall.RawConn) (cntl func(fd uintptr)) {
 	cntl = func(fd uintptr) {
 		// NOTE: is limited by /proc/sys/net/core/rmem_max
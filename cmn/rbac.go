@@ -0,0 +1,186 @@
+// Package cmn provides common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"fmt"
+	"time"
+)
+
+// AuthN permissions
+const (
+	PermRead         = "read"          // GET and HEAD objects, list objects, get bucket props and summary
+	PermWrite        = "write"         // PUT, APPEND, rename and promote objects; prefetch
+	PermDelete       = "delete"        // delete and evict objects
+	PermAdmin        = "admin"         // create, destroy, rename, and copy buckets; set bucket props (implies all the above)
+	PermClusterAdmin = "cluster-admin" // manage cluster, its nodes and jobs (implies all the above, cluster-wide only)
+)
+
+// AuthN predefined roles (see authn/README.md)
+const (
+	RoleClusterAdmin = "cluster-admin"
+	RoleBucketAdmin  = "bucket-admin"
+	RoleReadWrite    = "read-write"
+	RoleReadOnly     = "read-only"
+)
+
+type (
+	// AuthGrant gives permissions to:
+	// * a given bucket (Bucket is defined; empty Ns denotes the global namespace), or
+	// * all buckets of a given namespace (Bucket is empty, Ns is defined), or
+	// * all buckets in the cluster (both empty) - also, the only scope for PermClusterAdmin.
+	// Empty provider matches any provider.
+	AuthGrant struct {
+		Bucket   string   `json:"bucket,omitempty"`
+		Provider string   `json:"provider,omitempty"`
+		Ns       string   `json:"namespace,omitempty"` // namespace uname (see Ns.Uname)
+		Perms    []string `json:"perms"`
+	}
+	// AuthRole is a named set of grants
+	AuthRole struct {
+		Name   string      `json:"name"`
+		Desc   string      `json:"desc,omitempty"`
+		Grants []AuthGrant `json:"grants"`
+	}
+	// AuthAPIKey is a long-lived token issued for a service account; the key
+	// has the account's permissions. Token is returned only upon creation
+	AuthAPIKey struct {
+		ID      string    `json:"id"`
+		Name    string    `json:"name,omitempty"`
+		Account string    `json:"account"`
+		Created time.Time `json:"created"`
+		Expires time.Time `json:"expires,omitempty"` // zero: never expires
+		Token   string    `json:"token,omitempty"`
+	}
+	// AuthAPIKeyMsg is a request to create an API key
+	AuthAPIKeyMsg struct {
+		Account   string `json:"account"`
+		Name      string `json:"name,omitempty"`
+		ExpiresIn string `json:"expires_in,omitempty"` // duration, e.g. "720h"; empty: never expires
+	}
+)
+
+var (
+	permImplied = map[string][]string{
+		PermRead:         {PermRead},
+		PermWrite:        {PermWrite},
+		PermDelete:       {PermDelete},
+		PermAdmin:        {PermRead, PermWrite, PermDelete, PermAdmin},
+		PermClusterAdmin: {PermRead, PermWrite, PermDelete, PermAdmin, PermClusterAdmin},
+	}
+
+	PredefinedRoles = []*AuthRole{
+		{
+			Name:   RoleClusterAdmin,
+			Desc:   "Full access to the cluster, its nodes, buckets, and objects",
+			Grants: []AuthGrant{{Perms: []string{PermClusterAdmin}}},
+		},
+		{
+			Name:   RoleBucketAdmin,
+			Desc:   "Full access to all buckets and objects",
+			Grants: []AuthGrant{{Perms: []string{PermAdmin}}},
+		},
+		{
+			Name:   RoleReadWrite,
+			Desc:   "Read, write, and delete objects in all buckets",
+			Grants: []AuthGrant{{Perms: []string{PermRead, PermWrite, PermDelete}}},
+		},
+		{
+			Name:   RoleReadOnly,
+			Desc:   "Read objects in all buckets",
+			Grants: []AuthGrant{{Perms: []string{PermRead}}},
+		},
+	}
+)
+
+// PredefinedRole returns nil if there's no predefined role with a given name
+func PredefinedRole(name string) *AuthRole {
+	for _, role := range PredefinedRoles {
+		if role.Name == name {
+			return role
+		}
+	}
+	return nil
+}
+
+func (g *AuthGrant) IsClusterWide() bool { return g.Bucket == "" && g.Ns == "" }
+
+func (g *AuthGrant) Validate() error {
+	if len(g.Perms) == 0 {
+		return fmt.Errorf("grant %s: no permissions", g)
+	}
+	for _, perm := range g.Perms {
+		if _, ok := permImplied[perm]; !ok {
+			return fmt.Errorf("grant %s: invalid permission %q", g, perm)
+		}
+		if perm == PermClusterAdmin && !g.IsClusterWide() {
+			return fmt.Errorf("grant %s: %q permission must be cluster-wide", g, perm)
+		}
+	}
+	if g.Provider != "" && !IsValidProvider(g.Provider) {
+		return fmt.Errorf("grant %s: invalid provider %q", g, g.Provider)
+	}
+	if g.Bucket != "" {
+		if err := ValidateBckName(g.Bucket); err != nil {
+			return err
+		}
+	}
+	if g.Ns != "" {
+		return ParseNsUname(g.Ns).Validate()
+	}
+	return nil
+}
+
+func (g *AuthGrant) String() string {
+	switch {
+	case g.IsClusterWide():
+		return "[cluster]"
+	case g.Bucket == "":
+		return fmt.Sprintf("[namespace %s]", g.Ns)
+	default:
+		bck := Bck{Name: g.Bucket, Provider: g.Provider, Ns: ParseNsUname(g.Ns)}
+		return "[bucket " + bck.String() + "]"
+	}
+}
+
+// nil bucket denotes the cluster itself
+func (g *AuthGrant) covers(bck *Bck) bool {
+	if g.IsClusterWide() {
+		return true
+	}
+	if bck == nil {
+		return false
+	}
+	if g.Provider != "" && g.Provider != bck.Provider {
+		return false
+	}
+	if g.Bucket == "" {
+		return ParseNsUname(g.Ns) == bck.Ns
+	}
+	return g.Bucket == bck.Name && ParseNsUname(g.Ns) == bck.Ns
+}
+
+func (g *AuthGrant) allows(perm string) bool {
+	for _, p := range g.Perms {
+		if StringInSlice(perm, permImplied[p]) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPerm returns nil if the grants include the permission for a given bucket
+// or, if the bucket is nil, for the cluster as a whole
+func CheckPerm(grants []AuthGrant, bck *Bck, perm string) error {
+	for i := range grants {
+		if grants[i].covers(bck) && grants[i].allows(perm) {
+			return nil
+		}
+	}
+	if bck == nil {
+		return fmt.Errorf("permission denied: %q (cluster)", perm)
+	}
+	return fmt.Errorf("permission denied: %q (bucket %s)", perm, bck)
+}
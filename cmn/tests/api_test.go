@@ -125,4 +125,31 @@ var _ = Describe("API", func() {
 			),
 		)
 	})
+
+	Describe("RebPlan", func() {
+		It("should account for moved objects per bucket and per target", func() {
+			plan := cmn.NewRebPlan()
+			plan.Add("ais://a", "t1", 10)
+			plan.Add("ais://a", "t2", 20)
+			plan.Add("ais://b", "t1", 30)
+
+			Expect(plan.Total).To(Equal(cmn.RebPlanStats{Objects: 3, Bytes: 60}))
+			Expect(*plan.Buckets["ais://a"]).To(Equal(cmn.RebPlanStats{Objects: 2, Bytes: 30}))
+			Expect(*plan.Buckets["ais://b"]).To(Equal(cmn.RebPlanStats{Objects: 1, Bytes: 30}))
+			Expect(*plan.Targets["t1"]).To(Equal(cmn.RebPlanStats{Objects: 2, Bytes: 40}))
+			Expect(*plan.Targets["t2"]).To(Equal(cmn.RebPlanStats{Objects: 1, Bytes: 20}))
+		})
+
+		It("should sum up the plans of all targets", func() {
+			p1, p2 := cmn.NewRebPlan(), cmn.NewRebPlan()
+			p1.Add("ais://a", "t2", 10)
+			p2.Add("ais://a", "t1", 5)
+			p2.Add("ais://b", "t3", 1)
+
+			total := cmn.ClusterRebPlan{"t1": p1, "t2": p2}.Total()
+			Expect(total.Total).To(Equal(cmn.RebPlanStats{Objects: 3, Bytes: 16}))
+			Expect(*total.Buckets["ais://a"]).To(Equal(cmn.RebPlanStats{Objects: 2, Bytes: 15}))
+			Expect(total.Targets).To(HaveLen(3))
+		})
+	})
 })
@@ -0,0 +1,138 @@
+// Package cmn provides common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// generates a certificate signed by `parent` or, if nil, self-signed
+func genCert(name string, isCA bool, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	signer := &testCert{cert: tmpl, key: key}
+	if parent != nil {
+		signer = parent
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer.cert, &key.PublicKey, signer.key)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	return &testCert{cert: cert, key: key}
+}
+
+// writes certificate and key PEM files and returns their names
+func (tc *testCert) save(dir, name string) (certFile, keyFile string) {
+	certFile, keyFile = filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tc.cert.Raw})
+	Expect(ioutil.WriteFile(certFile, certPEM, 0600)).To(Succeed())
+	keyDER, err := x509.MarshalECPrivateKey(tc.key)
+	Expect(err).NotTo(HaveOccurred())
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	Expect(ioutil.WriteFile(keyFile, keyPEM, 0600)).To(Succeed())
+	return
+}
+
+var _ = Describe("Network", func() {
+	Describe("NewIntraTLSConfig", func() {
+		var (
+			dir      string
+			nodeConf *cmn.HTTPConf
+			server   *httptest.Server
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "intra-tls")
+			Expect(err).NotTo(HaveOccurred())
+
+			ca := genCert("ca", true, nil)
+			caFile, _ := ca.save(dir, "ca")
+			certFile, keyFile := genCert("node", false, ca).save(dir, "node")
+			nodeConf = &cmn.HTTPConf{Certificate: certFile, Key: keyFile, CACertificate: caFile}
+
+			tlsConf, err := cmn.NewIntraTLSConfig(nodeConf)
+			Expect(err).NotTo(HaveOccurred())
+			server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			server.TLS = tlsConf
+			server.StartTLS()
+		})
+
+		AfterEach(func() {
+			server.Close()
+			os.RemoveAll(dir)
+		})
+
+		It("should connect nodes with certificates signed by the cluster CA", func() {
+			tlsConf, err := cmn.NewIntraTLSConfig(nodeConf)
+			Expect(err).NotTo(HaveOccurred())
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConf}}
+			resp, err := client.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		})
+
+		It("should reject clients with no certificate", func() {
+			client := &http.Client{Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			}}
+			_, err := client.Get(server.URL)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject certificates signed by a different CA", func() {
+			rogueCA := genCert("rogue-ca", true, nil)
+			rogueCAFile, _ := rogueCA.save(dir, "rogue-ca")
+			certFile, keyFile := genCert("rogue", false, rogueCA).save(dir, "rogue")
+			tlsConf, err := cmn.NewIntraTLSConfig(&cmn.HTTPConf{
+				Certificate: certFile, Key: keyFile, CACertificate: rogueCAFile,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConf}}
+			_, err = client.Get(server.URL)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail to load a missing CA certificate", func() {
+			conf := *nodeConf
+			conf.CACertificate = filepath.Join(dir, "nonexistent.crt")
+			_, err := cmn.NewIntraTLSConfig(&conf)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,63 @@
+// Package cmn provides common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package tests
+
+import (
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RBAC", func() {
+	var (
+		ns  = cmn.Ns{Name: "ns1"}
+		abc = &cmn.Bck{Name: "abc", Provider: cmn.ProviderAIS}
+		xyz = &cmn.Bck{Name: "xyz", Provider: cmn.ProviderAIS}
+		nsb = &cmn.Bck{Name: "abc", Provider: cmn.ProviderAIS, Ns: ns}
+		aws = &cmn.Bck{Name: "abc", Provider: cmn.ProviderAmazon}
+	)
+
+	DescribeTable("CheckPerm",
+		func(grants []cmn.AuthGrant, bck *cmn.Bck, perm string, allowed bool) {
+			err := cmn.CheckPerm(grants, bck, perm)
+			if allowed {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("no grants", nil, abc, cmn.PermRead, false),
+		Entry("cluster-wide read", []cmn.AuthGrant{{Perms: []string{cmn.PermRead}}}, abc, cmn.PermRead, true),
+		Entry("read does not imply write", []cmn.AuthGrant{{Perms: []string{cmn.PermRead}}}, abc, cmn.PermWrite, false),
+		Entry("admin implies delete", []cmn.AuthGrant{{Perms: []string{cmn.PermAdmin}}}, abc, cmn.PermDelete, true),
+		Entry("admin does not imply cluster-admin", []cmn.AuthGrant{{Perms: []string{cmn.PermAdmin}}}, nil, cmn.PermClusterAdmin, false),
+		Entry("cluster-admin", []cmn.AuthGrant{{Perms: []string{cmn.PermClusterAdmin}}}, nil, cmn.PermClusterAdmin, true),
+		Entry("bucket grant", []cmn.AuthGrant{{Bucket: "abc", Perms: []string{cmn.PermWrite}}}, abc, cmn.PermWrite, true),
+		Entry("bucket grant, other bucket", []cmn.AuthGrant{{Bucket: "abc", Perms: []string{cmn.PermWrite}}}, xyz, cmn.PermWrite, false),
+		Entry("bucket grant, other namespace", []cmn.AuthGrant{{Bucket: "abc", Perms: []string{cmn.PermWrite}}}, nsb, cmn.PermWrite, false),
+		Entry("bucket grant, other provider", []cmn.AuthGrant{{Bucket: "abc", Provider: cmn.ProviderAIS, Perms: []string{cmn.PermWrite}}}, aws, cmn.PermWrite, false),
+		Entry("bucket grant, cluster", []cmn.AuthGrant{{Bucket: "abc", Perms: []string{cmn.PermAdmin}}}, nil, cmn.PermAdmin, false),
+		Entry("namespace grant", []cmn.AuthGrant{{Ns: ns.Uname(), Perms: []string{cmn.PermRead}}}, nsb, cmn.PermRead, true),
+		Entry("namespace grant, global namespace", []cmn.AuthGrant{{Ns: ns.Uname(), Perms: []string{cmn.PermRead}}}, abc, cmn.PermRead, false),
+	)
+
+	DescribeTable("AuthGrant.Validate",
+		func(grant cmn.AuthGrant, valid bool) {
+			err := grant.Validate()
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("cluster-wide", cmn.AuthGrant{Perms: []string{cmn.PermClusterAdmin}}, true),
+		Entry("bucket", cmn.AuthGrant{Bucket: "abc", Provider: cmn.ProviderAIS, Perms: []string{cmn.PermRead, cmn.PermWrite}}, true),
+		Entry("no permissions", cmn.AuthGrant{Bucket: "abc"}, false),
+		Entry("invalid permission", cmn.AuthGrant{Perms: []string{"superpower"}}, false),
+		Entry("bucket cluster-admin", cmn.AuthGrant{Bucket: "abc", Perms: []string{cmn.PermClusterAdmin}}, false),
+		Entry("invalid provider", cmn.AuthGrant{Bucket: "abc", Provider: "xyz", Perms: []string{cmn.PermRead}}, false),
+	)
+})
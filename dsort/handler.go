@@ -715,7 +715,7 @@ func finishedAckHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func broadcast(method, path string, urlParams url.Values, body []byte, nodes cluster.NodeMap, ignore ...*cluster.Snode) []response {
-	client := http.DefaultClient
+	client := ctx.client
 	responses := make([]response, len(nodes))
 
 	wg := &sync.WaitGroup{}
@@ -56,6 +56,7 @@ type (
 		node      *cluster.Snode
 		t         cluster.Target
 		stats     stats.Tracker
+		client    *http.Client // intra-control broadcasts
 	}
 
 	creationPhaseMetadata struct {
@@ -141,6 +142,7 @@ func RegisterNode(smapOwner cluster.Sowner, bmdOwner cluster.Bowner, snode *clus
 	ctx.node = snode
 	ctx.t = t
 	ctx.stats = stats
+	ctx.client = cmn.NewClient(cmn.TransportArgs{UseIntraTLS: true})
 	// TODO: try to introduce and benchmark a separate MMSA instance, e.g.:
 	//       mm = &memsys.MMSA{Name: cmn.DSortName + ".MMSA", TimeIval: time.Minute * 10, ...}
 	cmn.Assert(mm == nil)
@@ -188,6 +190,7 @@ func (m *Manager) init(rs *ParsedRequestSpec) error {
 	m.client = cmn.NewClient(cmn.TransportArgs{
 		DialTimeout: 5 * time.Minute,
 		Timeout:     30 * time.Minute,
+		UseIntraTLS: true,
 	})
 
 	m.fileExtension = rs.Extension
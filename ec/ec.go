@@ -5,6 +5,7 @@
 package ec
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -136,6 +137,7 @@ type (
 		ErrCh    chan error   // for final EC result
 		IsCopy   bool         // replicate or use erasure coding
 		Callback cluster.OnFinishObj
+		Ctx      context.Context // trace context (optional)
 
 		// private properties
 		putTime time.Time // time when the object is put into main queue
@@ -209,6 +211,7 @@ func (s *slice) release() {
 
 var (
 	mm           *memsys.MMSA       // memory manager and slab/SGL allocator
+	client       *http.Client       // intra-data requests (see RequestECMeta)
 	slicePadding = make([]byte, 64) // for padding EC slices
 	XactCount    atomic.Int32       // the number of currently active EC xactions
 
@@ -220,6 +223,7 @@ var (
 
 func Init(t cluster.Target, reg XactRegistry) {
 	mm = t.GetMMSA() // TODO: try to introduce and benchmark a separate MMSA for EC
+	client = cmn.NewClient(cmn.TransportArgs{UseIntraTLS: true})
 	fs.CSM.RegisterContentType(SliceType, &SliceSpec{})
 	fs.CSM.RegisterContentType(MetaType, &MetaSpec{})
 	if err := initManager(t, reg); err != nil {
@@ -322,7 +326,7 @@ func RequestECMeta(bck cmn.Bck, objName string, si *cluster.Snode) (md *Metadata
 		return nil, err
 	}
 	rq.URL.RawQuery = query.Encode()
-	resp, err := http.DefaultClient.Do(rq)
+	resp, err := client.Do(rq)
 	if err != nil {
 		if resp.StatusCode != http.StatusNotFound {
 			return nil, fmt.Errorf("Failed to read %s HEAD request: %v", objName, err)
@@ -19,6 +19,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/tracing"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/OneOfOne/xxhash"
 	"github.com/klauspost/reedsolomon"
@@ -80,7 +81,11 @@ func (c *getJogger) ec(req *Request) {
 			}
 		}
 		restore := func(req *Request, toDisk bool, buffer []byte, cb func(error)) {
+			var span *tracing.Span
+			req.Ctx, span = tracing.Start(req.Ctx, "EC reconstruct", tracing.KindInternal)
+			span.SetAttr("object", req.LOM.String())
 			err := c.restore(req, toDisk, buffer)
+			span.End(err)
 			c.parent.stats.updateDecodeTime(time.Since(req.tm), err != nil)
 			if cb != nil {
 				cb(err)
@@ -199,7 +204,7 @@ func (c *getJogger) restoreReplicatedFromMemory(req *Request, meta *Metadata, no
 		iReqBuf := c.parent.newIntraReq(reqGet, meta).NewPack(mm)
 
 		w := mm.NewSGL(cmn.KiB)
-		if _, err := c.parent.readRemote(req.LOM, node, uname, iReqBuf, w); err != nil {
+		if _, err := c.parent.readRemote(req.Ctx, req.LOM, node, uname, iReqBuf, w); err != nil {
 			glog.Errorf("Failed to read from %s", node)
 			w.Free()
 			mm.Free(iReqBuf)
@@ -272,7 +277,7 @@ func (c *getJogger) restoreReplicatedFromDisk(req *Request, meta *Metadata, node
 		}
 		iReqBuf := c.parent.newIntraReq(reqGet, meta).NewPack(mm)
 		req.LOM.FQN = tmpFQN
-		n, err = c.parent.readRemote(req.LOM, node, uname, iReqBuf, w)
+		n, err = c.parent.readRemote(req.Ctx, req.LOM, node, uname, iReqBuf, w)
 		mm.Free(iReqBuf)
 		w.Close()
 
@@ -382,9 +387,10 @@ func (c *getJogger) requestSlices(req *Request, meta *Metadata, nodes map[string
 	mm := c.parent.t.GetSmallMMSA()
 	request := iReq.NewPack(mm)
 	hdr := transport.Header{
-		Bck:     req.LOM.Bck().Bck,
-		ObjName: req.LOM.Objname,
-		Opaque:  request,
+		Bck:         req.LOM.Bck().Bck,
+		ObjName:     req.LOM.Objname,
+		Opaque:      request,
+		Traceparent: tracing.Traceparent(req.Ctx),
 	}
 
 	// broadcast slice request and wait for all targets respond
@@ -5,6 +5,7 @@
 package ec
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -17,6 +18,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/tracing"
 	"github.com/NVIDIA/aistore/transport"
 )
 
@@ -232,7 +234,14 @@ func (mgr *Manager) recvRequest(w http.ResponseWriter, hdr transport.Header, obj
 			return
 		}
 	}
+	var span *tracing.Span
+	if hdr.Traceparent != "" {
+		_, span = tracing.Start(tracing.WithTraceparent(context.Background(), hdr.Traceparent),
+			"EC request", tracing.KindServer)
+		span.SetAttr("object", bck.String()+"/"+hdr.ObjName)
+	}
 	mgr.RestoreBckRespXact(bck).DispatchReq(iReq, bck, hdr.ObjName)
+	span.End(nil)
 }
 
 // A function to process big chunks of data (replica/slice/meta) sent from other targets
@@ -333,7 +342,7 @@ func (mgr *Manager) CleanupObject(lom *cluster.LOM) {
 	mgr.RestoreBckPutXact(lom.Bck()).Cleanup(req)
 }
 
-func (mgr *Manager) RestoreObject(lom *cluster.LOM) error {
+func (mgr *Manager) RestoreObject(ctx context.Context, lom *cluster.LOM) error {
 	if !lom.Bprops().EC.Enabled {
 		return ErrorECDisabled
 	}
@@ -350,6 +359,7 @@ func (mgr *Manager) RestoreObject(lom *cluster.LOM) error {
 		Action: ActRestore,
 		LOM:    lom,
 		ErrCh:  make(chan error), // unbuffered
+		Ctx:    ctx,
 	}
 
 	mgr.RestoreBckGetXact(lom.Bck()).Decode(req)
@@ -5,6 +5,7 @@
 package ec
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -17,6 +18,7 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tracing"
 	"github.com/NVIDIA/aistore/transport"
 )
 
@@ -292,11 +294,13 @@ func (r *xactECBase) sendByDaemonID(daemonIDs []string, hdr transport.Header,
 //		name, it puts the data to its writer and notifies when download is done
 // * request - request to send
 // * writer - an opened writer that will receive the replica/slice/meta
-func (r *xactECBase) readRemote(lom *cluster.LOM, daemonID, uname string, request []byte, writer io.Writer) (int64, error) {
+func (r *xactECBase) readRemote(ctx context.Context, lom *cluster.LOM, daemonID, uname string, request []byte,
+	writer io.Writer) (int64, error) {
 	hdr := transport.Header{
-		Bck:     lom.Bck().Bck,
-		ObjName: lom.Objname,
-		Opaque:  request,
+		Bck:         lom.Bck().Bck,
+		ObjName:     lom.Objname,
+		Opaque:      request,
+		Traceparent: tracing.Traceparent(ctx),
 	}
 	var reader cmn.ReadOpenCloser
 	reader, hdr.ObjAttrs.Size = nil, 0
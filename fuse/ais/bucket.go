@@ -6,8 +6,10 @@ package ais
 
 import (
 	"errors"
+	"io"
 	"net/http"
 
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/cmn"
 )
@@ -20,23 +22,27 @@ type (
 		HeadObject(objName string) (obj *Object, exists bool, err error)
 		ListObjects(prefix, pageMarker string, pageSize int) (objs []*Object, newPageMarker string, err error)
 		DeleteObject(objName string) (err error)
+		RenameObject(oldName, newName string) (err error)
 	}
 
 	bucketAPI struct {
-		name      string
+		bck       cmn.Bck
 		apiParams api.BaseParams
+		isAIS     atomic.Int32 // 0 - unknown, 1 - ais bucket, -1 - cloud bucket
 	}
 )
 
-func NewBucket(name string, apiParams api.BaseParams) Bucket {
+// NewBucket returns the bucket; provider of `bck` may be empty (resolved by
+// the cluster).
+func NewBucket(bck cmn.Bck, apiParams api.BaseParams) Bucket {
 	return &bucketAPI{
-		name:      name,
+		bck:       bck,
 		apiParams: apiParams,
 	}
 }
 
-func (bck *bucketAPI) Name() string              { return bck.name }
-func (bck *bucketAPI) Bck() cmn.Bck              { return cmn.Bck{Name: bck.name} }
+func (bck *bucketAPI) Name() string              { return bck.bck.Name }
+func (bck *bucketAPI) Bck() cmn.Bck              { return bck.bck }
 func (bck *bucketAPI) APIParams() api.BaseParams { return bck.apiParams }
 
 func (bck *bucketAPI) HeadObject(objName string) (obj *Object, exists bool, err error) {
@@ -55,6 +61,12 @@ func (bck *bucketAPI) HeadObject(objName string) (obj *Object, exists bool, err
 		Name:      objName,
 		Size:      objProps.Size,
 		Atime:     objProps.Atime,
+		Version:   objProps.Version,
+		Cksum:     objProps.Checksum,
+
+		NumCopies:    objProps.NumCopies,
+		DataSlices:   objProps.DataSlices,
+		ParitySlices: objProps.ParitySlices,
 	}, true, nil
 }
 
@@ -85,3 +97,63 @@ func (bck *bucketAPI) DeleteObject(objName string) (err error) {
 	}
 	return
 }
+
+// Renames object in place if the bucket is an ais bucket, otherwise
+// (cloud buckets do not support renaming) copies and deletes it.
+func (bck *bucketAPI) RenameObject(oldName, newName string) (err error) {
+	isAIS, err := bck.checkAIS()
+	if err != nil {
+		return newBucketIOError(err, "RenameObject", oldName)
+	}
+	if isAIS {
+		err = api.RenameObject(bck.apiParams, bck.Bck(), oldName, newName)
+	} else if err = bck.copyObject(oldName, newName); err == nil {
+		err = api.DeleteObject(bck.apiParams, bck.Bck(), oldName)
+	}
+	if err != nil {
+		err = newBucketIOError(err, "RenameObject", oldName)
+	}
+	return
+}
+
+func (bck *bucketAPI) checkAIS() (bool, error) {
+	if v := bck.isAIS.Load(); v != 0 {
+		return v > 0, nil
+	}
+	if bck.bck.Provider != "" {
+		return bck.bck.IsAIS(), nil
+	}
+	props, err := api.HeadBucket(bck.apiParams, bck.Bck())
+	if err != nil {
+		return false, err
+	}
+	isAIS := props.CloudProvider == cmn.ProviderAIS
+	if isAIS {
+		bck.isAIS.Store(1)
+	} else {
+		bck.isAIS.Store(-1)
+	}
+	return isAIS, nil
+}
+
+// Streams the object (GET) into a new one (PUT) without buffering it in memory.
+func (bck *bucketAPI) copyObject(srcName, dstName string) error {
+	objProps, err := api.HeadObject(bck.apiParams, bck.Bck(), srcName)
+	if err != nil {
+		return err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := api.GetObject(bck.apiParams, bck.Bck(), srcName, api.GetObjectInput{Writer: pw})
+		pw.CloseWithError(err)
+	}()
+	err = api.PutObject(api.PutObjectArgs{
+		BaseParams: bck.apiParams,
+		Bck:        bck.Bck(),
+		Object:     dstName,
+		Reader:     cmn.NopOpener(pr),
+		Size:       uint64(objProps.Size),
+	})
+	pr.Close() // unblocks the reader if PUT has failed
+	return err
+}
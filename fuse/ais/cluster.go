@@ -0,0 +1,89 @@
+// Package ais implements an AIStore client.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"sync"
+
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+type (
+	// Cluster provides bucket-level operations (used when the whole cluster
+	// is mounted).
+	Cluster interface {
+		APIParams() api.BaseParams
+		Bucket(bck cmn.Bck) Bucket
+		// Cloud buckets are returned with their (actual) provider and namespace.
+		ListBuckets() (aisBuckets []string, cloudBuckets []cmn.Bck, err error)
+		CreateBucket(name string) error
+		DestroyBucket(name string) error
+	}
+
+	clusterAPI struct {
+		apiParams api.BaseParams
+
+		mu    sync.Mutex
+		cloud *cmn.Bck // provider and namespace of cloud buckets (nil until known)
+	}
+)
+
+func NewCluster(apiParams api.BaseParams) Cluster {
+	return &clusterAPI{apiParams: apiParams}
+}
+
+func (c *clusterAPI) APIParams() api.BaseParams { return c.apiParams }
+func (c *clusterAPI) Bucket(bck cmn.Bck) Bucket { return NewBucket(bck, c.apiParams) }
+
+func (c *clusterAPI) ListBuckets() (aisBuckets []string, cloudBuckets []cmn.Bck, err error) {
+	names, err := api.GetBucketNames(c.apiParams, cmn.Bck{})
+	if err != nil {
+		return nil, nil, newBucketIOError(err, "ListBuckets")
+	}
+	if len(names.Cloud) == 0 {
+		return names.AIS, nil, nil
+	}
+	cloud, err := c.cloudProvider(names.Cloud[0])
+	if err != nil {
+		return nil, nil, newBucketIOError(err, "ListBuckets")
+	}
+	cloudBuckets = make([]cmn.Bck, 0, len(names.Cloud))
+	for _, name := range names.Cloud {
+		cloudBuckets = append(cloudBuckets, cmn.Bck{Name: name, Provider: cloud.Provider, Ns: cloud.Ns})
+	}
+	return names.AIS, cloudBuckets, nil
+}
+
+// Returns the cloud provider (and its namespace) configured in the cluster,
+// as reported by HEAD of one of the cloud buckets - once. (Reading the cluster
+// configuration requires cluster-admin permissions.)
+func (c *clusterAPI) cloudProvider(bucket string) (cmn.Bck, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cloud != nil {
+		return *c.cloud, nil
+	}
+	_, bck, err := api.HeadCloudBucket(c.apiParams, cmn.Bck{Name: bucket, Provider: cmn.Cloud})
+	if err != nil {
+		return cmn.Bck{}, err
+	}
+	c.cloud = &cmn.Bck{Provider: bck.Provider, Ns: bck.Ns}
+	return *c.cloud, nil
+}
+
+func (c *clusterAPI) CreateBucket(name string) error {
+	if err := api.CreateBucket(c.apiParams, cmn.Bck{Name: name, Provider: cmn.ProviderAIS}); err != nil {
+		return newBucketIOError(err, "CreateBucket", name)
+	}
+	return nil
+}
+
+func (c *clusterAPI) DestroyBucket(name string) error {
+	if err := api.DestroyBucket(c.apiParams, cmn.Bck{Name: name, Provider: cmn.ProviderAIS}); err != nil {
+		return newBucketIOError(err, "DestroyBucket", name)
+	}
+	return nil
+}
@@ -15,11 +15,16 @@ import (
 )
 
 type Object struct {
-	apiParams api.BaseParams // FIXME: it is quite a big struct and should be removed
-	bck       cmn.Bck        // FIXME: bucket name is static so we should not have it as a field
-	Name      string
-	Size      int64
-	Atime     time.Time
+	apiParams    api.BaseParams // FIXME: it is quite a big struct and should be removed
+	bck          cmn.Bck        // FIXME: bucket name is static so we should not have it as a field
+	Name         string
+	Size         int64
+	Atime        time.Time
+	Version      string // set by HeadObject
+	Cksum        string // ditto
+	NumCopies    int    // ditto
+	DataSlices   int    // ditto (0 if not erasure coded)
+	ParitySlices int    // ditto
 }
 
 func NewObject(objName string, bucket Bucket, sizes ...int64) *Object {
@@ -37,6 +42,8 @@ func NewObject(objName string, bucket Bucket, sizes ...int64) *Object {
 	}
 }
 
+func (obj *Object) Bck() cmn.Bck { return obj.bck }
+
 func (obj *Object) Put(r cmn.ReadOpenCloser) (err error) {
 	putArgs := api.PutObjectArgs{
 		BaseParams: obj.apiParams,
@@ -34,6 +34,7 @@ VERSION:
 
 USAGE:
 	{{ .Name }} [OPTION...] BUCKET MOUNTPOINT
+	{{ .Name }} [OPTION...] --cluster MOUNTPOINT
 
 ARGUMENTS:
 	BUCKET      bucket name
@@ -93,7 +94,7 @@ func runDaemon(mountPath string, errorSink io.Writer) (err error) {
 	return
 }
 
-func dispatchSignalHandlers(mountPath string, mntCfg *fuse.MountConfig, serverCfg *fs.ServerConfig) {
+func dispatchSignalHandlers(mountPath, cfgName string, mntCfg *fuse.MountConfig, serverCfg *fs.ServerConfig) {
 	signalCh := make(chan os.Signal, 1)
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGHUP)
 	go func() {
@@ -111,7 +112,7 @@ func dispatchSignalHandlers(mountPath string, mntCfg *fuse.MountConfig, serverCf
 				}
 				mntCfg.ErrorLogger.Printf("Failed to unmount upon SIGINT: %v", err)
 			case syscall.SIGHUP:
-				cfg, err := loadConfig(serverCfg.BucketName)
+				cfg, err := loadConfig(cfgName)
 				if err != nil {
 					mntCfg.ErrorLogger.Printf("Failed to reload config upon SIGHUP: %v", err)
 					break
@@ -156,6 +157,11 @@ func newApp() *cli.App {
 				Usage: "wait for file system to be unmounted",
 			},
 
+			cli.BoolFlag{
+				Name:  "cluster",
+				Usage: "mount the whole cluster (top-level directories are buckets) instead of a single bucket",
+			},
+
 			cli.IntFlag{
 				Name:  "uid",
 				Value: -1,
@@ -183,6 +189,7 @@ func appMain(c *cli.Context) (err error) {
 		cfg       *Config
 		cluURL    string
 		bucket    string
+		cfgName   string
 		mountDir  string
 		mountPath string
 		errorLog  *log.Logger
@@ -199,19 +206,30 @@ func appMain(c *cli.Context) (err error) {
 		return
 	}
 
-	if c.NArg() < 1 {
-		return missingArgumentsError("BUCKET", "MOUNTPOINT")
-	}
-	if c.NArg() < 2 {
-		return missingArgumentsError("MOUNTPOINT")
-	}
-	if c.NArg() > 2 {
-		return incorrectUsageError(errors.New("too many arguments"))
-	}
-
 	flags = parseFlags(c)
-	bucket = c.Args().Get(0)
-	mountDir = c.Args().Get(1)
+	if flags.Cluster {
+		if c.NArg() < 1 {
+			return missingArgumentsError("MOUNTPOINT")
+		}
+		if c.NArg() > 1 {
+			return incorrectUsageError(errors.New("too many arguments (BUCKET cannot be used with --cluster)"))
+		}
+		cfgName = clusterCfgName
+		mountDir = c.Args().Get(0)
+	} else {
+		if c.NArg() < 1 {
+			return missingArgumentsError("BUCKET", "MOUNTPOINT")
+		}
+		if c.NArg() < 2 {
+			return missingArgumentsError("MOUNTPOINT")
+		}
+		if c.NArg() > 2 {
+			return incorrectUsageError(errors.New("too many arguments"))
+		}
+		bucket = c.Args().Get(0)
+		cfgName = bucket
+		mountDir = c.Args().Get(1)
+	}
 
 	mountPath, err = filepath.Abs(mountDir)
 	if err != nil {
@@ -246,7 +264,7 @@ func appMain(c *cli.Context) (err error) {
 	}
 
 	// Try to load existing config from file or use default one.
-	cfg, err = loadConfig(bucket)
+	cfg, err = loadConfig(cfgName)
 	if err != nil {
 		return
 	}
@@ -257,22 +275,27 @@ func appMain(c *cli.Context) (err error) {
 		return
 	}
 
-	errorLog, err = prepareLogFile(cfg.Log.ErrorFile, "ERROR: ", bucket)
+	errorLog, err = prepareLogFile(cfg.Log.ErrorFile, "ERROR: ", cfgName)
 	if err != nil {
 		return
 	}
 
 	// If cfg.Log.DebugFile == "" no debug logging is performed.
 	if cfg.Log.DebugFile != "" {
-		debugLog, err = prepareLogFile(cfg.Log.DebugFile, "DEBUG: ", bucket)
+		debugLog, err = prepareLogFile(cfg.Log.DebugFile, "DEBUG: ", cfgName)
 		if err != nil {
 			return
 		}
 	}
 
 	// Useful message describing some fs params, printed only if --wait flag was given by the user.
-	fmt.Fprintf(c.App.Writer, "Connecting to proxy at %q\nMounting bucket %q to %q\nuid %d\ngid %d\n",
-		cluURL, bucket, mountPath, fsowner.UID, fsowner.GID)
+	if flags.Cluster {
+		fmt.Fprintf(c.App.Writer, "Connecting to proxy at %q\nMounting cluster to %q\nuid %d\ngid %d\n",
+			cluURL, mountPath, fsowner.UID, fsowner.GID)
+	} else {
+		fmt.Fprintf(c.App.Writer, "Connecting to proxy at %q\nMounting bucket %q to %q\nuid %d\ngid %d\n",
+			cluURL, bucket, mountPath, fsowner.UID, fsowner.GID)
+	}
 
 	// Init a server configuration object.
 	serverCfg := &fs.ServerConfig{
@@ -280,6 +303,9 @@ func appMain(c *cli.Context) (err error) {
 		AISURL:     cluURL,
 		BucketName: bucket,
 		Owner:      fsowner,
+
+		WriteCacheDir: cfg.IO.WriteCacheDir,
+		ReadCacheDir:  cfg.IO.ReadCacheDir,
 	}
 	cfg.writeTo(serverCfg)
 
@@ -309,7 +335,7 @@ func appMain(c *cli.Context) (err error) {
 
 	// Start signal dispatcher which catches different signals and reacts upon
 	// receiving them.
-	dispatchSignalHandlers(mountPath, mountCfg, serverCfg)
+	dispatchSignalHandlers(mountPath, cfgName, mountCfg, serverCfg)
 
 	// Wait for the file system to be unmounted.
 	err = mfs.Join(context.Background())
@@ -15,7 +15,13 @@ import (
 	"github.com/NVIDIA/aistore/fuse/fs"
 )
 
-const configDirName = fs.Name
+const (
+	configDirName = fs.Name
+
+	// Used instead of the bucket name (in names of config and log files)
+	// when the whole cluster is mounted. Bucket names cannot contain '@'.
+	clusterCfgName = "@cluster"
+)
 
 var defaultConfig = Config{
 	Cluster: ClusterConfig{
@@ -39,6 +45,14 @@ var defaultConfig = Config{
 		// Determines the size of chunks that we write with append. The only exception
 		// when we write less is Flush (end-of-file).
 		WriteBufSize: cmn.MiB,
+		// Write-back cache (random writes, truncation) is disabled by default.
+		WriteCacheDir:  "",
+		WriteCacheSize: "1GiB",
+		// Read cache (blocks of objects on local disk) is disabled by default.
+		ReadCacheDir:  "",
+		ReadCacheSize: "10GiB",
+		// Number of blocks prefetched (into the read cache) on sequential reads.
+		ReadAhead: 4,
 	},
 	// By default we allow unlimited memory to be used by the cache.
 	MemoryLimit: "0B",
@@ -76,7 +90,12 @@ type (
 	}
 
 	IOConfig struct {
-		WriteBufSize int64 `json:"write_buf_size"`
+		WriteBufSize   int64  `json:"write_buf_size"`
+		WriteCacheDir  string `json:"write_cache_dir"`
+		WriteCacheSize string `json:"write_cache_size"`
+		ReadCacheDir   string `json:"read_cache_dir"`
+		ReadCacheSize  string `json:"read_cache_size"`
+		ReadAhead      int64  `json:"read_ahead"`
 	}
 )
 
@@ -99,6 +118,25 @@ func (c *Config) validate() (err error) {
 	if c.IO.WriteBufSize < 0 {
 		return fmt.Errorf("invalid io.write_buf_size value: %d: expected non-negative value", c.IO.WriteBufSize)
 	}
+	if c.IO.WriteCacheDir != "" && !filepath.IsAbs(c.IO.WriteCacheDir) {
+		return fmt.Errorf("invalid io.write_cache_dir format %q: path needs to be absolute", c.IO.WriteCacheDir)
+	}
+	if v, err := cmn.S2B(c.IO.WriteCacheSize); err != nil {
+		return fmt.Errorf("invalid io.write_cache_size value: %q: %v", c.IO.WriteCacheSize, err)
+	} else if v < 0 {
+		return fmt.Errorf("invalid io.write_cache_size value: %q: expected non-negative value", c.IO.WriteCacheSize)
+	}
+	if c.IO.ReadCacheDir != "" && !filepath.IsAbs(c.IO.ReadCacheDir) {
+		return fmt.Errorf("invalid io.read_cache_dir format %q: path needs to be absolute", c.IO.ReadCacheDir)
+	}
+	if v, err := cmn.S2B(c.IO.ReadCacheSize); err != nil {
+		return fmt.Errorf("invalid io.read_cache_size value: %q: %v", c.IO.ReadCacheSize, err)
+	} else if v < 0 {
+		return fmt.Errorf("invalid io.read_cache_size value: %q: expected non-negative value", c.IO.ReadCacheSize)
+	}
+	if c.IO.ReadAhead < 0 {
+		return fmt.Errorf("invalid io.read_ahead value: %d: expected non-negative value", c.IO.ReadAhead)
+	}
 	if v, err := cmn.S2B(c.MemoryLimit); err != nil {
 		return fmt.Errorf("invalid memory_limit value: %q: %v", c.MemoryLimit, err)
 	} else if v < 0 {
@@ -109,11 +147,16 @@ func (c *Config) validate() (err error) {
 
 func (c *Config) writeTo(srvCfg *fs.ServerConfig) {
 	memoryLimit, _ := cmn.S2B(c.MemoryLimit)
+	writeCacheSize, _ := cmn.S2B(c.IO.WriteCacheSize)
+	readCacheSize, _ := cmn.S2B(c.IO.ReadCacheSize)
 	srvCfg.TCPTimeout = c.Timeout.TCPTimeout
 	srvCfg.HTTPTimeout = c.Timeout.HTTPTimeout
 	srvCfg.SyncInterval.Store(c.Periodic.SyncInterval)
 	srvCfg.MemoryLimit.Store(uint64(memoryLimit))
 	srvCfg.MaxWriteBufSize.Store(c.IO.WriteBufSize)
+	srvCfg.WriteCacheSize.Store(writeCacheSize)
+	srvCfg.ReadCacheSize.Store(readCacheSize)
+	srvCfg.ReadAhead.Store(c.IO.ReadAhead)
 }
 
 func loadConfig(bucket string) (cfg *Config, err error) {
@@ -12,7 +12,8 @@ import (
 
 type flags struct {
 	// Control
-	Wait bool
+	Wait    bool
+	Cluster bool
 
 	// File System
 	AdditionalMountOptions map[string]string
@@ -23,7 +24,8 @@ type flags struct {
 func parseFlags(c *cli.Context) *flags {
 	flags := &flags{
 		// Control
-		Wait: c.Bool("wait"),
+		Wait:    c.Bool("wait"),
+		Cluster: c.Bool("cluster"),
 
 		// File System
 		AdditionalMountOptions: parseAdditionalMountOptions(c),
@@ -197,6 +197,45 @@ func (c *namespaceCache) remove(p string) {
 	wg.Wait()
 }
 
+// move moves all entries with prefix `src` (directory) to prefix `dst`
+// keeping their inode IDs.
+func (c *namespaceCache) move(src, dst string) {
+	cmn.Assert(strings.HasSuffix(src, separator) && strings.HasSuffix(dst, separator))
+	var (
+		mtx   sync.Mutex
+		wg    = &sync.WaitGroup{}
+		moved = make([]nsEntry, 0, 16)
+	)
+	for i := 0; i < cmn.MultiSyncMapCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			m := c.getCacheByIdx(i)
+			m.Range(func(k, v interface{}) bool {
+				if strings.HasPrefix(k.(string), src) {
+					m.Delete(k)
+					mtx.Lock()
+					moved = append(moved, v.(nsEntry))
+					mtx.Unlock()
+				}
+				return true
+			})
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, entry := range moved {
+		newPath := dst + entry.Name()[len(src):]
+		if entry.Ty() == entryDirTy {
+			c.add(entryDirTy, dtAttrs{id: entry.ID(), path: newPath})
+			continue
+		}
+		obj := *entry.Object()
+		obj.Name = newPath
+		c.add(entryFileTy, dtAttrs{id: entry.ID(), path: newPath, obj: &obj})
+	}
+}
+
 func (c *namespaceCache) lookup(p string) (res EntryLookupResult, entry nsEntry, exists bool) {
 	root := c.root
 	if p == "" {
@@ -8,7 +8,9 @@ import (
 	"net/http"
 
 	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fuse/ais"
+	"github.com/jacobsa/fuse/fuseops"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -28,7 +30,7 @@ var _ = Describe("Cache", func() {
 		)
 
 		BeforeEach(func() {
-			bck = ais.NewBucket("empty", api.BaseParams{
+			bck = ais.NewBucket(cmn.Bck{Name: "empty"}, api.BaseParams{
 				Client: http.DefaultClient,
 				URL:    "",
 			})
@@ -150,6 +152,56 @@ var _ = Describe("Cache", func() {
 			})
 		})
 
+		Describe("move", func() {
+			It("should move nonempty directory keeping inode IDs", func() {
+				var (
+					newDpath   = "x/y/"
+					dirID      = fuseops.InodeID(fuseops.RootInodeID + 10)
+					filesPaths = []string{
+						dpath + "d",
+						dpath + "e/f",
+					}
+				)
+
+				cache.add(entryDirTy, dtAttrs{
+					id:   dirID,
+					path: dpath,
+				})
+				for idx, filePath := range filesPaths {
+					cache.add(entryFileTy, dtAttrs{
+						id:   dirID + 1 + fuseops.InodeID(idx),
+						path: filePath,
+						obj:  ais.NewObject(filePath, bck, 1024),
+					})
+				}
+
+				cache.move(dpath, newDpath)
+				_, _, exists := cache.lookup(dpath)
+				Expect(exists).To(BeFalse())
+				_, entry, exists := cache.lookup(newDpath)
+				Expect(exists).To(BeTrue())
+				Expect(entry.ID()).To(Equal(dirID))
+
+				for idx, filePath := range filesPaths {
+					_, _, exists = cache.lookup(filePath)
+					Expect(exists).To(BeFalse())
+
+					newPath := newDpath + filePath[len(dpath):]
+					res, entry, exists := cache.lookup(newPath)
+					Expect(exists).To(BeTrue())
+					Expect(entry.ID()).To(Equal(dirID + 1 + fuseops.InodeID(idx)))
+					Expect(res.Object.Name).To(Equal(newPath))
+					Expect(res.Object.Size).To(BeEquivalentTo(1024))
+				}
+				_, _, exists = cache.lookup("x/y/e/")
+				Expect(exists).To(BeTrue())
+				for _, dirPath := range subDirs {
+					_, _, exists = cache.lookup(dirPath)
+					Expect(exists).To(BeTrue())
+				}
+			})
+		})
+
 		Describe("listEntries", func() {
 			It("should list no entries", func() {
 				var entries []nsEntry
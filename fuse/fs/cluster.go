@@ -0,0 +1,289 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fuse/ais"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+)
+
+// Theory of operation
+//
+// When the whole cluster is mounted (`ServerConfig.BucketName` is empty),
+// the root directory lists ais buckets plus the `@cloud` directory which, in
+// turn, lists cloud providers:
+//
+//   /<ais bucket>/...
+//   /@cloud/<provider>/<bucket>/...
+//   /@cloud/<provider>/@<namespace>/<bucket>/...
+//
+// Buckets of a provider that are in a namespace (e.g., buckets of a remote AIS
+// cluster) are listed in the namespace's directory. Directories listing
+// buckets, providers, or namespaces are backed by bucketsDir instead of
+// a namespace. Each bucket directory is the root of its bucket's namespace:
+// its path is empty, and paths of the inodes underneath are relative to the
+// bucket (as in the single bucket mount).
+//
+// Creating a directory in the root creates an ais bucket; removing an (empty)
+// bucket directory destroys the bucket. Cloud buckets can be neither created
+// nor destroyed, and files cannot be created outside of buckets.
+
+// Name of the directory (in the root) listing cloud providers, and the prefix
+// of namespace directories. Bucket names cannot contain '@', so these never
+// collide with buckets.
+const (
+	cloudDirName = "@cloud"
+	nsDirPrefix  = "@"
+)
+
+const (
+	dirAIS      = iota // the root: ais buckets and the cloud directory
+	dirCloud           // cloud providers
+	dirProvider        // buckets (and namespaces) of a cloud provider
+	dirNs              // buckets of a namespace
+)
+
+type bucketsDir struct {
+	cluster ais.Cluster
+	kind    int     // dirAIS, dirCloud, etc.
+	filter  cmn.Bck // provider and namespace of the listed buckets (dirProvider and dirNs)
+	cfg     *ServerConfig
+	logger  *log.Logger
+
+	mu         sync.Mutex
+	children   map[string]fuseops.InodeID // entries with known inodes
+	namespaces map[string]*namespace      // namespaces of looked up buckets
+}
+
+func newBucketsDir(cluster ais.Cluster, kind int, filter cmn.Bck, cfg *ServerConfig, logger *log.Logger) *bucketsDir {
+	return &bucketsDir{
+		cluster:    cluster,
+		kind:       kind,
+		filter:     filter,
+		cfg:        cfg,
+		logger:     logger,
+		children:   make(map[string]fuseops.InodeID),
+		namespaces: make(map[string]*namespace),
+	}
+}
+
+func NewBucketsDirInode(id fuseops.InodeID, attrs fuseops.InodeAttributes, name string, parent *DirectoryInode, buckets *bucketsDir) Inode {
+	return &DirectoryInode{
+		baseInode: newBaseInode(id, attrs, rootPath),
+		parent:    parent,
+		buckets:   buckets,
+		name:      name,
+	}
+}
+
+// Name of the inode's entry in its parent directory.
+func entryName(inode Inode) string {
+	if dir, ok := inode.(*DirectoryInode); ok && dir.name != "" {
+		return dir.name
+	}
+	return path.Base(inode.Path())
+}
+
+// Names of the entries listed by the directory: buckets are returned along
+// with the names, while the rest of the entries are subdirectories (see subdir).
+func (b *bucketsDir) names() (names []string, buckets map[string]cmn.Bck, err error) {
+	aisBuckets, cloudBuckets, err := b.cluster.ListBuckets()
+	if err != nil {
+		return nil, nil, err
+	}
+	buckets = make(map[string]cmn.Bck, len(aisBuckets)+len(cloudBuckets))
+	switch b.kind {
+	case dirAIS:
+		for _, name := range aisBuckets {
+			buckets[name] = cmn.Bck{Name: name, Provider: cmn.ProviderAIS}
+			names = append(names, name)
+		}
+		if len(cloudBuckets) > 0 {
+			names = append(names, cloudDirName)
+		}
+	case dirCloud:
+		for _, bck := range cloudBuckets {
+			if !cmn.StringInSlice(bck.Provider, names) {
+				names = append(names, bck.Provider)
+			}
+		}
+	default:
+		for _, bck := range cloudBuckets {
+			if bck.Provider != b.filter.Provider {
+				continue
+			}
+			if b.kind == dirProvider && !bck.Ns.IsGlobal() {
+				if name := nsDirPrefix + bck.Ns.Uname(); !cmn.StringInSlice(name, names) {
+					names = append(names, name)
+				}
+				continue
+			}
+			if bck.Ns != b.filter.Ns {
+				continue
+			}
+			buckets[bck.Name] = bck
+			names = append(names, bck.Name)
+		}
+	}
+	return names, buckets, nil
+}
+
+// Returns the directory listing buckets, providers, or namespaces that is
+// the (non-bucket) entry of this directory.
+func (b *bucketsDir) subdir(name string) *bucketsDir {
+	var (
+		kind   int
+		filter cmn.Bck
+	)
+	switch b.kind {
+	case dirAIS:
+		kind = dirCloud
+	case dirCloud:
+		kind, filter = dirProvider, cmn.Bck{Provider: name}
+	case dirProvider:
+		kind = dirNs
+		filter = cmn.Bck{Provider: b.filter.Provider, Ns: cmn.ParseNsUname(strings.TrimPrefix(name, nsDirPrefix))}
+	default:
+		cmn.AssertMsg(false, name)
+	}
+	return newBucketsDir(b.cluster, kind, filter, b.cfg, b.logger)
+}
+
+func (b *bucketsDir) readEntries() ([]fuseutil.Dirent, error) {
+	names, _, err := b.names()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	b.mu.Lock()
+	entries := make([]fuseutil.Dirent, 0, len(names))
+	for idx, name := range names {
+		id, ok := b.children[name]
+		if !ok {
+			id = invalidInodeID
+		}
+		entries = append(entries, fuseutil.Dirent{
+			Inode:  id,
+			Offset: fuseops.DirOffset(idx + 1),
+			Name:   name,
+			Type:   fuseutil.DT_Directory,
+		})
+	}
+	b.mu.Unlock()
+	return entries, nil
+}
+
+func (b *bucketsDir) lookup(name string) (res EntryLookupResult) {
+	b.mu.Lock()
+	id, ok := b.children[name]
+	b.mu.Unlock()
+	if !ok {
+		names, buckets, err := b.names()
+		if err != nil {
+			b.logger.Printf("failed to list buckets, err: %v", err)
+			return
+		}
+		if !cmn.StringInSlice(name, names) {
+			return
+		}
+		if bck, ok := buckets[name]; ok {
+			// Read the namespace of the bucket before it is needed (outside
+			// of any inode and file system locks).
+			if _, err := b.namespace(bck, false /*created*/); err != nil {
+				b.logger.Printf("failed to read bucket %q, err: %v", name, err)
+				return
+			}
+		}
+		id = invalidInodeID
+	}
+	res.Entry = &fuseutil.Dirent{
+		Inode: id,
+		Name:  name,
+		Type:  fuseutil.DT_Directory,
+	}
+	return
+}
+
+// Returns the namespace of the bucket, creating it if needed.
+func (b *bucketsDir) namespace(bck cmn.Bck, created bool) (*namespace, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ns, ok := b.namespaces[bck.Name]; ok {
+		return ns, nil
+	}
+	ns, err := newNamespace(b.cluster.Bucket(bck), b.logger, b.cfg)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		// The bucket is empty, no need to ever check with AIS for objects
+		// that are not in the cache.
+		ns.cacheHasAllObjects.Store(true)
+	}
+	b.namespaces[bck.Name] = ns
+	return ns, nil
+}
+
+// Creates the inode of an entry previously returned by lookup.
+func (b *bucketsDir) newChild(id fuseops.InodeID, attrs fuseops.InodeAttributes, parent *DirectoryInode, name string) Inode {
+	b.mu.Lock()
+	ns, ok := b.namespaces[name]
+	b.mu.Unlock()
+	if !ok {
+		return NewBucketsDirInode(id, attrs, name, parent, b.subdir(name))
+	}
+	dir := NewDirectoryInode(id, attrs, rootPath, parent, ns).(*DirectoryInode)
+	dir.name = name
+	return dir
+}
+
+func (b *bucketsDir) newEntry(name string, id fuseops.InodeID) {
+	b.mu.Lock()
+	b.children[name] = id
+	b.mu.Unlock()
+}
+
+// Forgets the inode of the entry. If the bucket was destroyed, its namespace
+// is dropped as well.
+func (b *bucketsDir) forgetEntry(name string, destroyed bool) {
+	b.mu.Lock()
+	delete(b.children, name)
+	if ns, ok := b.namespaces[name]; ok && destroyed {
+		ns.stop()
+		delete(b.namespaces, name)
+	}
+	b.mu.Unlock()
+}
+
+func (b *bucketsDir) createBucket(name string) error {
+	if b.kind != dirAIS {
+		return syscall.EPERM
+	}
+	if err := cmn.ValidateBckName(name); err != nil {
+		return syscall.EINVAL
+	}
+	if err := b.cluster.CreateBucket(name); err != nil {
+		return err
+	}
+	_, err := b.namespace(cmn.Bck{Name: name, Provider: cmn.ProviderAIS}, true /*created*/)
+	return err
+}
+
+func (b *bucketsDir) destroyBucket(name string) error {
+	if b.kind != dirAIS || name == cloudDirName {
+		return syscall.EPERM
+	}
+	return b.cluster.DestroyBucket(name)
+}
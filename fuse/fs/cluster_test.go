@@ -0,0 +1,131 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"io/ioutil"
+	"log"
+	"syscall"
+
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fuse/ais"
+	"github.com/jacobsa/fuse/fuseops"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type clusterMock struct {
+	aisBuckets   []string
+	cloudBuckets []cmn.Bck
+}
+
+var _ ais.Cluster = &clusterMock{}
+
+func (cm *clusterMock) APIParams() api.BaseParams   { return api.BaseParams{} }
+func (cm *clusterMock) Bucket(_ cmn.Bck) ais.Bucket { return newBucketMock() }
+func (cm *clusterMock) ListBuckets() (aisBuckets []string, cloudBuckets []cmn.Bck, err error) {
+	return cm.aisBuckets, cm.cloudBuckets, nil
+}
+func (cm *clusterMock) CreateBucket(name string) error {
+	cm.aisBuckets = append(cm.aisBuckets, name)
+	return nil
+}
+func (cm *clusterMock) DestroyBucket(name string) error {
+	for idx, bck := range cm.aisBuckets {
+		if bck == name {
+			cm.aisBuckets = append(cm.aisBuckets[:idx], cm.aisBuckets[idx+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+var _ = Describe("BucketsDir", func() {
+	var (
+		cluster *clusterMock
+		buckets *bucketsDir
+	)
+
+	BeforeEach(func() {
+		cluster = &clusterMock{aisBuckets: []string{"b", "a"}}
+		buckets = newBucketsDir(cluster, dirAIS, cmn.Bck{}, &ServerConfig{}, log.New(ioutil.Discard, "", 0))
+	})
+
+	entryNames := func(b *bucketsDir) (names []string) {
+		entries, err := b.readEntries()
+		Expect(err).NotTo(HaveOccurred())
+		for _, entry := range entries {
+			names = append(names, entry.Name)
+		}
+		return names
+	}
+
+	It("should list ais buckets", func() {
+		Expect(entryNames(buckets)).To(Equal([]string{"a", "b"}))
+
+		cluster.cloudBuckets = []cmn.Bck{{Name: "c", Provider: cmn.ProviderAmazon}}
+		Expect(entryNames(buckets)).To(Equal([]string{cloudDirName, "a", "b"}))
+	})
+
+	It("should list cloud buckets by provider and namespace", func() {
+		ns := cmn.Ns{UUID: "uuid", Name: "ns"}
+		cluster.cloudBuckets = []cmn.Bck{
+			{Name: "d", Provider: cmn.ProviderAmazon},
+			{Name: "c", Provider: cmn.ProviderAmazon},
+			{Name: "r", Provider: cmn.ProviderAIS, Ns: ns},
+		}
+		cloud := buckets.subdir(cloudDirName)
+		Expect(entryNames(cloud)).To(Equal([]string{cmn.ProviderAIS, cmn.ProviderAmazon}))
+
+		aws := cloud.subdir(cmn.ProviderAmazon)
+		Expect(entryNames(aws)).To(Equal([]string{"c", "d"}))
+		Expect(aws.lookup("c").NoEntry()).To(BeFalse())
+		Expect(aws.namespaces).To(HaveKey("c"))
+
+		remote := cloud.subdir(cmn.ProviderAIS)
+		nsDir := nsDirPrefix + ns.Uname()
+		Expect(entryNames(remote)).To(Equal([]string{nsDir}))
+		Expect(remote.lookup(nsDir).NoEntry()).To(BeFalse())
+		Expect(remote.namespaces).To(BeEmpty())
+
+		remoteNs := remote.subdir(nsDir)
+		Expect(remoteNs.filter).To(Equal(cmn.Bck{Provider: cmn.ProviderAIS, Ns: ns}))
+		Expect(entryNames(remoteNs)).To(Equal([]string{"r"}))
+		Expect(remoteNs.lookup("c").NoEntry()).To(BeTrue())
+	})
+
+	It("should look up buckets", func() {
+		res := buckets.lookup("a")
+		Expect(res.NoEntry()).To(BeFalse())
+		Expect(res.IsDir()).To(BeTrue())
+		Expect(res.NoInode()).To(BeTrue())
+		Expect(buckets.namespaces).To(HaveKey("a"))
+
+		Expect(buckets.lookup("c").NoEntry()).To(BeTrue())
+
+		id := fuseops.InodeID(fuseops.RootInodeID + 10)
+		buckets.newEntry("a", id)
+		Expect(buckets.lookup("a").Entry.Inode).To(Equal(id))
+	})
+
+	It("should create and destroy buckets", func() {
+		Expect(buckets.createBucket("c")).To(Succeed())
+		Expect(buckets.namespaces).To(HaveKey("c"))
+		Expect(entryNames(buckets)).To(ContainElement("c"))
+
+		Expect(buckets.destroyBucket("c")).To(Succeed())
+		buckets.forgetEntry("c", true /*destroyed*/)
+		Expect(buckets.namespaces).NotTo(HaveKey("c"))
+		Expect(entryNames(buckets)).NotTo(ContainElement("c"))
+
+		Expect(buckets.createBucket("a/b")).To(Equal(syscall.EINVAL))
+		Expect(buckets.destroyBucket(cloudDirName)).To(Equal(syscall.EPERM))
+
+		cloud := buckets.subdir(cloudDirName).subdir(cmn.ProviderAmazon)
+		Expect(cloud.createBucket("d")).To(Equal(syscall.EPERM))
+		Expect(cloud.destroyBucket("d")).To(Equal(syscall.EPERM))
+	})
+})
@@ -39,8 +39,6 @@ const (
 )
 
 var (
-	ns *namespace // Namespace for files and directories.
-
 	glMem2 *memsys.MMSA // Global memory manager
 )
 
@@ -55,7 +53,7 @@ type (
 
 		// Cluster
 		AISURL     string
-		BucketName string
+		BucketName string // empty - mount the whole cluster (see bucketsDir)
 
 		// Access
 		Owner *Owner
@@ -66,6 +64,15 @@ type (
 		SyncInterval    atomic.Duration
 		MemoryLimit     atomic.Uint64
 		MaxWriteBufSize atomic.Int64
+
+		// Write-back cache (disabled if the directory is not set)
+		WriteCacheDir  string
+		WriteCacheSize atomic.Int64 // 0 - unlimited
+
+		// Read cache (disabled if the directory is not set)
+		ReadCacheDir  string
+		ReadCacheSize atomic.Int64 // 0 - unlimited
+		ReadAhead     atomic.Int64 // number of blocks
 	}
 
 	// File system implementation.
@@ -92,6 +99,11 @@ type (
 		fileHandles  map[fuseops.HandleID]*fileHandle
 		lastHandleID atomic.Uint64
 
+		// Write-back cache (nil if disabled)
+		wcache *writeCache
+		// Read cache (nil if disabled)
+		rcache *readCache
+
 		// Access
 		modeBits *ModeBits
 
@@ -140,25 +152,36 @@ func NewAISFileSystemServer(cfg *ServerConfig, errLog *log.Logger) (srv fuse.Ser
 		errLog: errLog,
 	}
 
-	// Create a bucket.
-	apiParams := aisfs.aisAPIParams()
-	bucket := ais.NewBucket(cfg.BucketName, apiParams)
+	if aisfs.wcache, err = newWriteCache(cfg); err != nil {
+		return nil, err
+	}
+	if aisfs.rcache, err = newReadCache(cfg); err != nil {
+		return nil, err
+	}
 
 	// Create the root inode.
-	aisfs.root = NewDirectoryInode(
-		fuseops.RootInodeID,
-		aisfs.dirAttrs(aisfs.modeBits.Directory),
-		rootPath,
-		nil, /* parent */
-		bucket).(*DirectoryInode)
+	apiParams := aisfs.aisAPIParams()
+	rootAttrs := aisfs.dirAttrs(aisfs.modeBits.Directory)
+	if cfg.BucketName == "" {
+		// The whole cluster: the root lists ais buckets.
+		buckets := newBucketsDir(ais.NewCluster(apiParams), dirAIS, cmn.Bck{}, aisfs.cfg, aisfs.errLog)
+		aisfs.root = NewBucketsDirInode(fuseops.RootInodeID, rootAttrs, rootPath, nil /*parent*/, buckets).(*DirectoryInode)
+	} else {
+		bucket := ais.NewBucket(cmn.Bck{Name: cfg.BucketName}, apiParams)
+		ns, err := newNamespace(bucket, aisfs.errLog, aisfs.cfg)
+		if err != nil {
+			return nil, err
+		}
+		aisfs.root = NewDirectoryInode(
+			fuseops.RootInodeID,
+			rootAttrs,
+			rootPath,
+			nil, /* parent */
+			ns).(*DirectoryInode)
+	}
 
 	aisfs.root.IncLookupCount()
 	aisfs.inodeTable[fuseops.RootInodeID] = aisfs.root
-
-	ns, err = newNamespace(bucket, aisfs.errLog, aisfs.cfg)
-	if err != nil {
-		return nil, err
-	}
 	return fuseutil.NewFileSystemServer(aisfs), nil
 }
 
@@ -213,7 +236,7 @@ func (fs *aisfs) dirAttrs(mode os.FileMode) fuseops.InodeAttributes {
 func (fs *aisfs) allocateFileHandle(file *FileInode) fuseops.HandleID {
 	id := fs.nextHandleID()
 	file.RLock()
-	fs.fileHandles[id] = newFileHandle(id, file)
+	fs.fileHandles[id] = newFileHandle(id, file, fs.wcache, fs.rcache)
 	file.RUnlock()
 	return id
 }
@@ -266,10 +289,16 @@ func (fs *aisfs) createFileInode(inodeID fuseops.InodeID, parent *DirectoryInode
 
 // REQUIRES_LOCK(fs.mu)
 func (fs *aisfs) createDirectoryInode(inodeID fuseops.InodeID, parent *DirectoryInode, entryName string, mode os.FileMode) Inode {
-	attrs := fs.dirAttrs(mode)
-	fspath := path.Join(parent.Path(), entryName) + separator
-	bucket := ais.NewBucket(fs.cfg.BucketName, fs.aisAPIParams())
-	inode := NewDirectoryInode(inodeID, attrs, fspath, parent, bucket)
+	var (
+		attrs = fs.dirAttrs(mode)
+		inode Inode
+	)
+	if parent.buckets != nil {
+		inode = parent.buckets.newChild(inodeID, attrs, parent, entryName)
+	} else {
+		fspath := path.Join(parent.Path(), entryName) + separator
+		inode = NewDirectoryInode(inodeID, attrs, fspath, parent, parent.ns)
+	}
 	fs.inodeTable[inodeID] = inode
 	return inode
 }
@@ -294,7 +323,15 @@ func (fs *aisfs) SetInodeAttributes(ctx context.Context, req *fuseops.SetInodeAt
 	inode := fs.lookupMustExist(req.Inode)
 	fs.mu.RUnlock()
 
+	var truncated *FileInode
 	inode.Lock()
+	if req.Size != nil && fs.wcache != nil && !inode.IsDir() {
+		truncated = inode.(*FileInode)
+		if err = fs.truncateFile(truncated, int64(*req.Size)); err != nil {
+			inode.Unlock()
+			return fs.handleIOError(err)
+		}
+	}
 	updReq := &AttrUpdateReq{
 		Mode:  req.Mode,
 		Size:  req.Size,
@@ -303,9 +340,25 @@ func (fs *aisfs) SetInodeAttributes(ctx context.Context, req *fuseops.SetInodeAt
 	}
 	req.Attributes = inode.UpdateAttributes(updReq)
 	inode.Unlock()
+
+	// Unless the file is open, the result of truncation is uploaded right away.
+	if truncated != nil && truncated.handles.Load() == 0 {
+		if err = truncated.SyncAndDropCache(); err != nil {
+			return fs.handleIOError(err)
+		}
+	}
 	return
 }
 
+// Truncates the file via write-back cache.
+// REQUIRES_LOCK(file)
+func (fs *aisfs) truncateFile(file *FileInode, size int64) (err error) {
+	if file.cache == nil && file.Size() == uint64(size) {
+		return nil
+	}
+	return file.Truncate(fs.wcache, size)
+}
+
 func (fs *aisfs) LookUpInode(ctx context.Context, req *fuseops.LookUpInodeOp) (err error) {
 	var inode Inode
 
@@ -374,9 +427,8 @@ func (fs *aisfs) ForgetInode(ctx context.Context, req *fuseops.ForgetInodeOp) (e
 		fs.mu.Unlock()
 
 		// Remove entryName to inode ID mapping in parent.
-		name := path.Base(inode.Path())
 		parent.Lock()
-		parent.InvalidateInode(name, inode.IsDir())
+		parent.InvalidateInode(entryName(inode), inode.IsDir())
 		parent.Unlock()
 
 		// Any future cleanup related to inode goes here.
@@ -25,15 +25,25 @@ type DirectoryInode struct {
 
 	parent *DirectoryInode
 	bucket ais.Bucket
+	ns     *namespace // namespace of the bucket
+
+	// Set only if the directory lists buckets instead of objects
+	// (cluster mount, see bucketsDir).
+	buckets *bucketsDir
+	// Name of the entry in the parent directory. Set only for directories
+	// that do not map to a path in a bucket (roots of the buckets and
+	// directories listing buckets) - see entryName.
+	name string
 
 	entries []fuseutil.Dirent
 }
 
-func NewDirectoryInode(id fuseops.InodeID, attrs fuseops.InodeAttributes, path string, parent *DirectoryInode, bucket ais.Bucket) Inode {
+func NewDirectoryInode(id fuseops.InodeID, attrs fuseops.InodeAttributes, path string, parent *DirectoryInode, ns *namespace) Inode {
 	return &DirectoryInode{
 		baseInode: newBaseInode(id, attrs, path),
 		parent:    parent,
-		bucket:    bucket,
+		bucket:    ns.bck,
+		ns:        ns,
 	}
 }
 
@@ -45,6 +55,12 @@ func (dir *DirectoryInode) IsDir() bool {
 	return true
 }
 
+// REQUIRES_LOCK(dir)
+func (dir *DirectoryInode) Move(newParent *DirectoryInode, newPath string) {
+	dir.parent = newParent
+	dir.SetPath(newPath)
+}
+
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) UpdateAttributes(req *AttrUpdateReq) fuseops.InodeAttributes {
 	attrs := dir.Attributes()
@@ -59,7 +75,7 @@ func (dir *DirectoryInode) UpdateAttributes(req *AttrUpdateReq) fuseops.InodeAtt
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) NewFileEntry(entryName string, id fuseops.InodeID, object *ais.Object) {
 	entryName = path.Join(dir.Path(), entryName)
-	ns.add(entryFileTy, dtAttrs{id: id, path: entryName, obj: object})
+	dir.ns.add(entryFileTy, dtAttrs{id: id, path: entryName, obj: object})
 
 	// TODO: improve caching entries for `ReadEntries`
 	dir.entries = nil
@@ -68,7 +84,7 @@ func (dir *DirectoryInode) NewFileEntry(entryName string, id fuseops.InodeID, ob
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) ForgetFile(entryName string) {
 	entryName = path.Join(dir.Path(), entryName)
-	ns.remove(entryName)
+	dir.ns.remove(entryName)
 
 	// TODO: improve caching entries for `ReadEntries`
 	dir.entries = nil
@@ -76,8 +92,13 @@ func (dir *DirectoryInode) ForgetFile(entryName string) {
 
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) NewDirEntry(entryName string, id fuseops.InodeID) {
+	if dir.buckets != nil {
+		dir.buckets.newEntry(entryName, id)
+		dir.entries = nil
+		return
+	}
 	entryName = path.Join(dir.Path(), entryName) + separator
-	ns.add(entryDirTy, dtAttrs{id: id, path: entryName})
+	dir.ns.add(entryDirTy, dtAttrs{id: id, path: entryName})
 
 	// TODO: improve caching entries for `ReadEntries`
 	dir.entries = nil
@@ -85,25 +106,40 @@ func (dir *DirectoryInode) NewDirEntry(entryName string, id fuseops.InodeID) {
 
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) ForgetDir(entryName string) {
+	if dir.buckets != nil {
+		dir.buckets.forgetEntry(entryName, true /*destroyed*/)
+		dir.entries = nil
+		return
+	}
 	entryName = path.Join(dir.Path(), entryName) + separator
-	ns.remove(entryName)
+	dir.ns.remove(entryName)
 
 	// TODO: improve caching entries for `ReadEntries`
 	dir.entries = nil
 }
 
+// REQUIRES_LOCK(dir)
+func (dir *DirectoryInode) InvalidateEntries() {
+	// TODO: improve caching entries for `ReadEntries`
+	dir.entries = nil
+}
+
 func (dir *DirectoryInode) InvalidateInode(entryName string, isDir bool) {
+	if dir.buckets != nil {
+		dir.buckets.forgetEntry(entryName, false /*destroyed*/)
+		return
+	}
 	entryName = path.Join(dir.Path(), entryName)
 	ty := entryFileTy
 	if isDir {
 		entryName += separator
 		ty = entryDirTy
 	}
-	_, exists := ns.lookup(entryName)
+	_, exists := dir.ns.lookup(entryName)
 	if !exists {
 		return
 	}
-	ns.add(ty, dtAttrs{id: invalidInodeID, path: entryName})
+	dir.ns.add(ty, dtAttrs{id: invalidInodeID, path: entryName})
 }
 
 func (dir *DirectoryInode) LinkNewFile(fileName string) (*ais.Object, error) {
@@ -117,8 +153,15 @@ func (dir *DirectoryInode) LinkNewFile(fileName string) (*ais.Object, error) {
 
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) ReadEntries() (entries []fuseutil.Dirent, err error) {
+	if dir.buckets != nil {
+		if dir.entries == nil {
+			dir.entries, err = dir.buckets.readEntries()
+		}
+		return dir.entries, err
+	}
+
 	// Traverse files and subdirectories of dir read from the bucket.
-	_, exists := ns.lookup(dir.Path())
+	_, exists := dir.ns.lookup(dir.Path())
 	if !exists {
 		return nil, fuse.ENOENT
 	}
@@ -128,7 +171,7 @@ func (dir *DirectoryInode) ReadEntries() (entries []fuseutil.Dirent, err error)
 	}
 
 	var offset fuseops.DirOffset = 1
-	ns.listEntries(dir.Path(), func(child nsEntry) {
+	dir.ns.listEntries(dir.Path(), func(child nsEntry) {
 		dir.entries = append(dir.entries, fuseutil.Dirent{
 			Inode:  child.ID(),
 			Offset: offset,
@@ -154,6 +197,10 @@ func (dir *DirectoryInode) UnlinkEntry(entryName string) error {
 }
 
 func (dir *DirectoryInode) LookupEntry(entryName string) (res EntryLookupResult) {
+	if dir.buckets != nil {
+		return dir.buckets.lookup(entryName)
+	}
+
 	var (
 		exists       bool
 		objEntryName = path.Join(dir.Path(), entryName)
@@ -161,11 +208,11 @@ func (dir *DirectoryInode) LookupEntry(entryName string) (res EntryLookupResult)
 	)
 
 	// First check for directories
-	res, exists = ns.lookup(dirEntryName)
+	res, exists = dir.ns.lookup(dirEntryName)
 	if exists {
 		return res
 	}
 
-	res, _ = ns.lookup(objEntryName)
+	res, _ = dir.ns.lookup(objEntryName)
 	return res
 }
@@ -22,6 +22,10 @@ func (fs *aisfs) ReadDir(ctx context.Context, req *fuseops.ReadDirOp) (err error
 	fs.mu.RUnlock()
 
 	dir.Lock()
+	if req.Offset == 0 && dir.buckets != nil {
+		// Buckets may have been created or destroyed by other clients.
+		dir.InvalidateEntries()
+	}
 	entries, err := dir.ReadEntries()
 	dir.Unlock()
 	if err != nil {
@@ -60,6 +64,11 @@ func (fs *aisfs) MkDir(ctx context.Context, req *fuseops.MkDirOp) (err error) {
 	if !result.NoEntry() {
 		return fuse.EEXIST
 	}
+	if parent.buckets != nil {
+		if err = parent.buckets.createBucket(req.Name); err != nil {
+			return fs.handleIOError(err)
+		}
+	}
 
 	fs.mu.Lock()
 	inodeID := fs.nextInodeID()
@@ -110,6 +119,11 @@ func (fs *aisfs) RmDir(ctx context.Context, req *fuseops.RmDirOp) (err error) {
 	if len(entries) > 0 {
 		return fuse.ENOTEMPTY
 	}
+	if parent.buckets != nil {
+		if err = parent.buckets.destroyBucket(req.Name); err != nil {
+			return fs.handleIOError(err)
+		}
+	}
 	parent.ForgetDir(req.Name)
 	return
 }
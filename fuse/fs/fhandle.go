@@ -21,11 +21,17 @@ type fileHandle struct {
 	file     *FileInode
 	fileSize int64
 
+	// Write-back cache (nil if disabled)
+	wcache *writeCache
+	// Read cache (nil if disabled)
+	rcache *readCache
+
 	// Guard
 	mu sync.Mutex
 
 	// Reading
 	readBuffer *blockBuffer
+	lastBlock  int64 // number of the block read most recently (for read-ahead)
 
 	// Writing
 	writeBuffer  *writeBuffer
@@ -35,12 +41,26 @@ type fileHandle struct {
 }
 
 // REQUIRES_READ_LOCK(file)
-func newFileHandle(id fuseops.HandleID, file *FileInode) *fileHandle {
+func newFileHandle(id fuseops.HandleID, file *FileInode, wcache *writeCache, rcache *readCache) *fileHandle {
+	file.handles.Inc()
 	return &fileHandle{
-		id:       id,
-		file:     file,
-		fileSize: int64(file.Size()),
+		id:        id,
+		file:      file,
+		fileSize:  int64(file.Size()),
+		wcache:    wcache,
+		rcache:    rcache,
+		lastBlock: -1,
+	}
+}
+
+// Uploads and removes the local copy of the file (if any) when the last
+// handle of the file is released. The local copy is kept if the upload fails.
+// LOCKS(fh.file)
+func (fh *fileHandle) release() (err error) {
+	if fh.file.handles.Dec() == 0 {
+		err = fh.file.SyncAndDropCache()
 	}
+	return
 }
 
 // REQUIRES_LOCK(fh.mu)
@@ -78,6 +98,15 @@ func (fh *fileHandle) ensureReadBuffer() int64 {
 
 // LOCKS(fh.mu)
 func (fh *fileHandle) readChunk(dst []byte, offset int64) (n int, err error) {
+	// The file is being modified locally: read the local copy.
+	fh.file.RLock()
+	if cache := fh.file.cache; cache != nil {
+		n, err = cache.readAt(dst, offset)
+		fh.file.RUnlock()
+		return
+	}
+	fh.file.RUnlock()
+
 	if offset >= fh.fileSize {
 		return 0, io.EOF
 	}
@@ -90,16 +119,26 @@ func (fh *fileHandle) readChunk(dst []byte, offset int64) (n int, err error) {
 	blockSize := fh.ensureReadBuffer()
 	dstLen := len(dst)
 
+	loadBlock := fh.file.Load
+	if fh.rcache != nil {
+		loadBlock = func(w io.Writer, offset, length int64) (int64, error) {
+			return fh.file.LoadCached(fh.rcache, w, offset, length)
+		}
+	}
+
 	for {
 		blockNo := offset / blockSize
 		blockOffset := offset % blockSize
 
-		err = fh.readBuffer.EnsureBlock(blockNo, fh.file.Load)
+		err = fh.readBuffer.EnsureBlock(blockNo, loadBlock)
 		if err != nil {
 			// In case of error is encountered while loading a block,
 			// return the number of bytes read so far.
 			break
 		}
+		if blockNo != fh.lastBlock {
+			fh.readAhead(blockNo, blockSize)
+		}
 
 		var nread int
 		nread, err = fh.readBuffer.ReadAt(dst[n:], blockOffset)
@@ -135,6 +174,23 @@ func (fh *fileHandle) readChunk(dst []byte, offset int64) (n int, err error) {
 	return
 }
 
+// Prefetches the blocks following the current one if the file is being
+// read sequentially.
+// REQUIRES_LOCK(fh.mu)
+func (fh *fileHandle) readAhead(blockNo, blockSize int64) {
+	sequential := blockNo == fh.lastBlock+1
+	fh.lastBlock = blockNo
+	if fh.rcache == nil || !sequential {
+		return
+	}
+	count := fh.rcache.cfg.ReadAhead.Load()
+	fh.file.RLock()
+	for next := blockNo + 1; next <= blockNo+count && next*blockSize < fh.fileSize; next++ {
+		fh.file.Prefetch(fh.rcache, next*blockSize, blockSize)
+	}
+	fh.file.RUnlock()
+}
+
 /////////////
 // WRITING //
 /////////////
@@ -169,8 +225,15 @@ func (fh *fileHandle) writeChunk(data []byte, offset uint64, maxWriteBufSize int
 	fh.mu.Lock()
 	defer fh.mu.Unlock()
 
+	if fh.wcache != nil {
+		fh.file.Lock()
+		err = fh.file.WriteAt(fh.wcache, data, int64(offset))
+		fh.file.Unlock()
+		return
+	}
+
 	if offset != fh.wsize {
-		return fmt.Errorf("write file (inode %d): random access write not yet implemented", fh.file.ID())
+		return fmt.Errorf("write file (inode %d): random access write requires write-back cache (see io.write_cache_dir)", fh.file.ID())
 	}
 
 	return fh._writeChunk(data, maxWriteBufSize, false /*force*/)
@@ -180,10 +243,24 @@ func (fh *fileHandle) writeChunk(data []byte, offset uint64, maxWriteBufSize int
 // READING AND WRITING //
 /////////////////////////
 
+// Uploads the local copy of the file (if modified). Appended data cannot be
+// synced before the handle is flushed.
+// LOCKS(fh.file)
+func (fh *fileHandle) sync() error {
+	if fh.wcache == nil {
+		return nil
+	}
+	return fh.file.SyncCache()
+}
+
 func (fh *fileHandle) flush() (err error) {
 	fh.mu.Lock()
 	defer fh.mu.Unlock()
 
+	if fh.wcache != nil {
+		return fh.file.SyncCache()
+	}
+
 	if !fh.dirty {
 		return nil
 	}
@@ -22,13 +22,48 @@ import (
 // OpenFile creates a file handle to be used in subsequent file operations
 // that provide a valid handle ID (also generated here).
 func (fs *aisfs) OpenFile(ctx context.Context, req *fuseops.OpenFileOp) (err error) {
-	fs.mu.Lock()
+	fs.mu.RLock()
 	file := fs.lookupFileMustExist(req.Inode)
+	fs.mu.RUnlock()
+
+	if fs.rcache != nil {
+		// Make sure that version and checksum of the object are up to date
+		// so that the cached blocks of its previous versions are not used.
+		if err = fs.refreshFile(file); err != nil {
+			return fs.handleIOError(err)
+		}
+	}
+
+	fs.mu.Lock()
 	req.Handle = fs.allocateFileHandle(file)
 	fs.mu.Unlock()
 	return
 }
 
+// LOCKS(file)
+func (fs *aisfs) refreshFile(file *FileInode) error {
+	file.RLock()
+	var (
+		bucket  = file.parent.bucket
+		objName = file.object.Name
+		cached  = file.cache != nil
+	)
+	file.RUnlock()
+	if cached {
+		return nil
+	}
+
+	obj, exists, err := bucket.HeadObject(objName)
+	if err != nil || !exists {
+		// The object may not have been flushed yet: keep it as is.
+		return err
+	}
+	file.Lock()
+	file.Refresh(obj)
+	file.Unlock()
+	return nil
+}
+
 func (fs *aisfs) CreateFile(ctx context.Context, req *fuseops.CreateFileOp) (err error) {
 	var newFile Inode
 
@@ -36,6 +71,11 @@ func (fs *aisfs) CreateFile(ctx context.Context, req *fuseops.CreateFileOp) (err
 	parent := fs.lookupDirMustExist(req.Parent)
 	fs.mu.RUnlock()
 
+	if parent.buckets != nil {
+		// Files can only be created in buckets (cluster mount).
+		return syscall.EPERM
+	}
+
 	fileName := path.Join(parent.Path(), req.Name)
 	object, err := parent.LinkNewFile(fileName)
 	if err != nil {
@@ -117,11 +157,31 @@ func (fs *aisfs) FlushFile(ctx context.Context, req *fuseops.FlushFileOp) (err e
 	return
 }
 
+func (fs *aisfs) SyncFile(ctx context.Context, req *fuseops.SyncFileOp) (err error) {
+	fs.mu.RLock()
+	handle := fs.lookupFhandleMustExist(req.Handle)
+	fs.mu.RUnlock()
+
+	if err = handle.sync(); err != nil {
+		return fs.handleIOError(err)
+	}
+	return
+}
+
 func (fs *aisfs) ReleaseFileHandle(ctx context.Context, req *fuseops.ReleaseFileHandleOp) (err error) {
+	fs.mu.RLock()
+	fhandle := fs.lookupFhandleMustExist(req.Handle)
+	fs.mu.RUnlock()
+
+	// Upload the local copy of the file, if any (inodes must be locked
+	// before the file system).
+	if err := fhandle.release(); err != nil {
+		fs.logf("failed to upload file (inode %d), keeping local copy: %v", fhandle.file.ID(), err)
+	}
+
 	fs.mu.Lock()
 
-	// Lookup and release the handle's resources.
-	fhandle := fs.lookupFhandleMustExist(req.Handle)
+	// Release the handle's resources.
 	fhandle.destroy()
 
 	// Remove the handle from the file handles table.
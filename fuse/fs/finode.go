@@ -5,9 +5,11 @@
 package fs
 
 import (
+	"fmt"
 	"io"
 	"time"
 
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fuse/ais"
 	"github.com/jacobsa/fuse/fuseops"
@@ -24,6 +26,11 @@ type FileInode struct {
 	// Object used by current inode. When possible it should be updated with
 	// newer version.
 	object ais.Object
+
+	// Local copy of the file if it is being modified via write-back cache.
+	cache *cachedFile
+	// Number of open file handles.
+	handles atomic.Int32
 }
 
 func NewFileInode(id fuseops.InodeID, attrs fuseops.InodeAttributes, parent *DirectoryInode, object *ais.Object) Inode {
@@ -42,6 +49,13 @@ func (file *FileInode) IsDir() bool {
 	return false
 }
 
+// REQUIRES_LOCK(file)
+func (file *FileInode) Move(newParent *DirectoryInode, newPath string) {
+	file.parent = newParent
+	file.SetPath(newPath)
+	file.object.Name = newPath
+}
+
 // REQUIRES_READ_LOCK(file)
 func (file *FileInode) Size() uint64 {
 	return file.attrs.Size
@@ -81,11 +95,21 @@ func (file *FileInode) UpdateAttributes(req *AttrUpdateReq) fuseops.InodeAttribu
 	return attrs
 }
 
+// REQUIRES_LOCK(file)
+func (file *FileInode) Destroy() (err error) {
+	// NOTE: the local copy is not uploaded here as the file may have been removed.
+	if file.cache != nil && file.cache.dirty {
+		err = fmt.Errorf("local modifications of %q were not uploaded and are lost", file.object.Name)
+	}
+	file.DropCache()
+	return
+}
+
 // REQUIRES_LOCK(file)
 func (file *FileInode) UpdateBackingObject(obj *ais.Object) {
 	cmn.Assert(obj != nil)
-	// Only update object if it is newer
-	if file.object.Atime.After(obj.Atime) {
+	// Only update object if it is newer and not being modified locally
+	if file.object.Atime.After(obj.Atime) || file.cache != nil {
 		return
 	}
 
@@ -103,7 +127,17 @@ func (file *FileInode) UpdateBackingObject(obj *ais.Object) {
 
 // REQUIRES_READ_LOCK(file)
 func (file *FileInode) Load(w io.Writer, offset int64, length int64) (n int64, err error) {
-	n, err = file.object.GetChunk(w, offset, length)
+	return file.LoadCached(nil, w, offset, length)
+}
+
+// LoadCached is Load that goes through the read cache (if not nil).
+// REQUIRES_READ_LOCK(file)
+func (file *FileInode) LoadCached(rc *readCache, w io.Writer, offset int64, length int64) (n int64, err error) {
+	if rc != nil {
+		n, err = rc.load(w, &file.object, offset, length)
+	} else {
+		n, err = file.object.GetChunk(w, offset, length)
+	}
 	if err != nil {
 		return 0, err
 	}
@@ -113,6 +147,34 @@ func (file *FileInode) Load(w io.Writer, offset int64, length int64) (n int64, e
 	return n, nil
 }
 
+// Prefetch loads the given range of the object into the read cache in the
+// background.
+// REQUIRES_READ_LOCK(file)
+func (file *FileInode) Prefetch(rc *readCache, offset int64, length int64) {
+	rc.prefetch(file.object, offset, length)
+}
+
+// Refresh replaces the object with the one returned by HEAD, so that version
+// and checksum (used by the read cache) are up to date.
+// REQUIRES_LOCK(file)
+func (file *FileInode) Refresh(obj *ais.Object) {
+	cmn.Assert(obj != nil)
+	if file.cache != nil {
+		return
+	}
+	size := uint64(obj.Size)
+	file.UpdateAttributes(&AttrUpdateReq{Size: &size})
+	file.object = *obj
+}
+
+// The object has been modified: until refreshed, its blocks can neither be
+// read from nor added to the read cache.
+// REQUIRES_LOCK(file)
+func (file *FileInode) resetVersion() {
+	file.object.Version = ""
+	file.object.Cksum = ""
+}
+
 /////////////
 // WRITING //
 /////////////
@@ -133,9 +195,118 @@ func (file *FileInode) Flush(handle string) error {
 	if err != nil {
 		return err
 	}
+	file.resetVersion()
 	now := time.Now()
 	file.object.Atime = now
 	file.attrs.Atime = now
 	file.attrs.Mtime = now
 	return nil
 }
+
+//////////////////////
+// WRITE-BACK CACHE //
+//////////////////////
+
+// REQUIRES_LOCK(file)
+func (file *FileInode) ensureCached(wc *writeCache, download bool) (err error) {
+	if file.cache == nil {
+		file.cache, err = wc.open(&file.object, download)
+	}
+	return
+}
+
+// REQUIRES_LOCK(file)
+func (file *FileInode) WriteAt(wc *writeCache, p []byte, offset int64) error {
+	if err := file.ensureCached(wc, true /*download*/); err != nil {
+		return err
+	}
+	if err := file.cache.writeAt(p, offset); err != nil {
+		return err
+	}
+	file.attrs.Size = uint64(file.cache.size)
+	file.attrs.Mtime = time.Now()
+	return nil
+}
+
+// REQUIRES_LOCK(file)
+func (file *FileInode) Truncate(wc *writeCache, size int64) error {
+	if err := file.ensureCached(wc, size > 0 /*download*/); err != nil {
+		return err
+	}
+	if err := file.cache.truncate(size); err != nil {
+		return err
+	}
+	file.attrs.Size = uint64(size)
+	file.attrs.Mtime = time.Now()
+	return nil
+}
+
+// SyncCache uploads the local copy of the file (if modified). The upload runs
+// without holding the lock (see cachedFile.snapshot).
+// LOCKS(file)
+func (file *FileInode) SyncCache() error {
+	file.RLock()
+	cf := file.cache
+	file.RUnlock()
+	if cf == nil {
+		return nil
+	}
+	cf.upMu.Lock()
+	defer cf.upMu.Unlock()
+
+	file.Lock()
+	if file.cache != cf {
+		file.Unlock()
+		return nil // dropped in the meantime
+	}
+	snap, err := cf.snapshot()
+	obj := file.object
+	file.Unlock()
+	if snap == nil || err != nil {
+		return err
+	}
+
+	err = snap.upload(&obj)
+	snap.f.Close()
+	if err != nil {
+		return err
+	}
+
+	file.Lock()
+	if file.object.Name == obj.Name { // not renamed in the meantime
+		cf.uploaded(snap)
+	}
+	file.resetVersion()
+	now := time.Now()
+	file.object.Size = snap.size
+	file.object.Atime = now
+	file.attrs.Atime = now
+	file.Unlock()
+	return nil
+}
+
+// SyncAndDropCache uploads the local copy of the file (if modified) and, if
+// succeeded, removes it unless the file has been opened or modified in the
+// meantime. Otherwise, the local copy is kept to retry later.
+// LOCKS(file)
+func (file *FileInode) SyncAndDropCache() error {
+	if err := file.SyncCache(); err != nil {
+		return err
+	}
+	file.Lock()
+	if file.cache != nil && !file.cache.dirty && file.handles.Load() == 0 {
+		file.DropCache()
+	}
+	file.Unlock()
+	return nil
+}
+
+// DropCache removes the local copy of the file (modifications that were not
+// uploaded are lost).
+// REQUIRES_LOCK(file)
+func (file *FileInode) DropCache() {
+	if file.cache != nil {
+		file.cache.close()
+		file.cache = nil
+	}
+}
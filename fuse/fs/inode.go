@@ -75,6 +75,12 @@ func (in *baseInode) Path() string {
 	return in.path
 }
 
+// SetPath changes the path of the inode (rename).
+// REQUIRES_LOCK(in)
+func (in *baseInode) SetPath(path string) {
+	in.path = path
+}
+
 // Attributes returns inode's attributes (mode, size, atime...).
 // REQUIRES_READ_LOCK(in)
 func (in *baseInode) Attributes() (attrs fuseops.InodeAttributes) {
@@ -63,6 +63,8 @@ type (
 		// In case we do have all objects in memory we can enable some of the
 		// performance improvements.
 		cacheHasAllObjects atomic.Bool
+
+		stopCh chan struct{} // stops syncing (see stop)
 	}
 )
 
@@ -85,9 +87,10 @@ func newNamespace(bck ais.Bucket, logger *log.Logger, cfg *ServerConfig) (*names
 	}
 
 	ns := &namespace{
-		bck:   bck,
-		cfg:   cfg,
-		cache: nsCache,
+		bck:    bck,
+		cfg:    cfg,
+		cache:  nsCache,
+		stopCh: make(chan struct{}),
 	}
 	ns.cacheHasAllObjects.Store(hasAllObjects)
 
@@ -103,7 +106,11 @@ func newNamespace(bck ais.Bucket, logger *log.Logger, cfg *ServerConfig) (*names
 				return
 			}
 
-			time.Sleep(interval)
+			select {
+			case <-time.After(interval):
+			case <-ns.stopCh:
+				return
+			}
 			logger.Printf("syncing with AIS...")
 			if hasAllObjects, err := ns.cache.refresh(); err != nil {
 				logger.Printf("failed to sync, err: %v", err)
@@ -116,6 +123,11 @@ func newNamespace(bck ais.Bucket, logger *log.Logger, cfg *ServerConfig) (*names
 	return ns, nil
 }
 
+// Stops syncing the namespace with AIS (e.g., the bucket was destroyed).
+func (ns *namespace) stop() {
+	close(ns.stopCh)
+}
+
 func (ns *namespace) add(ty entryType, dta dtAttrs) {
 	if !ns.cacheHasAllObjects.Load() {
 		// TODO: maybe we have enough memory to store this given entry - we should
@@ -130,6 +142,16 @@ func (ns *namespace) remove(p string) {
 	ns.cache.remove(p)
 }
 
+// Moves all entries with prefix `src` to prefix `dst` (directory rename).
+func (ns *namespace) move(src, dst string) {
+	if !ns.cacheHasAllObjects.Load() {
+		// Entries which are not cached will be looked up in the bucket.
+		ns.cache.remove(src)
+		return
+	}
+	ns.cache.move(src, dst)
+}
+
 func (ns *namespace) lookup(p string) (res EntryLookupResult, exists bool) {
 	if ns.cacheHasAllObjects.Load() {
 		res, _, exists = ns.cache.lookup(p)
@@ -0,0 +1,240 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"bytes"
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/fuse/ais"
+	"github.com/OneOfOne/xxhash"
+)
+
+// Theory of operation
+//
+// Read cache keeps blocks of objects (as read by file handles, see
+// fileHandle.readChunk) in a local directory, one file per block, so that
+// repeated reads of the same data (e.g., training epochs) do not go over HTTP.
+//
+// A block is identified by the hash of the bucket and object names, object's
+// version and checksum (as returned by HEAD when the file is opened), and
+// the block's offset and length. Thus, a modified object never hits stale
+// blocks - those simply age out. Objects that have neither version nor
+// checksum are not cached.
+//
+// The total size of the cache is limited by `ServerConfig.ReadCacheSize` and
+// the least recently used blocks get evicted. The blocks survive remounts:
+// the cache directory is scanned at mount time.
+//
+// Additionally, when a file is read sequentially, the following
+// `ServerConfig.ReadAhead` blocks get prefetched into the cache.
+
+const tmpBlockSuffix = ".tmp"
+
+var errCacheMiss = errors.New("cache miss")
+
+type (
+	readCache struct {
+		cfg *ServerConfig
+
+		mu       sync.Mutex
+		lru      *list.List               // front - most recently used
+		blocks   map[string]*list.Element // block key => element of lru
+		inflight map[string]struct{}      // blocks being prefetched
+		used     int64
+	}
+
+	cachedBlock struct {
+		key  string
+		size int64
+	}
+)
+
+func newReadCache(cfg *ServerConfig) (*readCache, error) {
+	if cfg.ReadCacheDir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(cfg.ReadCacheDir, 0700); err != nil {
+		return nil, err
+	}
+	rc := &readCache{
+		cfg:      cfg,
+		lru:      list.New(),
+		blocks:   make(map[string]*list.Element),
+		inflight: make(map[string]struct{}),
+	}
+	if err := rc.scan(); err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
+
+// scan adds blocks cached by previous mounts (the least recently used first).
+func (rc *readCache) scan() error {
+	type block struct {
+		cachedBlock
+		mtime time.Time
+	}
+	var found []block
+	err := filepath.Walk(rc.cfg.ReadCacheDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		if strings.HasSuffix(path, tmpBlockSuffix) {
+			os.Remove(path)
+			return nil
+		}
+		found = append(found, block{cachedBlock{key: info.Name(), size: info.Size()}, info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].mtime.Before(found[j].mtime) })
+	rc.mu.Lock()
+	for _, b := range found {
+		rc.blocks[b.key] = rc.lru.PushFront(&cachedBlock{key: b.key, size: b.size})
+		rc.used += b.size
+	}
+	rc.evict()
+	rc.mu.Unlock()
+	return nil
+}
+
+func blockKey(obj *ais.Object, offset, length int64) string {
+	if obj.Version == "" && obj.Cksum == "" {
+		return ""
+	}
+	s := fmt.Sprintf("%s/%s@%s:%s#%d+%d", obj.Bck(), obj.Name, obj.Version, obj.Cksum, offset, length)
+	return fmt.Sprintf("%016x", xxhash.ChecksumString64(s))
+}
+
+func (rc *readCache) path(key string) string {
+	return filepath.Join(rc.cfg.ReadCacheDir, key[:2], key)
+}
+
+// load writes the requested block of the object to `w`, reading it from the
+// cache or, on miss, from the cluster (and caching it).
+func (rc *readCache) load(w io.Writer, obj *ais.Object, offset, length int64) (n int64, err error) {
+	key := blockKey(obj, offset, length)
+	if key == "" {
+		return obj.GetChunk(w, offset, length)
+	}
+	if n, err = rc.read(w, key); err != errCacheMiss {
+		return n, err
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, length))
+	if _, err = obj.GetChunk(buf, offset, length); err != nil {
+		return 0, err
+	}
+	rc.store(key, buf.Bytes())
+	return io.Copy(w, buf)
+}
+
+// prefetch loads the block into the cache in the background.
+func (rc *readCache) prefetch(obj ais.Object, offset, length int64) {
+	key := blockKey(&obj, offset, length)
+	if key == "" {
+		return
+	}
+	rc.mu.Lock()
+	_, cached := rc.blocks[key]
+	_, loading := rc.inflight[key]
+	if cached || loading {
+		rc.mu.Unlock()
+		return
+	}
+	rc.inflight[key] = struct{}{}
+	rc.mu.Unlock()
+
+	go func() {
+		buf := bytes.NewBuffer(make([]byte, 0, length))
+		if _, err := obj.GetChunk(buf, offset, length); err == nil {
+			rc.store(key, buf.Bytes())
+		}
+		rc.mu.Lock()
+		delete(rc.inflight, key)
+		rc.mu.Unlock()
+	}()
+}
+
+func (rc *readCache) read(w io.Writer, key string) (int64, error) {
+	rc.mu.Lock()
+	el, ok := rc.blocks[key]
+	if ok {
+		rc.lru.MoveToFront(el)
+	}
+	rc.mu.Unlock()
+	if !ok {
+		return 0, errCacheMiss
+	}
+
+	f, err := os.Open(rc.path(key))
+	if err != nil {
+		rc.mu.Lock()
+		rc.remove(key)
+		rc.mu.Unlock()
+		return 0, errCacheMiss
+	}
+	defer f.Close()
+	return io.Copy(w, f)
+}
+
+func (rc *readCache) store(key string, b []byte) {
+	var (
+		path = rc.path(key)
+		tmp  = path + tmpBlockSuffix
+	)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		os.Remove(tmp)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return
+	}
+
+	rc.mu.Lock()
+	if _, ok := rc.blocks[key]; !ok {
+		rc.blocks[key] = rc.lru.PushFront(&cachedBlock{key: key, size: int64(len(b))})
+		rc.used += int64(len(b))
+		rc.evict()
+	}
+	rc.mu.Unlock()
+}
+
+// REQUIRES_LOCK(rc.mu)
+func (rc *readCache) evict() {
+	limit := rc.cfg.ReadCacheSize.Load()
+	for limit > 0 && rc.used > limit && rc.lru.Len() > 0 {
+		rc.remove(rc.lru.Back().Value.(*cachedBlock).key)
+	}
+}
+
+// REQUIRES_LOCK(rc.mu)
+func (rc *readCache) remove(key string) {
+	el, ok := rc.blocks[key]
+	if !ok {
+		return
+	}
+	block := el.Value.(*cachedBlock)
+	rc.lru.Remove(el)
+	delete(rc.blocks, key)
+	rc.used -= block.size
+	os.Remove(rc.path(key))
+}
@@ -0,0 +1,107 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	"github.com/NVIDIA/aistore/fuse/ais"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReadCache", func() {
+	var (
+		dir string
+		cfg *ServerConfig
+		rc  *readCache
+		obj *ais.Object
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "rcache")
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg = &ServerConfig{ReadCacheDir: dir}
+		cfg.ReadCacheSize.Store(16)
+		rc, err = newReadCache(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		obj = ais.NewObject("obj", newBucketMock())
+		obj.Version = "1"
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	readBlock := func(key string) (string, error) {
+		buf := &bytes.Buffer{}
+		_, err := rc.read(buf, key)
+		return buf.String(), err
+	}
+
+	It("should store and read blocks", func() {
+		key := blockKey(obj, 0, 8)
+		Expect(key).NotTo(BeEmpty())
+		_, err := readBlock(key)
+		Expect(err).To(Equal(errCacheMiss))
+
+		rc.store(key, []byte("abcdefgh"))
+		data, err := readBlock(key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal("abcdefgh"))
+		Expect(rc.used).To(BeEquivalentTo(8))
+	})
+
+	It("should not cache objects without version and checksum", func() {
+		obj.Version = ""
+		Expect(blockKey(obj, 0, 8)).To(BeEmpty())
+	})
+
+	It("should use different keys for different versions", func() {
+		key := blockKey(obj, 0, 8)
+		obj.Version = "2"
+		Expect(blockKey(obj, 0, 8)).NotTo(Equal(key))
+	})
+
+	It("should evict least recently used blocks", func() {
+		keys := []string{blockKey(obj, 0, 8), blockKey(obj, 8, 8), blockKey(obj, 16, 8)}
+		rc.store(keys[0], []byte("00000000"))
+		rc.store(keys[1], []byte("11111111"))
+		_, err := readBlock(keys[0])
+		Expect(err).NotTo(HaveOccurred())
+
+		rc.store(keys[2], []byte("22222222"))
+		Expect(rc.used).To(BeEquivalentTo(16))
+		_, err = readBlock(keys[1])
+		Expect(err).To(Equal(errCacheMiss))
+		_, err = os.Stat(rc.path(keys[1]))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		for _, key := range []string{keys[0], keys[2]} {
+			_, err = readBlock(key)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should keep blocks across remounts", func() {
+		key := blockKey(obj, 0, 8)
+		rc.store(key, []byte("abcdefgh"))
+		Expect(ioutil.WriteFile(rc.path(key)+tmpBlockSuffix, []byte("x"), 0600)).To(Succeed())
+
+		var err error
+		rc, err = newReadCache(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rc.used).To(BeEquivalentTo(8))
+		data, err := readBlock(key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal("abcdefgh"))
+		_, err = os.Stat(rc.path(key) + tmpBlockSuffix)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
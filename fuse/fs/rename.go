@@ -0,0 +1,213 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+)
+
+// Rename moves a file or a directory. AIS has no notion of directories, so
+// renaming a directory means renaming all objects with the directory prefix
+// (one by one, i.e. the operation is not atomic).
+func (fs *aisfs) Rename(ctx context.Context, req *fuseops.RenameOp) (err error) {
+	fs.mu.RLock()
+	oldParent := fs.lookupDirMustExist(req.OldParent)
+	newParent := fs.lookupDirMustExist(req.NewParent)
+	fs.mu.RUnlock()
+
+	if oldParent.buckets != nil || newParent.buckets != nil {
+		// Buckets cannot be renamed (cluster mount).
+		return syscall.EPERM
+	}
+	if oldParent.ns != newParent.ns {
+		// Objects cannot be moved between buckets in place - let the caller
+		// (e.g., `mv`) copy and delete them.
+		return syscall.EXDEV
+	}
+
+	result := oldParent.LookupEntry(req.OldName)
+	if result.NoEntry() {
+		return fuse.ENOENT
+	}
+	target := newParent.LookupEntry(req.NewName)
+
+	var (
+		oldPath = path.Join(oldParent.Path(), req.OldName)
+		newPath = path.Join(newParent.Path(), req.NewName)
+	)
+	if oldPath == newPath {
+		return
+	}
+
+	if !result.IsDir() {
+		if !target.NoEntry() && target.IsDir() {
+			return syscall.EISDIR
+		}
+		if err = oldParent.bucket.RenameObject(oldPath, newPath); err != nil {
+			return fs.handleIOError(err)
+		}
+		obj := *result.Object
+		obj.Name = newPath
+		fs.lockParents(oldParent, newParent)
+		oldParent.ForgetFile(req.OldName)
+		newParent.NewFileEntry(req.NewName, result.Entry.Inode, &obj)
+		fs.unlockParents(oldParent, newParent)
+	} else {
+		oldPath += separator
+		newPath += separator
+		if strings.HasPrefix(newPath, oldPath) {
+			// Cannot make a directory a subdirectory of itself.
+			return syscall.EINVAL
+		}
+		if !target.NoEntry() {
+			if !target.IsDir() {
+				return syscall.ENOTDIR
+			}
+			if empty, err := fs.isEmptyDir(newParent, newPath, target); err != nil || !empty {
+				if err != nil {
+					return fs.handleIOError(err)
+				}
+				return fuse.ENOTEMPTY
+			}
+		}
+		if err = fs.renameObjects(oldParent, oldPath, newPath); err != nil {
+			return fs.handleIOError(err)
+		}
+		fs.lockParents(oldParent, newParent)
+		oldParent.ns.move(oldPath, newPath)
+		oldParent.InvalidateEntries()
+		newParent.InvalidateEntries()
+		fs.unlockParents(oldParent, newParent)
+	}
+
+	fs.moveInodes(newParent, result.Entry.Inode, oldPath, newPath)
+	return
+}
+
+// Renames all objects with prefix `oldPrefix`.
+func (fs *aisfs) renameObjects(dir *DirectoryInode, oldPrefix, newPrefix string) error {
+	// First list all objects, so that renamed objects are not listed again.
+	var (
+		names      []string
+		pageMarker string
+	)
+	for {
+		objs, newPageMarker, err := dir.bucket.ListObjects(oldPrefix, pageMarker, listObjsPageSize)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			names = append(names, obj.Name)
+		}
+		if newPageMarker == "" {
+			break
+		}
+		pageMarker = newPageMarker
+	}
+	for _, name := range names {
+		if err := dir.bucket.RenameObject(name, newPrefix+name[len(oldPrefix):]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fs *aisfs) isEmptyDir(parent *DirectoryInode, dirPath string, res EntryLookupResult) (bool, error) {
+	if res.NoInode() {
+		objs, _, err := parent.bucket.ListObjects(dirPath, "", 1)
+		return len(objs) == 0, err
+	}
+	fs.mu.RLock()
+	dir := fs.lookupDirMustExist(res.Entry.Inode)
+	fs.mu.RUnlock()
+
+	dir.Lock()
+	entries, err := dir.ReadEntries()
+	dir.Unlock()
+	return len(entries) == 0, err
+}
+
+// Updates paths of the renamed inode and, if it is a directory, of all the
+// inodes underneath. Inode IDs do not change, as expected by the kernel.
+func (fs *aisfs) moveInodes(newParent *DirectoryInode, id fuseops.InodeID, oldPath, newPath string) {
+	var (
+		inodes []Inode
+		isDir  = strings.HasSuffix(oldPath, separator)
+	)
+	fs.mu.RLock()
+	for _, inode := range fs.inodeTable {
+		if inode.ID() == id || (isDir && inodeNamespace(inode) == newParent.ns && strings.HasPrefix(inode.Path(), oldPath)) {
+			inodes = append(inodes, inode)
+		}
+	}
+	fs.mu.RUnlock()
+
+	// Lock inodes in the ascending order of their IDs.
+	sort.Slice(inodes, func(i, j int) bool { return inodes[i].ID() < inodes[j].ID() })
+	for _, inode := range inodes {
+		inode.Lock()
+		var (
+			p      = newPath + strings.TrimPrefix(inode.Path(), oldPath)
+			parent *DirectoryInode
+		)
+		if inode.ID() == id {
+			parent = newParent
+		}
+		switch in := inode.(type) {
+		case *FileInode:
+			if parent == nil {
+				parent = in.parent
+			}
+			in.Move(parent, p)
+		case *DirectoryInode:
+			if parent == nil {
+				parent = in.parent
+			}
+			in.Move(parent, p)
+		}
+		inode.Unlock()
+	}
+}
+
+// Paths of inodes are relative to their buckets, so only the inodes of the
+// same namespace can be matched by path.
+func inodeNamespace(inode Inode) *namespace {
+	switch in := inode.(type) {
+	case *FileInode:
+		return in.parent.ns
+	case *DirectoryInode:
+		return in.ns
+	}
+	return nil
+}
+
+// Locks parent directories of a rename in the ascending order of their IDs.
+func (fs *aisfs) lockParents(oldParent, newParent *DirectoryInode) {
+	if oldParent == newParent {
+		oldParent.Lock()
+		return
+	}
+	if oldParent.ID() < newParent.ID() {
+		oldParent.Lock()
+		newParent.Lock()
+	} else {
+		newParent.Lock()
+		oldParent.Lock()
+	}
+}
+
+func (fs *aisfs) unlockParents(oldParent, newParent *DirectoryInode) {
+	oldParent.Unlock()
+	if oldParent != newParent {
+		newParent.Unlock()
+	}
+}
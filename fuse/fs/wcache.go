@@ -0,0 +1,222 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/NVIDIA/aistore/fuse/ais"
+)
+
+// Theory of operation
+//
+// AIS objects can only be appended to, which is enough for the sequential
+// writes (see fileHandle.writeChunk). To support random writes and truncation
+// (required by, e.g., HDF5 and SQLite) the file system can use a local
+// write-back cache: upon the first modification the file gets materialized
+// in the cache directory (the object is downloaded), all writes and reads go
+// to the local copy, and the copy gets uploaded (as a whole) on flush.
+// The local copy is removed once the last file handle is released and the
+// copy is uploaded. If the upload fails (after retries), the copy is kept and
+// its upload is retried upon the next flush, fsync, or release of the file.
+// The upload (that may take long) does not hold the inode's lock: it reads the
+// snapshot of the copy (its size and a duplicate descriptor), and the copy
+// remains modified if it gets modified in the meantime.
+//
+// The total size of local copies is limited by `ServerConfig.WriteCacheSize`;
+// a modification that would exceed the limit fails with ENOSPC.
+
+const (
+	uploadRetries    = 3
+	uploadRetryDelay = time.Second
+)
+
+type (
+	writeCache struct {
+		cfg  *ServerConfig
+		mu   sync.Mutex
+		used int64
+	}
+
+	cachedFile struct {
+		wc    *writeCache
+		f     *os.File
+		size  int64
+		dirty bool
+		gen   int64      // incremented upon every modification
+		upMu  sync.Mutex // serializes uploads
+	}
+
+	// What gets uploaded (see cachedFile.snapshot).
+	cacheSnapshot struct {
+		f    *os.File
+		size int64
+		gen  int64
+	}
+
+	// Reader of the local copy (see cmn.ReadOpenCloser).
+	cachedFileReader struct {
+		*io.SectionReader
+		f    *os.File
+		size int64
+	}
+)
+
+func newWriteCache(cfg *ServerConfig) (*writeCache, error) {
+	if cfg.WriteCacheDir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(cfg.WriteCacheDir, 0700); err != nil {
+		return nil, err
+	}
+	return &writeCache{cfg: cfg}, nil
+}
+
+func (wc *writeCache) reserve(n int64) error {
+	limit := wc.cfg.WriteCacheSize.Load()
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	if limit > 0 && wc.used+n > limit {
+		return syscall.ENOSPC
+	}
+	wc.used += n
+	return nil
+}
+
+func (wc *writeCache) release(n int64) {
+	wc.mu.Lock()
+	wc.used -= n
+	wc.mu.Unlock()
+}
+
+// open creates a local copy of the object. If download is false, the copy
+// is empty (e.g., the file is being truncated).
+func (wc *writeCache) open(obj *ais.Object, download bool) (cf *cachedFile, err error) {
+	var size int64
+	if download {
+		size = obj.Size
+	}
+	if err = wc.reserve(size); err != nil {
+		return nil, err
+	}
+	f, err := ioutil.TempFile(wc.cfg.WriteCacheDir, Name+"-")
+	if err != nil {
+		wc.release(size)
+		return nil, err
+	}
+	// The file remains accessible through the descriptor; unlinking it right
+	// away ensures that no leftovers remain if the process gets killed.
+	os.Remove(f.Name())
+
+	cf = &cachedFile{wc: wc, f: f, size: size}
+	if size > 0 {
+		if _, err = obj.GetChunk(f, 0, size); err != nil {
+			cf.close()
+			return nil, err
+		}
+	}
+	return cf, nil
+}
+
+func (cf *cachedFile) readAt(p []byte, offset int64) (n int, err error) {
+	if offset >= cf.size {
+		return 0, io.EOF
+	}
+	if rem := cf.size - offset; int64(len(p)) > rem {
+		p = p[:rem]
+	}
+	return cf.f.ReadAt(p, offset)
+}
+
+func (cf *cachedFile) writeAt(p []byte, offset int64) (err error) {
+	if end := offset + int64(len(p)); end > cf.size {
+		if err = cf.wc.reserve(end - cf.size); err != nil {
+			return err
+		}
+		if _, err = cf.f.WriteAt(p, offset); err != nil {
+			cf.wc.release(end - cf.size)
+			return err
+		}
+		cf.size = end
+	} else if _, err = cf.f.WriteAt(p, offset); err != nil {
+		return err
+	}
+	cf.dirty = true
+	cf.gen++
+	return nil
+}
+
+func (cf *cachedFile) truncate(size int64) (err error) {
+	if size > cf.size {
+		if err = cf.wc.reserve(size - cf.size); err != nil {
+			return err
+		}
+	}
+	if err = cf.f.Truncate(size); err != nil {
+		if size > cf.size {
+			cf.wc.release(size - cf.size)
+		}
+		return err
+	}
+	if size < cf.size {
+		cf.wc.release(cf.size - size)
+	}
+	cf.size = size
+	cf.dirty = true
+	cf.gen++
+	return nil
+}
+
+// snapshot returns the local copy to upload, or nil if the copy is not modified.
+// REQUIRES_LOCK(file)
+func (cf *cachedFile) snapshot() (*cacheSnapshot, error) {
+	if !cf.dirty {
+		return nil, nil
+	}
+	fd, err := syscall.Dup(int(cf.f.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	return &cacheSnapshot{f: os.NewFile(uintptr(fd), cf.f.Name()), size: cf.size, gen: cf.gen}, nil
+}
+
+// uploaded marks the copy as not modified unless it has been modified since
+// the snapshot.
+// REQUIRES_LOCK(file)
+func (cf *cachedFile) uploaded(s *cacheSnapshot) {
+	if cf.gen == s.gen {
+		cf.dirty = false
+	}
+}
+
+// upload replaces the object with the snapshot of the local copy. The upload
+// is retried a few times.
+func (s *cacheSnapshot) upload(obj *ais.Object) (err error) {
+	for i := 0; i < uploadRetries; i++ {
+		if i > 0 {
+			time.Sleep(uploadRetryDelay)
+		}
+		r := &cachedFileReader{SectionReader: io.NewSectionReader(s.f, 0, s.size), f: s.f, size: s.size}
+		if err = obj.Put(r); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (cf *cachedFile) close() {
+	cf.f.Close()
+	cf.wc.release(cf.size)
+}
+
+func (r *cachedFileReader) Close() error { return nil }
+func (r *cachedFileReader) Open() (io.ReadCloser, error) {
+	return &cachedFileReader{SectionReader: io.NewSectionReader(r.f, 0, r.size), f: r.f, size: r.size}, nil
+}
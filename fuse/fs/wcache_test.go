@@ -0,0 +1,163 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/fuse/ais"
+	"github.com/jacobsa/fuse/fuseops"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteCache", func() {
+	var (
+		dir string
+		wc  *writeCache
+		obj *ais.Object
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "wcache")
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg := &ServerConfig{WriteCacheDir: dir}
+		cfg.WriteCacheSize.Store(16)
+		wc, err = newWriteCache(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		obj = ais.NewObject("obj", newBucketMock())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should write and read at arbitrary offsets", func() {
+		cf, err := wc.open(obj, false /*download*/)
+		Expect(err).NotTo(HaveOccurred())
+		defer cf.close()
+
+		Expect(cf.writeAt([]byte("world"), 6)).To(Succeed())
+		Expect(cf.writeAt([]byte("hello"), 0)).To(Succeed())
+		Expect(cf.size).To(BeEquivalentTo(11))
+		Expect(cf.dirty).To(BeTrue())
+
+		buf := make([]byte, 16)
+		n, err := cf.readAt(buf, 0)
+		Expect(err).To(Or(BeNil(), Equal(io.EOF)))
+		Expect(buf[:n]).To(Equal([]byte("hello\x00world")))
+
+		_, err = cf.readAt(buf, 11)
+		Expect(err).To(Equal(io.EOF))
+	})
+
+	It("should truncate", func() {
+		cf, err := wc.open(obj, false /*download*/)
+		Expect(err).NotTo(HaveOccurred())
+		defer cf.close()
+
+		Expect(cf.writeAt([]byte("0123456789"), 0)).To(Succeed())
+		Expect(cf.truncate(4)).To(Succeed())
+		Expect(wc.used).To(BeEquivalentTo(4))
+
+		buf := make([]byte, 16)
+		n, _ := cf.readAt(buf, 0)
+		Expect(buf[:n]).To(Equal([]byte("0123")))
+
+		Expect(cf.truncate(8)).To(Succeed())
+		n, _ = cf.readAt(buf, 0)
+		Expect(buf[:n]).To(Equal([]byte("0123\x00\x00\x00\x00")))
+	})
+
+	It("should respect the size limit", func() {
+		cf, err := wc.open(obj, false /*download*/)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cf.writeAt(make([]byte, 10), 0)).To(Succeed())
+		Expect(cf.writeAt(make([]byte, 10), 10)).To(Equal(syscall.ENOSPC))
+		Expect(cf.truncate(17)).To(Equal(syscall.ENOSPC))
+		Expect(cf.size).To(BeEquivalentTo(10))
+
+		cf.close()
+		Expect(wc.used).To(BeZero())
+	})
+
+	It("should keep the local copy if upload fails", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+		obj = ais.NewObject("obj", &failingBucket{newBucketMock(), srv.URL})
+		file := NewFileInode(1, fuseops.InodeAttributes{}, nil, obj).(*FileInode)
+		file.Lock()
+		Expect(file.WriteAt(wc, []byte("data"), 0)).To(Succeed())
+		file.Unlock()
+
+		Expect(file.SyncAndDropCache()).NotTo(Succeed())
+		file.Lock()
+		defer file.Unlock()
+		Expect(file.cache).NotTo(BeNil())
+		Expect(file.cache.dirty).To(BeTrue())
+
+		buf := make([]byte, 4)
+		n, _ := file.cache.readAt(buf, 0)
+		Expect(buf[:n]).To(Equal([]byte("data")))
+		Expect(file.Destroy()).NotTo(Succeed())
+		Expect(wc.used).To(BeZero())
+	})
+
+	It("should not hold the lock while uploading", func() {
+		var (
+			started = make(chan struct{})
+			proceed = make(chan struct{})
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ioutil.ReadAll(r.Body)
+			close(started)
+			<-proceed
+		}))
+		defer srv.Close()
+		obj = ais.NewObject("obj", &failingBucket{newBucketMock(), srv.URL})
+		file := NewFileInode(1, fuseops.InodeAttributes{}, nil, obj).(*FileInode)
+		file.Lock()
+		Expect(file.WriteAt(wc, []byte("data"), 0)).To(Succeed())
+		file.Unlock()
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- file.SyncCache() }()
+		<-started
+
+		// the file can be read and modified while being uploaded...
+		file.Lock()
+		Expect(file.WriteAt(wc, []byte("more"), 4)).To(Succeed())
+		file.Unlock()
+		close(proceed)
+		Expect(<-errCh).To(Succeed())
+
+		// ...and then remains modified
+		file.Lock()
+		defer file.Unlock()
+		Expect(file.object.Size).To(BeEquivalentTo(4))
+		Expect(file.cache.dirty).To(BeTrue())
+		file.DropCache()
+	})
+})
+
+type failingBucket struct {
+	*bucketMock
+	url string
+}
+
+func (b *failingBucket) APIParams() api.BaseParams {
+	return api.BaseParams{Client: http.DefaultClient, URL: b.url}
+}
@@ -0,0 +1,130 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/NVIDIA/aistore/fuse/ais"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+)
+
+// Extended attributes expose metadata of the object backing a file
+// (as returned by HEAD). They are read-only: AIS objects have no user-defined
+// metadata that setxattr(2) could be mapped to.
+
+const xattrPrefix = "user.ais."
+
+type xattr struct {
+	name  string
+	value string
+}
+
+func objectXattrs(obj *ais.Object) []xattr {
+	attrs := make([]xattr, 0, 5)
+	if obj.Cksum != "" {
+		attrs = append(attrs, xattr{xattrPrefix + "checksum", obj.Cksum})
+	}
+	if obj.Version != "" {
+		attrs = append(attrs, xattr{xattrPrefix + "version", obj.Version})
+	}
+	if !obj.Atime.IsZero() {
+		attrs = append(attrs, xattr{xattrPrefix + "atime", obj.Atime.Format(time.RFC3339Nano)})
+	}
+	attrs = append(attrs, xattr{xattrPrefix + "copies", strconv.Itoa(obj.NumCopies)})
+	if obj.DataSlices > 0 {
+		attrs = append(attrs, xattr{xattrPrefix + "ec", fmt.Sprintf("%d:%d", obj.DataSlices, obj.ParitySlices)})
+	}
+	return attrs
+}
+
+// Returns extended attributes of the inode: none for directories and
+// for files whose objects do not exist (yet).
+func (fs *aisfs) xattrs(id fuseops.InodeID) ([]xattr, error) {
+	fs.mu.RLock()
+	inode := fs.lookupMustExist(id)
+	fs.mu.RUnlock()
+	if inode.IsDir() {
+		return nil, nil
+	}
+
+	file := inode.(*FileInode)
+	file.RLock()
+	var (
+		bucket  = file.parent.bucket
+		objName = file.object.Name
+	)
+	file.RUnlock()
+
+	obj, exists, err := bucket.HeadObject(objName)
+	if err != nil {
+		return nil, fs.handleIOError(err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	return objectXattrs(obj), nil
+}
+
+func (fs *aisfs) GetXattr(ctx context.Context, req *fuseops.GetXattrOp) (err error) {
+	attrs, err := fs.xattrs(req.Inode)
+	if err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		if attr.name != req.Name {
+			continue
+		}
+		req.BytesRead = len(attr.value)
+		if len(req.Dst) < len(attr.value) {
+			return syscall.ERANGE
+		}
+		copy(req.Dst, attr.value)
+		return nil
+	}
+	return fuse.ENOATTR
+}
+
+func (fs *aisfs) ListXattr(ctx context.Context, req *fuseops.ListXattrOp) (err error) {
+	attrs, err := fs.xattrs(req.Inode)
+	if err != nil {
+		return err
+	}
+	dst := req.Dst
+	for _, attr := range attrs {
+		// Names are NUL-terminated.
+		nameLen := len(attr.name) + 1
+		if err == nil && len(dst) >= nameLen {
+			copy(dst, attr.name)
+			dst[len(attr.name)] = 0
+			dst = dst[nameLen:]
+		} else {
+			err = syscall.ERANGE
+		}
+		req.BytesRead += nameLen
+	}
+	return
+}
+
+func (fs *aisfs) SetXattr(ctx context.Context, req *fuseops.SetXattrOp) (err error) {
+	return xattrReadOnly(req.Name)
+}
+
+func (fs *aisfs) RemoveXattr(ctx context.Context, req *fuseops.RemoveXattrOp) (err error) {
+	return xattrReadOnly(req.Name)
+}
+
+func xattrReadOnly(name string) error {
+	if strings.HasPrefix(name, xattrPrefix) {
+		return syscall.EPERM
+	}
+	return syscall.ENOTSUP
+}
@@ -0,0 +1,39 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"syscall"
+	"time"
+
+	"github.com/NVIDIA/aistore/fuse/ais"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Xattr", func() {
+	It("should expose object metadata", func() {
+		obj := ais.NewObject("obj", newBucketMock())
+		obj.Cksum = "abc"
+		obj.Version = "2"
+		obj.Atime = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		obj.NumCopies = 2
+
+		Expect(objectXattrs(obj)).To(Equal([]xattr{
+			{"user.ais.checksum", "abc"},
+			{"user.ais.version", "2"},
+			{"user.ais.atime", "2020-01-02T03:04:05Z"},
+			{"user.ais.copies", "2"},
+		}))
+
+		obj.DataSlices, obj.ParitySlices = 4, 2
+		Expect(objectXattrs(obj)).To(ContainElement(xattr{"user.ais.ec", "4:2"}))
+	})
+
+	It("should not allow to modify attributes", func() {
+		Expect(xattrReadOnly("user.ais.version")).To(Equal(syscall.EPERM))
+		Expect(xattrReadOnly("user.comment")).To(Equal(syscall.ENOTSUP))
+	})
+})
@@ -45,7 +45,8 @@ func determineClusterURL(c *cli.Context, cfg *Config, bck cmn.Bck) (clusterURL s
 		return "", err
 	}
 
-	// Try to access the bucket, possibly catching an early error
+	// Try to access the bucket (or the cluster if the whole cluster is to be
+	// mounted), possibly catching an early error
 	if ok := tryAccessBucket(clusterURL, bck); !ok {
 		err = fmt.Errorf("No response from proxy at %q (bucket %q)", clusterURL, bck)
 		return "", err
@@ -100,7 +101,12 @@ func tryAccessBucket(url string, bck cmn.Bck) bool {
 		URL:    url,
 	}
 
-	_, err := api.HeadBucket(baseParams, bck)
+	var err error
+	if bck.Name == "" {
+		_, err = api.GetBucketNames(baseParams, bck)
+	} else {
+		_, err = api.HeadBucket(baseParams, bck)
+	}
 	return err == nil
 }
 
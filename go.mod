@@ -15,6 +15,7 @@ require (
 	github.com/jcelliott/lumber v0.0.0-20160324203708-dd349441af25 // indirect
 	github.com/json-iterator/go v1.1.7
 	github.com/karrick/godirwalk v1.12.0
+	github.com/klauspost/compress v1.8.2
 	github.com/klauspost/reedsolomon v1.9.3
 	github.com/kylelemons/godebug v1.1.0 // indirect
 	github.com/lufia/iostat v0.0.0-20170605150913-9f7362b77ad3
@@ -30,6 +31,7 @@ require (
 	github.com/urfave/cli v1.22.2
 	github.com/valyala/fasthttp v1.9.0
 	github.com/vbauerster/mpb/v4 v4.10.1
+	golang.org/x/crypto v0.0.0-20191011191535-87dc89f01550
 	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e
 	golang.org/x/sys v0.0.0-20191120155948-bd437916bb0e
 	google.golang.org/api v0.14.0
@@ -5,6 +5,7 @@
 package mirror
 
 import (
+	"context"
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
@@ -13,6 +14,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/tracing"
 )
 
 type (
@@ -21,7 +23,7 @@ type (
 		cmn.XactDemandBase
 		cmn.MountpathXact
 		// runtime
-		workCh   chan *cluster.LOM
+		workCh   chan replReq
 		mpathers map[string]mpather
 		// init
 		mirror  cmn.MirrorConf
@@ -32,9 +34,13 @@ type (
 	xputJogger struct { // one per mountpath
 		parent    *XactPutLRepl
 		mpathInfo *fs.MountpathInfo
-		workCh    chan *cluster.LOM
+		workCh    chan replReq
 		stopCh    cmn.StopCh
 	}
+	replReq struct {
+		lom  *cluster.LOM
+		span *tracing.Span // ends when the copies are added (optional)
+	}
 )
 
 //
@@ -55,7 +61,7 @@ func RunXactPutLRepl(id string, lom *cluster.LOM, slab *memsys.Slab) (r *XactPut
 		r = nil
 		return
 	}
-	r.workCh = make(chan *cluster.LOM, r.mirror.Burst)
+	r.workCh = make(chan replReq, r.mirror.Burst)
 	r.mpathers = make(map[string]mpather, mpathCount)
 
 	// Run
@@ -75,17 +81,19 @@ func (r *XactPutLRepl) Run() error {
 	glog.Infoln(r.String())
 	for {
 		select {
-		case src := <-r.workCh:
-			lom := src.Clone(src.FQN)
+		case req := <-r.workCh:
+			lom := req.lom.Clone(req.lom.FQN)
 			if err := lom.Load(); err != nil {
 				glog.Error(err)
+				req.span.End(err)
 				break
 			}
 			path := lom.ParsedFQN.MpathInfo.MakePathCT(r.Bck(), fs.ObjectType)
 			if mpather, ok := r.mpathers[path]; ok {
-				mpather.post(lom)
+				mpather.(*xputJogger).workCh <- replReq{lom: lom, span: req.span}
 			} else {
 				glog.Errorf("failed to get mpather with path: %s", path)
+				req.span.End(nil)
 			}
 		case <-r.ChanCheckTimeout():
 			if r.Timeout() {
@@ -100,7 +108,8 @@ func (r *XactPutLRepl) Run() error {
 }
 
 // main method: replicate a given locally stored object
-func (r *XactPutLRepl) Repl(lom *cluster.LOM) (err error) {
+// (ctx may carry the trace context of the request that has caused the replication)
+func (r *XactPutLRepl) Repl(ctx context.Context, lom *cluster.LOM) (err error) {
 	if r.Finished() {
 		err = cmn.NewErrXactExpired("Cannot replicate: " + r.String())
 		return
@@ -120,7 +129,12 @@ func (r *XactPutLRepl) Repl(lom *cluster.LOM) (err error) {
 		}
 	}
 	r.IncPending() // ref-count via base to support on-demand action
-	r.workCh <- lom
+	req := replReq{lom: lom}
+	if _, ok := tracing.FromContext(ctx); ok {
+		_, req.span = tracing.Start(ctx, "mirror copy", tracing.KindInternal)
+		req.span.SetAttr("object", lom.String())
+	}
+	r.workCh <- req
 
 	// [throttle]
 	// a bit of back-pressure when approaching the fixed boundary
@@ -164,8 +178,9 @@ func (r *XactPutLRepl) stop() {
 		mpather.stop()
 	}
 	r.EndTime(time.Now())
-	for lom := range r.workCh {
-		glog.Infof("Stopping, not copying %s", lom)
+	for req := range r.workCh {
+		glog.Infof("Stopping, not copying %s", req.lom)
+		req.span.End(nil)
 		r.DecPending()
 	}
 }
@@ -182,7 +197,7 @@ func newXputJogger(parent *XactPutLRepl, mpathInfo *fs.MountpathInfo) *xputJogge
 	return &xputJogger{
 		parent:    parent,
 		mpathInfo: mpathInfo,
-		workCh:    make(chan *cluster.LOM, parent.mirror.Burst),
+		workCh:    make(chan replReq, parent.mirror.Burst),
 		stopCh:    cmn.NewStopCh(),
 	}
 }
@@ -193,10 +208,13 @@ func (j *xputJogger) jog() {
 
 	for {
 		select {
-		case src := <-j.workCh:
-			lom := src.Clone(src.FQN)
+		case req := <-j.workCh:
+			lom := req.lom.Clone(req.lom.FQN)
 			copies := int(lom.Bprops().Mirror.Copies)
-			if _, err := addCopies(lom, copies, j.parent.mpathers, buf); err != nil {
+			req.span.SetAttr("copies", copies)
+			_, err := addCopies(lom, copies, j.parent.mpathers, buf)
+			req.span.End(err)
+			if err != nil {
 				glog.Error(err)
 			} else {
 				if v := j.parent.ObjectsAdd(int64(copies)); (v % logNumProcessed) == 0 {
@@ -217,11 +235,12 @@ func (j *xputJogger) jog() {
 //
 
 func (j *xputJogger) mountpathInfo() *fs.MountpathInfo { return j.mpathInfo }
-func (j *xputJogger) post(lom *cluster.LOM)            { j.workCh <- lom }
+func (j *xputJogger) post(lom *cluster.LOM)            { j.workCh <- replReq{lom: lom} }
 
 func (j *xputJogger) stop() {
-	for lom := range j.workCh {
-		glog.Infof("Stopping, not copying %s", lom)
+	for req := range j.workCh {
+		glog.Infof("Stopping, not copying %s", req.lom)
+		req.span.End(nil)
 		j.parent.DecPending()
 	}
 	j.stopCh.Close()
@@ -0,0 +1,111 @@
+// Package reb provides resilvering and rebalancing functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package reb
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Rebalance dry-run: given a hypothetical cluster map, traverse all local
+// objects and account for those that the (same as global rebalance) HRW
+// would place elsewhere. Nothing is moved.
+//
+// Similar to global rebalance, objects of EC-enabled buckets are skipped:
+// their slices and replicas are relocated by the EC-specific logic.
+
+type planJogger struct {
+	t    cluster.Target
+	smap *cluster.Smap
+	plan *cmn.RebPlan
+}
+
+func (reb *Manager) Plan(smap *cluster.Smap) (*cmn.RebPlan, error) {
+	var (
+		wg       = &sync.WaitGroup{}
+		paths, _ = fs.Mountpaths.Get()
+		cfg      = cmn.GCO.Get()
+		bcks     = []cmn.Bck{{Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}}
+		joggers  = make([]*planJogger, 0, len(paths)*2)
+		errCh    = make(chan error, len(paths)*2)
+		plan     = cmn.NewRebPlan()
+	)
+	if cfg.Cloud.Supported {
+		bcks = append(bcks, cmn.Bck{Provider: cfg.Cloud.Provider, Ns: cfg.Cloud.Ns})
+	}
+	for _, mpathInfo := range paths {
+		for _, bck := range bcks {
+			pj := &planJogger{t: reb.t, smap: smap, plan: cmn.NewRebPlan()}
+			joggers = append(joggers, pj)
+			wg.Add(1)
+			go func(mpathInfo *fs.MountpathInfo, bck cmn.Bck) {
+				defer wg.Done()
+				if err := pj.jog(mpathInfo, bck); err != nil {
+					errCh <- err
+				}
+			}(mpathInfo, bck)
+		}
+	}
+	wg.Wait()
+	close(errCh)
+	if err, ok := <-errCh; ok {
+		return nil, err
+	}
+	for _, pj := range joggers {
+		plan.Merge(pj.plan)
+	}
+	return plan, nil
+}
+
+func (pj *planJogger) jog(mpathInfo *fs.MountpathInfo, bck cmn.Bck) error {
+	opts := &fs.Options{
+		Mpath:    mpathInfo,
+		Bck:      bck,
+		CTs:      []string{fs.ObjectType},
+		Callback: pj.walk,
+		Sorted:   false,
+	}
+	return fs.Walk(opts)
+}
+
+func (pj *planJogger) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	lom := &cluster.LOM{T: pj.t, FQN: fqn}
+	if err := lom.Init(cmn.Bck{}); err != nil {
+		if cmn.IsErrBucketLevel(err) {
+			return err
+		}
+		if glog.FastV(4, glog.SmoduleReb) {
+			glog.Warningf("%s, err %s - skipping...", lom, err)
+		}
+		return nil
+	}
+	if lom.Bck().Props.EC.Enabled {
+		return filepath.SkipDir
+	}
+	tsi, err := cluster.HrwTarget(lom.Uname(), pj.smap)
+	if err != nil {
+		return err
+	}
+	if tsi.ID() == pj.t.Snode().ID() {
+		return nil
+	}
+	if err := lom.Load(); err != nil {
+		return nil
+	}
+	// mirrored copies are not transferred - only the main replica is
+	if lom.IsCopy() {
+		return nil
+	}
+	pj.plan.Add(lom.Bck().Bck.String(), tsi.ID(), lom.Size())
+	return nil
+}
@@ -0,0 +1,178 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Per-bucket stats are maintained by targets in addition to (and independently of)
+// the node-level CoreStats. The counters are updated in place (atomically) - unlike
+// the node-level ones they are not logged and not sent to StatsD.
+
+type (
+	// BucketStats is a snapshot of the per-bucket stats (of a single target
+	// or, when merged, of the entire cluster)
+	BucketStats struct {
+		Bck          cmn.Bck    `json:"bck"`
+		GetCount     int64      `json:"get.n,string"`
+		PutCount     int64      `json:"put.n,string"`
+		AppendCount  int64      `json:"append.n,string"`
+		DeleteCount  int64      `json:"del.n,string"`
+		GetColdCount int64      `json:"get.cold.n,string"`
+		ErrCount     int64      `json:"err.n,string"`
+		InSize       int64      `json:"in.size,string"`  // bytes received: PUT and cold GET
+		OutSize      int64      `json:"out.size,string"` // bytes sent: GET
+		GetLatency   *Histogram `json:"get.µs"`
+		PutLatency   *Histogram `json:"put.µs"`
+	}
+	// name => BucketStats, where name is cmn.Bck.String()
+	BucketStatsMap map[string]*BucketStats
+
+	bucketStats struct {
+		bck                                  cmn.Bck
+		get, put, append, del, getCold, errs atomic.Int64
+		in, out                              atomic.Int64
+		getLat, putLat                       histogram
+	}
+	bucketTracker struct {
+		m sync.Map // cmn.Bck.String() => *bucketStats
+	}
+)
+
+func (bt *bucketTracker) add(bck cmn.Bck, nvs ...NamedVal64) {
+	var (
+		bs    *bucketStats
+		uname = bck.String()
+	)
+	if v, ok := bt.m.Load(uname); ok {
+		bs = v.(*bucketStats)
+	} else {
+		v, _ = bt.m.LoadOrStore(uname, &bucketStats{bck: bck})
+		bs = v.(*bucketStats)
+	}
+	for _, nv := range nvs {
+		switch nv.Name {
+		case GetCount:
+			bs.get.Add(nv.Value)
+		case PutCount:
+			bs.put.Add(nv.Value)
+		case AppendCount:
+			bs.append.Add(nv.Value)
+		case DeleteCount:
+			bs.del.Add(nv.Value)
+		case GetColdCount:
+			bs.getCold.Add(nv.Value)
+		case GetColdSize, PutSize:
+			bs.in.Add(nv.Value)
+		case GetThroughput:
+			bs.out.Add(nv.Value)
+		case GetLatency:
+			bs.getLat.add(time.Duration(nv.Value))
+		case PutLatency:
+			bs.putLat.add(time.Duration(nv.Value))
+		default:
+			if isErrName(nv.Name) {
+				bs.errs.Add(nv.Value)
+			}
+		}
+	}
+}
+
+// drops the stats of the buckets that no longer exist (destroyed or evicted);
+// a bucket that is subsequently re-created starts from scratch
+func (bt *bucketTracker) del(bcks ...cmn.Bck) {
+	for _, bck := range bcks {
+		bt.m.Delete(bck.String())
+	}
+}
+
+// returns stats of the buckets that match the query where empty name, provider,
+// and namespace match all buckets, all providers, and all namespaces, respectively
+func (bt *bucketTracker) snapshot(query cmn.Bck) BucketStatsMap {
+	all := make(BucketStatsMap, 8)
+	bt.m.Range(func(k, v interface{}) bool {
+		bs := v.(*bucketStats)
+		if bckMatch(query, bs.bck) {
+			all[k.(string)] = bs.snapshot()
+		}
+		return true
+	})
+	return all
+}
+
+func (bs *bucketStats) snapshot() *BucketStats {
+	return &BucketStats{
+		Bck:          bs.bck,
+		GetCount:     bs.get.Load(),
+		PutCount:     bs.put.Load(),
+		AppendCount:  bs.append.Load(),
+		DeleteCount:  bs.del.Load(),
+		GetColdCount: bs.getCold.Load(),
+		ErrCount:     bs.errs.Load(),
+		InSize:       bs.in.Load(),
+		OutSize:      bs.out.Load(),
+		GetLatency:   bs.getLat.snapshot(),
+		PutLatency:   bs.putLat.snapshot(),
+	}
+}
+
+//
+// BucketStats
+//
+
+func (s *BucketStats) Merge(other *BucketStats) {
+	s.GetCount += other.GetCount
+	s.PutCount += other.PutCount
+	s.AppendCount += other.AppendCount
+	s.DeleteCount += other.DeleteCount
+	s.GetColdCount += other.GetColdCount
+	s.ErrCount += other.ErrCount
+	s.InSize += other.InSize
+	s.OutSize += other.OutSize
+	if s.GetLatency == nil {
+		s.GetLatency = &Histogram{}
+	}
+	s.GetLatency.Merge(other.GetLatency)
+	if s.PutLatency == nil {
+		s.PutLatency = &Histogram{}
+	}
+	s.PutLatency.Merge(other.PutLatency)
+}
+
+func (m BucketStatsMap) Merge(other BucketStatsMap) {
+	for name, bs := range other {
+		if mine, ok := m[name]; ok {
+			mine.Merge(bs)
+		} else {
+			m[name] = bs
+		}
+	}
+}
+
+func bckMatch(query, bck cmn.Bck) bool {
+	if query.Name != "" && query.Name != bck.Name {
+		return false
+	}
+	if !query.Ns.IsGlobal() && query.Ns != bck.Ns {
+		return false
+	}
+	switch query.Provider {
+	case "":
+		return true
+	case cmn.Cloud:
+		return bck.IsCloud()
+	default:
+		return query.Provider == bck.Provider
+	}
+}
+
+// all error counters are named "err.*"
+func isErrName(name string) bool { return len(name) > 4 && name[:4] == "err." }
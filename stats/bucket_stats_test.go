@@ -0,0 +1,95 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestHistogramPercentile(t *testing.T) {
+	h := &histogram{}
+	for i := 1; i <= 100; i++ {
+		h.add(time.Duration(i) * time.Millisecond)
+	}
+	s := h.snapshot()
+	tassert.Fatalf(t, s.Count == 100, "expected 100 samples, got %d", s.Count)
+	tassert.Errorf(t, s.Avg() == 50500*time.Microsecond, "expected avg 50.5ms, got %v", s.Avg())
+
+	for _, tc := range []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{0.5, 50 * time.Millisecond},
+		{0.9, 90 * time.Millisecond},
+		{0.99, 99 * time.Millisecond},
+		{1, 100 * time.Millisecond},
+	} {
+		// the result is the upper boundary of the bucket: within 19% of the actual value
+		got := s.Percentile(tc.p)
+		tassert.Errorf(t, got >= tc.expected && float64(got) < 1.19*float64(tc.expected),
+			"p%v: expected ~%v, got %v", tc.p*100, tc.expected, got)
+	}
+	tassert.Errorf(t, (&Histogram{}).Percentile(0.5) == 0, "empty histogram")
+}
+
+func TestBucketStatsMerge(t *testing.T) {
+	var (
+		bt1, bt2 bucketTracker
+		bckAIS   = cmn.Bck{Name: "a", Provider: cmn.ProviderAIS}
+		bckAWS   = cmn.Bck{Name: "b", Provider: cmn.ProviderAmazon}
+	)
+	bt1.add(bckAIS,
+		NamedVal64{Name: GetCount, Value: 1},
+		NamedVal64{Name: GetThroughput, Value: 100},
+		NamedVal64{Name: GetLatency, Value: int64(time.Millisecond)},
+	)
+	bt1.add(bckAWS, NamedVal64{Name: GetColdCount, Value: 1}, NamedVal64{Name: GetColdSize, Value: 10})
+	bt2.add(bckAIS,
+		NamedVal64{Name: PutCount, Value: 1},
+		NamedVal64{Name: PutSize, Value: 200},
+		NamedVal64{Name: PutLatency, Value: int64(time.Second)},
+	)
+	bt2.add(bckAIS, NamedVal64{Name: ErrGetCount, Value: 1}, NamedVal64{Name: VerChangeCount, Value: 1})
+
+	all := bt1.snapshot(cmn.Bck{})
+	tassert.Fatalf(t, len(all) == 2, "expected 2 buckets, got %d", len(all))
+	cloud := bt1.snapshot(cmn.Bck{Provider: cmn.Cloud})
+	tassert.Fatalf(t, len(cloud) == 1 && cloud[bckAWS.String()] != nil, "expected %s only, got %v", bckAWS, cloud)
+
+	all.Merge(bt2.snapshot(cmn.Bck{Name: bckAIS.Name}))
+	bs := all[bckAIS.String()]
+	tassert.Errorf(t, bs.Bck.Equal(bckAIS), "expected %s, got %s", bckAIS, bs.Bck)
+	tassert.Errorf(t, bs.GetCount == 1 && bs.PutCount == 1 && bs.ErrCount == 1,
+		"unexpected counts: get %d, put %d, err %d", bs.GetCount, bs.PutCount, bs.ErrCount)
+	tassert.Errorf(t, bs.InSize == 200 && bs.OutSize == 100, "unexpected sizes: in %d, out %d", bs.InSize, bs.OutSize)
+	tassert.Errorf(t, bs.GetLatency.Count == 1 && bs.PutLatency.Count == 1, "unexpected latency samples")
+	bs = all[bckAWS.String()]
+	tassert.Errorf(t, bs.GetColdCount == 1 && bs.InSize == 10, "unexpected cold GET stats: %+v", bs)
+}
+
+func TestBucketStatsDel(t *testing.T) {
+	var (
+		bt     bucketTracker
+		bckAIS = cmn.Bck{Name: "a", Provider: cmn.ProviderAIS}
+		bckGCP = cmn.Bck{Name: "a", Provider: cmn.ProviderGoogle}
+	)
+	bt.add(bckAIS, NamedVal64{Name: PutCount, Value: 1})
+	bt.add(bckGCP, NamedVal64{Name: GetColdCount, Value: 1})
+
+	bt.del(bckGCP) // evicted
+	all := bt.snapshot(cmn.Bck{})
+	tassert.Fatalf(t, len(all) == 1 && all[bckAIS.String()] != nil, "expected %s only, got %v", bckAIS, all)
+
+	bt.del(bckAIS) // destroyed and re-created
+	bt.add(bckAIS, NamedVal64{Name: GetCount, Value: 1})
+	bs := bt.snapshot(cmn.Bck{})[bckAIS.String()]
+	tassert.Fatalf(t, bs != nil, "expected %s", bckAIS)
+	tassert.Errorf(t, bs.PutCount == 0 && bs.GetCount == 1, "expected fresh stats, got %+v", bs)
+}
@@ -87,6 +87,7 @@ type (
 		Get(name string) int64
 		AddErrorHTTP(method string, val int64)
 		AddMany(namedVal64 ...NamedVal64)
+		AddBucket(bck cmn.Bck, namedVal64 ...NamedVal64)
 		Register(name string, kind string)
 	}
 	NamedVal64 struct {
@@ -148,14 +149,18 @@ type (
 	}
 	// Stats are tracked via a map of stats names (key) to statsValue (values).
 	// There are two main types of stats: counter and latency declared
-	// using the the kind field. Only latency stats have numSamples used to compute latency.
+	// using the the kind field. Only latency stats have numSamples used to compute latency
+	// and histograms used to compute latency percentiles.
 	statsValue struct {
 		sync.RWMutex
 		Value      int64 `json:"v,string"`
 		kind       string
 		numSamples int64
 		cumulative int64
-		isCommon   bool // optional, common to the proxy and target
+		cumSamples int64      // total number of latency samples (never reset)
+		hist       *histogram // all latency samples (never reset)
+		ihist      *histogram // latency samples of the current stats interval
+		isCommon   bool       // optional, common to the proxy and target
 	}
 	copyValue struct {
 		Value int64 `json:"v,string"`
@@ -185,9 +190,27 @@ func (s *CoreStats) UpdateUptime(d time.Duration) {
 	v.Unlock()
 }
 
-func (s *CoreStats) MarshalJSON() ([]byte, error) { return jsoniter.Marshal(s.Tracker) }
 func (s *CoreStats) UnmarshalJSON(b []byte) error { return jsoniter.Unmarshal(b, &s.Tracker) }
 
+// NOTE: in addition to the tracked values, includes percentiles of all latencies (see addPercentiles)
+func (s *CoreStats) MarshalJSON() ([]byte, error) {
+	ctracker := make(copyTracker, len(s.Tracker)+16)
+	for name, v := range s.Tracker {
+		v.RLock()
+		ctracker[name] = &copyValue{Value: v.Value}
+		v.RUnlock()
+		if v.kind == KindLatency {
+			addPercentiles(name, v.hist.snapshot(), ctracker)
+		}
+	}
+	// same encoding as statsValue (see UnmarshalJSON)
+	values := make(map[string]int64, len(ctracker))
+	for name, v := range ctracker {
+		values[name] = v.Value
+	}
+	return jsoniter.Marshal(values)
+}
+
 func (s *CoreStats) get(name string) (val int64) {
 	v := s.Tracker[name]
 	v.RLock()
@@ -217,6 +240,9 @@ func (s *CoreStats) doAdd(name, nameSuffix string, val int64) {
 		}
 		v.Lock()
 		v.numSamples++
+		v.cumSamples++
+		v.hist.add(time.Duration(val))
+		v.ihist.add(time.Duration(val))
 		val = int64(time.Duration(val) / time.Microsecond)
 		v.cumulative += val
 		v.Value += val
@@ -246,14 +272,21 @@ func (s *CoreStats) copyT(ctracker copyTracker) (updatedCnt int) {
 	for name, v := range s.Tracker {
 		switch v.kind {
 		case KindLatency:
+			var ihist *Histogram
 			v.Lock()
 			if v.numSamples > 0 {
 				ctracker[name] = &copyValue{Value: v.Value / v.numSamples}
 				updatedCnt++
+				ihist = v.ihist.snapshot()
+				v.ihist.reset()
 			}
 			v.Value = 0
 			v.numSamples = 0
 			v.Unlock()
+			if ihist != nil {
+				addPercentiles(name, ihist, ctracker)
+				s.sendPercentiles(name, ihist)
+			}
 		case KindThroughput:
 			var throughput int64
 			v.Lock()
@@ -291,7 +324,10 @@ func (s *CoreStats) copyCumulative(ctracker copyTracker) {
 
 	for name, v := range s.Tracker {
 		v.RLock()
-		if v.kind == KindLatency || v.kind == KindThroughput {
+		if v.kind == KindLatency {
+			ctracker[name] = &copyValue{Value: v.cumulative}
+			addPercentiles(name, v.hist.snapshot(), ctracker)
+		} else if v.kind == KindThroughput {
 			ctracker[name] = &copyValue{Value: v.cumulative}
 		} else if v.kind == KindCounter {
 			if v.Value != 0 {
@@ -304,6 +340,41 @@ func (s *CoreStats) copyCumulative(ctracker copyTracker) {
 	}
 }
 
+// Latency percentiles are reported (in microseconds) as "<latency name>.<suffix>",
+// e.g. "get.µs.p99", whereby the REST API and CLI report percentiles of all latency
+// samples since the node started while the log and StatsD - of the last stats interval.
+var latencyPercentiles = []struct {
+	suffix string
+	p      float64
+}{
+	{"p50", 0.5}, {"p90", 0.9}, {"p99", 0.99}, {"p999", 0.999},
+}
+
+func addPercentiles(name string, h *Histogram, ctracker copyTracker) {
+	if h.Count == 0 {
+		return
+	}
+	for _, pct := range latencyPercentiles {
+		ctracker[name+"."+pct.suffix] = &copyValue{Value: int64(h.Percentile(pct.p) / time.Microsecond)}
+	}
+}
+
+// e.g. "get.latency.p99" gauge (in milliseconds)
+func (s *CoreStats) sendPercentiles(name string, h *Histogram) {
+	if !strings.HasSuffix(name, ".µs") {
+		return
+	}
+	metrics := make([]metric, 0, len(latencyPercentiles))
+	for _, pct := range latencyPercentiles {
+		metrics = append(metrics, metric{
+			Type:  statsd.Gauge,
+			Name:  "latency." + pct.suffix,
+			Value: float64(h.Percentile(pct.p)) / float64(time.Millisecond),
+		})
+	}
+	s.statsdC.Send(strings.TrimSuffix(name, ".µs"), 1, metrics...)
+}
+
 //
 // StatsD client using 8125 (default) StatsD port - https://github.com/etsy/statsd
 //
@@ -354,6 +425,9 @@ func (tracker statsTracker) register(key string, kind string, isCommon ...bool)
 	cmn.AssertMsg(cmn.StringInSlice(kind, kinds), "invalid stats kind '"+kind+"'")
 
 	tracker[key] = &statsValue{kind: kind}
+	if kind == KindLatency {
+		tracker[key].hist, tracker[key].ihist = &histogram{}, &histogram{}
+	}
 	if len(isCommon) > 0 {
 		tracker[key].isCommon = isCommon[0]
 	}
@@ -468,6 +542,10 @@ func (r *statsRunner) AddMany(nvs ...NamedVal64) {
 		r.workCh <- nv
 	}
 }
+
+// NOTE: per-bucket stats are tracked only by targets (see Trunner)
+func (r *statsRunner) AddBucket(cmn.Bck, ...NamedVal64) {}
+
 func (r *statsRunner) housekeep(bool) {
 	// keep total log size below the configured max
 	r.logIdx++
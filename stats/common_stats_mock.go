@@ -5,6 +5,8 @@
  */
 package stats
 
+import "github.com/NVIDIA/aistore/cmn"
+
 type (
 	TrackerMock struct{}
 )
@@ -17,9 +19,10 @@ func NewTrackerMock() Tracker {
 	return &TrackerMock{}
 }
 
-func (*TrackerMock) StartedUp() bool                       { return true }
-func (*TrackerMock) Add(name string, val int64)            {}
-func (*TrackerMock) Get(name string) int64                 { return 0 }
-func (*TrackerMock) AddErrorHTTP(method string, val int64) {}
-func (*TrackerMock) AddMany(namedVal64 ...NamedVal64)      {}
-func (*TrackerMock) Register(name string, kind string)     {}
+func (*TrackerMock) StartedUp() bool                          { return true }
+func (*TrackerMock) Add(name string, val int64)               {}
+func (*TrackerMock) Get(name string) int64                    { return 0 }
+func (*TrackerMock) AddErrorHTTP(method string, val int64)    {}
+func (*TrackerMock) AddMany(namedVal64 ...NamedVal64)         {}
+func (*TrackerMock) AddBucket(bck cmn.Bck, nvs ...NamedVal64) {}
+func (*TrackerMock) Register(name string, kind string)        {}
@@ -0,0 +1,62 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/stats/statsd"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+	jsoniter "github.com/json-iterator/go"
+)
+
+func TestLatencyPercentiles(t *testing.T) {
+	s := &CoreStats{statsdC: &statsd.Client{}}
+	s.init(24)
+	for i := 1; i <= 1000; i++ {
+		s.doAdd(GetLatency, "", int64(time.Duration(i)*time.Millisecond))
+	}
+	s.doAdd(GetLatency, "", int64(10*time.Second)) // the tail
+
+	// log/StatsD: the current interval
+	ctracker := make(copyTracker, 48)
+	s.copyT(ctracker)
+	checkPct := func(ctracker copyTracker, suffix string, expected time.Duration) {
+		v, ok := ctracker[GetLatency+"."+suffix]
+		tassert.Fatalf(t, ok, "%s.%s not found", GetLatency, suffix)
+		got := time.Duration(v.Value) * time.Microsecond
+		tassert.Errorf(t, got >= expected && float64(got) < 1.19*float64(expected),
+			"%s: expected ~%v, got %v", suffix, expected, got)
+	}
+	checkPct(ctracker, "p50", 501*time.Millisecond)
+	checkPct(ctracker, "p90", 901*time.Millisecond)
+	checkPct(ctracker, "p99", 991*time.Millisecond)
+	checkPct(ctracker, "p999", 1000*time.Millisecond)
+	tassert.Errorf(t, s.Tracker[GetLatency].ihist.count.Load() == 0, "interval histogram must be reset")
+
+	// REST API: since startup, regardless of the intervals
+	s.doAdd(GetLatency, "", int64(time.Millisecond))
+	ctracker = make(copyTracker, 48)
+	s.copyCumulative(ctracker)
+	checkPct(ctracker, "p50", 501*time.Millisecond)
+}
+
+func TestCoreStatsJSON(t *testing.T) {
+	s := &CoreStats{statsdC: &statsd.Client{}}
+	s.init(24)
+	s.doAdd(GetCount, "", 10)
+	s.doAdd(GetLatency, "", int64(time.Millisecond))
+
+	b, err := jsoniter.Marshal(s)
+	tassert.CheckFatal(t, err)
+	decoded := &CoreStats{}
+	tassert.CheckFatal(t, jsoniter.Unmarshal(b, decoded))
+	tassert.Errorf(t, decoded.Tracker[GetCount].Value == 10,
+		"expected %s=10, got %d", GetCount, decoded.Tracker[GetCount].Value)
+	_, ok := decoded.Tracker[GetLatency+".p99"]
+	tassert.Errorf(t, ok, "expected %s percentiles", GetLatency)
+}
@@ -0,0 +1,124 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"math"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+)
+
+// Latency histogram with fixed logarithmic buckets (in microseconds): bucket 0 counts
+// latencies under 1µs while bucket i > 0 counts latencies in [2^((i-1)/4), 2^(i/4)) µs.
+// With 4 sub-buckets per power of two the relative error of a percentile is under 19%.
+// Fixed boundaries make histograms from different nodes trivially mergeable.
+const (
+	histSubBuckets = 4
+	histNumBuckets = 40*histSubBuckets + 1 // up to 2^40µs (~12 days)
+)
+
+type (
+	Histogram struct {
+		Buckets []int64 `json:"buckets"`    // trailing empty buckets are omitted
+		Sum     int64   `json:"sum,string"` // µs
+		Count   int64   `json:"count,string"`
+	}
+	// lock-free runtime counterpart of the Histogram
+	histogram struct {
+		buckets    [histNumBuckets]atomic.Int64
+		sum, count atomic.Int64
+	}
+)
+
+func histIndex(us int64) int {
+	if us < 1 {
+		return 0
+	}
+	i := int(math.Log2(float64(us))*histSubBuckets) + 1
+	if i >= histNumBuckets {
+		i = histNumBuckets - 1
+	}
+	return i
+}
+
+// upper boundary of the i-th bucket (µs)
+func histUpper(i int) float64 { return math.Exp2(float64(i) / histSubBuckets) }
+
+func (h *histogram) add(d time.Duration) {
+	us := int64(d / time.Microsecond)
+	h.buckets[histIndex(us)].Inc()
+	h.sum.Add(us)
+	h.count.Inc()
+}
+
+func (h *histogram) reset() {
+	for i := range h.buckets {
+		h.buckets[i].Store(0)
+	}
+	h.sum.Store(0)
+	h.count.Store(0)
+}
+
+func (h *histogram) snapshot() *Histogram {
+	s := &Histogram{Sum: h.sum.Load(), Count: h.count.Load()}
+	last := -1
+	counts := make([]int64, histNumBuckets)
+	for i := range h.buckets {
+		if counts[i] = h.buckets[i].Load(); counts[i] != 0 {
+			last = i
+		}
+	}
+	s.Buckets = counts[:last+1]
+	return s
+}
+
+//
+// Histogram
+//
+
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil {
+		return
+	}
+	if len(h.Buckets) < len(other.Buckets) {
+		buckets := make([]int64, len(other.Buckets))
+		copy(buckets, h.Buckets)
+		h.Buckets = buckets
+	}
+	for i, cnt := range other.Buckets {
+		h.Buckets[i] += cnt
+	}
+	h.Sum += other.Sum
+	h.Count += other.Count
+}
+
+func (h *Histogram) Avg() time.Duration {
+	if h == nil || h.Count == 0 {
+		return 0
+	}
+	return time.Duration(h.Sum/h.Count) * time.Microsecond
+}
+
+// Percentile returns the upper boundary of the bucket containing p-th percentile, p in (0, 1]
+func (h *Histogram) Percentile(p float64) time.Duration {
+	if h == nil || h.Count == 0 {
+		return 0
+	}
+	var (
+		cum    int64
+		target = int64(math.Ceil(p * float64(h.Count)))
+	)
+	if target < 1 {
+		target = 1
+	}
+	for i, cnt := range h.Buckets {
+		if cum += cnt; cum >= target {
+			return time.Duration(histUpper(i) * float64(time.Microsecond))
+		}
+	}
+	return time.Duration(histUpper(len(h.Buckets)-1) * float64(time.Microsecond))
+}
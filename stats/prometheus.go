@@ -0,0 +1,204 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Prometheus text exposition format, version 0.0.4
+// (see https://prometheus.io/docs/instrumenting/exposition_formats)
+const (
+	PromContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	promPrefix = "ais_"
+
+	promCounter = "counter"
+	promGauge   = "gauge"
+	promSummary = "summary"
+)
+
+type (
+	// PromWriter collects metrics (grouped by metric family) and writes them out
+	// in the Prometheus text format. Each sample gets labeled with the node ID.
+	PromWriter struct {
+		node     string
+		families map[string]*promFamily
+	}
+	promFamily struct {
+		typ     string
+		help    string
+		samples []string
+	}
+)
+
+func NewPromWriter(node string) *PromWriter {
+	return &PromWriter{node: node, families: make(map[string]*promFamily, 64)}
+}
+
+// labels are given as name-value pairs
+func (pw *PromWriter) add(name, typ, help string, value float64, labels ...string) {
+	pw.addSample(name, name, typ, help, value, labels...)
+}
+
+func (pw *PromWriter) addSample(family, name, typ, help string, value float64, labels ...string) {
+	f, ok := pw.families[family]
+	if !ok {
+		f = &promFamily{typ: typ, help: help}
+		pw.families[family] = f
+	}
+	sb := strings.Builder{}
+	sb.WriteString(name)
+	sb.WriteString(`{node="`)
+	sb.WriteString(promEscape(pw.node))
+	sb.WriteByte('"')
+	for i := 0; i+1 < len(labels); i += 2 {
+		sb.WriteByte(',')
+		sb.WriteString(labels[i])
+		sb.WriteString(`="`)
+		sb.WriteString(promEscape(labels[i+1]))
+		sb.WriteByte('"')
+	}
+	sb.WriteString("} ")
+	sb.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	f.samples = append(f.samples, sb.String())
+}
+
+func (pw *PromWriter) WriteTo(w io.Writer) (int64, error) {
+	names := make([]string, 0, len(pw.families))
+	for name := range pw.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	buf := &bytes.Buffer{}
+	for _, name := range names {
+		f := pw.families[name]
+		fmt.Fprintf(buf, "# HELP %s %s\n", name, f.help)
+		fmt.Fprintf(buf, "# TYPE %s %s\n", name, f.typ)
+		for _, sample := range f.samples {
+			buf.WriteString(sample)
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.WriteTo(w)
+}
+
+// CoreStats => metrics, e.g.:
+// get.n => ais_get_total, get.cold.size => ais_get_cold_bytes_total,
+// get.µs => ais_get_latency_seconds (summary with quantiles), get.bps => ais_get_throughput_bytes_total
+func (s *CoreStats) prometheus(pw *PromWriter) {
+	for name, v := range s.Tracker {
+		v.RLock()
+		switch v.kind {
+		case KindCounter:
+			var pname string
+			if strings.HasSuffix(name, ".size") {
+				pname = promName(strings.TrimSuffix(name, ".size")) + "_bytes_total"
+			} else {
+				pname = promName(strings.TrimSuffix(name, ".n")) + "_total"
+			}
+			pw.add(pname, promCounter, name, float64(v.Value))
+		case KindLatency:
+			family := promName(strings.Replace(name, ".µs", "", 1)) + "_latency_seconds"
+			help := name + " (cumulative)"
+			if h := v.hist.snapshot(); h.Count > 0 {
+				for _, pct := range latencyPercentiles {
+					pw.addSample(family, family, promSummary, help, h.Percentile(pct.p).Seconds(),
+						"quantile", strconv.FormatFloat(pct.p, 'g', -1, 64))
+				}
+			}
+			pw.addSample(family, family+"_sum", promSummary, help, float64(v.cumulative)/1e6)
+			pw.addSample(family, family+"_count", promSummary, help, float64(v.cumSamples))
+		case KindThroughput:
+			pname := promName(strings.TrimSuffix(name, ".bps")) + "_throughput_bytes_total"
+			pw.add(pname, promCounter, name+" (cumulative)", float64(v.cumulative))
+		default:
+			if name == Uptime {
+				pw.add(promPrefix+"uptime_seconds", promGauge, name, float64(v.Value)/1e6)
+			} else {
+				pw.add(promName(name), promGauge, name, float64(v.Value))
+			}
+		}
+		v.RUnlock()
+	}
+}
+
+func (r *Prunner) Prometheus(pw *PromWriter) { r.Core.prometheus(pw) }
+
+// core stats, plus mountpath capacities and utilizations
+func (r *Trunner) Prometheus(pw *PromWriter) {
+	r.Core.prometheus(pw)
+	for mpath, fsCapacity := range r.Capacity {
+		pw.add(promPrefix+"mountpath_used_bytes", promGauge, "used capacity",
+			float64(fsCapacity.Used), "mountpath", mpath)
+		pw.add(promPrefix+"mountpath_avail_bytes", promGauge, "available capacity",
+			float64(fsCapacity.Avail), "mountpath", mpath)
+	}
+	for mpath, util := range fs.Mountpaths.GetAllMpathUtils(time.Now()) {
+		pw.add(promPrefix+"mountpath_util_percent", promGauge, "disk utilization",
+			float64(util), "mountpath", mpath)
+	}
+}
+
+// the most recent xaction of a given kind (and bucket, if applicable)
+func (pw *PromWriter) AddXactStats(xacts map[string]XactStats) {
+	type key struct{ kind, bck string }
+	latest := make(map[key]XactStats, len(xacts))
+	for _, xact := range xacts {
+		var bck string
+		if b := xact.Bck(); b.Name != "" {
+			bck = b.String()
+		}
+		k := key{xact.Kind(), bck}
+		if prev, ok := latest[k]; !ok || prev.StartTime().Before(xact.StartTime()) {
+			latest[k] = xact
+		}
+	}
+	for k, xact := range latest {
+		var running float64
+		if xact.Running() {
+			running = 1
+		}
+		labels := []string{"xaction", k.kind, "bucket", k.bck}
+		pw.add(promPrefix+"xaction_objects", promGauge, "number of objects processed by the most recent xaction",
+			float64(xact.ObjCount()), labels...)
+		pw.add(promPrefix+"xaction_bytes", promGauge, "number of bytes processed by the most recent xaction",
+			float64(xact.BytesCount()), labels...)
+		pw.add(promPrefix+"xaction_running", promGauge, "1 if the most recent xaction is running, 0 otherwise",
+			running, labels...)
+		pw.add(promPrefix+"xaction_start_time_seconds", promGauge, "start time of the most recent xaction",
+			float64(xact.StartTime().UnixNano())/1e9, labels...)
+	}
+}
+
+//
+// helpers
+//
+
+// e.g.: "err.cksum" => "ais_err_cksum"
+func promName(name string) string {
+	b := []byte(promPrefix + name)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+func promEscape(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
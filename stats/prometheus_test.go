@@ -0,0 +1,55 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestPrometheus(t *testing.T) {
+	s := &CoreStats{}
+	s.init(24)
+	s.Tracker.register(GetColdSize, KindCounter)
+	s.Tracker.register(GetThroughput, KindThroughput)
+	s.Tracker[GetCount].Value = 10
+	s.Tracker[GetColdSize].Value = 2048
+	s.Tracker[GetLatency].cumulative = 3000000 // µs
+	s.Tracker[GetLatency].cumSamples = 4
+	s.Tracker[GetThroughput].cumulative = 4096
+	s.UpdateUptime(time.Minute)
+
+	pw := NewPromWriter("t1")
+	s.prometheus(pw)
+	pw.AddXactStats(map[string]XactStats{
+		"x1": &BaseXactStats{IDX: "x1", KindX: cmn.ActLRU, ObjCountX: 5, StartTimeX: time.Unix(1, 0)},
+		"x2": &BaseXactStats{IDX: "x2", KindX: cmn.ActLRU, ObjCountX: 7, StartTimeX: time.Unix(2, 0)},
+	})
+	buf := &bytes.Buffer{}
+	_, err := pw.WriteTo(buf)
+	tassert.CheckFatal(t, err)
+	out := buf.String()
+
+	for _, expected := range []string{
+		"# TYPE ais_get_total counter\nais_get_total{node=\"t1\"} 10\n",
+		"ais_get_cold_bytes_total{node=\"t1\"} 2048\n",
+		"# TYPE ais_get_latency_seconds summary\n",
+		"ais_get_latency_seconds_sum{node=\"t1\"} 3\n",
+		"ais_get_latency_seconds_count{node=\"t1\"} 4\n",
+		"ais_get_throughput_bytes_total{node=\"t1\"} 4096\n",
+		"ais_uptime_seconds{node=\"t1\"} 60\n",
+		"ais_xaction_objects{node=\"t1\",xaction=\"" + cmn.ActLRU + "\",bucket=\"\"} 7\n",
+		"ais_xaction_running{node=\"t1\",xaction=\"" + cmn.ActLRU + "\",bucket=\"\"} 1\n",
+	} {
+		tassert.Errorf(t, strings.Contains(out, expected), "expected %q in:\n%s", expected, out)
+	}
+	tassert.Errorf(t, strings.Count(out, "# TYPE ais_get_latency_seconds ") == 1, "summary family must be declared once")
+}
@@ -40,6 +40,7 @@ const (
 	ErrCksumSize     = "err.cksum.size"
 	ErrMetadataCount = "err.md.n"
 	ErrIOCount       = "err.io.n"
+	PutSize          = "put.size"
 	DownloadSize     = "dl.size"
 
 	// KindLatency
@@ -79,7 +80,8 @@ type (
 			capLimit atomic.Int64
 			capIdx   int64 // update capacity: time interval counting
 		}
-		lines []string
+		lines   []string
+		buckets bucketTracker
 	}
 	copyRunner struct {
 		Tracker  copyTracker            `json:"core"`
@@ -106,6 +108,15 @@ func (r *Trunner) Register(name string, kind string) { r.Core.Tracker.register(n
 func (r *Trunner) Run() error                        { return r.runcommon(r) }
 func (r *Trunner) Get(name string) (val int64)       { return r.Core.get(name) }
 
+// per-bucket stats are updated in place, bypassing the runner's channel
+func (r *Trunner) AddBucket(bck cmn.Bck, nvs ...NamedVal64) { r.buckets.add(bck, nvs...) }
+
+// to be called upon receiving BMD that removes buckets
+func (r *Trunner) DelBuckets(bcks ...cmn.Bck) { r.buckets.del(bcks...) }
+
+// see bucketTracker.snapshot for the query semantics
+func (r *Trunner) GetBucketStats(query cmn.Bck) BucketStatsMap { return r.buckets.snapshot(query) }
+
 func (r *Trunner) Init(daemonStr, daemonID string, daemonStarted *atomic.Bool) *atomic.Bool {
 	r.Core = &CoreStats{}
 	r.Core.init(48) // and register common stats (target's own stats are registered elsewhere via the Register() above)
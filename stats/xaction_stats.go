@@ -70,6 +70,16 @@ type RebalanceTargetStats struct {
 	Ext ExtRebalanceStats `json:"ext"`
 }
 
+type DecommissionTargetStats struct {
+	BaseXactStats
+	Ext ExtDecommissionStats `json:"ext"`
+}
+
+type ExtDecommissionStats struct {
+	Stage string `json:"stage"`
+	Err   string `json:"error,omitempty"`
+}
+
 type ExtRebalanceStats struct {
 	TxRebCount  int64 `json:"tx.reb.n,string"`
 	TxRebSize   int64 `json:"tx.reb.size,string"`
@@ -0,0 +1,209 @@
+// Package tracing provides distributed request tracing: W3C trace context propagation
+// across proxies and targets, spans, and OpenTelemetry (OTLP) compatible export.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Spans are exported in batches encoded as OTLP/JSON (ExportTraceServiceRequest),
+// which makes both exporters compatible with the OpenTelemetry collector: the "otlp"
+// exporter POSTs the batches to the collector's OTLP/HTTP receiver while the "file"
+// exporter appends them, one batch per line, to a local file (see the collector's
+// "otlpjsonfile" receiver).
+
+const (
+	exportTimeout = 10 * time.Second
+	statusError   = 2 // OTLP STATUS_CODE_ERROR
+)
+
+type (
+	exporter interface {
+		export(b []byte) error
+		close() error
+	}
+	fileExporter struct {
+		fh *os.File
+	}
+	otlpExporter struct {
+		endpoint string
+		client   *http.Client
+	}
+
+	// OTLP/JSON
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttr `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID      string      `json:"traceId"`
+		SpanID       string      `json:"spanId"`
+		ParentSpanID string      `json:"parentSpanId,omitempty"`
+		Name         string      `json:"name"`
+		Kind         Kind        `json:"kind"`
+		Start        string      `json:"startTimeUnixNano"`
+		End          string      `json:"endTimeUnixNano"`
+		Attributes   []otlpAttr  `json:"attributes,omitempty"`
+		Status       *otlpStatus `json:"status,omitempty"`
+	}
+	otlpAttr struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		String *string  `json:"stringValue,omitempty"`
+		Int    *string  `json:"intValue,omitempty"` // int64 is a string in OTLP/JSON
+		Double *float64 `json:"doubleValue,omitempty"`
+		Bool   *bool    `json:"boolValue,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+)
+
+func newExporter(conf *cmn.TracingConf) (exporter, error) {
+	switch conf.Exporter {
+	case cmn.TracingExporterFile:
+		fh, err := os.OpenFile(conf.Endpoint, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return &fileExporter{fh: fh}, nil
+	case cmn.TracingExporterOTLP:
+		client := cmn.NewClient(cmn.TransportArgs{Timeout: exportTimeout, UseHTTPProxyEnv: true})
+		return &otlpExporter{endpoint: conf.Endpoint, client: client}, nil
+	default:
+		return nil, fmt.Errorf("invalid tracing exporter %q", conf.Exporter)
+	}
+}
+
+//
+// file
+//
+
+func (e *fileExporter) export(b []byte) (err error) {
+	_, err = e.fh.Write(append(b, '\n'))
+	return
+}
+
+func (e *fileExporter) close() error { return e.fh.Close() }
+
+//
+// OTLP/HTTP
+//
+
+func (e *otlpExporter) export(b []byte) error {
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%s: %s (%s)", e.endpoint, resp.Status, body)
+	}
+	return nil
+}
+
+func (e *otlpExporter) close() error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+//
+// encoding
+//
+
+func (t *tracer) encode(spans []*Span) []byte {
+	scope := otlpScopeSpans{Scope: otlpScope{Name: "aistore"}, Spans: make([]otlpSpan, 0, len(spans))}
+	for _, s := range spans {
+		sp := otlpSpan{
+			TraceID: hex.EncodeToString(s.sc.TraceID[:]),
+			SpanID:  hex.EncodeToString(s.sc.SpanID[:]),
+			Name:    s.name,
+			Kind:    s.kind,
+			Start:   strconv.FormatInt(s.start.UnixNano(), 10),
+			End:     strconv.FormatInt(s.end.UnixNano(), 10),
+		}
+		if s.parent != (SpanID{}) {
+			sp.ParentSpanID = hex.EncodeToString(s.parent[:])
+		}
+		if len(s.attrs) > 0 {
+			sp.Attributes = make([]otlpAttr, 0, len(s.attrs))
+			for _, a := range s.attrs {
+				sp.Attributes = append(sp.Attributes, newAttr(a.key, a.value))
+			}
+		}
+		if s.errMsg != "" {
+			sp.Status = &otlpStatus{Code: statusError, Message: s.errMsg}
+		}
+		scope.Spans = append(scope.Spans, sp)
+	}
+	req := otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpAttr{
+			newAttr("service.name", t.service),
+			newAttr("service.instance.id", t.node),
+		}},
+		ScopeSpans: []otlpScopeSpans{scope},
+	}}}
+	b, err := jsoniter.Marshal(&req)
+	cmn.AssertNoErr(err)
+	return b
+}
+
+func newAttr(key string, value interface{}) otlpAttr {
+	a := otlpAttr{Key: key}
+	switch v := value.(type) {
+	case string:
+		a.Value.String = &v
+	case bool:
+		a.Value.Bool = &v
+	case int:
+		s := strconv.Itoa(v)
+		a.Value.Int = &s
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		a.Value.Int = &s
+	case float64:
+		a.Value.Double = &v
+	case time.Duration:
+		s := v.String()
+		a.Value.String = &s
+	default:
+		s := fmt.Sprintf("%v", v)
+		a.Value.String = &s
+	}
+	return a
+}
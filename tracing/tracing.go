@@ -0,0 +1,368 @@
+// Package tracing provides distributed request tracing: W3C trace context propagation
+// across proxies and targets, spans, and OpenTelemetry (OTLP) compatible export.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// span kinds (the values are OTLP's)
+const (
+	KindInternal Kind = 1
+	KindServer   Kind = 2
+	KindClient   Kind = 3
+)
+
+const (
+	batchSize     = 512
+	flushInterval = time.Second
+	traceVersion  = "00"
+	flagSampled   = 0x1
+)
+
+type (
+	Kind int
+
+	TraceID [16]byte
+	SpanID  [8]byte
+
+	// SpanContext is what gets propagated: in the context, in the intra-cluster
+	// requests (cmn.HeaderTraceparent), in the redirect URLs (cmn.URLParamTraceparent),
+	// and in the transport headers
+	SpanContext struct {
+		TraceID TraceID
+		SpanID  SpanID
+		Sampled bool
+	}
+
+	// Span is a timed operation; nil span (tracing disabled or trace not sampled)
+	// is a valid no-op span
+	Span struct {
+		sc     SpanContext
+		parent SpanID
+		name   string
+		kind   Kind
+		start  time.Time
+		end    time.Time
+		attrs  []attr
+		errMsg string
+		ended  atomic.Bool
+	}
+	attr struct {
+		key   string
+		value interface{}
+	}
+
+	tracer struct {
+		service string
+		node    string
+		ratio   float64
+		exp     exporter
+		spanCh  chan *Span
+		stopCh  cmn.StopCh
+		wg      sync.WaitGroup
+		dropped atomic.Int64
+	}
+
+	ctxKey struct{}
+)
+
+var (
+	// NOTE: initialized once at startup, prior to serving requests
+	tr *tracer
+
+	rndMu sync.Mutex
+	rnd   = cmn.NowRand()
+)
+
+func Init(conf *cmn.TracingConf, service, node string) (err error) {
+	if !conf.Enabled {
+		return
+	}
+	t := &tracer{
+		service: service,
+		node:    node,
+		ratio:   conf.SampleRatio,
+		spanCh:  make(chan *Span, batchSize*4),
+		stopCh:  cmn.NewStopCh(),
+	}
+	if t.exp, err = newExporter(conf); err != nil {
+		return
+	}
+	t.wg.Add(1)
+	go t.run()
+	tr = t
+	glog.Infof("tracing: %s => %s %s (sample ratio %v)", node, conf.Exporter, conf.Endpoint, conf.SampleRatio)
+	return
+}
+
+// Stop flushes the pending spans and closes the exporter
+func Stop() {
+	t := tr
+	if t == nil {
+		return
+	}
+	tr = nil
+	t.stopCh.Close()
+	t.wg.Wait()
+}
+
+func Enabled() bool { return tr != nil }
+
+// Start starts a new span as a child of the (local or remote) span in the context, if any;
+// otherwise, the new span becomes the root of a new trace, subject to sampling
+func Start(ctx context.Context, name string, kind Kind) (context.Context, *Span) {
+	return StartAt(ctx, name, kind, time.Now())
+}
+
+func StartAt(ctx context.Context, name string, kind Kind, start time.Time) (context.Context, *Span) {
+	t := tr
+	if t == nil {
+		return ctx, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	span := &Span{name: name, kind: kind, start: start}
+	if parent, ok := FromContext(ctx); ok {
+		if !parent.Sampled {
+			return ctx, nil
+		}
+		span.sc.TraceID, span.parent = parent.TraceID, parent.SpanID
+	} else {
+		span.sc.TraceID = newTraceID()
+		if t.ratio < 1 && random() >= t.ratio {
+			// not sampled: propagate the decision downstream (with a valid, non-zero
+			// span ID - see ParseTraceparent)
+			sc := SpanContext{TraceID: span.sc.TraceID, SpanID: newSpanID()}
+			return context.WithValue(ctx, ctxKey{}, sc), nil
+		}
+	}
+	span.sc.SpanID = newSpanID()
+	span.sc.Sampled = true
+	return context.WithValue(ctx, ctxKey{}, span.sc), span
+}
+
+func FromContext(ctx context.Context) (sc SpanContext, ok bool) {
+	if ctx == nil {
+		return
+	}
+	sc, ok = ctx.Value(ctxKey{}).(SpanContext)
+	return
+}
+
+// WithTraceparent returns context with the remote parent (if valid)
+func WithTraceparent(ctx context.Context, traceparent string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if tr == nil || traceparent == "" {
+		return ctx
+	}
+	sc, err := ParseTraceparent(traceparent)
+	if err != nil {
+		glog.Warningf("tracing: %v", err)
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, sc)
+}
+
+// FromRequest returns context with the remote parent: the redirecting proxy's span
+// (URL query) takes precedence over the caller's (request header)
+func FromRequest(ctx context.Context, r *http.Request) context.Context {
+	if tr == nil {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		return ctx
+	}
+	traceparent := r.URL.Query().Get(cmn.URLParamTraceparent)
+	if traceparent == "" {
+		traceparent = r.Header.Get(cmn.HeaderTraceparent)
+	}
+	return WithTraceparent(ctx, traceparent)
+}
+
+// Traceparent returns the context's trace context formatted for propagation
+// (empty if there's none)
+func Traceparent(ctx context.Context) string {
+	if tr == nil {
+		return ""
+	}
+	if sc, ok := FromContext(ctx); ok {
+		return sc.String()
+	}
+	return ""
+}
+
+// Inject adds the context's trace context to the outgoing request header
+func Inject(ctx context.Context, hdr http.Header) {
+	if traceparent := Traceparent(ctx); traceparent != "" {
+		hdr.Set(cmn.HeaderTraceparent, traceparent)
+	}
+}
+
+//
+// SpanContext
+//
+
+// version "00" traceparent: "00-<trace ID>-<span ID>-<flags>"
+func (sc SpanContext) String() string {
+	var flags byte
+	if sc.Sampled {
+		flags = flagSampled
+	}
+	return fmt.Sprintf("%s-%x-%x-%02x", traceVersion, sc.TraceID[:], sc.SpanID[:], flags)
+}
+
+func ParseTraceparent(traceparent string) (sc SpanContext, err error) {
+	var flags [1]byte
+	if len(traceparent) != 55 || traceparent[:3] != traceVersion+"-" ||
+		traceparent[35] != '-' || traceparent[52] != '-' {
+		err = fmt.Errorf("invalid traceparent %q", traceparent)
+		return
+	}
+	if _, err = hex.Decode(sc.TraceID[:], []byte(traceparent[3:35])); err != nil {
+		return
+	}
+	if _, err = hex.Decode(sc.SpanID[:], []byte(traceparent[36:52])); err != nil {
+		return
+	}
+	if _, err = hex.Decode(flags[:], []byte(traceparent[53:])); err != nil {
+		return
+	}
+	if sc.TraceID == (TraceID{}) || sc.SpanID == (SpanID{}) {
+		err = fmt.Errorf("invalid traceparent %q: zero ID", traceparent)
+		return
+	}
+	sc.Sampled = flags[0]&flagSampled != 0
+	return
+}
+
+//
+// Span
+//
+
+func (s *Span) SetAttr(key string, value interface{}) {
+	if s != nil {
+		s.attrs = append(s.attrs, attr{key, value})
+	}
+}
+
+// Traceparent to propagate this span as the remote parent
+func (s *Span) Traceparent() string {
+	if s == nil {
+		return ""
+	}
+	return s.sc.String()
+}
+
+// End records the span; non-nil error sets the span's status
+func (s *Span) End(err error) {
+	if s == nil || !s.ended.CAS(false, true) {
+		return
+	}
+	s.end = time.Now()
+	if err != nil {
+		s.errMsg = err.Error()
+	}
+	t := tr
+	if t == nil {
+		return
+	}
+	select {
+	case t.spanCh <- s:
+	default:
+		if n := t.dropped.Inc(); n%1000 == 1 {
+			glog.Warningf("tracing: exporter can't keep up, dropped %d spans", n)
+		}
+	}
+}
+
+//
+// tracer
+//
+
+func (t *tracer) run() {
+	var (
+		batch  = make([]*Span, 0, batchSize)
+		ticker = time.NewTicker(flushInterval)
+	)
+	defer func() {
+		ticker.Stop()
+		if err := t.exp.close(); err != nil {
+			glog.Errorf("tracing: %v", err)
+		}
+		t.wg.Done()
+	}()
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := t.exp.export(t.encode(batch)); err != nil {
+			glog.Errorf("tracing: failed to export %d spans: %v", len(batch), err)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case s := <-t.spanCh:
+			if batch = append(batch, s); len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-t.stopCh.Listen():
+			for {
+				select {
+				case s := <-t.spanCh:
+					batch = append(batch, s)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+//
+// IDs
+//
+
+func newTraceID() (id TraceID) {
+	rndMu.Lock()
+	binary.BigEndian.PutUint64(id[:8], rnd.Uint64())
+	binary.BigEndian.PutUint64(id[8:], rnd.Uint64())
+	rndMu.Unlock()
+	return
+}
+
+func newSpanID() (id SpanID) {
+	rndMu.Lock()
+	for id == (SpanID{}) {
+		binary.BigEndian.PutUint64(id[:], rnd.Uint64())
+	}
+	rndMu.Unlock()
+	return
+}
+
+func random() (f float64) {
+	rndMu.Lock()
+	f = rnd.Float64()
+	rndMu.Unlock()
+	return
+}
@@ -0,0 +1,159 @@
+// Package tracing provides distributed request tracing: W3C trace context propagation
+// across proxies and targets, spans, and OpenTelemetry (OTLP) compatible export.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+	jsoniter "github.com/json-iterator/go"
+)
+
+func TestTraceparent(t *testing.T) {
+	const tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, err := ParseTraceparent(tp)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, sc.Sampled, "expected sampled")
+	tassert.Errorf(t, sc.String() == tp, "expected %q, got %q", tp, sc.String())
+
+	sc.Sampled = false
+	tassert.Errorf(t, strings.HasSuffix(sc.String(), "-00"), "expected not sampled, got %q", sc.String())
+
+	for _, invalid := range []string{
+		"",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01",
+	} {
+		_, err := ParseTraceparent(invalid)
+		tassert.Errorf(t, err != nil, "expected %q to be invalid", invalid)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	ctx, span := Start(context.Background(), "noop", KindInternal)
+	tassert.Errorf(t, span == nil, "expected no-op span")
+	span.SetAttr("k", "v")
+	span.End(nil)
+	tassert.Errorf(t, Traceparent(ctx) == "", "expected no trace context")
+}
+
+func TestExportFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+	var (
+		path = filepath.Join(dir, "spans.json")
+		conf = &cmn.TracingConf{Enabled: true, Exporter: cmn.TracingExporterFile, Endpoint: path, SampleRatio: 1}
+	)
+	tassert.CheckFatal(t, Init(conf, "aisproxy", "p1"))
+
+	// proxy: the root span, propagated via request header
+	ctx, root := Start(context.Background(), "GET redirect", KindServer)
+	root.SetAttr("target", "t1")
+	hdr := make(http.Header)
+	Inject(ctx, hdr)
+	tassert.Fatalf(t, hdr.Get(cmn.HeaderTraceparent) == root.Traceparent(), "expected traceparent header")
+
+	// target: the child span, and its own child
+	req, _ := http.NewRequest(http.MethodGet, "http://t1/v1/objects/b/o", nil)
+	req.Header = hdr
+	ctx2, child := Start(FromRequest(context.Background(), req), "GET", KindServer)
+	_, grandchild := Start(ctx2, "cold GET", KindInternal)
+	grandchild.End(errors.New("failed"))
+	child.End(nil)
+	child.End(nil) // no-op
+	root.End(nil)
+	Stop()
+	tassert.Errorf(t, !Enabled(), "expected disabled after Stop")
+
+	fh, err := os.Open(path)
+	tassert.CheckFatal(t, err)
+	defer fh.Close()
+	var (
+		spans   = make(map[string]otlpSpan)
+		scanner = bufio.NewScanner(fh)
+	)
+	for scanner.Scan() {
+		var req otlpRequest
+		tassert.CheckFatal(t, jsoniter.Unmarshal(scanner.Bytes(), &req))
+		tassert.Fatalf(t, len(req.ResourceSpans) == 1, "expected single resource")
+		attrs := req.ResourceSpans[0].Resource.Attributes
+		tassert.Errorf(t, len(attrs) == 2 && *attrs[0].Value.String == "aisproxy", "unexpected resource %+v", attrs)
+		for _, sp := range req.ResourceSpans[0].ScopeSpans[0].Spans {
+			spans[sp.Name] = sp
+		}
+	}
+	tassert.Fatalf(t, len(spans) == 3, "expected 3 spans, got %d", len(spans))
+	var (
+		r, c, g = spans["GET redirect"], spans["GET"], spans["cold GET"]
+	)
+	tassert.Errorf(t, r.TraceID == c.TraceID && c.TraceID == g.TraceID, "expected single trace")
+	tassert.Errorf(t, r.ParentSpanID == "", "expected root span")
+	tassert.Errorf(t, c.ParentSpanID == r.SpanID && g.ParentSpanID == c.SpanID, "unexpected parent-child relationship")
+	tassert.Errorf(t, c.Kind == KindServer && g.Kind == KindInternal, "unexpected kinds")
+	tassert.Errorf(t, len(r.Attributes) == 1 && *r.Attributes[0].Value.String == "t1", "unexpected attributes")
+	tassert.Errorf(t, g.Status != nil && g.Status.Code == statusError && g.Status.Message == "failed",
+		"expected error status, got %+v", g.Status)
+	tassert.Errorf(t, c.Status == nil, "expected ok status")
+}
+
+func TestSampling(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+	conf := &cmn.TracingConf{
+		Enabled:     true,
+		Exporter:    cmn.TracingExporterFile,
+		Endpoint:    filepath.Join(dir, "spans.json"),
+		SampleRatio: 0.0001,
+	}
+	tassert.CheckFatal(t, Init(conf, "aistarget", "t1"))
+	defer Stop()
+
+	var (
+		sampled int
+		tps     []string
+	)
+	for i := 0; i < 100; i++ {
+		ctx, span := Start(context.Background(), "root", KindServer)
+		if span != nil {
+			sampled++
+			continue
+		}
+		// the decision not to sample propagates: no child spans
+		tp := Traceparent(ctx)
+		sc, err := ParseTraceparent(tp)
+		tassert.Fatalf(t, err == nil && !sc.Sampled, "expected valid not sampled traceparent, got %q (%v)", tp, err)
+		rctx := WithTraceparent(context.Background(), tp)
+		rsc, ok := FromContext(rctx)
+		tassert.Errorf(t, ok && rsc.TraceID == sc.TraceID, "expected remote parent %q", tp)
+		_, child := Start(rctx, "child", KindInternal)
+		tassert.Errorf(t, child == nil, "expected child of not sampled span to be no-op")
+		tps = append(tps, tp)
+	}
+	tassert.Errorf(t, sampled < 10, "expected (almost) nothing sampled, got %d", sampled)
+
+	// the receiver that samples everything keeps the sender's decision
+	Stop()
+	conf.SampleRatio = 1
+	tassert.CheckFatal(t, Init(conf, "aistarget", "t2"))
+	for _, tp := range tps {
+		_, child := Start(WithTraceparent(context.Background(), tp), "child", KindServer)
+		tassert.Errorf(t, child == nil, "expected child of not sampled %q to be no-op", tp)
+	}
+}
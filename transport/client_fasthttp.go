@@ -34,11 +34,19 @@ func dialTimeout(addr string) (net.Conn, error) {
 // intra-cluster networking: fasthttp client
 func NewIntraDataClient() Client {
 	config := cmn.GCO.Get()
-	return &fasthttp.Client{
+	client := &fasthttp.Client{
 		Dial:            dialTimeout,
 		ReadBufferSize:  config.Net.HTTP.ReadBufferSize,
 		WriteBufferSize: config.Net.HTTP.WriteBufferSize,
+		// streamed request body cannot be replayed - see reliable mode for retransmission
+		MaxIdemponentCallAttempts: 1,
 	}
+	if config.Net.HTTP.UseIntraTLS {
+		tlsConf, err := cmn.NewIntraTLSConfig(&config.Net.HTTP)
+		cmn.AssertNoErr(err) // validated at startup
+		client.TLSConfig = tlsConf
+	}
+	return client
 }
 
 func (s *Stream) do(body io.Reader) (err error) {
@@ -48,9 +56,12 @@ func (s *Stream) do(body io.Reader) (err error) {
 	req.SetRequestURI(s.toURL)
 	req.SetBodyStream(body, -1)
 	if s.compressed() {
-		req.Header.Set(cmn.HeaderCompress, cmn.LZ4Compression)
+		req.Header.Set(cmn.HeaderCompress, s.cmprs.codec)
 	}
 	req.Header.Set(cmn.HeaderSessID, strconv.FormatInt(s.sessID, 10))
+	if s.rel != nil {
+		req.Header.Set(cmn.HeaderStreamID, s.rel.id)
+	}
 	// do
 	err = s.client.Do(req, resp)
 	if err != nil {
@@ -59,11 +70,14 @@ func (s *Stream) do(body io.Reader) (err error) {
 	}
 	// handle response & cleanup
 	resp.BodyWriteTo(ioutil.Discard)
+	if s.rel != nil {
+		err = s.ackRecv(string(resp.Header.Peek(cmn.HeaderSeqAck)), resp.StatusCode())
+	}
 	fasthttp.ReleaseRequest(req)
 	fasthttp.ReleaseResponse(resp)
 	if s.compressed() {
-		s.lz4s.sgl.Reset()
-		s.lz4s.zw.Reset(nil)
+		s.cmprs.sgl.reset()
+		s.cmprs.zw.Reset(nil)
 	}
 	return
 }
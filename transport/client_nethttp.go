@@ -30,6 +30,7 @@ func NewIntraDataClient() *http.Client {
 		SndRcvBufSize:   config.Net.L4.SndRcvBufSize,
 		WriteBufferSize: config.Net.HTTP.WriteBufferSize,
 		ReadBufferSize:  config.Net.HTTP.ReadBufferSize,
+		UseIntraTLS:     true,
 	})
 }
 
@@ -43,9 +44,12 @@ func (s *Stream) do(body io.Reader) (err error) {
 		return
 	}
 	if s.compressed() {
-		request.Header.Set(cmn.HeaderCompress, cmn.LZ4Compression)
+		request.Header.Set(cmn.HeaderCompress, s.cmprs.codec)
 	}
 	request.Header.Set(cmn.HeaderSessID, strconv.FormatInt(s.sessID, 10))
+	if s.rel != nil {
+		request.Header.Set(cmn.HeaderStreamID, s.rel.id)
+	}
 
 	// do
 	response, err = s.client.Do(request)
@@ -57,9 +61,12 @@ func (s *Stream) do(body io.Reader) (err error) {
 	// handle response & cleanup
 	io.Copy(ioutil.Discard, response.Body)
 	response.Body.Close()
+	if s.rel != nil {
+		err = s.ackRecv(response.Header.Get(cmn.HeaderSeqAck), response.StatusCode)
+	}
 	if s.compressed() {
-		s.lz4s.sgl.Reset()
-		s.lz4s.zw.Reset(nil)
+		s.cmprs.sgl.reset()
+		s.cmprs.zw.Reset(nil)
 	}
 	return
 }
@@ -9,6 +9,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"net/http"
 	"path"
@@ -16,6 +17,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/3rdparty/golang/mux"
 	"github.com/NVIDIA/aistore/cmn"
@@ -23,6 +25,7 @@ import (
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/xoshiro256"
 	"github.com/OneOfOne/xxhash"
+	"github.com/klauspost/compress/zstd"
 	lz4 "github.com/pierrec/lz4/v3"
 )
 
@@ -40,21 +43,29 @@ type (
 		body      io.Reader
 		fbuf      *fixedBuffer // when extraBuffering == true
 		headerBuf []byte
+		reliable  bool // headers carry sequence numbers
 	}
 	objReader struct {
 		body io.Reader
 		off  int64
 		fbuf *fixedBuffer // ditto
 		hdr  Header
+		seq  int64 // reliable mode: sequence number
 	}
 	handler struct {
 		trname      string
 		callback    Receive
 		sessions    sync.Map // map[uint64]*Stats
 		oldSessions sync.Map // map[uint64]time.Time
+		reliable    sync.Map // map[string]*relRecv by stream ID (reliable mode)
 		hkName      string   // house-keeping name
 		mem         *memsys.MMSA
 	}
+	// counts compressed bytes received (note: zstd decoder reads in its own goroutine)
+	countingReader struct {
+		r io.Reader
+		n atomic.Int64
+	}
 	fixedBuffer struct {
 		slab *memsys.Slab
 		buf  []byte
@@ -210,16 +221,32 @@ func (h *handler) receive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var (
-		reader    io.Reader = r.Body
-		lz4Reader *lz4.Reader
-		fbuf      *fixedBuffer
-		debug     = bool(glog.FastV(4, glog.SmoduleTransport))
+		reader     io.Reader = r.Body
+		lz4Reader  *lz4.Reader
+		zstdReader *zstd.Decoder
+		fbuf       *fixedBuffer
+		cbody      *countingReader
+		debug      = bool(glog.FastV(4, glog.SmoduleTransport))
 	)
 	// compression
-	if compressionType := r.Header.Get(cmn.HeaderCompress); compressionType != "" {
-		cmn.Assert(compressionType == cmn.LZ4Compression)
-		lz4Reader = lz4.NewReader(r.Body)
-		reader = lz4Reader
+	codec := r.Header.Get(cmn.HeaderCompress)
+	if codec != "" {
+		cbody = &countingReader{r: r.Body}
+		switch codec {
+		case cmn.LZ4Compression:
+			lz4Reader = lz4.NewReader(cbody)
+			reader = lz4Reader
+		case cmn.ZstdCompression:
+			var err error
+			if zstdReader, err = zstd.NewReader(cbody, zstd.WithDecoderConcurrency(1)); err != nil {
+				cmn.InvalidHandlerDetailed(w, r, fmt.Sprintf("%s: failed to init %s decoder, err %v", trname, codec, err))
+				return
+			}
+			reader = zstdReader
+		default:
+			cmn.InvalidHandlerDetailed(w, r, fmt.Sprintf("%s: unsupported compression %q", trname, codec))
+			return
+		}
 		if extraBuffering {
 			fbuf = newFixedBuffer(h.mem)
 		}
@@ -231,7 +258,7 @@ func (h *handler) receive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	uid := uniqueID(r, sessID)
-	statsif, loaded := h.sessions.LoadOrStore(uid, &Stats{})
+	statsif, loaded := h.sessions.LoadOrStore(uid, &Stats{Codec: codec})
 	if !loaded && debug {
 		xxh, id := UID2SessID(uid)
 		cmn.Assert(id == uint64(sessID))
@@ -239,21 +266,53 @@ func (h *handler) receive(w http.ResponseWriter, r *http.Request) {
 	}
 	stats := statsif.(*Stats)
 
+	// reliable mode: deliver at most once and acknowledge
+	var rr *relRecv
+	if streamID := r.Header.Get(cmn.HeaderStreamID); streamID != "" {
+		rr = h.relRecv(streamID)
+	}
+
 	// Rx loop
-	it := &iterator{trname: trname, body: reader, fbuf: fbuf, headerBuf: make([]byte, maxHeaderSize)}
+	it := &iterator{trname: trname, body: reader, fbuf: fbuf, headerBuf: make([]byte, maxHeaderSize), reliable: rr != nil}
 	for {
 		objReader, hl64, err := it.next()
 		if hl64 != 0 {
 			_ = stats.Offset.Add(hl64)
 		}
+		if cbody != nil {
+			stats.CompressedSize.Add(cbody.swap())
+		}
+		if objReader != nil && objReader.hdr.isSkip() && rr != nil {
+			// the sender has failed to retransmit - skip the sequence number
+			_, errSeq := rr.deliver(objReader.seq, func() bool { return true })
+			if errSeq == nil {
+				continue
+			}
+			xxh, _ := UID2SessID(uid)
+			err, objReader = fmt.Errorf("%s[%d:%d]: %v (skip)", trname, xxh, sessID, errSeq), nil
+		}
 		if objReader != nil {
 			er := err
 			if er == io.EOF {
 				er = nil
 			}
-			h.callback(w, objReader.hdr, objReader, er)
-			hdr := &objReader.hdr
-			if hdr.ObjAttrs.Size == objReader.off {
+			var (
+				hdr    = &objReader.hdr
+				errSeq error
+			)
+			if rr == nil {
+				h.callback(w, *hdr, objReader, er)
+			} else {
+				var dup bool
+				dup, errSeq = rr.deliver(objReader.seq, func() bool {
+					h.callback(w, *hdr, objReader, er)
+					return hdr.ObjAttrs.Size == objReader.off
+				})
+				if dup {
+					_, _ = io.Copy(ioutil.Discard, objReader) // retransmitted duplicate
+				}
+			}
+			if errSeq == nil && hdr.ObjAttrs.Size == objReader.off {
 				var (
 					num = stats.Num.Inc()
 					siz = stats.Size.Add(hdr.ObjAttrs.Size)
@@ -267,11 +326,18 @@ func (h *handler) receive(w http.ResponseWriter, r *http.Request) {
 				continue
 			}
 			xxh, _ := UID2SessID(uid)
-			err = fmt.Errorf("%s[%d:%d]: sbrk #3: err %v, off %d != %d size, num=%d, %s/%s",
-				trname, xxh, sessID, err, objReader.off, hdr.ObjAttrs.Size, stats.Num.Load(), hdr.Bck, hdr.ObjName)
+			if errSeq != nil {
+				err = fmt.Errorf("%s[%d:%d]: %v, %s/%s", trname, xxh, sessID, errSeq, hdr.Bck, hdr.ObjName)
+			} else {
+				err = fmt.Errorf("%s[%d:%d]: sbrk #3: err %v, off %d != %d size, num=%d, %s/%s",
+					trname, xxh, sessID, err, objReader.off, hdr.ObjAttrs.Size, stats.Num.Load(), hdr.Bck, hdr.ObjName)
+			}
 		}
 		if err != nil {
 			h.oldSessions.Store(uid, time.Now())
+			if rr != nil {
+				w.Header().Set(cmn.HeaderSeqAck, rr.ack())
+			}
 			if err != io.EOF {
 				h.callback(w, Header{}, nil, err)
 				cmn.InvalidHandlerDetailed(w, r, err.Error())
@@ -279,6 +345,9 @@ func (h *handler) receive(w http.ResponseWriter, r *http.Request) {
 			if lz4Reader != nil {
 				lz4Reader.Reset(nil)
 			}
+			if zstdReader != nil {
+				zstdReader.Close()
+			}
 			if fbuf != nil {
 				fbuf.Free()
 			}
@@ -299,6 +368,7 @@ func (h *handler) cleanupOldSessions() time.Duration {
 		return true
 	}
 	h.oldSessions.Range(f)
+	h.cleanupReliable(now)
 	return cleanupInterval
 }
 
@@ -363,13 +433,14 @@ func (it *iterator) next() (obj *objReader, hl64 int64, err error) {
 		cmn.AssertMsg(n == hlen, fmt.Sprintf("%d != %d", n, hlen))
 	}
 	// buf => obj header
-	hdr = ExtHeader(it.headerBuf, hlen)
+	var seq int64
+	hdr, seq = extHeader(it.headerBuf, hlen, it.reliable)
 	if hdr.IsLast() {
 		err = io.EOF
 		return
 	}
 
-	obj = &objReader{body: it.body, fbuf: it.fbuf, hdr: hdr}
+	obj = &objReader{body: it.body, fbuf: it.fbuf, hdr: hdr, seq: seq}
 	return
 }
 
@@ -439,6 +510,11 @@ func (bb *fixedBuffer) Free()         { bb.slab.Free(bb.buf) }
 // helpers
 //
 func ExtHeader(body []byte, hlen int) (hdr Header) {
+	hdr, _ = extHeader(body, hlen, false)
+	return
+}
+
+func extHeader(body []byte, hlen int, reliable bool) (hdr Header, seq int64) {
 	var off int
 	off, hdr.Bck.Name = extString(0, body)
 	off, hdr.ObjName = extString(off, body)
@@ -447,6 +523,10 @@ func ExtHeader(body []byte, hlen int) (hdr Header) {
 	off, hdr.Bck.Ns.UUID = extString(off, body)
 	off, hdr.Opaque = extByte(off, body)
 	off, hdr.ObjAttrs = extAttrs(off, body)
+	off, hdr.Traceparent = extString(off, body)
+	if reliable {
+		off, seq = extInt64(off, body)
+	}
 	if _, ok := cmn.CheckDebug(pkgName); ok {
 		cmn.AssertMsg(off == hlen, fmt.Sprintf("off %d, hlen %d", off, hlen))
 	}
@@ -484,6 +564,18 @@ func extAttrs(off int, from []byte) (n int, attr ObjectAttrs) {
 	return off, attr
 }
 
+//
+// countingReader
+//
+
+func (cr *countingReader) Read(b []byte) (n int, err error) {
+	n, err = cr.r.Read(b)
+	cr.n.Add(int64(n))
+	return
+}
+
+func (cr *countingReader) swap() int64 { return cr.n.Swap(0) }
+
 //
 // sessID => unique ID
 //
@@ -0,0 +1,255 @@
+// Package transport provides streaming object-based transport over http for intra-cluster continuous
+// intra-cluster communications (see README for details and usage example).
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package transport
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Reliable mode (see Extra.Reliable): the sender numbers objects (starting from 1) and keeps
+// the sent ones pending until acknowledged. The receiver delivers each sequence number at most
+// once and responds with the cumulative ACK (the last delivered sequence number) at the end of
+// each HTTP session. Upon session failure the sender reconnects and retransmits all unacknowledged
+// objects in the original order while the receiver discards duplicates.
+const (
+	relMaxPending = 256                    // max num sent-but-unacknowledged objects; when reached, the session is renewed
+	relMaxRetries = 5                      // max consecutive reconnect attempts
+	relBackoff    = time.Millisecond * 500 // reconnect backoff (multiplied by the attempt number)
+)
+
+type (
+	// sender side
+	reliable struct {
+		mu      sync.Mutex
+		id      string // stream ID that persists across sessions
+		seq     int64  // last assigned sequence number
+		pending []Obj  // sent and not yet acknowledged
+		resend  []Obj  // to retransmit upon reconnect
+		retries int    // consecutive failed sessions with no progress
+		rotate  bool   // renew session to get the pending objects acknowledged
+		rotated bool   // session renewed
+	}
+	// receiver side
+	relRecv struct {
+		mu      sync.Mutex
+		last    int64     // last delivered sequence number
+		touched time.Time // last activity
+	}
+)
+
+//
+// sender
+//
+
+func newReliable() *reliable { return &reliable{id: cmn.GenUUID()} }
+
+// returns the next object to retransmit, if any
+func (rel *reliable) next() (obj Obj, ok bool) {
+	rel.mu.Lock()
+	if len(rel.resend) > 0 {
+		obj, ok = rel.resend[0], true
+		rel.resend = rel.resend[1:]
+	}
+	rel.mu.Unlock()
+	return
+}
+
+func (rel *reliable) nextSeq() int64 {
+	rel.mu.Lock()
+	rel.seq++
+	seq := rel.seq
+	rel.mu.Unlock()
+	return seq
+}
+
+// returns true if the current session must end at the object boundary
+func (rel *reliable) rotating() (yes bool) {
+	rel.mu.Lock()
+	if rel.rotate {
+		rel.rotate, rel.rotated, yes = false, true, true
+	}
+	rel.mu.Unlock()
+	return
+}
+
+// sent in its entirety - hold on until acknowledged
+func (rel *reliable) sent(obj Obj) {
+	rel.mu.Lock()
+	rel.pending = append(rel.pending, obj)
+	if len(rel.pending) >= relMaxPending {
+		rel.rotate = true
+	}
+	rel.mu.Unlock()
+}
+
+// returns true if there's more to send in a new session without waiting for the next Send
+func (rel *reliable) again() (yes bool) {
+	rel.mu.Lock()
+	yes = rel.rotated || len(rel.resend) > 0
+	rel.rotated = false
+	rel.mu.Unlock()
+	return
+}
+
+// rewind the reader prior to retransmitting
+func (obj *Obj) rewind() (err error) {
+	if obj.Hdr.IsHeaderOnly() {
+		return
+	}
+	seeker, ok := obj.Reader.(io.Seeker)
+	if !ok {
+		return fmt.Errorf("%s/%s: reader %T is not seekable", obj.Hdr.Bck, obj.Hdr.ObjName, obj.Reader)
+	}
+	_, err = seeker.Seek(0, io.SeekStart)
+	return
+}
+
+// handle the receiver's response at the end of the session: complete acknowledged
+// objects and fail the session if anything remains unacknowledged
+func (s *Stream) ackRecv(ack string, status int) error {
+	rel := s.rel
+	rel.mu.Lock()
+	var acked []Obj
+	if ack != "" {
+		last, err := strconv.ParseInt(ack, 10, 64)
+		if err != nil {
+			rel.mu.Unlock()
+			return fmt.Errorf("%s: invalid %s %q: %v", s, cmn.HeaderSeqAck, ack, err)
+		}
+		i := 0
+		for ; i < len(rel.pending) && rel.pending[i].seq <= last; i++ {
+		}
+		acked, rel.pending = rel.pending[:i], rel.pending[i:]
+	} else if status < 400 {
+		// the receiver has processed the entire session without reporting the ACK
+		acked, rel.pending = rel.pending, nil
+	}
+	num := len(rel.pending)
+	if num == 0 {
+		rel.pending = nil
+	}
+	if len(acked) > 0 {
+		rel.retries = 0 // making progress
+	}
+	rel.mu.Unlock()
+
+	for _, obj := range acked {
+		s.cmplCh <- cmpl{obj, nil}
+	}
+	if status >= 400 {
+		return fmt.Errorf("%s: session failed with status %d (unacknowledged: %d)", s, status, num)
+	}
+	if num > 0 {
+		return fmt.Errorf("%s: %d object(s) unacknowledged", s, num)
+	}
+	return nil
+}
+
+// reconnect and retransmit everything that has not been acknowledged
+// returns false when out of retries or stopped
+func (s *Stream) retransmit(err error) bool {
+	rel := s.rel
+	rel.mu.Lock()
+	if rel.retries >= relMaxRetries {
+		rel.mu.Unlock()
+		return false
+	}
+	rel.retries++
+	retries := rel.retries
+	resend := rel.pending
+	if s.sendoff.obj.Reader != nil { // in-flight
+		resend = append(resend, s.sendoff.obj)
+	}
+	rel.resend = append(resend, rel.resend...)
+	rel.pending = nil
+	rel.rotate = false
+	num := len(rel.resend)
+	rel.mu.Unlock()
+
+	s.sendoff = sendoff{}
+	glog.Warningf("%s: %v - reconnecting to retransmit %d object(s), attempt %d/%d", s, err, num, retries, relMaxRetries)
+	select {
+	case <-time.After(relBackoff * time.Duration(retries)):
+	case <-s.stopCh.Listen():
+		return false
+	}
+	s.sessST.Store(active)
+	return true
+}
+
+func (s *Stream) resetRetries() {
+	s.rel.mu.Lock()
+	s.rel.retries = 0
+	s.rel.mu.Unlock()
+}
+
+// terminated: complete all objects that were not (and will not be) acknowledged
+func (s *Stream) relAbort(err error) {
+	rel := s.rel
+	rel.mu.Lock()
+	objs := append(rel.pending, rel.resend...)
+	rel.pending, rel.resend = nil, nil
+	rel.mu.Unlock()
+	for i := range objs {
+		s.objDone(&objs[i], err)
+	}
+}
+
+//
+// receiver
+//
+
+func (h *handler) relRecv(streamID string) *relRecv {
+	v, _ := h.reliable.LoadOrStore(streamID, &relRecv{})
+	return v.(*relRecv)
+}
+
+// deliver the object unless it's a duplicate; the callback executes under lock to
+// serialize concurrent sessions of the same stream (e.g., broken and renewed)
+func (rr *relRecv) deliver(seq int64, callback func() (received bool)) (dup bool, err error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.touched = time.Now()
+	switch {
+	case seq <= rr.last:
+		dup = true
+	case rr.last != 0 && seq != rr.last+1: // (zero when new or restarted)
+		err = fmt.Errorf("out of sequence: expecting %d, got %d", rr.last+1, seq)
+	default:
+		if callback() {
+			rr.last = seq
+		}
+	}
+	return
+}
+
+func (rr *relRecv) ack() string {
+	rr.mu.Lock()
+	last := rr.last
+	rr.mu.Unlock()
+	return strconv.FormatInt(last, 10)
+}
+
+func (h *handler) cleanupReliable(now time.Time) {
+	f := func(key, value interface{}) bool {
+		rr := value.(*relRecv)
+		rr.mu.Lock()
+		idle := now.Sub(rr.touched)
+		rr.mu.Unlock()
+		if idle > cleanupInterval {
+			h.reliable.Delete(key)
+		}
+		return true
+	}
+	h.reliable.Range(f)
+}
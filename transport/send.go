@@ -25,6 +25,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/xoshiro256"
+	"github.com/klauspost/compress/zstd"
 	lz4 "github.com/pierrec/lz4/v3"
 )
 
@@ -33,6 +34,7 @@ const (
 	maxHeaderSize  = 1024
 	lastMarker     = math.MaxInt64
 	tickMarker     = math.MaxInt64 ^ 0xa5a5a5a5
+	skipMarker     = math.MaxInt64 ^ 0x5a5a5a5a // reliable mode: failed to retransmit (see Stream.Read)
 	tickUnit       = time.Second
 	defaultIdleOut = time.Second * 2
 	burstNum       = 32 // default max num objects that can be posted for sending without any back-pressure
@@ -88,15 +90,17 @@ type (
 			err        error
 			reason     *string
 		}
-		lz4s lz4Stream
+		cmprs cmprStream
+		rel   *reliable // reliable mode (optional)
 	}
 	// advanced usage: additional stream control
 	Extra struct {
 		IdleTimeout time.Duration // stream idle timeout: causes PUT to terminate (and renew on the next obj send)
 		Callback    SendCallback  // typical usage: to free SGLs, close files, etc.
-		Compression string        // see CompressAlways, etc. enum
+		Compression string        // see CompressAlways, CompressZstd, etc. enum
 		MMSA        *memsys.MMSA  // compression-related buffering
 		Config      *cmn.Config
+		Reliable    bool // sequence numbers, receiver ACKs, and retransmit upon reconnect (see reliable.go)
 	}
 	// stream stats
 	Stats struct {
@@ -104,6 +108,7 @@ type (
 		Size           atomic.Int64 // transferred object size (does not include transport headers)
 		Offset         atomic.Int64 // stream offset, in bytes
 		CompressedSize atomic.Int64 // compressed size (NOTE: converges to the actual compressed size over time)
+		Codec          string       // compression codec: cmn.LZ4Compression, etc. (empty when not compressed)
 	}
 	EndpointStats map[uint64]*Stats // all stats for a given http endpoint defined by a tuple(network, trname) by session ID
 
@@ -117,10 +122,11 @@ type (
 	}
 	// object header
 	Header struct {
-		Bck      cmn.Bck
-		ObjName  string
-		ObjAttrs ObjectAttrs // attributes/metadata of the sent object
-		Opaque   []byte      // custom control (optional)
+		Bck         cmn.Bck
+		ObjName     string
+		ObjAttrs    ObjectAttrs // attributes/metadata of the sent object
+		Opaque      []byte      // custom control (optional)
+		Traceparent string      // trace context of the sender (optional, see package tracing)
 	}
 	// object to transmit
 	Obj struct {
@@ -130,6 +136,7 @@ type (
 		CmplPtr  unsafe.Pointer // local pointer that gets returned to the caller via Send completion callback
 		// private
 		prc *atomic.Int64 // if present, ref-counts num sent objects to call SendCallback only once
+		seq int64         // reliable mode: sequence number
 	}
 
 	// object-sent callback that has the following signature can optionally be defined on a:
@@ -147,12 +154,23 @@ type (
 
 // internal types
 type (
-	lz4Stream struct {
+	compressor interface {
+		io.WriteCloser
+		Flush() error
+		Reset(w io.Writer)
+	}
+	cmprStream struct {
 		s             *Stream
-		zw            *lz4.Writer // orig reader => zw
-		sgl           *memsys.SGL // zw => bb => network
-		blockMaxSize  int         // *uncompressed* block max size
-		frameChecksum bool        // true: checksum lz4 frames
+		zw            compressor // orig reader => zw
+		sgl           lockedSGL  // zw => bb => network
+		codec         string     // cmn.LZ4Compression | cmn.ZstdCompression
+		blockMaxSize  int        // *uncompressed* block max size (lz4)
+		frameChecksum bool       // true: checksum frames
+	}
+	// zstd encoder writes compressed blocks asynchronously, concurrently with Read
+	lockedSGL struct {
+		mu  sync.Mutex
+		sgl *memsys.SGL
 	}
 	sendoff struct {
 		obj Obj
@@ -190,6 +208,13 @@ func (extra *Extra) compressed() bool {
 	return extra.Compression != "" && extra.Compression != cmn.CompressNever
 }
 
+func (extra *Extra) codec() string {
+	if extra.Compression == cmn.CompressZstd {
+		return cmn.ZstdCompression
+	}
+	return cmn.LZ4Compression
+}
+
 //
 // API methods
 //
@@ -207,23 +232,28 @@ func NewStream(client Client, toURL string, extra *Extra) (s *Stream) {
 		if extra.IdleTimeout > 0 {
 			s.time.idleOut = extra.IdleTimeout
 		}
+		if extra.Reliable {
+			s.rel = newReliable()
+		}
 		if extra.compressed() {
 			config := extra.Config
 			if config == nil {
 				config = cmn.GCO.Get()
 			}
-			s.lz4s.s = s
-			s.lz4s.blockMaxSize = config.Compression.BlockMaxSize
-			s.lz4s.frameChecksum = config.Compression.Checksum
+			s.cmprs.s = s
+			s.cmprs.codec = extra.codec()
+			s.cmprs.blockMaxSize = config.Compression.BlockMaxSize
+			s.cmprs.frameChecksum = config.Compression.Checksum
+			s.stats.Codec = s.cmprs.codec
 			mem := extra.MMSA
 			if mem == nil {
 				mem = memsys.DefaultPageMM()
 				glog.Warningln("Using global memory manager for streaming inline compression")
 			}
-			if s.lz4s.blockMaxSize >= memsys.MaxPageSlabSize {
-				s.lz4s.sgl = mem.NewSGL(memsys.MaxPageSlabSize, memsys.MaxPageSlabSize)
+			if s.cmprs.codec == cmn.LZ4Compression && s.cmprs.blockMaxSize >= memsys.MaxPageSlabSize {
+				s.cmprs.sgl.sgl = mem.NewSGL(memsys.MaxPageSlabSize, memsys.MaxPageSlabSize)
 			} else {
-				s.lz4s.sgl = mem.NewSGL(cmn.KiB*64, cmn.KiB*64)
+				s.cmprs.sgl.sgl = mem.NewSGL(cmn.KiB*64, cmn.KiB*64)
 			}
 		}
 	}
@@ -235,8 +265,10 @@ func NewStream(client Client, toURL string, extra *Extra) (s *Stream) {
 	s.trname = path.Base(u.Path)
 	if !s.compressed() {
 		s.lid = fmt.Sprintf("%s[%d]", s.trname, s.sessID)
+	} else if s.cmprs.codec == cmn.LZ4Compression {
+		s.lid = fmt.Sprintf("%s[%d[%s]]", s.trname, s.sessID, cmn.B2S(int64(s.cmprs.blockMaxSize), 0))
 	} else {
-		s.lid = fmt.Sprintf("%s[%d[%s]]", s.trname, s.sessID, cmn.B2S(int64(s.lz4s.blockMaxSize), 0))
+		s.lid = fmt.Sprintf("%s[%d[%s]]", s.trname, s.sessID, s.cmprs.codec)
 	}
 
 	// burst size: the number of objects the caller is permitted to post for sending
@@ -277,7 +309,7 @@ func NewStream(client Client, toURL string, extra *Extra) (s *Stream) {
 	return
 }
 
-func (s *Stream) compressed() bool { return s.lz4s.s == s }
+func (s *Stream) compressed() bool { return s.cmprs.s == s }
 
 // Asynchronously send an object defined by its header and its reader.
 // ---------------------------------------------------------------------------------------
@@ -319,6 +351,14 @@ func (s *Stream) Send(obj Obj) (err error) {
 			glog.Infof("%s: inactive => active", s)
 		}
 	}
+	if s.rel != nil && !hdr.IsHeaderOnly() {
+		if _, ok := obj.Reader.(io.Seeker); !ok {
+			err = fmt.Errorf("%s: reliable mode requires seekable reader to send [%s/%s(%d)]",
+				s, hdr.Bck, hdr.ObjName, hdr.ObjAttrs.Size)
+			glog.Errorln(err)
+			return
+		}
+	}
 	// next object => SQ
 	if obj.Reader == nil {
 		cmn.Assert(hdr.IsHeaderOnly())
@@ -363,10 +403,10 @@ func (s *Stream) terminate() {
 	gc.remove(s)
 
 	if s.compressed() {
-		s.lz4s.sgl.Free()
-		if s.lz4s.zw != nil {
-			s.lz4s.zw.Reset(nil)
+		if s.cmprs.zw != nil {
+			s.cmprs.zw.Reset(nil)
 		}
+		s.cmprs.sgl.sgl.Free()
 	}
 }
 
@@ -386,12 +426,14 @@ func (s *Stream) GetStats() (stats Stats) {
 	stats.Offset.Store(s.stats.Offset.Load())
 	stats.Size.Store(s.stats.Size.Load())
 	stats.CompressedSize.Store(s.stats.CompressedSize.Load())
+	stats.Codec = s.stats.Codec
 	return
 }
 
 func (hdr *Header) IsLast() bool       { return hdr.ObjAttrs.Size == lastMarker }
 func (hdr *Header) IsIdleTick() bool   { return hdr.ObjAttrs.Size == tickMarker }
-func (hdr *Header) IsHeaderOnly() bool { return hdr.ObjAttrs.Size == 0 || hdr.IsLast() }
+func (hdr *Header) IsHeaderOnly() bool { return hdr.ObjAttrs.Size == 0 || hdr.IsLast() || hdr.isSkip() }
+func (hdr *Header) isSkip() bool       { return hdr.ObjAttrs.Size == skipMarker }
 
 //
 // internal methods including the sending and completing loops below, each running in its own goroutine
@@ -403,11 +445,20 @@ func (s *Stream) sendLoop(dryrun bool) {
 			if dryrun {
 				s.dryrun()
 			} else if err := s.doRequest(); err != nil {
+				if s.rel != nil && s.retransmit(err) {
+					continue
+				}
 				*s.term.reason = reasonError
 				s.term.err = err
 				break
+			} else if s.rel != nil {
+				s.resetRetries()
 			}
 		}
+		if s.rel != nil && s.rel.again() {
+			s.sessST.Store(active)
+			continue
+		}
 		if !s.isNextReq() {
 			break
 		}
@@ -433,6 +484,10 @@ func (s *Stream) sendLoop(dryrun bool) {
 			obj := &s.sendoff.obj
 			s.objDone(obj, s.term.err)
 		}
+		// reliable mode: unacknowledged and pending retransmission
+		if s.rel != nil {
+			s.relAbort(s.term.err)
+		}
 		// finally, handle pending SQ
 		for obj := range s.workCh {
 			s.objDone(&obj, s.term.err)
@@ -455,6 +510,9 @@ func (s *Stream) cmplLoop() {
 // refcount, invoke Sendcallback, and *always* close the reader
 func (s *Stream) objDone(obj *Obj, err error) {
 	var rc int64
+	if obj.Hdr.isSkip() { // the object itself has already been completed
+		return
+	}
 	if obj.prc != nil {
 		rc = obj.prc.Dec()
 		cmn.Assert(rc >= 0) // remove
@@ -502,17 +560,8 @@ func (s *Stream) doRequest() (err error) {
 	)
 	s.Numcur, s.Sizecur = 0, 0
 	if s.compressed() {
-		s.lz4s.sgl.Reset()
-		if s.lz4s.zw == nil {
-			s.lz4s.zw = lz4.NewWriter(s.lz4s.sgl)
-		} else {
-			s.lz4s.zw.Reset(s.lz4s.sgl)
-		}
-		// lz4 framing spec at http://fastcompression.blogspot.com/2013/04/lz4-streaming-format-final.html
-		s.lz4s.zw.Header.BlockChecksum = false
-		s.lz4s.zw.Header.NoChecksum = !s.lz4s.frameChecksum
-		s.lz4s.zw.Header.BlockMaxSize = s.lz4s.blockMaxSize
-		body = &s.lz4s
+		s.cmprs.reset()
+		body = &s.cmprs
 	}
 	return s.do(body)
 }
@@ -537,6 +586,27 @@ func (s *Stream) Read(b []byte) (n int, err error) {
 		}
 	}
 repeat:
+	if s.rel != nil {
+		if resend, ok := s.rel.next(); ok { // retransmit
+			if err := resend.rewind(); err != nil {
+				glog.Errorln(err)
+				s.cmplCh <- cmpl{resend, err}
+				// keep the sequence contiguous: the receiver will skip this number
+				resend = Obj{
+					Hdr:    Header{Bck: resend.Hdr.Bck, ObjName: resend.Hdr.ObjName, ObjAttrs: ObjectAttrs{Size: skipMarker}},
+					Reader: nopRC,
+					seq:    resend.seq,
+				}
+			}
+			s.sendoff.obj = resend
+			l := s.insHeader(&s.sendoff.obj)
+			s.header = s.maxheader[:l]
+			return s.sendHdr(b)
+		}
+		if s.rel.rotating() {
+			return s.deactivate()
+		}
+	}
 	select {
 	case s.sendoff.obj = <-s.workCh: // next object OR idle tick
 		if s.sendoff.obj.Hdr.IsIdleTick() {
@@ -545,7 +615,10 @@ repeat:
 			}
 			return s.deactivate()
 		}
-		l := s.insHeader(s.sendoff.obj.Hdr)
+		if s.rel != nil && !s.sendoff.obj.Hdr.IsLast() {
+			s.sendoff.obj.seq = s.rel.nextSeq()
+		}
+		l := s.insHeader(&s.sendoff.obj)
 		s.header = s.maxheader[:l]
 		return s.sendHdr(b)
 	case <-s.stopCh.Listen():
@@ -622,6 +695,9 @@ func (s *Stream) eoObj(err error) {
 	if err != nil {
 		goto exit
 	}
+	if obj.Hdr.isSkip() {
+		goto exit
+	}
 	if s.sendoff.off != obj.Hdr.ObjAttrs.Size {
 		err = fmt.Errorf("%s: obj %s/%s offset %d != %d size",
 			s, s.sendoff.obj.Hdr.Bck, s.sendoff.obj.Hdr.ObjName, s.sendoff.off, obj.Hdr.ObjAttrs.Size)
@@ -638,15 +714,20 @@ exit:
 		glog.Errorln(err)
 	}
 
-	// next completion => SCQ
-	s.cmplCh <- cmpl{s.sendoff.obj, err}
+	// next completion => SCQ (reliable mode: upon ACK)
+	if s.rel != nil && err == nil {
+		s.rel.sent(s.sendoff.obj)
+	} else {
+		s.cmplCh <- cmpl{s.sendoff.obj, err}
+	}
 	s.sendoff = sendoff{}
 }
 
 //
 // stream helpers
 //
-func (s *Stream) insHeader(hdr Header) (l int) {
+func (s *Stream) insHeader(obj *Obj) (l int) {
+	hdr := &obj.Hdr
 	l = cmn.SizeofI64 * 2
 	l = insString(l, s.maxheader, hdr.Bck.Name)
 	l = insString(l, s.maxheader, hdr.ObjName)
@@ -655,6 +736,10 @@ func (s *Stream) insHeader(hdr Header) (l int) {
 	l = insString(l, s.maxheader, hdr.Bck.Ns.UUID)
 	l = insByte(l, s.maxheader, hdr.Opaque)
 	l = insAttrs(l, s.maxheader, hdr.ObjAttrs)
+	l = insString(l, s.maxheader, hdr.Traceparent)
+	if s.rel != nil {
+		l = insInt64(l, s.maxheader, obj.seq)
+	}
 	hlen := l - cmn.SizeofI64*2
 	insInt64(0, s.maxheader, int64(hlen))
 	checksum := xoshiro256.Hash(uint64(hlen))
@@ -699,7 +784,7 @@ func insAttrs(off int, to []byte, attr ObjectAttrs) int {
 func (s *Stream) dryrun() {
 	buf := make([]byte, cmn.KiB*32)
 	scloser := ioutil.NopCloser(s)
-	it := iterator{trname: s.trname, body: scloser, headerBuf: make([]byte, maxHeaderSize)}
+	it := iterator{trname: s.trname, body: scloser, headerBuf: make([]byte, maxHeaderSize), reliable: s.rel != nil}
 	for {
 		objReader, _, err := it.next()
 		if objReader != nil {
@@ -731,50 +816,124 @@ func (r *nopReadCloser) Read([]byte) (n int, err error) { return }
 func (r *nopReadCloser) Close() error                   { return nil }
 
 //
-// lz4Stream ---------------------------
+// cmprStream ---------------------------
 //
 
-func (lz4s *lz4Stream) Read(b []byte) (n int, err error) {
+func (cs *cmprStream) reset() {
+	cs.sgl.reset()
+	switch cs.codec {
+	case cmn.ZstdCompression:
+		if cs.zw == nil {
+			zw, err := zstd.NewWriter(&cs.sgl, zstd.WithEncoderConcurrency(1), zstd.WithEncoderCRC(cs.frameChecksum))
+			cmn.AssertNoErr(err)
+			cs.zw = zw
+		} else {
+			cs.zw.Reset(&cs.sgl)
+		}
+	default:
+		var zw *lz4.Writer
+		if cs.zw == nil {
+			zw = lz4.NewWriter(&cs.sgl)
+			cs.zw = zw
+		} else {
+			zw = cs.zw.(*lz4.Writer)
+			zw.Reset(&cs.sgl)
+		}
+		// lz4 framing spec at http://fastcompression.blogspot.com/2013/04/lz4-streaming-format-final.html
+		zw.Header.BlockChecksum = false
+		zw.Header.NoChecksum = !cs.frameChecksum
+		zw.Header.BlockMaxSize = cs.blockMaxSize
+	}
+}
+
+// end of the HTTP session: lz4 frames are left open (the receiver stops upon
+// the last header), while zstd requires the end-of-frame to decode without error
+func (cs *cmprStream) fin() {
+	if cs.codec == cmn.ZstdCompression {
+		cs.zw.Close()
+	} else {
+		cs.zw.Flush()
+	}
+}
+
+func (cs *cmprStream) Read(b []byte) (n int, err error) {
 	var (
-		sendoff = &lz4s.s.sendoff
+		sendoff = &cs.s.sendoff
 		last    = sendoff.obj.Hdr.IsLast()
-		retry   = 64 // insist on returning n > 0 (note that lz4 compresses /blocks/)
+		retry   = 64 // insist on returning n > 0 (note that lz4 and zstd compress /blocks/)
 	)
-	if lz4s.sgl.Len() > 0 {
-		lz4s.zw.Flush()
-		n, err = lz4s.sgl.Read(b)
+	if cs.sgl.len() > 0 {
+		cs.zw.Flush()
+		n, err = cs.sgl.read(b)
 		if err == io.EOF { // reusing/rewinding this buf multiple times
 			err = nil
 		}
 		goto ex
 	}
 re:
-	n, err = lz4s.s.Read(b)
-	_, _ = lz4s.zw.Write(b[:n])
-	if last {
-		lz4s.zw.Flush()
+	n, err = cs.s.Read(b)
+	_, _ = cs.zw.Write(b[:n])
+	if last || err != nil {
+		cs.fin()
 		retry = 0
-	} else if lz4s.s.sendoff.obj.Reader == nil /*eoObj*/ || err != nil {
-		lz4s.zw.Flush()
+	} else if cs.s.sendoff.obj.Reader == nil /*eoObj*/ {
+		cs.zw.Flush()
 		retry = 0
 	}
-	n, _ = lz4s.sgl.Read(b)
+	n, _ = cs.sgl.read(b)
 	if n == 0 {
 		if retry > 0 {
 			retry--
 			runtime.Gosched()
 			goto re
 		}
-		lz4s.zw.Flush()
-		n, _ = lz4s.sgl.Read(b)
+		cs.zw.Flush()
+		n, _ = cs.sgl.read(b)
 	}
 ex:
-	lz4s.s.stats.CompressedSize.Add(int64(n))
-	if lz4s.sgl.Len() == 0 {
-		lz4s.sgl.Reset()
-	}
+	cs.s.stats.CompressedSize.Add(int64(n))
+	cs.sgl.resetIfEmpty()
 	if last && err == nil {
 		err = io.EOF
 	}
 	return
 }
+
+//
+// lockedSGL ---------------------------
+//
+
+func (lsgl *lockedSGL) Write(b []byte) (n int, err error) {
+	lsgl.mu.Lock()
+	n, err = lsgl.sgl.Write(b)
+	lsgl.mu.Unlock()
+	return
+}
+
+func (lsgl *lockedSGL) read(b []byte) (n int, err error) {
+	lsgl.mu.Lock()
+	n, err = lsgl.sgl.Read(b)
+	lsgl.mu.Unlock()
+	return
+}
+
+func (lsgl *lockedSGL) len() (l int64) {
+	lsgl.mu.Lock()
+	l = lsgl.sgl.Len()
+	lsgl.mu.Unlock()
+	return
+}
+
+func (lsgl *lockedSGL) reset() {
+	lsgl.mu.Lock()
+	lsgl.sgl.Reset()
+	lsgl.mu.Unlock()
+}
+
+func (lsgl *lockedSGL) resetIfEmpty() {
+	lsgl.mu.Lock()
+	if lsgl.sgl.Len() == 0 {
+		lsgl.sgl.Reset()
+	}
+	lsgl.mu.Unlock()
+}
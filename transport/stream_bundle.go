@@ -106,6 +106,8 @@ func NewStreamBundle(sowner cluster.Sowner, lsnode *cluster.Snode, cl Client, sb
 	}
 	if !sb.extra.compressed() {
 		sb.lid = fmt.Sprintf("sb[%s=>%s/%s]", sb.lsnode.ID(), sb.network, sb.trname)
+	} else if codec := sb.extra.codec(); codec != cmn.LZ4Compression {
+		sb.lid = fmt.Sprintf("sb[%s=>%s/%s[%s]]", sb.lsnode.ID(), sb.network, sb.trname, codec)
 	} else {
 		sb.lid = fmt.Sprintf("sb[%s=>%s/%s[%s]]", sb.lsnode.ID(), sb.network, sb.trname,
 			cmn.B2S(int64(sb.extra.Config.Compression.BlockMaxSize), 0))
@@ -66,6 +66,13 @@ func Test_Bundle(t *testing.T) {
 				"block":       "256KiB",
 			},
 		},
+		{
+			name: "compress-zstd",
+			nvs: cmn.SimpleKVs{
+				"compression": cmn.CompressZstd,
+				"block":       "256KiB",
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -170,8 +177,8 @@ func testBundle(t *testing.T, nvs cmn.SimpleKVs) {
 
 	if nvs["compression"] != cmn.CompressNever {
 		for id, tstat := range stats {
-			fmt.Printf("send$ %s/%s: offset=%d, num=%d(%d), compression-ratio=%.2f\n",
-				id, trname, tstat.Offset.Load(), tstat.Num.Load(), num, tstat.CompressionRatio())
+			fmt.Printf("send$ %s/%s: offset=%d, num=%d(%d), %s compression-ratio=%.2f\n",
+				id, trname, tstat.Offset.Load(), tstat.Num.Load(), num, tstat.Codec, tstat.CompressionRatio())
 		}
 	} else {
 		for id, tstat := range stats {
@@ -21,7 +21,9 @@ package transport_test
 //
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -121,8 +123,8 @@ func Example_headers() {
 	stream.Fin()
 
 	// Output:
-	// {Bck:aws://uuid#namespace/abc ObjName:X ObjAttrs:{Atime:663346294 Size:231 CksumType:xxhash CksumValue:hash Version:2} Opaque:[]} (119)
-	// {Bck:ais://abracadabra ObjName:p/q/s ObjAttrs:{Atime:663346294 Size:213 CksumType:xxhash CksumValue:hash Version:2} Opaque:[49 50 51]} (121)
+	// {Bck:aws://uuid#namespace/abc ObjName:X ObjAttrs:{Atime:663346294 Size:231 CksumType:xxhash CksumValue:hash Version:2} Opaque:[] Traceparent:} (127)
+	// {Bck:ais://abracadabra ObjName:p/q/s ObjAttrs:{Atime:663346294 Size:213 CksumType:xxhash CksumValue:hash Version:2} Opaque:[49 50 51] Traceparent:} (129)
 }
 
 func sendText(stream *transport.Stream, txt1, txt2 string) {
@@ -450,6 +452,104 @@ func Test_ObjAttrs(t *testing.T) {
 	}
 }
 
+func Test_Reliable(t *testing.T) {
+	testReliable(t, "reliable", nil)
+}
+
+// every third object cannot be rewound: failing to retransmit it must not
+// affect the rest of the stream
+func Test_ReliableNoRewind(t *testing.T) {
+	testReliable(t, "reliable-norewind", func(idx int) bool { return idx%3 == 0 })
+}
+
+func testReliable(t *testing.T, trname string, noRewind func(idx int) bool) {
+	var (
+		objectCnt = 1000
+		mux       = mux.NewServeMux()
+		sessions  atomic.Int64
+		mu        sync.Mutex
+		received  = make(map[uint32]int, objectCnt)
+		sent      = make(map[uint32]error, objectCnt)
+	)
+	transport.SetMux("n1", mux)
+
+	// break the first few sessions: with an error (that the receiver reports
+	// along with the ACK) or by aborting the connection (no response)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := sessions.Inc(); n <= 4 {
+			r.Body = &breakingBody{ReadCloser: r.Body, limit: 64 * cmn.KiB, abort: n%2 == 0}
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	recvFunc := func(w http.ResponseWriter, hdr transport.Header, objReader io.Reader, err error) {
+		if err != nil {
+			return
+		}
+		b, err := ioutil.ReadAll(objReader)
+		if err != nil || int64(len(b)) != hdr.ObjAttrs.Size {
+			return // broken session
+		}
+		idx := binary.BigEndian.Uint32(hdr.Opaque)
+		for _, c := range b {
+			cmn.Assert(c == byte(idx))
+		}
+		mu.Lock()
+		received[idx]++
+		mu.Unlock()
+	}
+	path, err := transport.Register("n1", trname, recvFunc)
+	tassert.CheckFatal(t, err)
+	defer transport.Unregister("n1", trname)
+
+	callback := func(hdr transport.Header, _ io.ReadCloser, _ unsafe.Pointer, err error) {
+		mu.Lock()
+		sent[binary.BigEndian.Uint32(hdr.Opaque)] = err
+		mu.Unlock()
+	}
+	httpclient := transport.NewIntraDataClient()
+	stream := transport.NewStream(httpclient, ts.URL+path, &transport.Extra{Callback: callback, Reliable: true})
+
+	random := newRand(time.Now().UnixNano())
+	for idx := 0; idx < objectCnt; idx++ {
+		var (
+			size   = random.Int63n(16*cmn.KiB) + 1
+			opaque = make([]byte, 4)
+		)
+		binary.BigEndian.PutUint32(opaque, uint32(idx))
+		hdr := transport.Header{
+			Bck:      cmn.Bck{Name: trname, Provider: cmn.ProviderAIS},
+			ObjName:  strconv.Itoa(idx),
+			ObjAttrs: transport.ObjectAttrs{Size: size},
+			Opaque:   opaque,
+		}
+		reader := &seekReader{Reader: bytes.NewReader(bytes.Repeat([]byte{byte(idx)}, int(size)))}
+		reader.noRewind = noRewind != nil && noRewind(idx)
+		tassert.CheckFatal(t, stream.Send(transport.Obj{Hdr: hdr, Reader: reader}))
+	}
+	stream.Fin()
+
+	_, err = stream.TermInfo()
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, sessions.Load() > 3, "expecting broken sessions, got %d session(s) total", sessions.Load())
+	failed := 0
+	for idx := 0; idx < objectCnt; idx++ {
+		err, ok := sent[uint32(idx)]
+		tassert.Errorf(t, ok, "object %d: sent-callback not fired", idx)
+		if err != nil && noRewind != nil && noRewind(idx) {
+			failed++
+			tassert.Errorf(t, received[uint32(idx)] <= 1, "object %d: received %d times", idx, received[uint32(idx)])
+			continue
+		}
+		tassert.Errorf(t, err == nil, "object %d: sent-callback failed (%v)", idx, err)
+		tassert.Errorf(t, received[uint32(idx)] == 1, "object %d: received %d times", idx, received[uint32(idx)])
+	}
+	if noRewind != nil {
+		tassert.Errorf(t, failed > 0, "expecting some objects to fail retransmission")
+	}
+}
+
 //
 // test helpers
 //
@@ -666,3 +766,43 @@ func (rrc *randReaderCtx) sentCallback(hdr transport.Header, reader io.ReadClose
 	rrc.posted[rrc.idx] = nil
 	rrc.mu.Unlock()
 }
+
+//
+// reliable mode helpers
+//
+
+type (
+	seekReader struct {
+		*bytes.Reader
+		noRewind bool
+	}
+	breakingBody struct {
+		io.ReadCloser
+		limit int64
+		abort bool
+	}
+)
+
+func (*seekReader) Close() error { return nil }
+
+func (r *seekReader) Seek(offset int64, whence int) (int64, error) {
+	if r.noRewind {
+		return 0, errors.New("cannot rewind")
+	}
+	return r.Reader.Seek(offset, whence)
+}
+
+func (b *breakingBody) Read(p []byte) (n int, err error) {
+	if b.limit <= 0 {
+		if b.abort {
+			panic(http.ErrAbortHandler)
+		}
+		return 0, errors.New("broken session")
+	}
+	if int64(len(p)) > b.limit {
+		p = p[:b.limit]
+	}
+	n, err = b.ReadCloser.Read(p)
+	b.limit -= int64(n)
+	return
+}
@@ -168,6 +168,30 @@ func (e *electionEntry) Kind() string  { return cmn.ActElection }
 
 func (e *electionEntry) preRenewHook(_ globalEntry) bool { return true }
 
+//
+// decommissionEntry
+//
+type decommissionEntry struct {
+	baseGlobalEntry
+	xact *Decommission
+}
+
+func (e *decommissionEntry) Start(id string, _ cmn.Bck) error {
+	e.xact = &Decommission{XactBase: *cmn.NewXactBase(id, cmn.ActDecommission)}
+	return nil
+}
+func (e *decommissionEntry) Get() cmn.Xact { return e.xact }
+func (e *decommissionEntry) Kind() string  { return cmn.ActDecommission }
+
+func (e *decommissionEntry) preRenewHook(_ globalEntry) bool { return true }
+
+func (e *decommissionEntry) Stats(xact cmn.Xact) stats.XactStats {
+	cmn.Assert(xact == e.xact)
+	decommStats := &stats.DecommissionTargetStats{BaseXactStats: *stats.NewXactStats(e.xact)}
+	decommStats.Ext.Stage, decommStats.Ext.Err = e.xact.Status()
+	return decommStats
+}
+
 //
 // downloadEntry
 //
@@ -59,6 +59,13 @@ type (
 		cmn.NonmountpathXact
 		cmn.XactBase
 	}
+	Decommission struct {
+		cmn.NonmountpathXact
+		cmn.XactBase
+		mtx   sync.Mutex
+		stage string
+		err   string
+	}
 	bckListTask struct {
 		cmn.XactBase
 		ctx context.Context
@@ -113,6 +120,29 @@ func (xact *GlobalReb) Description() string      { return "cluster-wide global r
 func (xact *bckListTask) Description() string    { return "asynchronous bucket list task" }
 func (xact *bckSummaryTask) Description() string { return "asynchronous bucket summary task" }
 func (xact *Election) Description() string       { return "elect new primary proxy/gateway" }
+func (xact *Decommission) Description() string {
+	return "evacuate all objects from the target prior to its removal from the cluster"
+}
+
+// SetStage and Fail record the progress of the decommission, to be reported
+// in its stats (see decommissionEntry.Stats).
+func (xact *Decommission) SetStage(stage string) {
+	xact.mtx.Lock()
+	xact.stage = stage
+	xact.mtx.Unlock()
+}
+func (xact *Decommission) Fail(err error) {
+	xact.mtx.Lock()
+	xact.err = err.Error()
+	xact.mtx.Unlock()
+	xact.Abort()
+}
+func (xact *Decommission) Status() (stage, err string) {
+	xact.mtx.Lock()
+	stage, err = xact.stage, xact.err
+	xact.mtx.Unlock()
+	return
+}
 func (xact *LocalReb) Description() string {
 	return "resilver local storage upon mountpath-change events"
 }
@@ -626,6 +656,16 @@ func (r *registry) RenewElection() *Election {
 	return entry.xact
 }
 
+func (r *registry) RenewDecommission() *Decommission {
+	e := &decommissionEntry{}
+	ee, keep, _ := r.renewGlobalXaction(e)
+	entry := ee.(*decommissionEntry)
+	if keep { // previous decommission is still running
+		return nil
+	}
+	return entry.xact
+}
+
 func (r *registry) RenewDownloader(t cluster.Target, statsT stats.Tracker) (*downloader.Downloader, error) {
 	e := &downloaderEntry{t: t, statsT: statsT}
 	ee, _, err := r.renewGlobalXaction(e)
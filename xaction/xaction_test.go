@@ -7,6 +7,7 @@ package xaction
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
@@ -70,6 +71,18 @@ func TestXactionRenewPrefetch(t *testing.T) {
 	tassert.Errorf(t, notNilCount == 1, "expected just one Prefetch xaction to be created, got %d", notNilCount)
 }
 
+func TestXactionRenewDecommission(t *testing.T) {
+	xactions := newRegistry()
+	defer xactions.AbortAll()
+
+	xact := xactions.RenewDecommission()
+	tassert.Fatalf(t, xact != nil, "expected decommission xaction to be created")
+	tassert.Errorf(t, xactions.RenewDecommission() == nil, "expected running decommission not to be renewed")
+
+	xact.EndTime(time.Now())
+	tassert.Errorf(t, xactions.RenewDecommission() != nil, "expected new decommission after the previous one finished")
+}
+
 func TestXactionRenewEvictDelete(t *testing.T) {
 	xactions := newRegistry()
 	bmd := cluster.NewBaseBownerMock()